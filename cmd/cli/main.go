@@ -3,8 +3,13 @@ package main
 import (
 	"fmt"
 	grpcClient "github.com/dagozba/golangsmallshop/internal/client"
+	pb "github.com/dagozba/golangsmallshop/internal/generated/api/v1"
+	"github.com/golang/protobuf/ptypes"
 	"gopkg.in/urfave/cli.v1"
 	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 	"time"
 )
 
@@ -28,7 +33,11 @@ func main() {
 				{
 					Name: "create",
 					Action: func(c *cli.Context) {
-						id := grpcClient.CreateBasketCall()
+						id, err := grpcClient.CreateBasketCall()
+						if err != nil {
+							fmt.Println(grpcClient.Describe(err))
+							os.Exit(1)
+						}
 						fmt.Println("Created Basket with id: ", id)
 					},
 				},
@@ -38,7 +47,65 @@ func main() {
 					Action: func(c *cli.Context) {
 						basketId := c.Args().First()
 						fmt.Println("Basket to delete: ", basketId)
-						grpcClient.RemoveBasketCall(basketId)
+						result, err := grpcClient.RemoveBasketCall(basketId)
+						if err != nil {
+							fmt.Println(grpcClient.Describe(err))
+							os.Exit(1)
+						}
+						if result {
+							fmt.Println("Basket correctly deleted")
+						}
+					},
+				},
+				{
+					Name:  "show",
+					Usage: "BASKETID",
+					Action: func(c *cli.Context) {
+						basketId := c.Args().First()
+						b, err := grpcClient.GetBasketCall(basketId)
+						if err != nil {
+							fmt.Println(grpcClient.Describe(err))
+							os.Exit(1)
+						}
+						printBasket(b)
+					},
+				},
+				{
+					Name:  "set-qty",
+					Usage: "BASKETID ITEMID QUANTITY",
+					Action: func(c *cli.Context) {
+						basketId := c.Args().First()
+						item := c.Args().Get(1)
+						quantity, err := strconv.Atoi(c.Args().Get(2))
+						if err != nil {
+							fmt.Println("The quantity must be a number: ", c.Args().Get(2))
+							os.Exit(1)
+						}
+						fmt.Println("Basket id: ", basketId)
+						result, err := grpcClient.SetItemQuantityCall(basketId, item, quantity)
+						if err != nil {
+							fmt.Println(grpcClient.Describe(err))
+							os.Exit(1)
+						}
+						if result {
+							fmt.Printf("Item %s quantity set to %d\n", item, quantity)
+						}
+					},
+				},
+				{
+					Name:  "clear",
+					Usage: "BASKETID",
+					Action: func(c *cli.Context) {
+						basketId := c.Args().First()
+						fmt.Println("Basket to clear: ", basketId)
+						result, err := grpcClient.ClearBasketCall(basketId)
+						if err != nil {
+							fmt.Println(grpcClient.Describe(err))
+							os.Exit(1)
+						}
+						if result {
+							fmt.Println("Basket correctly cleared")
+						}
 					},
 				},
 			},
@@ -46,15 +113,30 @@ func main() {
 		{
 			Name:    "scan",
 			Aliases: []string{"s"},
-			Usage:   "Scans an item to add it to the given basket",
+			Usage:   "Scans an item to add it to the given basket, use --remove to take a mis-scanned item out of it",
+			Flags: []cli.Flag{
+				cli.BoolFlag{Name: "remove, r", Usage: "Removes a unit of the item from the basket instead of adding it"},
+			},
 			Action: func(c *cli.Context) {
 				basketId := c.Args().First()
 				item := c.Args().Get(1)
 				fmt.Println("Basket id: ", basketId)
+				if c.Bool("remove") {
+					fmt.Println("Item to Remove: ", item)
+					result, err := grpcClient.RemoveItemCall(basketId, item)
+					if err != nil {
+						fmt.Println(grpcClient.Describe(err))
+						os.Exit(1)
+					}
+					if result {
+						fmt.Printf("Item %s correctly removed\n", item)
+					}
+					return
+				}
 				fmt.Println("Item to Assign: ", item)
 				result, err := grpcClient.ScanItemCall(basketId, item)
 				if err != nil {
-					fmt.Println(err)
+					fmt.Println(grpcClient.Describe(err))
 					os.Exit(1)
 				}
 				if result {
@@ -63,6 +145,155 @@ func main() {
 
 			},
 		},
+		{
+			Name:    "weigh",
+			Aliases: []string{"w"},
+			Usage:   "Scans the weighed or measured quantity of an item sold by weight or measure: BASKETID ITEMID QUANTITY, or BASKETID --barcode BARCODE",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "barcode, b", Usage: "The variable measure barcode printed by the scale, embedding the item and its quantity"},
+			},
+			Action: func(c *cli.Context) {
+				basketId := c.Args().First()
+				item := c.Args().Get(1)
+				quantity := c.Args().Get(2)
+				barcode := c.String("barcode")
+				fmt.Println("Basket id: ", basketId)
+				result, err := grpcClient.ScanWeightedItemCall(basketId, item, quantity, barcode)
+				if err != nil {
+					fmt.Println(grpcClient.Describe(err))
+					os.Exit(1)
+				}
+				if result && barcode != "" {
+					fmt.Printf("Barcode %s correctly scanned\n", barcode)
+				} else if result {
+					fmt.Printf("%s of item %s correctly scanned\n", quantity, item)
+				}
+			},
+		},
+		{
+			Name:    "coupon",
+			Aliases: []string{"c"},
+			Usage:   "Applies a coupon to the given basket, use --remove to take it out of it",
+			Flags: []cli.Flag{
+				cli.BoolFlag{Name: "remove, r", Usage: "Removes the coupon from the basket instead of applying it"},
+			},
+			Action: func(c *cli.Context) {
+				basketId := c.Args().First()
+				code := c.Args().Get(1)
+				fmt.Println("Basket id: ", basketId)
+				if c.Bool("remove") {
+					result, err := grpcClient.RemoveCouponCall(basketId, code)
+					if err != nil {
+						fmt.Println(grpcClient.Describe(err))
+						os.Exit(1)
+					}
+					if result {
+						fmt.Printf("Coupon %s correctly removed\n", code)
+					}
+					return
+				}
+				result, err := grpcClient.ApplyCouponCall(basketId, code)
+				if err != nil {
+					fmt.Println(grpcClient.Describe(err))
+					os.Exit(1)
+				}
+				if result {
+					fmt.Printf("Coupon %s correctly applied\n", code)
+				}
+			},
+		},
+		{
+			Name:  "stock",
+			Usage: "Interacts with the stock of the items",
+			Subcommands: []cli.Command{
+				{
+					Name:  "show",
+					Usage: "ITEMID",
+					Action: func(c *cli.Context) {
+						l, err := grpcClient.GetStockLevelCall(c.Args().First())
+						if err != nil {
+							fmt.Println(grpcClient.Describe(err))
+							os.Exit(1)
+						}
+						printStockLevel(l)
+					},
+				},
+				{
+					Name:  "set",
+					Usage: "ITEMID UNITS",
+					Action: func(c *cli.Context) {
+						item := c.Args().First()
+						units, err := strconv.Atoi(c.Args().Get(1))
+						if err != nil {
+							fmt.Println("The units must be a number: ", c.Args().Get(1))
+							os.Exit(1)
+						}
+						l, err := grpcClient.SetStockLevelCall(item, units)
+						if err != nil {
+							fmt.Println(grpcClient.Describe(err))
+							os.Exit(1)
+						}
+						printStockLevel(l)
+					},
+				},
+			},
+		},
+		{
+			Name:  "checkout",
+			Usage: "Checks out the given basket into an order, the basket can't be changed afterwards: BASKETID",
+			Action: func(c *cli.Context) {
+				o, err := grpcClient.CheckoutCall(c.Args().First())
+				if err != nil {
+					fmt.Println(grpcClient.Describe(err))
+					os.Exit(1)
+				}
+				printOrder(o)
+			},
+		},
+		{
+			Name:  "order",
+			Usage: "Interacts with the checked out orders",
+			Subcommands: []cli.Command{
+				{
+					Name:  "show",
+					Usage: "ORDERNUMBER",
+					Action: func(c *cli.Context) {
+						o, err := grpcClient.GetOrderCall(c.Args().First())
+						if err != nil {
+							fmt.Println(grpcClient.Describe(err))
+							os.Exit(1)
+						}
+						printOrder(o)
+					},
+				},
+				{
+					Name:  "list",
+					Usage: "Lists the orders checked out within the given dates, ie: --from 2021-03-01 --to 2021-03-02",
+					Flags: []cli.Flag{
+						cli.StringFlag{Name: "from", Usage: "The first date or RFC 3339 time of the range, included"},
+						cli.StringFlag{Name: "to", Usage: "The last date or RFC 3339 time of the range, excluded"},
+					},
+					Action: func(c *cli.Context) {
+						from, err := parseTime(c.String("from"))
+						if err != nil {
+							fmt.Println("The start of the range is not a date: ", c.String("from"))
+							os.Exit(1)
+						}
+						to, err := parseTime(c.String("to"))
+						if err != nil {
+							fmt.Println("The end of the range is not a date: ", c.String("to"))
+							os.Exit(1)
+						}
+						orders, err := grpcClient.ListOrdersCall(from, to)
+						if err != nil {
+							fmt.Println(grpcClient.Describe(err))
+							os.Exit(1)
+						}
+						printOrders(orders)
+					},
+				},
+			},
+		},
 		{
 			Name:    "get-price",
 			Aliases: []string{"g"},
@@ -72,10 +303,25 @@ func main() {
 				fmt.Println("Basket id: ", basketId)
 				p, err := grpcClient.GetTotalAmountCall(basketId)
 				if err != nil {
-					fmt.Println(err)
+					fmt.Println(grpcClient.Describe(err))
 					os.Exit(1)
 				}
-				fmt.Printf("Obtained price is %.2f\n: ", float64(p / 100))
+				fmt.Printf("Obtained price is %s\n", p)
+			},
+		},
+		{
+			Name:    "breakdown",
+			Aliases: []string{"bd"},
+			Usage:   "Explains the price of a given basket line by line, including every applied promotion",
+			Action: func(c *cli.Context) {
+				basketId := c.Args().First()
+				fmt.Println("Basket id: ", basketId)
+				b, err := grpcClient.GetBasketBreakdownCall(basketId)
+				if err != nil {
+					fmt.Println(grpcClient.Describe(err))
+					os.Exit(1)
+				}
+				printBreakdown(b)
 			},
 		},
 	}
@@ -83,3 +329,148 @@ func main() {
 	app.Run(os.Args)
 
 }
+
+func printStockLevel(l *pb.StockLevelReply) {
+	if !l.Tracked {
+		fmt.Printf("The stock of item %s is not tracked, %d units reserved by the baskets\n", l.ItemId, l.Reserved)
+		return
+	}
+	fmt.Printf("Item %s: %d units on hand, %d reserved by the baskets, %d available\n", l.ItemId, l.OnHand, l.Reserved, l.Available)
+}
+
+func printOrder(o *pb.OrderReply) {
+	fmt.Println("Order number: ", o.OrderNumber)
+	fmt.Println("Basket id: ", o.BasketId)
+	if checkedOutAt, err := ptypes.Timestamp(o.CheckedOutAt); err == nil {
+		fmt.Println("Checked out at: ", checkedOutAt.Local().Format(time.RFC1123))
+	}
+	if o.ConfigVersion != "" {
+		fmt.Println("Configuration: ", o.ConfigVersion)
+	}
+	if len(o.Coupons) > 0 {
+		fmt.Println("Coupons: ", strings.Join(o.Coupons, ", "))
+	}
+	fmt.Println()
+	printBreakdown(o.Breakdown)
+}
+
+func printOrders(orders []*pb.OrderReply) {
+	if len(orders) == 0 {
+		fmt.Println("No orders were checked out within the range")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ORDER\tCHECKED OUT AT\tBASKET\tTOTAL")
+	for _, o := range orders {
+		checkedOutAt, _ := ptypes.Timestamp(o.CheckedOutAt)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", o.OrderNumber, checkedOutAt.Local().Format(time.RFC1123), o.BasketId, grpcClient.ToMoney(o.Breakdown.GetTotal()))
+	}
+	w.Flush()
+}
+
+//Parses a date, in local time, or an RFC 3339 time, an empty value is the zero time
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func printBasket(b *pb.GetBasketReply) {
+	fmt.Println("Basket id: ", b.BasketId)
+	if createdAt, err := ptypes.Timestamp(b.CreatedAt); err == nil {
+		fmt.Println("Created at: ", createdAt.Local().Format(time.RFC1123))
+	}
+	if len(b.Coupons) > 0 {
+		fmt.Println("Coupons: ", strings.Join(b.Coupons, ", "))
+	}
+	if len(b.Lines) == 0 {
+		fmt.Println("The basket is empty")
+		return
+	}
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ITEM\tNAME\tQTY")
+	for _, l := range b.Lines {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", l.ItemId, l.Name, quantityOf(l.Quantity, l.Measure, l.Unit))
+	}
+	w.Flush()
+}
+
+//Returns the units of a line, or its measure and unit for the items sold by weight or measure
+func quantityOf(units int32, measure string, unit string) string {
+	if measure == "" {
+		return strconv.Itoa(int(units))
+	}
+	return strings.TrimSpace(measure + " " + unit)
+}
+
+func printBreakdown(b *pb.BasketBreakdownReply) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ITEM\tNAME\tQTY\tUNIT PRICE\tGROSS")
+	for _, l := range b.Lines {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", l.ItemId, l.Name, quantityOf(l.Quantity, l.Measure, l.Unit), grpcClient.ToMoney(l.UnitPrice), grpcClient.ToMoney(l.Gross))
+	}
+	w.Flush()
+	if len(b.AppliedRules) > 0 {
+		fmt.Println()
+		fmt.Fprintln(w, "PROMOTION\tITEM\tUNITS\tDISCOUNT\tCONSUMED")
+		for _, r := range b.AppliedRules {
+			affected := r.AffectedItem
+			if affected == "" && len(r.Discounted) > 0 {
+				affected = consumedUnits(r.Discounted)
+			} else if affected == "" {
+				affected = "(basket)"
+			}
+			name := r.RuleName
+			if r.Stacked {
+				name += " (stacked)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t-%s\t%s\n", name, affected, quantityOf(r.UnitsAffected, r.Measure, ""), grpcClient.ToMoney(r.Discount), consumedUnits(r.Consumed))
+		}
+		w.Flush()
+	}
+	if len(b.SkippedRules) > 0 {
+		fmt.Println()
+		fmt.Fprintln(w, "SKIPPED PROMOTION\tREASON")
+		for _, s := range b.SkippedRules {
+			fmt.Fprintf(w, "%s\t%s\n", s.RuleName, s.Reason)
+		}
+		w.Flush()
+	}
+	if a := b.Assignment; a != nil {
+		search := "heuristic"
+		if a.TimedOut {
+			search = "timed out"
+		} else if a.Exhaustive {
+			search = "exhaustive"
+		}
+		fmt.Println()
+		fmt.Printf("Promotion order: %s (%d orders evaluated, %s), saving %s\n", strings.Join(a.Rules, ", "), a.Evaluated, search, grpcClient.ToMoney(a.Saving))
+	}
+	if len(b.Taxes) > 0 {
+		fmt.Println()
+		fmt.Fprintln(w, "TAX CLASS\tRATE\tNET\tTAX\tGROSS")
+		for _, t := range b.Taxes {
+			fmt.Fprintf(w, "%s\t%s%%\t%s\t%s\t%s\n", t.TaxClass, t.Rate, grpcClient.ToMoney(t.Net), grpcClient.ToMoney(t.Tax), grpcClient.ToMoney(t.Gross))
+		}
+		w.Flush()
+	}
+	fmt.Println()
+	fmt.Printf("Gross: %s\n", grpcClient.ToMoney(b.Gross))
+	fmt.Printf("Net: %s\n", grpcClient.ToMoney(b.Net))
+	fmt.Printf("Tax: %s\n", grpcClient.ToMoney(b.Tax))
+	fmt.Printf("Total: %s\n", grpcClient.ToMoney(b.Total))
+}
+
+//Formats the units consumed by a promotion, ie: "2 TSHIRT"
+func consumedUnits(consumed []*pb.ItemUnits) string {
+	var units []string
+	for _, c := range consumed {
+		units = append(units, fmt.Sprintf("%d %s", c.Units, c.ItemId))
+	}
+	return strings.Join(units, ", ")
+}
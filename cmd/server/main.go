@@ -2,43 +2,334 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	pb "github.com/dagozba/golangsmallshop/internal/generated/api/v1"
+	"github.com/dagozba/golangsmallshop/internal/money"
 	"github.com/dagozba/golangsmallshop/internal/parser"
 	"github.com/dagozba/golangsmallshop/internal/pricer"
 	"github.com/dagozba/golangsmallshop/internal/rules"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 	"net"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 type server struct {
-	pricer pricer.Pricer
+	pricer *pricer.Pricer
 }
 
 func (s *server) CreateBasket(context.Context, *empty.Empty) (*pb.BasketReply, error) {
-	id := s.pricer.CreateBasket()
+	id, err := s.pricer.CreateBasket()
+	if err != nil {
+		return nil, toStatusError(err)
+	}
 	return &pb.BasketReply{BasketId: id}, nil
 }
 
 func (s *server) ScanItem(context context.Context, request *pb.ItemRequest) (*pb.ItemReply, error) {
 	result, err := s.pricer.ScanItem(request.ItemId, request.BasketId)
-	return &pb.ItemReply{Result: result}, err
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.ItemReply{Result: result}, nil
+}
+
+//Scans the item and quantity of the request or, if a barcode is given instead, the item and quantity embedded in it
+func (s *server) ScanWeightedItem(context context.Context, request *pb.WeightedItemRequest) (*pb.ItemReply, error) {
+	if request.Barcode != "" {
+		if request.ItemId != "" || request.Quantity != "" {
+			return nil, badRequest("barcode", "either a barcode or an item and its quantity must be given, not both")
+		}
+		result, err := s.pricer.ScanVariableBarcode(request.Barcode, request.BasketId)
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+		return &pb.ItemReply{Result: result}, nil
+	}
+	quantity, err := money.ParseDecimal(request.Quantity)
+	if err != nil {
+		return nil, badRequest("quantity", fmt.Sprintf("the quantity is not valid: %v", err))
+	}
+	result, err := s.pricer.ScanWeightedItem(request.ItemId, request.BasketId, quantity)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.ItemReply{Result: result}, nil
 }
 
 func (s *server) GetTotalAmount(context context.Context, request *pb.TotalAmountRequest) (*pb.TotalAmountReply, error) {
 	totalAmount, err := s.pricer.GetTotalAmount(request.BasketId)
-	return &pb.TotalAmountReply{TotalAmount: totalAmount}, err
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.TotalAmountReply{TotalAmount: totalAmount.Amount, Total: toProtoMoney(totalAmount)}, nil
 }
 
 func (s *server) RemoveBasket(context context.Context, request *pb.RemoveBasketRequest) (*pb.RemoveBasketReply, error) {
-	result := s.pricer.RemoveBasket(request.BasketId)
+	result, err := s.pricer.RemoveBasket(request.BasketId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
 	return &pb.RemoveBasketReply{Result: result}, nil
 }
 
+func (s *server) RemoveItem(context context.Context, request *pb.ItemRequest) (*pb.ItemReply, error) {
+	result, err := s.pricer.RemoveItem(request.ItemId, request.BasketId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.ItemReply{Result: result}, nil
+}
+
+func (s *server) SetItemQuantity(context context.Context, request *pb.ItemQuantityRequest) (*pb.ItemReply, error) {
+	result, err := s.pricer.SetItemQuantity(request.ItemId, request.BasketId, int(request.Quantity))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.ItemReply{Result: result}, nil
+}
+
+func (s *server) ClearBasket(context context.Context, request *pb.ClearBasketRequest) (*pb.ClearBasketReply, error) {
+	result, err := s.pricer.ClearBasket(request.BasketId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.ClearBasketReply{Result: result}, nil
+}
+
+func (s *server) ApplyCoupon(context context.Context, request *pb.CouponRequest) (*pb.CouponReply, error) {
+	result, err := s.pricer.ApplyCoupon(request.Code, request.BasketId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.CouponReply{Result: result}, nil
+}
+
+func (s *server) RemoveCoupon(context context.Context, request *pb.CouponRequest) (*pb.CouponReply, error) {
+	result, err := s.pricer.RemoveCoupon(request.Code, request.BasketId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.CouponReply{Result: result}, nil
+}
+
+func (s *server) SetStockLevel(context context.Context, request *pb.StockLevelRequest) (*pb.StockLevelReply, error) {
+	level, err := s.pricer.SetStockLevel(request.ItemId, int(request.OnHand))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toProtoStockLevel(level), nil
+}
+
+func (s *server) GetStockLevel(context context.Context, request *pb.GetStockLevelRequest) (*pb.StockLevelReply, error) {
+	level, err := s.pricer.GetStockLevel(request.ItemId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toProtoStockLevel(level), nil
+}
+
+func toProtoStockLevel(l pricer.StockLevel) *pb.StockLevelReply {
+	return &pb.StockLevelReply{ItemId: l.ItemId, Tracked: l.Tracked, OnHand: int32(l.OnHand), Reserved: int32(l.Reserved),
+		Available: int32(l.Available)}
+}
+
+func (s *server) GetBasket(context context.Context, request *pb.GetBasketRequest) (*pb.GetBasketReply, error) {
+	contents, err := s.pricer.GetBasket(request.BasketId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	createdAt, err := ptypes.TimestampProto(contents.CreatedAt)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	reply := &pb.GetBasketReply{BasketId: contents.BasketId, CreatedAt: createdAt, Coupons: contents.Coupons}
+	for _, l := range contents.Lines {
+		reply.Lines = append(reply.Lines, &pb.BasketLine{ItemId: l.ItemId, Name: l.Name, Quantity: int32(l.Quantity),
+			Measure: toProtoMeasure(l.Measure), Unit: l.Unit})
+	}
+	return reply, nil
+}
+
+func (s *server) GetBasketBreakdown(context context.Context, request *pb.BasketBreakdownRequest) (*pb.BasketBreakdownReply, error) {
+	breakdown, err := s.pricer.GetBasketBreakdown(request.BasketId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toProtoBreakdown(breakdown), nil
+}
+
+func toProtoBreakdown(breakdown pricer.Breakdown) *pb.BasketBreakdownReply {
+	reply := &pb.BasketBreakdownReply{
+		BasketId: breakdown.BasketId,
+		Gross:    toProtoMoney(breakdown.Gross),
+		Net:      toProtoMoney(breakdown.Net),
+		Tax:      toProtoMoney(breakdown.Tax),
+		Total:    toProtoMoney(breakdown.Total),
+	}
+	for _, l := range breakdown.Lines {
+		reply.Lines = append(reply.Lines, &pb.BreakdownLine{
+			ItemId:    l.ItemId,
+			Name:      l.Name,
+			Quantity:  int32(l.Quantity),
+			Measure:   toProtoMeasure(l.Measure),
+			Unit:      l.Unit,
+			UnitPrice: toProtoMoney(l.UnitPrice),
+			Gross:     toProtoMoney(l.Gross),
+		})
+	}
+	for _, r := range breakdown.AppliedRules {
+		applied := &pb.AppliedRule{
+			RuleName:      r.RuleName,
+			AffectedItem:  r.AffectedItem,
+			UnitsAffected: int32(r.Units),
+			Measure:       toProtoMeasure(r.Measure),
+			Discount:      toProtoMoney(r.Discount),
+			Stacked:       r.Stacked,
+		}
+		for _, c := range r.Consumed {
+			applied.Consumed = append(applied.Consumed, &pb.ItemUnits{ItemId: c.ItemId, Units: int32(c.Units)})
+		}
+		for _, d := range r.Discounted {
+			applied.Discounted = append(applied.Discounted, &pb.ItemUnits{ItemId: d.ItemId, Units: int32(d.Units)})
+		}
+		reply.AppliedRules = append(reply.AppliedRules, applied)
+	}
+	for _, s := range breakdown.SkippedRules {
+		reply.SkippedRules = append(reply.SkippedRules, &pb.SkippedRule{RuleName: s.RuleName, Reason: s.Reason})
+	}
+	for _, t := range breakdown.Taxes {
+		reply.Taxes = append(reply.Taxes, &pb.TaxLine{TaxClass: t.TaxClass, Rate: t.Rate.String(), Net: toProtoMoney(t.Net),
+			Tax: toProtoMoney(t.Tax), Gross: toProtoMoney(t.Gross)})
+	}
+	if a := breakdown.Assignment; a != nil {
+		reply.Assignment = &pb.PromotionAssignment{Rules: a.Rules, Evaluated: int32(a.Evaluated), Exhaustive: a.Exhaustive,
+			TimedOut: a.TimedOut, Saving: toProtoMoney(a.Saving)}
+	}
+	return reply
+}
+
+func (s *server) Checkout(context context.Context, request *pb.CheckoutRequest) (*pb.OrderReply, error) {
+	order, err := s.pricer.Checkout(request.BasketId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toProtoOrder(order)
+}
+
+func (s *server) GetOrder(context context.Context, request *pb.GetOrderRequest) (*pb.OrderReply, error) {
+	order, err := s.pricer.GetOrder(request.OrderNumber)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toProtoOrder(order)
+}
+
+func (s *server) ListOrders(context context.Context, request *pb.ListOrdersRequest) (*pb.ListOrdersReply, error) {
+	from, err := fromProtoTime(request.From)
+	if err != nil {
+		return nil, badRequest("from", fmt.Sprintf("the start of the range is not valid: %v", err))
+	}
+	to, err := fromProtoTime(request.To)
+	if err != nil {
+		return nil, badRequest("to", fmt.Sprintf("the end of the range is not valid: %v", err))
+	}
+	orders, err := s.pricer.ListOrders(from, to)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	reply := &pb.ListOrdersReply{}
+	for _, o := range orders {
+		order, err := toProtoOrder(o)
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+		reply.Orders = append(reply.Orders, order)
+	}
+	return reply, nil
+}
+
+func toProtoOrder(o pricer.Order) (*pb.OrderReply, error) {
+	openedAt, err := ptypes.TimestampProto(o.OpenedAt)
+	if err != nil {
+		return nil, err
+	}
+	checkedOutAt, err := ptypes.TimestampProto(o.CheckedOutAt)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.OrderReply{OrderNumber: o.Number, BasketId: o.BasketId, OpenedAt: openedAt, CheckedOutAt: checkedOutAt,
+		ConfigVersion: o.ConfigVersion, Coupons: o.Coupons, Breakdown: toProtoBreakdown(o.Breakdown)}, nil
+}
+
+//Converts a timestamp of a request into a time, an unset timestamp is the zero time
+func fromProtoTime(t *timestamp.Timestamp) (time.Time, error) {
+	if t == nil {
+		return time.Time{}, nil
+	}
+	return ptypes.Timestamp(t)
+}
+
+func toProtoMoney(m money.Money) *pb.Money {
+	return &pb.Money{Amount: m.Amount, Currency: m.Currency}
+}
+
+//Converts a weighed or measured quantity into its decimal representation, empty for the items counted by units
+func toProtoMeasure(d money.Decimal) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+//Creates the basket store selected by the -basket-store flag
+func newBasketStore(storeType string, path string) (pricer.BasketStore, error) {
+	switch storeType {
+	case "memory":
+		log.Info("Baskets will be stored in memory")
+		return pricer.NewMemoryBasketStore(), nil
+	case "file":
+		log.Info("Baskets will be stored in ", path)
+		return pricer.OpenFileBasketStore(path)
+	default:
+		return nil, fmt.Errorf("unknown basket store '%s', expected memory or file", storeType)
+	}
+}
+
+//Creates the order store selected by the -order-store flag
+func newOrderStore(storeType string, path string) (pricer.OrderStore, error) {
+	switch storeType {
+	case "memory":
+		log.Info("Orders will be stored in memory")
+		return pricer.NewMemoryOrderStore(), nil
+	case "file":
+		log.Info("Orders will be stored in ", path)
+		return pricer.OpenFileOrderStore(path)
+	default:
+		return nil, fmt.Errorf("unknown order store '%s', expected memory or file", storeType)
+	}
+}
+
+//Reloads the config files every time the process receives a SIGHUP
+func reloadOnHangup(reloader *pricer.ConfigReloader) {
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	go func() {
+		for range hangups {
+			log.Info("SIGHUP received")
+			reloader.Reload()
+		}
+	}()
+}
+
 //It starts the GRPC server that will listen to requests to the CheckoutService
 func main() {
 
@@ -46,10 +337,40 @@ func main() {
 		port                    = flag.String("host", ":50051", "GRPC service address")
 		rulesFilePath           = flag.String("rules-path", "", "The path to the Rules yaml config file")
 		itemDefinitionsFilePath = flag.String("items-path", "", "The path to the item definitions yaml config file")
+		roundingModeName        = flag.String("rounding", "half-up", "Rounding applied to the basket totals: half-up, half-even or floor")
+		basketStoreType         = flag.String("basket-store", "memory", "Where the baskets are stored: memory or file")
+		basketStorePath         = flag.String("basket-store-path", "baskets.log", "The path to the baskets log when using the file basket store")
+		basketTTL               = flag.Duration("basket-ttl", 30*time.Minute, "Baskets not modified for this long expire, 0 disables it")
+		basketMaxLifetime       = flag.Duration("basket-max-lifetime", 12*time.Hour, "Baskets older than this expire, 0 disables it")
+		basketSweepInterval     = flag.Duration("basket-sweep-interval", time.Minute, "How often the expired baskets are evicted")
+		configPollInterval      = flag.Duration("config-poll-interval", 5*time.Second, "How often the config files are checked for changes, 0 disables it")
+		lenientConfig           = flag.Bool("lenient-config", false, "Discard the invalid rules and items with a warning instead of refusing the whole config")
+		optimisePromotions      = flag.Bool("optimise-promotions", false, "Execute the competing promotions in the order giving the cheapest total")
+		optimiserBudget         = flag.Duration("optimiser-budget", rules.DefaultBudget, "The maximum time spent searching the cheapest order of the promotions of a basket")
+		promotionTime           = flag.String("promotion-time", "total", "When the schedules of the promotions are checked: total, when the basket is priced, or scan, at the last change to the basket")
+		trackStock              = flag.Bool("track-stock", false, "Track the stock of the items with a stock level, reserving the units scanned into the baskets")
+		orderStoreType          = flag.String("order-store", "memory", "Where the checked out orders are stored: memory or file")
+		orderStorePath          = flag.String("order-store-path", "orders.log", "The path to the orders log when using the file order store")
 	)
 
+	if len(os.Args) > 1 && os.Args[1] == validateConfigCommand {
+		os.Exit(validateConfig(os.Args[2:]))
+	}
+
 	flag.Parse()
 
+	roundingMode, err := money.ParseRoundingMode(*roundingModeName)
+	if err != nil {
+		log.Fatal("Invalid rounding mode - ", err)
+		os.Exit(1)
+	}
+
+	schedulePolicy, err := pricer.ParseSchedulePolicy(*promotionTime)
+	if err != nil {
+		log.Fatal("Invalid promotion time - ", err)
+		os.Exit(1)
+	}
+
 	log.Info("Starting GRPC server listening on port: ", *port)
 	lis, err := net.Listen("tcp", *port)
 	if err != nil {
@@ -57,18 +378,79 @@ func main() {
 		os.Exit(1)
 	}
 
-	ruleFactory := &rules.RuleStrategyFactory{RuleParser: parser.RuleParser{}}
-	if err := ruleFactory.LoadRules(*rulesFilePath); err != nil {
-		log.Fatal("There was a problem loading the pricing rules for the service - ", err)
+	baskets, err := newBasketStore(*basketStoreType, *basketStorePath)
+	if err != nil {
+		log.Fatal("There was a problem opening the basket store - ", err)
+		os.Exit(1)
+	}
+
+	orders, err := newOrderStore(*orderStoreType, *orderStorePath)
+	if err != nil {
+		log.Fatal("There was a problem opening the order store - ", err)
+		os.Exit(1)
+	}
+
+	if *basketSweepInterval <= 0 {
+		log.Fatal("Invalid basket sweep interval - it must be greater than 0")
 		os.Exit(1)
 	}
 
-	basketPricer := pricer.Pricer{StrategyFactory: *ruleFactory, ItemsParser: parser.ItemsParser{}}
-	if err := basketPricer.LoadItems(*itemDefinitionsFilePath); err != nil {
-		log.Fatal("There was a problem loading the item definitions for the service - ", err)
+	//The units of the baskets kept by the basket store are reserved again
+	var stock *pricer.Inventory
+	if *trackStock {
+		if stock, err = pricer.NewInventory(baskets); err != nil {
+			log.Fatal("There was a problem reserving the stock of the stored baskets - ", err)
+			os.Exit(1)
+		}
+		log.Info("The stock of the items is tracked")
+	}
+
+	var janitor *pricer.BasketJanitor
+	if *basketTTL > 0 || *basketMaxLifetime > 0 {
+		janitor = pricer.NewBasketJanitor(baskets, pricer.ExpiryPolicy{IdleTTL: *basketTTL, MaxLifetime: *basketMaxLifetime})
+		janitor.Stock = stock
+		janitor.Start(*basketSweepInterval)
+		defer janitor.Stop()
+	}
+
+	//The config is loaded the same way it's reloaded, so a config the server starts with is never rejected by a reload
+	//In strict mode, the default, both files must be completely valid and consistent with each other or the server
+	//doesn't start. In lenient mode the invalid rules and items are discarded, but the rest must still be consistent
+	strict := !*lenientConfig
+	ruleParser := parser.RuleParser{Strict: strict}
+	itemsParser := parser.ItemsParser{Strict: strict}
+	ruleFactory := &rules.RuleStrategyFactory{RuleParser: ruleParser}
+	var optimiser *rules.Optimiser
+	if *optimisePromotions {
+		optimiser = &rules.Optimiser{Budget: *optimiserBudget}
+	}
+	basketPricer := pricer.NewPricer(*ruleFactory, itemsParser, baskets)
+	basketPricer.Rounding = roundingMode
+	basketPricer.Janitor = janitor
+	basketPricer.Optimiser = optimiser
+	basketPricer.SchedulePolicy = schedulePolicy
+	basketPricer.Stock = stock
+	basketPricer.Orders = orders
+	reloader := &pricer.ConfigReloader{
+		Pricer:        basketPricer,
+		RuleParser:    ruleParser,
+		ItemsParser:   itemsParser,
+		RulesFilePath: *rulesFilePath,
+		ItemsFilePath: *itemDefinitionsFilePath,
+	}
+	if err := reloader.Reload(); err != nil {
+		log.Fatal("There was a problem loading the configuration for the service - ", err)
 		os.Exit(1)
 	}
 
+	stopWatching := make(chan struct{})
+	defer close(stopWatching)
+	if *configPollInterval > 0 {
+		log.Infof("Watching the config files for changes every %s", *configPollInterval)
+		go reloader.Watch(*configPollInterval, stopWatching)
+	}
+	reloadOnHangup(reloader)
+
 	s := grpc.NewServer()
 	log.Info("Registering Checkout GRPC Service")
 	pb.RegisterCheckoutServer(s, &server{pricer: basketPricer})
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dagozba/golangsmallshop/internal/pricer"
+	"github.com/golang/protobuf/proto"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//Resource types and precondition violation types sent in the error details, so clients know what the error refers to
+const (
+	basketResourceType = "basket"
+	itemResourceType   = "item"
+	couponResourceType = "coupon"
+	orderResourceType  = "order"
+
+	basketExpiredViolation       = "BASKET_EXPIRED"
+	itemNotInBasketViolation     = "ITEM_NOT_IN_BASKET"
+	stockNotTrackedViolation     = "STOCK_NOT_TRACKED"
+	basketCheckedOutViolation    = "BASKET_CHECKED_OUT"
+	basketEmptyViolation         = "BASKET_EMPTY"
+	ordersNotConfiguredViolation = "ORDERS_NOT_CONFIGURED"
+)
+
+//Precondition violation types of the coupons that can't be used with a basket
+var couponViolations = []struct {
+	err       error
+	violation string
+}{
+	{pricer.ErrCouponNotValidYet, "COUPON_NOT_VALID_YET"},
+	{pricer.ErrCouponExpired, "COUPON_EXPIRED"},
+	{pricer.ErrCouponExhausted, "COUPON_EXHAUSTED"},
+	{pricer.ErrCouponAlreadyApplied, "COUPON_ALREADY_APPLIED"},
+	{pricer.ErrCouponNotInBasket, "COUPON_NOT_IN_BASKET"},
+}
+
+//Converts an error returned by the Pricer into a gRPC status error with the matching code, carrying the offending
+//basket or item in its details:
+//the basket doesn't exist -> NotFound with a ResourceInfo of the basket
+//the basket has expired, has been checked out or has nothing to check out -> FailedPrecondition with a
+//PreconditionFailure of the basket
+//the item isn't configured or the quantity is negative -> InvalidArgument with a BadRequest pointing at the field
+//the item can't be scanned that way, it's sold by weight or measure or it isn't, or the barcode is not valid ->
+//InvalidArgument with a BadRequest pointing at the field
+//the item isn't in the basket -> FailedPrecondition with a PreconditionFailure of the item
+//there aren't enough units of the item in stock -> ResourceExhausted with a QuotaFailure of the item telling the units
+//still available
+//the stock is not tracked -> FailedPrecondition with a PreconditionFailure of the item
+//the coupon doesn't exist -> NotFound with a ResourceInfo of the coupon
+//the coupon can't be used with the basket -> FailedPrecondition with a PreconditionFailure of the coupon
+//the order doesn't exist -> NotFound with a ResourceInfo of the order
+//the orders are not stored -> FailedPrecondition with a PreconditionFailure of the basket, if any
+//the date range ends before it starts -> InvalidArgument with a BadRequest pointing at the field
+//any other error -> Internal, without details
+func toStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var pricerErr *pricer.PricerError
+	if !errors.As(err, &pricerErr) {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	var st *status.Status
+	switch {
+	case errors.Is(err, pricer.ErrCouponNotFound):
+		st = withDetails(status.New(codes.NotFound, err.Error()), &errdetails.ResourceInfo{
+			ResourceType: couponResourceType,
+			ResourceName: pricerErr.CouponCode,
+			Description:  err.Error(),
+		})
+	case pricerErr.CouponCode != "":
+		st = status.New(codes.FailedPrecondition, err.Error())
+		for _, v := range couponViolations {
+			if errors.Is(err, v.err) {
+				st = withDetails(st, &errdetails.PreconditionFailure{
+					Violations: []*errdetails.PreconditionFailure_Violation{{
+						Type:        v.violation,
+						Subject:     pricerErr.CouponCode,
+						Description: err.Error(),
+					}},
+				})
+				break
+			}
+		}
+	case errors.Is(err, pricer.ErrBasketNotFound):
+		st = withDetails(status.New(codes.NotFound, err.Error()), &errdetails.ResourceInfo{
+			ResourceType: basketResourceType,
+			ResourceName: pricerErr.BasketId,
+			Description:  err.Error(),
+		})
+	case errors.Is(err, pricer.ErrBasketExpired):
+		st = withDetails(status.New(codes.FailedPrecondition, err.Error()), &errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{{
+				Type:        basketExpiredViolation,
+				Subject:     pricerErr.BasketId,
+				Description: "the basket has expired, a new basket must be created",
+			}},
+		})
+	case errors.Is(err, pricer.ErrBasketCheckedOut):
+		st = withDetails(status.New(codes.FailedPrecondition, err.Error()), &errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{{
+				Type:        basketCheckedOutViolation,
+				Subject:     pricerErr.BasketId,
+				Description: err.Error(),
+			}},
+		})
+	case errors.Is(err, pricer.ErrBasketEmpty):
+		st = withDetails(status.New(codes.FailedPrecondition, err.Error()), &errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{{
+				Type:        basketEmptyViolation,
+				Subject:     pricerErr.BasketId,
+				Description: err.Error(),
+			}},
+		})
+	case errors.Is(err, pricer.ErrOrderNotFound):
+		st = withDetails(status.New(codes.NotFound, err.Error()), &errdetails.ResourceInfo{
+			ResourceType: orderResourceType,
+			ResourceName: pricerErr.OrderNumber,
+			Description:  err.Error(),
+		})
+	case errors.Is(err, pricer.ErrOrdersNotConfigured):
+		st = withDetails(status.New(codes.FailedPrecondition, err.Error()), &errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{{
+				Type:        ordersNotConfiguredViolation,
+				Subject:     pricerErr.BasketId,
+				Description: err.Error(),
+			}},
+		})
+	case errors.Is(err, pricer.ErrInvalidDateRange):
+		st = withDetails(status.New(codes.InvalidArgument, err.Error()), &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "to", Description: err.Error()}},
+		})
+	case errors.Is(err, pricer.ErrItemNotConfigured):
+		st = withDetails(status.New(codes.InvalidArgument, err.Error()), &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "itemId", Description: err.Error()}},
+		}, &errdetails.ResourceInfo{
+			ResourceType: itemResourceType,
+			ResourceName: pricerErr.ItemId,
+			Description:  err.Error(),
+		})
+	case errors.Is(err, pricer.ErrInvalidQuantity):
+		st = withDetails(status.New(codes.InvalidArgument, err.Error()), &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "quantity", Description: err.Error()}},
+		})
+	case errors.Is(err, pricer.ErrItemMeasured), errors.Is(err, pricer.ErrItemNotMeasured), errors.Is(err, pricer.ErrItemHasVariants):
+		st = withDetails(status.New(codes.InvalidArgument, err.Error()), &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "itemId", Description: err.Error()}},
+		})
+	case errors.Is(err, pricer.ErrInvalidBarcode):
+		st = withDetails(status.New(codes.InvalidArgument, err.Error()), &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "barcode", Description: err.Error()}},
+		})
+	case errors.Is(err, pricer.ErrItemNotInBasket):
+		st = withDetails(status.New(codes.FailedPrecondition, err.Error()), &errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{{
+				Type:        itemNotInBasketViolation,
+				Subject:     pricerErr.ItemId,
+				Description: err.Error(),
+			}},
+		})
+	case errors.Is(err, pricer.ErrInsufficientStock):
+		var stockErr *pricer.StockError
+		available := 0
+		if errors.As(err, &stockErr) {
+			available = stockErr.Available
+		}
+		st = withDetails(status.New(codes.ResourceExhausted, err.Error()), &errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{{
+				Subject:     pricerErr.ItemId,
+				Description: fmt.Sprintf("%d units are available", available),
+			}},
+		})
+	case errors.Is(err, pricer.ErrStockNotTracked):
+		st = withDetails(status.New(codes.FailedPrecondition, err.Error()), &errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{{
+				Type:        stockNotTrackedViolation,
+				Subject:     pricerErr.ItemId,
+				Description: err.Error(),
+			}},
+		})
+	default:
+		st = status.New(codes.Internal, err.Error())
+	}
+	return st.Err()
+}
+
+//Returns an InvalidArgument status error for a request field that is not valid, before it reaches the Pricer
+func badRequest(field string, description string) error {
+	return withDetails(status.New(codes.InvalidArgument, description), &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: description}},
+	}).Err()
+}
+
+//Attaches the details to the status, returning the status without them if they can't be attached
+func withDetails(st *status.Status, details ...proto.Message) *status.Status {
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		log.Errorf("The error details couldn't be attached to the status: %v", err)
+		return st
+	}
+	return detailed
+}
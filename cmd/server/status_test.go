@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"github.com/dagozba/golangsmallshop/internal/pricer"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestToStatusError(t *testing.T) {
+
+	//ARRANGE
+	tests := []struct {
+		name     string
+		err      error
+		expected codes.Code
+	}{
+		{"Basket not found", &pricer.PricerError{Err: pricer.ErrBasketNotFound, BasketId: "B"}, codes.NotFound},
+		{"Basket expired", &pricer.PricerError{Err: pricer.ErrBasketExpired, BasketId: "B"}, codes.FailedPrecondition},
+		{"Item not configured", &pricer.PricerError{Err: pricer.ErrItemNotConfigured, BasketId: "B", ItemId: "I"}, codes.InvalidArgument},
+		{"Invalid quantity", &pricer.PricerError{Err: pricer.ErrInvalidQuantity, BasketId: "B", ItemId: "I"}, codes.InvalidArgument},
+		{"Item measured", &pricer.PricerError{Err: pricer.ErrItemMeasured, BasketId: "B", ItemId: "I"}, codes.InvalidArgument},
+		{"Item not measured", &pricer.PricerError{Err: pricer.ErrItemNotMeasured, BasketId: "B", ItemId: "I"}, codes.InvalidArgument},
+		{"Item has variants", &pricer.PricerError{Err: pricer.ErrItemHasVariants, BasketId: "B", ItemId: "I"}, codes.InvalidArgument},
+		{"Invalid barcode", &pricer.PricerError{Err: pricer.ErrInvalidBarcode, BasketId: "B"}, codes.InvalidArgument},
+		{"Item not in basket", &pricer.PricerError{Err: pricer.ErrItemNotInBasket, BasketId: "B", ItemId: "I"}, codes.FailedPrecondition},
+		{"Coupon not found", &pricer.PricerError{Err: pricer.ErrCouponNotFound, BasketId: "B", CouponCode: "C"}, codes.NotFound},
+		{"Coupon expired", &pricer.PricerError{Err: pricer.ErrCouponExpired, BasketId: "B", CouponCode: "C"}, codes.FailedPrecondition},
+		{"Coupon exhausted", &pricer.PricerError{Err: pricer.ErrCouponExhausted, BasketId: "B", CouponCode: "C"}, codes.FailedPrecondition},
+		{"Insufficient stock", &pricer.PricerError{Err: &pricer.StockError{Available: 2}, BasketId: "B", ItemId: "I"}, codes.ResourceExhausted},
+		{"Stock not tracked", &pricer.PricerError{Err: pricer.ErrStockNotTracked, ItemId: "I"}, codes.FailedPrecondition},
+		{"Basket checked out", &pricer.PricerError{Err: pricer.ErrBasketCheckedOut, BasketId: "B"}, codes.FailedPrecondition},
+		{"Basket empty", &pricer.PricerError{Err: pricer.ErrBasketEmpty, BasketId: "B"}, codes.FailedPrecondition},
+		{"Order not found", &pricer.PricerError{Err: pricer.ErrOrderNotFound, OrderNumber: "O"}, codes.NotFound},
+		{"Invalid date range", &pricer.PricerError{Err: pricer.ErrInvalidDateRange}, codes.InvalidArgument},
+		{"Orders not configured", &pricer.PricerError{Err: pricer.ErrOrdersNotConfigured, BasketId: "B"}, codes.FailedPrecondition},
+		{"Store failure", &pricer.PricerError{Err: errors.New("disk full"), BasketId: "B"}, codes.Internal},
+		{"Unknown error", errors.New("unknown"), codes.Internal},
+	}
+
+	for _, test := range tests {
+		//ACT
+		st := status.Convert(toStatusError(test.err))
+
+		//ASSERT
+		if st.Code() != test.expected {
+			t.Errorf("%s: expected code %s, got %s", test.name, test.expected, st.Code())
+		}
+	}
+
+}
+
+func TestToStatusErrorDetails(t *testing.T) {
+
+	//ARRANGE
+	err := &pricer.PricerError{Err: pricer.ErrBasketNotFound, BasketId: "FAKEBASKETID", ItemId: "MUG"}
+
+	//ACT
+	st := status.Convert(toStatusError(err))
+
+	//ASSERT
+	if len(st.Details()) != 1 {
+		t.Fatalf("The status should carry a single detail, got: %+v", st.Details())
+	}
+	info, ok := st.Details()[0].(*errdetails.ResourceInfo)
+	if !ok || info.ResourceType != "basket" || info.ResourceName != "FAKEBASKETID" {
+		t.Errorf("The status should carry the missing basket as a ResourceInfo, got: %+v", st.Details()[0])
+	}
+
+}
+
+func TestToStatusErrorCouponDetails(t *testing.T) {
+
+	//ARRANGE
+	err := &pricer.PricerError{Err: pricer.ErrCouponExhausted, BasketId: "FAKEBASKETID", CouponCode: "SPRING10"}
+
+	//ACT
+	st := status.Convert(toStatusError(err))
+
+	//ASSERT
+	if len(st.Details()) != 1 {
+		t.Fatalf("The status should carry a single detail, got: %+v", st.Details())
+	}
+	failure, ok := st.Details()[0].(*errdetails.PreconditionFailure)
+	if !ok || len(failure.Violations) != 1 || failure.Violations[0].Type != "COUPON_EXHAUSTED" || failure.Violations[0].Subject != "SPRING10" {
+		t.Errorf("The status should carry the exhausted coupon as a PreconditionFailure, got: %+v", st.Details()[0])
+	}
+
+}
+
+func TestToStatusErrorStockDetails(t *testing.T) {
+
+	//ARRANGE
+	err := &pricer.PricerError{Err: &pricer.StockError{Available: 2}, BasketId: "FAKEBASKETID", ItemId: "MUG"}
+
+	//ACT
+	st := status.Convert(toStatusError(err))
+
+	//ASSERT
+	if len(st.Details()) != 1 {
+		t.Fatalf("The status should carry a single detail, got: %+v", st.Details())
+	}
+	failure, ok := st.Details()[0].(*errdetails.QuotaFailure)
+	if !ok || len(failure.Violations) != 1 || failure.Violations[0].Subject != "MUG" || failure.Violations[0].Description != "2 units are available" {
+		t.Errorf("The status should carry the units available of the item as a QuotaFailure, got: %+v", st.Details()[0])
+	}
+
+}
+
+func TestToStatusErrorOrderDetails(t *testing.T) {
+
+	//ARRANGE
+	err := &pricer.PricerError{Err: pricer.ErrOrderNotFound, OrderNumber: "FAKEORDER"}
+
+	//ACT
+	st := status.Convert(toStatusError(err))
+
+	//ASSERT
+	if len(st.Details()) != 1 {
+		t.Fatalf("The status should carry a single detail, got: %+v", st.Details())
+	}
+	info, ok := st.Details()[0].(*errdetails.ResourceInfo)
+	if !ok || info.ResourceType != "order" || info.ResourceName != "FAKEORDER" {
+		t.Errorf("The status should carry the missing order as a ResourceInfo, got: %+v", st.Details()[0])
+	}
+
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"os"
+)
+
+const validateConfigCommand = "validate-config"
+
+//Validates the config files in strict mode, the way the server loads them, and reports every problem found in them
+//It's meant to be run by CI against config changes, so it exits with 1 if the config is not valid and 2 if it couldn't
+//be run at all
+//
+//	$ ./server validate-config -rules-path configs/rules.yaml -items-path configs/item_definitions.yaml
+func validateConfig(args []string) int {
+	flags := flag.NewFlagSet(validateConfigCommand, flag.ContinueOnError)
+	rulesFilePath := flags.String("rules-path", "", "The path to the Rules yaml config file")
+	itemDefinitionsFilePath := flags.String("items-path", "", "The path to the item definitions yaml config file")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *rulesFilePath == "" || *itemDefinitionsFilePath == "" {
+		fmt.Fprintln(os.Stderr, "both -rules-path and -items-path are required")
+		return 2
+	}
+
+	rules, items, err := parser.ValidateConfig(*rulesFilePath, *itemDefinitionsFilePath)
+	if configErr, ok := err.(*parser.ConfigError); ok {
+		for _, p := range configErr.Problems {
+			fmt.Println(p)
+		}
+		fmt.Printf("The configuration is not valid, problems found: %d\n", len(configErr.Problems))
+		return 1
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	fmt.Printf("The configuration is valid: %d items and %d pricing rules\n", len(items), rules.Count())
+	return 0
+}
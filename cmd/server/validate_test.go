@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestValidateConfigCommand(t *testing.T) {
+
+	//ARRANGE
+	tests := []struct {
+		name     string
+		args     []string
+		expected int
+	}{
+		{"Valid config", []string{"-rules-path", "../../configs/rules.yaml", "-items-path", "../../configs/item_definitions.yaml"}, 0},
+		{"Missing file", []string{"-rules-path", "../../configs/missing.yaml", "-items-path", "../../configs/item_definitions.yaml"}, 1},
+		{"Missing flag", []string{"-rules-path", "../../configs/rules.yaml"}, 2},
+	}
+
+	for _, test := range tests {
+		//ACT
+		code := validateConfig(test.args)
+
+		//ASSERT
+		if code != test.expected {
+			t.Errorf("%s: expected exit code %d, got %d", test.name, test.expected, code)
+		}
+	}
+
+}
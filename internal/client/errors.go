@@ -0,0 +1,113 @@
+package client
+
+import (
+	"fmt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//Precondition violation types sent by the server in the error details
+const (
+	basketExpiredViolation    = "BASKET_EXPIRED"
+	basketCheckedOutViolation = "BASKET_CHECKED_OUT"
+	basketEmptyViolation      = "BASKET_EMPTY"
+	itemNotInBasketViolation  = "ITEM_NOT_IN_BASKET"
+
+	couponNotValidYetViolation    = "COUPON_NOT_VALID_YET"
+	couponExpiredViolation        = "COUPON_EXPIRED"
+	couponExhaustedViolation      = "COUPON_EXHAUSTED"
+	couponAlreadyAppliedViolation = "COUPON_ALREADY_APPLIED"
+	couponNotInBasketViolation    = "COUPON_NOT_IN_BASKET"
+
+	stockNotTrackedViolation = "STOCK_NOT_TRACKED"
+)
+
+//Returns a message for the user explaining the error returned by a call to the server
+//It relies on the gRPC status code and the error details sent by the server, never on the error message
+func Describe(err error) string {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err.Error()
+	}
+	switch st.Code() {
+	case codes.NotFound:
+		if r := resourceInfo(st); r != nil {
+			return fmt.Sprintf("The %s '%s' doesn't exist", r.ResourceType, r.ResourceName)
+		}
+	case codes.InvalidArgument:
+		if r := resourceInfo(st); r != nil {
+			return fmt.Sprintf("The %s '%s' is not sold in this shop", r.ResourceType, r.ResourceName)
+		}
+		if b := badRequest(st); b != nil && len(b.FieldViolations) > 0 {
+			return fmt.Sprintf("Invalid %s: %s", b.FieldViolations[0].Field, b.FieldViolations[0].Description)
+		}
+	case codes.FailedPrecondition:
+		if p := preconditionFailure(st); p != nil && len(p.Violations) > 0 {
+			v := p.Violations[0]
+			switch v.Type {
+			case basketExpiredViolation:
+				return fmt.Sprintf("The basket '%s' has expired, please create a new one", v.Subject)
+			case basketCheckedOutViolation:
+				return fmt.Sprintf("The basket '%s' has been checked out, please create a new one", v.Subject)
+			case basketEmptyViolation:
+				return fmt.Sprintf("The basket '%s' has no items to check out", v.Subject)
+			case itemNotInBasketViolation:
+				return fmt.Sprintf("The item '%s' is not in the basket", v.Subject)
+			case couponNotValidYetViolation, couponExpiredViolation:
+				return fmt.Sprintf("The coupon '%s' can't be used at this time: %s", v.Subject, v.Description)
+			case couponExhaustedViolation:
+				return fmt.Sprintf("The coupon '%s' has run out, please remove it from the basket", v.Subject)
+			case couponAlreadyAppliedViolation:
+				return fmt.Sprintf("The coupon '%s' can only be used once per basket", v.Subject)
+			case couponNotInBasketViolation:
+				return fmt.Sprintf("The coupon '%s' is not applied to the basket", v.Subject)
+			case stockNotTrackedViolation:
+				return "The stock of the items is not tracked by the server"
+			}
+		}
+	case codes.ResourceExhausted:
+		if q := quotaFailure(st); q != nil && len(q.Violations) > 0 {
+			return fmt.Sprintf("There isn't enough stock of the item '%s', %s", q.Violations[0].Subject, q.Violations[0].Description)
+		}
+	case codes.Unavailable:
+		return "The server is not available, please try again later"
+	}
+	return fmt.Sprintf("The server returned an error (%s): %s", st.Code(), st.Message())
+}
+
+func resourceInfo(st *status.Status) *errdetails.ResourceInfo {
+	for _, d := range st.Details() {
+		if r, ok := d.(*errdetails.ResourceInfo); ok {
+			return r
+		}
+	}
+	return nil
+}
+
+func badRequest(st *status.Status) *errdetails.BadRequest {
+	for _, d := range st.Details() {
+		if b, ok := d.(*errdetails.BadRequest); ok {
+			return b
+		}
+	}
+	return nil
+}
+
+func preconditionFailure(st *status.Status) *errdetails.PreconditionFailure {
+	for _, d := range st.Details() {
+		if p, ok := d.(*errdetails.PreconditionFailure); ok {
+			return p
+		}
+	}
+	return nil
+}
+
+func quotaFailure(st *status.Status) *errdetails.QuotaFailure {
+	for _, d := range st.Details() {
+		if q, ok := d.(*errdetails.QuotaFailure); ok {
+			return q
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+
+	//ARRANGE
+	expired, _ := status.New(codes.FailedPrecondition, "the specified basket has expired").WithDetails(&errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{{Type: "BASKET_EXPIRED", Subject: "B"}},
+	})
+	notFound, _ := status.New(codes.NotFound, "the specified basket doesn't exist").WithDetails(&errdetails.ResourceInfo{
+		ResourceType: "basket", ResourceName: "B",
+	})
+	exhausted, _ := status.New(codes.FailedPrecondition, "the specified coupon has run out").WithDetails(&errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{{Type: "COUPON_EXHAUSTED", Subject: "SPRING10"}},
+	})
+	outOfStock, _ := status.New(codes.ResourceExhausted, "there isn't enough stock of the specified item").WithDetails(&errdetails.QuotaFailure{
+		Violations: []*errdetails.QuotaFailure_Violation{{Subject: "MUG", Description: "2 units are available"}},
+	})
+	checkedOut, _ := status.New(codes.FailedPrecondition, "the specified basket has been checked out").WithDetails(&errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{{Type: "BASKET_CHECKED_OUT", Subject: "B"}},
+	})
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"Expired basket", expired.Err(), "The basket 'B' has expired, please create a new one"},
+		{"Checked out basket", checkedOut.Err(), "The basket 'B' has been checked out, please create a new one"},
+		{"Exhausted coupon", exhausted.Err(), "The coupon 'SPRING10' has run out, please remove it from the basket"},
+		{"Out of stock item", outOfStock.Err(), "There isn't enough stock of the item 'MUG', 2 units are available"},
+		{"Non existent basket", notFound.Err(), "The basket 'B' doesn't exist"},
+		{"Unavailable server", status.Error(codes.Unavailable, "connection refused"), "The server is not available, please try again later"},
+		{"No details", status.Error(codes.Internal, "disk full"), "The server returned an error (Internal): disk full"},
+	}
+
+	for _, test := range tests {
+		//ACT
+		message := Describe(test.err)
+
+		//ASSERT
+		if message != test.expected {
+			t.Errorf("%s: expected '%s', got '%s'", test.name, test.expected, message)
+		}
+	}
+
+}
@@ -3,10 +3,14 @@ package client
 import (
 	"flag"
 	pb "github.com/dagozba/golangsmallshop/internal/generated/api/v1"
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"log"
+	"time"
 )
 
 func InitializeConnection() *grpc.ClientConn {
@@ -21,12 +25,15 @@ func InitializeConnection() *grpc.ClientConn {
 	return conn
 }
 
-func CreateBasketCall() string {
+func CreateBasketCall() (string, error) {
 	conn := InitializeConnection()
 	defer conn.Close()
 	c := pb.NewCheckoutClient(conn)
-	r, _ := c.CreateBasket(context.Background(), &empty.Empty{})
-	return r.BasketId
+	r, err := c.CreateBasket(context.Background(), &empty.Empty{})
+	if err != nil {
+		return "", err
+	}
+	return r.BasketId, nil
 }
 
 func ScanItemCall(basketId string, item string) (bool, error) {
@@ -40,21 +47,167 @@ func ScanItemCall(basketId string, item string) (bool, error) {
 	return r.Result, nil
 }
 
-func GetTotalAmountCall(basketId string) (int64, error) {
+func RemoveItemCall(basketId string, item string) (bool, error) {
+	conn := InitializeConnection()
+	defer conn.Close()
+	c := pb.NewCheckoutClient(conn)
+	r, err := c.RemoveItem(context.Background(), &pb.ItemRequest{BasketId: basketId, ItemId: item})
+	if err != nil {
+		return false, err
+	}
+	return r.Result, nil
+}
+
+func SetItemQuantityCall(basketId string, item string, quantity int) (bool, error) {
+	conn := InitializeConnection()
+	defer conn.Close()
+	c := pb.NewCheckoutClient(conn)
+	r, err := c.SetItemQuantity(context.Background(), &pb.ItemQuantityRequest{BasketId: basketId, ItemId: item, Quantity: int32(quantity)})
+	if err != nil {
+		return false, err
+	}
+	return r.Result, nil
+}
+
+//Scans the quantity of an item sold by weight or measure, or the item and quantity embedded in the barcode if the item
+//is empty
+func ScanWeightedItemCall(basketId string, item string, quantity string, barcode string) (bool, error) {
+	conn := InitializeConnection()
+	defer conn.Close()
+	c := pb.NewCheckoutClient(conn)
+	r, err := c.ScanWeightedItem(context.Background(), &pb.WeightedItemRequest{BasketId: basketId, ItemId: item, Quantity: quantity, Barcode: barcode})
+	if err != nil {
+		return false, err
+	}
+	return r.Result, nil
+}
+
+func ClearBasketCall(basketId string) (bool, error) {
+	conn := InitializeConnection()
+	defer conn.Close()
+	c := pb.NewCheckoutClient(conn)
+	r, err := c.ClearBasket(context.Background(), &pb.ClearBasketRequest{BasketId: basketId})
+	if err != nil {
+		return false, err
+	}
+	return r.Result, nil
+}
+
+func GetTotalAmountCall(basketId string) (money.Money, error) {
 	conn := InitializeConnection()
 	defer conn.Close()
 	c := pb.NewCheckoutClient(conn)
 	r, err := c.GetTotalAmount(context.Background(), &pb.TotalAmountRequest{BasketId: basketId})
 	if err != nil {
-		return 0, err
+		return money.Money{}, err
+	}
+	return ToMoney(r.Total), nil
+}
+
+func GetBasketCall(basketId string) (*pb.GetBasketReply, error) {
+	conn := InitializeConnection()
+	defer conn.Close()
+	c := pb.NewCheckoutClient(conn)
+	return c.GetBasket(context.Background(), &pb.GetBasketRequest{BasketId: basketId})
+}
+
+func GetBasketBreakdownCall(basketId string) (*pb.BasketBreakdownReply, error) {
+	conn := InitializeConnection()
+	defer conn.Close()
+	c := pb.NewCheckoutClient(conn)
+	return c.GetBasketBreakdown(context.Background(), &pb.BasketBreakdownRequest{BasketId: basketId})
+}
+
+func ApplyCouponCall(basketId string, code string) (bool, error) {
+	conn := InitializeConnection()
+	defer conn.Close()
+	c := pb.NewCheckoutClient(conn)
+	r, err := c.ApplyCoupon(context.Background(), &pb.CouponRequest{BasketId: basketId, Code: code})
+	if err != nil {
+		return false, err
+	}
+	return r.Result, nil
+}
+
+func RemoveCouponCall(basketId string, code string) (bool, error) {
+	conn := InitializeConnection()
+	defer conn.Close()
+	c := pb.NewCheckoutClient(conn)
+	r, err := c.RemoveCoupon(context.Background(), &pb.CouponRequest{BasketId: basketId, Code: code})
+	if err != nil {
+		return false, err
+	}
+	return r.Result, nil
+}
+
+//Converts an amount received from the server into Money
+func ToMoney(m *pb.Money) money.Money {
+	return money.New(m.GetAmount(), m.GetCurrency())
+}
+
+func RemoveBasketCall(basketId string) (bool, error) {
+	conn := InitializeConnection()
+	defer conn.Close()
+	c := pb.NewCheckoutClient(conn)
+	r, err := c.RemoveBasket(context.Background(), &pb.RemoveBasketRequest{BasketId: basketId})
+	if err != nil {
+		return false, err
 	}
-	return r.TotalAmount, nil
+	return r.Result, nil
+}
+
+func SetStockLevelCall(item string, onHand int) (*pb.StockLevelReply, error) {
+	conn := InitializeConnection()
+	defer conn.Close()
+	c := pb.NewCheckoutClient(conn)
+	return c.SetStockLevel(context.Background(), &pb.StockLevelRequest{ItemId: item, OnHand: int32(onHand)})
+}
+
+func GetStockLevelCall(item string) (*pb.StockLevelReply, error) {
+	conn := InitializeConnection()
+	defer conn.Close()
+	c := pb.NewCheckoutClient(conn)
+	return c.GetStockLevel(context.Background(), &pb.GetStockLevelRequest{ItemId: item})
+}
+
+func CheckoutCall(basketId string) (*pb.OrderReply, error) {
+	conn := InitializeConnection()
+	defer conn.Close()
+	c := pb.NewCheckoutClient(conn)
+	return c.Checkout(context.Background(), &pb.CheckoutRequest{BasketId: basketId})
 }
 
-func RemoveBasketCall(basketId string) bool {
+func GetOrderCall(number string) (*pb.OrderReply, error) {
 	conn := InitializeConnection()
 	defer conn.Close()
 	c := pb.NewCheckoutClient(conn)
-	r, _ := c.RemoveBasket(context.Background(), &pb.RemoveBasketRequest{BasketId: basketId})
-	return r.Result
+	return c.GetOrder(context.Background(), &pb.GetOrderRequest{OrderNumber: number})
+}
+
+//Lists the orders checked out from the given time, included, to the given one, excluded, a zero time leaves that end
+//of the range open
+func ListOrdersCall(from time.Time, to time.Time) ([]*pb.OrderReply, error) {
+	request := &pb.ListOrdersRequest{}
+	var err error
+	if request.From, err = toProtoTime(from); err != nil {
+		return nil, err
+	}
+	if request.To, err = toProtoTime(to); err != nil {
+		return nil, err
+	}
+	conn := InitializeConnection()
+	defer conn.Close()
+	c := pb.NewCheckoutClient(conn)
+	r, err := c.ListOrders(context.Background(), request)
+	if err != nil {
+		return nil, err
+	}
+	return r.Orders, nil
+}
+
+func toProtoTime(t time.Time) (*timestamp.Timestamp, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return ptypes.TimestampProto(t)
 }
@@ -7,6 +7,7 @@ import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
 import empty "github.com/golang/protobuf/ptypes/empty"
+import timestamp "github.com/golang/protobuf/ptypes/timestamp"
 
 import (
 	context "golang.org/x/net/context"
@@ -36,7 +37,7 @@ func (m *BasketReply) Reset()         { *m = BasketReply{} }
 func (m *BasketReply) String() string { return proto.CompactTextString(m) }
 func (*BasketReply) ProtoMessage()    {}
 func (*BasketReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_checkout_3ebee9caf288178b, []int{0}
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{0}
 }
 func (m *BasketReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BasketReply.Unmarshal(m, b)
@@ -64,6 +65,7 @@ func (m *BasketReply) GetBasketId() string {
 }
 
 // Item request message that sends the target basketId and the itemId (Pre defined in the server)
+// When scanning or removing an Item, the itemId can also be any of the EAN-8, UPC-A, EAN-13 or GTIN-14 barcodes of the Item
 type ItemRequest struct {
 	BasketId             string   `protobuf:"bytes,1,opt,name=basketId,proto3" json:"basketId,omitempty"`
 	ItemId               string   `protobuf:"bytes,2,opt,name=itemId,proto3" json:"itemId,omitempty"`
@@ -76,7 +78,7 @@ func (m *ItemRequest) Reset()         { *m = ItemRequest{} }
 func (m *ItemRequest) String() string { return proto.CompactTextString(m) }
 func (*ItemRequest) ProtoMessage()    {}
 func (*ItemRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_checkout_3ebee9caf288178b, []int{1}
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{1}
 }
 func (m *ItemRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ItemRequest.Unmarshal(m, b)
@@ -110,10 +112,76 @@ func (m *ItemRequest) GetItemId() string {
 	return ""
 }
 
+// Request message that sends the target basketId and either the itemId of an item sold by weight or measure and its
+// quantity in units of the item, a decimal such as "0.350" for 350 g of an item sold by kg, or an EAN-13 variable measure
+// barcode embedding the item and its quantity, such as "2012345003507"
+type WeightedItemRequest struct {
+	BasketId             string   `protobuf:"bytes,1,opt,name=basketId,proto3" json:"basketId,omitempty"`
+	ItemId               string   `protobuf:"bytes,2,opt,name=itemId,proto3" json:"itemId,omitempty"`
+	Quantity             string   `protobuf:"bytes,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Barcode              string   `protobuf:"bytes,4,opt,name=barcode,proto3" json:"barcode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WeightedItemRequest) Reset()         { *m = WeightedItemRequest{} }
+func (m *WeightedItemRequest) String() string { return proto.CompactTextString(m) }
+func (*WeightedItemRequest) ProtoMessage()    {}
+func (*WeightedItemRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{2}
+}
+func (m *WeightedItemRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WeightedItemRequest.Unmarshal(m, b)
+}
+func (m *WeightedItemRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WeightedItemRequest.Marshal(b, m, deterministic)
+}
+func (dst *WeightedItemRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WeightedItemRequest.Merge(dst, src)
+}
+func (m *WeightedItemRequest) XXX_Size() int {
+	return xxx_messageInfo_WeightedItemRequest.Size(m)
+}
+func (m *WeightedItemRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WeightedItemRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WeightedItemRequest proto.InternalMessageInfo
+
+func (m *WeightedItemRequest) GetBasketId() string {
+	if m != nil {
+		return m.BasketId
+	}
+	return ""
+}
+
+func (m *WeightedItemRequest) GetItemId() string {
+	if m != nil {
+		return m.ItemId
+	}
+	return ""
+}
+
+func (m *WeightedItemRequest) GetQuantity() string {
+	if m != nil {
+		return m.Quantity
+	}
+	return ""
+}
+
+func (m *WeightedItemRequest) GetBarcode() string {
+	if m != nil {
+		return m.Barcode
+	}
+	return ""
+}
+
 // Reply message when scanning a new item into the given basket
+// serverError has never been populated, errors are returned as gRPC status codes with error details
 type ItemReply struct {
 	Result               bool     `protobuf:"varint,1,opt,name=result,proto3" json:"result,omitempty"`
-	ServerError          string   `protobuf:"bytes,2,opt,name=serverError,proto3" json:"serverError,omitempty"`
+	ServerError          string   `protobuf:"bytes,2,opt,name=serverError,proto3" json:"serverError,omitempty"` // Deprecated: Do not use.
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -123,7 +191,7 @@ func (m *ItemReply) Reset()         { *m = ItemReply{} }
 func (m *ItemReply) String() string { return proto.CompactTextString(m) }
 func (*ItemReply) ProtoMessage()    {}
 func (*ItemReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_checkout_3ebee9caf288178b, []int{2}
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{3}
 }
 func (m *ItemReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ItemReply.Unmarshal(m, b)
@@ -150,6 +218,7 @@ func (m *ItemReply) GetResult() bool {
 	return false
 }
 
+// Deprecated: Do not use.
 func (m *ItemReply) GetServerError() string {
 	if m != nil {
 		return m.ServerError
@@ -169,7 +238,7 @@ func (m *TotalAmountRequest) Reset()         { *m = TotalAmountRequest{} }
 func (m *TotalAmountRequest) String() string { return proto.CompactTextString(m) }
 func (*TotalAmountRequest) ProtoMessage()    {}
 func (*TotalAmountRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_checkout_3ebee9caf288178b, []int{3}
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{4}
 }
 func (m *TotalAmountRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TotalAmountRequest.Unmarshal(m, b)
@@ -196,9 +265,58 @@ func (m *TotalAmountRequest) GetBasketId() string {
 	return ""
 }
 
+// An exact amount of money expressed in the minor units of its currency (ie: 750 EUR is 7.50 EUR)
+type Money struct {
+	Amount               int64    `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency             string   `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Money) Reset()         { *m = Money{} }
+func (m *Money) String() string { return proto.CompactTextString(m) }
+func (*Money) ProtoMessage()    {}
+func (*Money) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{5}
+}
+func (m *Money) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Money.Unmarshal(m, b)
+}
+func (m *Money) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Money.Marshal(b, m, deterministic)
+}
+func (dst *Money) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Money.Merge(dst, src)
+}
+func (m *Money) XXX_Size() int {
+	return xxx_messageInfo_Money.Size(m)
+}
+func (m *Money) XXX_DiscardUnknown() {
+	xxx_messageInfo_Money.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Money proto.InternalMessageInfo
+
+func (m *Money) GetAmount() int64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *Money) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
 // Reply message containing the total price of items contained within the provided basketId
+// totalAmount is kept for older clients and holds the same amount as total, in minor units
 type TotalAmountReply struct {
 	TotalAmount          int64    `protobuf:"varint,1,opt,name=totalAmount,proto3" json:"totalAmount,omitempty"`
+	Total                *Money   `protobuf:"bytes,2,opt,name=total,proto3" json:"total,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -208,7 +326,7 @@ func (m *TotalAmountReply) Reset()         { *m = TotalAmountReply{} }
 func (m *TotalAmountReply) String() string { return proto.CompactTextString(m) }
 func (*TotalAmountReply) ProtoMessage()    {}
 func (*TotalAmountReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_checkout_3ebee9caf288178b, []int{4}
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{6}
 }
 func (m *TotalAmountReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TotalAmountReply.Unmarshal(m, b)
@@ -235,6 +353,13 @@ func (m *TotalAmountReply) GetTotalAmount() int64 {
 	return 0
 }
 
+func (m *TotalAmountReply) GetTotal() *Money {
+	if m != nil {
+		return m.Total
+	}
+	return nil
+}
+
 // Request message that provides a basketId to remove it from the server
 type RemoveBasketRequest struct {
 	BasketId             string   `protobuf:"bytes,1,opt,name=basketId,proto3" json:"basketId,omitempty"`
@@ -247,7 +372,7 @@ func (m *RemoveBasketRequest) Reset()         { *m = RemoveBasketRequest{} }
 func (m *RemoveBasketRequest) String() string { return proto.CompactTextString(m) }
 func (*RemoveBasketRequest) ProtoMessage()    {}
 func (*RemoveBasketRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_checkout_3ebee9caf288178b, []int{5}
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{7}
 }
 func (m *RemoveBasketRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RemoveBasketRequest.Unmarshal(m, b)
@@ -275,9 +400,10 @@ func (m *RemoveBasketRequest) GetBasketId() string {
 }
 
 // Reply message that provides the result of the delete request
+// serverError has never been populated, errors are returned as gRPC status codes with error details
 type RemoveBasketReply struct {
 	Result               bool     `protobuf:"varint,1,opt,name=result,proto3" json:"result,omitempty"`
-	ServerError          string   `protobuf:"bytes,2,opt,name=serverError,proto3" json:"serverError,omitempty"`
+	ServerError          string   `protobuf:"bytes,2,opt,name=serverError,proto3" json:"serverError,omitempty"` // Deprecated: Do not use.
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -287,7 +413,7 @@ func (m *RemoveBasketReply) Reset()         { *m = RemoveBasketReply{} }
 func (m *RemoveBasketReply) String() string { return proto.CompactTextString(m) }
 func (*RemoveBasketReply) ProtoMessage()    {}
 func (*RemoveBasketReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_checkout_3ebee9caf288178b, []int{6}
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{8}
 }
 func (m *RemoveBasketReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RemoveBasketReply.Unmarshal(m, b)
@@ -314,6 +440,7 @@ func (m *RemoveBasketReply) GetResult() bool {
 	return false
 }
 
+// Deprecated: Do not use.
 func (m *RemoveBasketReply) GetServerError() string {
 	if m != nil {
 		return m.ServerError
@@ -321,168 +448,1987 @@ func (m *RemoveBasketReply) GetServerError() string {
 	return ""
 }
 
-func init() {
-	proto.RegisterType((*BasketReply)(nil), "checkout.BasketReply")
-	proto.RegisterType((*ItemRequest)(nil), "checkout.ItemRequest")
-	proto.RegisterType((*ItemReply)(nil), "checkout.ItemReply")
-	proto.RegisterType((*TotalAmountRequest)(nil), "checkout.TotalAmountRequest")
-	proto.RegisterType((*TotalAmountReply)(nil), "checkout.TotalAmountReply")
-	proto.RegisterType((*RemoveBasketRequest)(nil), "checkout.RemoveBasketRequest")
-	proto.RegisterType((*RemoveBasketReply)(nil), "checkout.RemoveBasketReply")
+// Request message that provides the basketId to obtain the price breakdown of
+type BasketBreakdownRequest struct {
+	BasketId             string   `protobuf:"bytes,1,opt,name=basketId,proto3" json:"basketId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *BasketBreakdownRequest) Reset()         { *m = BasketBreakdownRequest{} }
+func (m *BasketBreakdownRequest) String() string { return proto.CompactTextString(m) }
+func (*BasketBreakdownRequest) ProtoMessage()    {}
+func (*BasketBreakdownRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{9}
+}
+func (m *BasketBreakdownRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BasketBreakdownRequest.Unmarshal(m, b)
+}
+func (m *BasketBreakdownRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BasketBreakdownRequest.Marshal(b, m, deterministic)
+}
+func (dst *BasketBreakdownRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BasketBreakdownRequest.Merge(dst, src)
+}
+func (m *BasketBreakdownRequest) XXX_Size() int {
+	return xxx_messageInfo_BasketBreakdownRequest.Size(m)
+}
+func (m *BasketBreakdownRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BasketBreakdownRequest.DiscardUnknown(m)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+var xxx_messageInfo_BasketBreakdownRequest proto.InternalMessageInfo
 
-// CheckoutClient is the client API for Checkout service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type CheckoutClient interface {
-	// Creates a new Basket in the server, receives a BasketRequest message and produces a BasketReply
-	CreateBasket(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*BasketReply, error)
-	// Scans an Item and adds it to the Basket which is referenced in the ItemRequest message. Returns an ItemReply
-	ScanItem(ctx context.Context, in *ItemRequest, opts ...grpc.CallOption) (*ItemReply, error)
-	// Returns the total cost of a given basket, referenced in the TotalAmountRequest message and returns the amount in the TotalAmountReply
-	GetTotalAmount(ctx context.Context, in *TotalAmountRequest, opts ...grpc.CallOption) (*TotalAmountReply, error)
-	// Removes the basket referenced in the RemoveBasketRequest message. Returns whether it was successful or not.
-	RemoveBasket(ctx context.Context, in *RemoveBasketRequest, opts ...grpc.CallOption) (*RemoveBasketReply, error)
+func (m *BasketBreakdownRequest) GetBasketId() string {
+	if m != nil {
+		return m.BasketId
+	}
+	return ""
 }
 
-type checkoutClient struct {
-	cc *grpc.ClientConn
+// A line of the basket before applying any promotion
+// The lines of the items sold by weight or measure have the measure, a decimal such as "0.350", in their unit instead
+// of a quantity, the unit price is the price of one unit of measure
+type BreakdownLine struct {
+	ItemId               string   `protobuf:"bytes,1,opt,name=itemId,proto3" json:"itemId,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Quantity             int32    `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	UnitPrice            *Money   `protobuf:"bytes,4,opt,name=unitPrice,proto3" json:"unitPrice,omitempty"`
+	Gross                *Money   `protobuf:"bytes,5,opt,name=gross,proto3" json:"gross,omitempty"`
+	Measure              string   `protobuf:"bytes,6,opt,name=measure,proto3" json:"measure,omitempty"`
+	Unit                 string   `protobuf:"bytes,7,opt,name=unit,proto3" json:"unit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func NewCheckoutClient(cc *grpc.ClientConn) CheckoutClient {
-	return &checkoutClient{cc}
+func (m *BreakdownLine) Reset()         { *m = BreakdownLine{} }
+func (m *BreakdownLine) String() string { return proto.CompactTextString(m) }
+func (*BreakdownLine) ProtoMessage()    {}
+func (*BreakdownLine) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{10}
+}
+func (m *BreakdownLine) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BreakdownLine.Unmarshal(m, b)
+}
+func (m *BreakdownLine) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BreakdownLine.Marshal(b, m, deterministic)
+}
+func (dst *BreakdownLine) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BreakdownLine.Merge(dst, src)
+}
+func (m *BreakdownLine) XXX_Size() int {
+	return xxx_messageInfo_BreakdownLine.Size(m)
+}
+func (m *BreakdownLine) XXX_DiscardUnknown() {
+	xxx_messageInfo_BreakdownLine.DiscardUnknown(m)
 }
 
-func (c *checkoutClient) CreateBasket(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*BasketReply, error) {
-	out := new(BasketReply)
-	err := c.cc.Invoke(ctx, "/checkout.Checkout/CreateBasket", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_BreakdownLine proto.InternalMessageInfo
+
+func (m *BreakdownLine) GetItemId() string {
+	if m != nil {
+		return m.ItemId
 	}
-	return out, nil
+	return ""
 }
 
-func (c *checkoutClient) ScanItem(ctx context.Context, in *ItemRequest, opts ...grpc.CallOption) (*ItemReply, error) {
-	out := new(ItemReply)
-	err := c.cc.Invoke(ctx, "/checkout.Checkout/ScanItem", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *BreakdownLine) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return out, nil
+	return ""
 }
 
-func (c *checkoutClient) GetTotalAmount(ctx context.Context, in *TotalAmountRequest, opts ...grpc.CallOption) (*TotalAmountReply, error) {
-	out := new(TotalAmountReply)
-	err := c.cc.Invoke(ctx, "/checkout.Checkout/GetTotalAmount", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *BreakdownLine) GetQuantity() int32 {
+	if m != nil {
+		return m.Quantity
 	}
-	return out, nil
+	return 0
 }
 
-func (c *checkoutClient) RemoveBasket(ctx context.Context, in *RemoveBasketRequest, opts ...grpc.CallOption) (*RemoveBasketReply, error) {
-	out := new(RemoveBasketReply)
-	err := c.cc.Invoke(ctx, "/checkout.Checkout/RemoveBasket", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *BreakdownLine) GetUnitPrice() *Money {
+	if m != nil {
+		return m.UnitPrice
 	}
-	return out, nil
+	return nil
 }
 
-// CheckoutServer is the server API for Checkout service.
-type CheckoutServer interface {
-	// Creates a new Basket in the server, receives a BasketRequest message and produces a BasketReply
-	CreateBasket(context.Context, *empty.Empty) (*BasketReply, error)
-	// Scans an Item and adds it to the Basket which is referenced in the ItemRequest message. Returns an ItemReply
-	ScanItem(context.Context, *ItemRequest) (*ItemReply, error)
-	// Returns the total cost of a given basket, referenced in the TotalAmountRequest message and returns the amount in the TotalAmountReply
-	GetTotalAmount(context.Context, *TotalAmountRequest) (*TotalAmountReply, error)
-	// Removes the basket referenced in the RemoveBasketRequest message. Returns whether it was successful or not.
-	RemoveBasket(context.Context, *RemoveBasketRequest) (*RemoveBasketReply, error)
+func (m *BreakdownLine) GetGross() *Money {
+	if m != nil {
+		return m.Gross
+	}
+	return nil
 }
 
-func RegisterCheckoutServer(s *grpc.Server, srv CheckoutServer) {
-	s.RegisterService(&_Checkout_serviceDesc, srv)
+func (m *BreakdownLine) GetMeasure() string {
+	if m != nil {
+		return m.Measure
+	}
+	return ""
 }
 
-func _Checkout_CreateBasket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(CheckoutServer).CreateBasket(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/checkout.Checkout/CreateBasket",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CheckoutServer).CreateBasket(ctx, req.(*empty.Empty))
+func (m *BreakdownLine) GetUnit() string {
+	if m != nil {
+		return m.Unit
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _Checkout_ScanItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ItemRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(CheckoutServer).ScanItem(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/checkout.Checkout/ScanItem",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CheckoutServer).ScanItem(ctx, req.(*ItemRequest))
-	}
-	return interceptor(ctx, in, info, handler)
+// A promotion applied to the basket, the item and number of units it affected, the discount it produced and the units
+// of other items consumed to get it. The affected item is empty for the basket level promotions
+// stacked promotions discounted the amount already charged by other promotions instead of the item price
+// measure is the quantity discounted of an item sold by weight or measure, which has no units
+// discounted are the units selected for the discount by the promotions discounting several items, ie: the cheapest item
+// of a mix and match, whose affected item is empty
+type AppliedRule struct {
+	RuleName             string       `protobuf:"bytes,1,opt,name=ruleName,proto3" json:"ruleName,omitempty"`
+	AffectedItem         string       `protobuf:"bytes,2,opt,name=affectedItem,proto3" json:"affectedItem,omitempty"`
+	UnitsAffected        int32        `protobuf:"varint,3,opt,name=unitsAffected,proto3" json:"unitsAffected,omitempty"`
+	Discount             *Money       `protobuf:"bytes,4,opt,name=discount,proto3" json:"discount,omitempty"`
+	Consumed             []*ItemUnits `protobuf:"bytes,5,rep,name=consumed,proto3" json:"consumed,omitempty"`
+	Stacked              bool         `protobuf:"varint,6,opt,name=stacked,proto3" json:"stacked,omitempty"`
+	Measure              string       `protobuf:"bytes,7,opt,name=measure,proto3" json:"measure,omitempty"`
+	Discounted           []*ItemUnits `protobuf:"bytes,8,rep,name=discounted,proto3" json:"discounted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
 
-func _Checkout_GetTotalAmount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TotalAmountRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *AppliedRule) Reset()         { *m = AppliedRule{} }
+func (m *AppliedRule) String() string { return proto.CompactTextString(m) }
+func (*AppliedRule) ProtoMessage()    {}
+func (*AppliedRule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{11}
+}
+func (m *AppliedRule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppliedRule.Unmarshal(m, b)
+}
+func (m *AppliedRule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppliedRule.Marshal(b, m, deterministic)
+}
+func (dst *AppliedRule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppliedRule.Merge(dst, src)
+}
+func (m *AppliedRule) XXX_Size() int {
+	return xxx_messageInfo_AppliedRule.Size(m)
+}
+func (m *AppliedRule) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppliedRule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppliedRule proto.InternalMessageInfo
+
+func (m *AppliedRule) GetRuleName() string {
+	if m != nil {
+		return m.RuleName
 	}
-	if interceptor == nil {
-		return srv.(CheckoutServer).GetTotalAmount(ctx, in)
+	return ""
+}
+
+func (m *AppliedRule) GetAffectedItem() string {
+	if m != nil {
+		return m.AffectedItem
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/checkout.Checkout/GetTotalAmount",
+	return ""
+}
+
+func (m *AppliedRule) GetUnitsAffected() int32 {
+	if m != nil {
+		return m.UnitsAffected
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CheckoutServer).GetTotalAmount(ctx, req.(*TotalAmountRequest))
+	return 0
+}
+
+func (m *AppliedRule) GetDiscount() *Money {
+	if m != nil {
+		return m.Discount
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _Checkout_RemoveBasket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RemoveBasketRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *AppliedRule) GetConsumed() []*ItemUnits {
+	if m != nil {
+		return m.Consumed
 	}
-	if interceptor == nil {
-		return srv.(CheckoutServer).RemoveBasket(ctx, in)
+	return nil
+}
+
+func (m *AppliedRule) GetStacked() bool {
+	if m != nil {
+		return m.Stacked
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/checkout.Checkout/RemoveBasket",
+	return false
+}
+
+func (m *AppliedRule) GetMeasure() string {
+	if m != nil {
+		return m.Measure
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CheckoutServer).RemoveBasket(ctx, req.(*RemoveBasketRequest))
+	return ""
+}
+
+func (m *AppliedRule) GetDiscounted() []*ItemUnits {
+	if m != nil {
+		return m.Discounted
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
+}
+
+// A promotion that would have given a discount but was not applied and the reason why, ie: another promotion of its
+// exclusive group was applied first
+type SkippedRule struct {
+	RuleName             string   `protobuf:"bytes,1,opt,name=ruleName,proto3" json:"ruleName,omitempty"`
+	Reason               string   `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SkippedRule) Reset()         { *m = SkippedRule{} }
+func (m *SkippedRule) String() string { return proto.CompactTextString(m) }
+func (*SkippedRule) ProtoMessage()    {}
+func (*SkippedRule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{12}
+}
+func (m *SkippedRule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SkippedRule.Unmarshal(m, b)
+}
+func (m *SkippedRule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SkippedRule.Marshal(b, m, deterministic)
+}
+func (dst *SkippedRule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SkippedRule.Merge(dst, src)
+}
+func (m *SkippedRule) XXX_Size() int {
+	return xxx_messageInfo_SkippedRule.Size(m)
+}
+func (m *SkippedRule) XXX_DiscardUnknown() {
+	xxx_messageInfo_SkippedRule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SkippedRule proto.InternalMessageInfo
+
+func (m *SkippedRule) GetRuleName() string {
+	if m != nil {
+		return m.RuleName
+	}
+	return ""
+}
+
+func (m *SkippedRule) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+// A number of units of an item, ie: the trigger units consumed by a buy X get Y promotion
+type ItemUnits struct {
+	ItemId               string   `protobuf:"bytes,1,opt,name=itemId,proto3" json:"itemId,omitempty"`
+	Units                int32    `protobuf:"varint,2,opt,name=units,proto3" json:"units,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ItemUnits) Reset()         { *m = ItemUnits{} }
+func (m *ItemUnits) String() string { return proto.CompactTextString(m) }
+func (*ItemUnits) ProtoMessage()    {}
+func (*ItemUnits) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{13}
+}
+func (m *ItemUnits) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ItemUnits.Unmarshal(m, b)
+}
+func (m *ItemUnits) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ItemUnits.Marshal(b, m, deterministic)
+}
+func (dst *ItemUnits) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ItemUnits.Merge(dst, src)
+}
+func (m *ItemUnits) XXX_Size() int {
+	return xxx_messageInfo_ItemUnits.Size(m)
+}
+func (m *ItemUnits) XXX_DiscardUnknown() {
+	xxx_messageInfo_ItemUnits.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ItemUnits proto.InternalMessageInfo
+
+func (m *ItemUnits) GetItemId() string {
+	if m != nil {
+		return m.ItemId
+	}
+	return ""
+}
+
+func (m *ItemUnits) GetUnits() int32 {
+	if m != nil {
+		return m.Units
+	}
+	return 0
+}
+
+// Reply message with the lines of the basket, the applied promotions and the totals before and after applying them
+// The total is split into its net amount and its tax, which is also given by tax class
+type BasketBreakdownReply struct {
+	BasketId             string               `protobuf:"bytes,1,opt,name=basketId,proto3" json:"basketId,omitempty"`
+	Lines                []*BreakdownLine     `protobuf:"bytes,2,rep,name=lines,proto3" json:"lines,omitempty"`
+	AppliedRules         []*AppliedRule       `protobuf:"bytes,3,rep,name=appliedRules,proto3" json:"appliedRules,omitempty"`
+	Gross                *Money               `protobuf:"bytes,4,opt,name=gross,proto3" json:"gross,omitempty"`
+	Total                *Money               `protobuf:"bytes,5,opt,name=total,proto3" json:"total,omitempty"`
+	SkippedRules         []*SkippedRule       `protobuf:"bytes,6,rep,name=skippedRules,proto3" json:"skippedRules,omitempty"`
+	Assignment           *PromotionAssignment `protobuf:"bytes,7,opt,name=assignment,proto3" json:"assignment,omitempty"`
+	Net                  *Money               `protobuf:"bytes,8,opt,name=net,proto3" json:"net,omitempty"`
+	Tax                  *Money               `protobuf:"bytes,9,opt,name=tax,proto3" json:"tax,omitempty"`
+	Taxes                []*TaxLine           `protobuf:"bytes,10,rep,name=taxes,proto3" json:"taxes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *BasketBreakdownReply) Reset()         { *m = BasketBreakdownReply{} }
+func (m *BasketBreakdownReply) String() string { return proto.CompactTextString(m) }
+func (*BasketBreakdownReply) ProtoMessage()    {}
+func (*BasketBreakdownReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{14}
+}
+func (m *BasketBreakdownReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BasketBreakdownReply.Unmarshal(m, b)
+}
+func (m *BasketBreakdownReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BasketBreakdownReply.Marshal(b, m, deterministic)
+}
+func (dst *BasketBreakdownReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BasketBreakdownReply.Merge(dst, src)
+}
+func (m *BasketBreakdownReply) XXX_Size() int {
+	return xxx_messageInfo_BasketBreakdownReply.Size(m)
+}
+func (m *BasketBreakdownReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_BasketBreakdownReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BasketBreakdownReply proto.InternalMessageInfo
+
+func (m *BasketBreakdownReply) GetBasketId() string {
+	if m != nil {
+		return m.BasketId
+	}
+	return ""
+}
+
+func (m *BasketBreakdownReply) GetLines() []*BreakdownLine {
+	if m != nil {
+		return m.Lines
+	}
+	return nil
+}
+
+func (m *BasketBreakdownReply) GetAppliedRules() []*AppliedRule {
+	if m != nil {
+		return m.AppliedRules
+	}
+	return nil
+}
+
+func (m *BasketBreakdownReply) GetGross() *Money {
+	if m != nil {
+		return m.Gross
+	}
+	return nil
+}
+
+func (m *BasketBreakdownReply) GetTotal() *Money {
+	if m != nil {
+		return m.Total
+	}
+	return nil
+}
+
+func (m *BasketBreakdownReply) GetSkippedRules() []*SkippedRule {
+	if m != nil {
+		return m.SkippedRules
+	}
+	return nil
+}
+
+func (m *BasketBreakdownReply) GetAssignment() *PromotionAssignment {
+	if m != nil {
+		return m.Assignment
+	}
+	return nil
+}
+
+func (m *BasketBreakdownReply) GetNet() *Money {
+	if m != nil {
+		return m.Net
+	}
+	return nil
+}
+
+func (m *BasketBreakdownReply) GetTax() *Money {
+	if m != nil {
+		return m.Tax
+	}
+	return nil
+}
+
+func (m *BasketBreakdownReply) GetTaxes() []*TaxLine {
+	if m != nil {
+		return m.Taxes
+	}
+	return nil
+}
+
+// The tax of the basket for a tax class, the rate is a percentage such as "21" or "5.5"
+type TaxLine struct {
+	TaxClass             string   `protobuf:"bytes,1,opt,name=taxClass,proto3" json:"taxClass,omitempty"`
+	Rate                 string   `protobuf:"bytes,2,opt,name=rate,proto3" json:"rate,omitempty"`
+	Net                  *Money   `protobuf:"bytes,3,opt,name=net,proto3" json:"net,omitempty"`
+	Tax                  *Money   `protobuf:"bytes,4,opt,name=tax,proto3" json:"tax,omitempty"`
+	Gross                *Money   `protobuf:"bytes,5,opt,name=gross,proto3" json:"gross,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TaxLine) Reset()         { *m = TaxLine{} }
+func (m *TaxLine) String() string { return proto.CompactTextString(m) }
+func (*TaxLine) ProtoMessage()    {}
+func (*TaxLine) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{15}
+}
+func (m *TaxLine) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TaxLine.Unmarshal(m, b)
+}
+func (m *TaxLine) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TaxLine.Marshal(b, m, deterministic)
+}
+func (dst *TaxLine) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaxLine.Merge(dst, src)
+}
+func (m *TaxLine) XXX_Size() int {
+	return xxx_messageInfo_TaxLine.Size(m)
+}
+func (m *TaxLine) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaxLine.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TaxLine proto.InternalMessageInfo
+
+func (m *TaxLine) GetTaxClass() string {
+	if m != nil {
+		return m.TaxClass
+	}
+	return ""
+}
+
+func (m *TaxLine) GetRate() string {
+	if m != nil {
+		return m.Rate
+	}
+	return ""
+}
+
+func (m *TaxLine) GetNet() *Money {
+	if m != nil {
+		return m.Net
+	}
+	return nil
+}
+
+func (m *TaxLine) GetTax() *Money {
+	if m != nil {
+		return m.Tax
+	}
+	return nil
+}
+
+func (m *TaxLine) GetGross() *Money {
+	if m != nil {
+		return m.Gross
+	}
+	return nil
+}
+
+// The order in which the competing promotions were executed when the server optimises the basket price, how many orders
+// were evaluated, whether all of them were and how much cheaper the basket is than with the configured order
+type PromotionAssignment struct {
+	Rules                []string `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	Evaluated            int32    `protobuf:"varint,2,opt,name=evaluated,proto3" json:"evaluated,omitempty"`
+	Exhaustive           bool     `protobuf:"varint,3,opt,name=exhaustive,proto3" json:"exhaustive,omitempty"`
+	TimedOut             bool     `protobuf:"varint,4,opt,name=timedOut,proto3" json:"timedOut,omitempty"`
+	Saving               *Money   `protobuf:"bytes,5,opt,name=saving,proto3" json:"saving,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PromotionAssignment) Reset()         { *m = PromotionAssignment{} }
+func (m *PromotionAssignment) String() string { return proto.CompactTextString(m) }
+func (*PromotionAssignment) ProtoMessage()    {}
+func (*PromotionAssignment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{16}
+}
+func (m *PromotionAssignment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PromotionAssignment.Unmarshal(m, b)
+}
+func (m *PromotionAssignment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PromotionAssignment.Marshal(b, m, deterministic)
+}
+func (dst *PromotionAssignment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PromotionAssignment.Merge(dst, src)
+}
+func (m *PromotionAssignment) XXX_Size() int {
+	return xxx_messageInfo_PromotionAssignment.Size(m)
+}
+func (m *PromotionAssignment) XXX_DiscardUnknown() {
+	xxx_messageInfo_PromotionAssignment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PromotionAssignment proto.InternalMessageInfo
+
+func (m *PromotionAssignment) GetRules() []string {
+	if m != nil {
+		return m.Rules
+	}
+	return nil
+}
+
+func (m *PromotionAssignment) GetEvaluated() int32 {
+	if m != nil {
+		return m.Evaluated
+	}
+	return 0
+}
+
+func (m *PromotionAssignment) GetExhaustive() bool {
+	if m != nil {
+		return m.Exhaustive
+	}
+	return false
+}
+
+func (m *PromotionAssignment) GetTimedOut() bool {
+	if m != nil {
+		return m.TimedOut
+	}
+	return false
+}
+
+func (m *PromotionAssignment) GetSaving() *Money {
+	if m != nil {
+		return m.Saving
+	}
+	return nil
+}
+
+// Request message that sets the quantity of an item (Pre defined in the server) in the target basketId
+type ItemQuantityRequest struct {
+	BasketId             string   `protobuf:"bytes,1,opt,name=basketId,proto3" json:"basketId,omitempty"`
+	ItemId               string   `protobuf:"bytes,2,opt,name=itemId,proto3" json:"itemId,omitempty"`
+	Quantity             int32    `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ItemQuantityRequest) Reset()         { *m = ItemQuantityRequest{} }
+func (m *ItemQuantityRequest) String() string { return proto.CompactTextString(m) }
+func (*ItemQuantityRequest) ProtoMessage()    {}
+func (*ItemQuantityRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{17}
+}
+func (m *ItemQuantityRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ItemQuantityRequest.Unmarshal(m, b)
+}
+func (m *ItemQuantityRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ItemQuantityRequest.Marshal(b, m, deterministic)
+}
+func (dst *ItemQuantityRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ItemQuantityRequest.Merge(dst, src)
+}
+func (m *ItemQuantityRequest) XXX_Size() int {
+	return xxx_messageInfo_ItemQuantityRequest.Size(m)
+}
+func (m *ItemQuantityRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ItemQuantityRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ItemQuantityRequest proto.InternalMessageInfo
+
+func (m *ItemQuantityRequest) GetBasketId() string {
+	if m != nil {
+		return m.BasketId
+	}
+	return ""
+}
+
+func (m *ItemQuantityRequest) GetItemId() string {
+	if m != nil {
+		return m.ItemId
+	}
+	return ""
+}
+
+func (m *ItemQuantityRequest) GetQuantity() int32 {
+	if m != nil {
+		return m.Quantity
+	}
+	return 0
+}
+
+// Request message that provides the basketId to remove every item from
+type ClearBasketRequest struct {
+	BasketId             string   `protobuf:"bytes,1,opt,name=basketId,proto3" json:"basketId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClearBasketRequest) Reset()         { *m = ClearBasketRequest{} }
+func (m *ClearBasketRequest) String() string { return proto.CompactTextString(m) }
+func (*ClearBasketRequest) ProtoMessage()    {}
+func (*ClearBasketRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{18}
+}
+func (m *ClearBasketRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClearBasketRequest.Unmarshal(m, b)
+}
+func (m *ClearBasketRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClearBasketRequest.Marshal(b, m, deterministic)
+}
+func (dst *ClearBasketRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClearBasketRequest.Merge(dst, src)
+}
+func (m *ClearBasketRequest) XXX_Size() int {
+	return xxx_messageInfo_ClearBasketRequest.Size(m)
+}
+func (m *ClearBasketRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClearBasketRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClearBasketRequest proto.InternalMessageInfo
+
+func (m *ClearBasketRequest) GetBasketId() string {
+	if m != nil {
+		return m.BasketId
+	}
+	return ""
+}
+
+// Reply message that provides the result of the clear request
+type ClearBasketReply struct {
+	Result               bool     `protobuf:"varint,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClearBasketReply) Reset()         { *m = ClearBasketReply{} }
+func (m *ClearBasketReply) String() string { return proto.CompactTextString(m) }
+func (*ClearBasketReply) ProtoMessage()    {}
+func (*ClearBasketReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{19}
+}
+func (m *ClearBasketReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClearBasketReply.Unmarshal(m, b)
+}
+func (m *ClearBasketReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClearBasketReply.Marshal(b, m, deterministic)
+}
+func (dst *ClearBasketReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClearBasketReply.Merge(dst, src)
+}
+func (m *ClearBasketReply) XXX_Size() int {
+	return xxx_messageInfo_ClearBasketReply.Size(m)
+}
+func (m *ClearBasketReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClearBasketReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClearBasketReply proto.InternalMessageInfo
+
+func (m *ClearBasketReply) GetResult() bool {
+	if m != nil {
+		return m.Result
+	}
+	return false
+}
+
+// Request message that provides the basketId to obtain the contents of
+type GetBasketRequest struct {
+	BasketId             string   `protobuf:"bytes,1,opt,name=basketId,proto3" json:"basketId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBasketRequest) Reset()         { *m = GetBasketRequest{} }
+func (m *GetBasketRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBasketRequest) ProtoMessage()    {}
+func (*GetBasketRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{20}
+}
+func (m *GetBasketRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBasketRequest.Unmarshal(m, b)
+}
+func (m *GetBasketRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBasketRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetBasketRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBasketRequest.Merge(dst, src)
+}
+func (m *GetBasketRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBasketRequest.Size(m)
+}
+func (m *GetBasketRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBasketRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBasketRequest proto.InternalMessageInfo
+
+func (m *GetBasketRequest) GetBasketId() string {
+	if m != nil {
+		return m.BasketId
+	}
+	return ""
+}
+
+// An item scanned into a basket, its configured name and the number of units of it, or the measure weighed or measured
+// in its unit for the items sold by weight or measure
+type BasketLine struct {
+	ItemId               string   `protobuf:"bytes,1,opt,name=itemId,proto3" json:"itemId,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Quantity             int32    `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Measure              string   `protobuf:"bytes,4,opt,name=measure,proto3" json:"measure,omitempty"`
+	Unit                 string   `protobuf:"bytes,5,opt,name=unit,proto3" json:"unit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BasketLine) Reset()         { *m = BasketLine{} }
+func (m *BasketLine) String() string { return proto.CompactTextString(m) }
+func (*BasketLine) ProtoMessage()    {}
+func (*BasketLine) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{21}
+}
+func (m *BasketLine) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BasketLine.Unmarshal(m, b)
+}
+func (m *BasketLine) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BasketLine.Marshal(b, m, deterministic)
+}
+func (dst *BasketLine) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BasketLine.Merge(dst, src)
+}
+func (m *BasketLine) XXX_Size() int {
+	return xxx_messageInfo_BasketLine.Size(m)
+}
+func (m *BasketLine) XXX_DiscardUnknown() {
+	xxx_messageInfo_BasketLine.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BasketLine proto.InternalMessageInfo
+
+func (m *BasketLine) GetItemId() string {
+	if m != nil {
+		return m.ItemId
+	}
+	return ""
+}
+
+func (m *BasketLine) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *BasketLine) GetQuantity() int32 {
+	if m != nil {
+		return m.Quantity
+	}
+	return 0
+}
+
+func (m *BasketLine) GetMeasure() string {
+	if m != nil {
+		return m.Measure
+	}
+	return ""
+}
+
+func (m *BasketLine) GetUnit() string {
+	if m != nil {
+		return m.Unit
+	}
+	return ""
+}
+
+// Reply message with the contents of a basket
+type GetBasketReply struct {
+	BasketId             string               `protobuf:"bytes,1,opt,name=basketId,proto3" json:"basketId,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,2,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	Lines                []*BasketLine        `protobuf:"bytes,3,rep,name=lines,proto3" json:"lines,omitempty"`
+	Coupons              []string             `protobuf:"bytes,4,rep,name=coupons,proto3" json:"coupons,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *GetBasketReply) Reset()         { *m = GetBasketReply{} }
+func (m *GetBasketReply) String() string { return proto.CompactTextString(m) }
+func (*GetBasketReply) ProtoMessage()    {}
+func (*GetBasketReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{22}
+}
+func (m *GetBasketReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBasketReply.Unmarshal(m, b)
+}
+func (m *GetBasketReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBasketReply.Marshal(b, m, deterministic)
+}
+func (dst *GetBasketReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBasketReply.Merge(dst, src)
+}
+func (m *GetBasketReply) XXX_Size() int {
+	return xxx_messageInfo_GetBasketReply.Size(m)
+}
+func (m *GetBasketReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBasketReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBasketReply proto.InternalMessageInfo
+
+func (m *GetBasketReply) GetBasketId() string {
+	if m != nil {
+		return m.BasketId
+	}
+	return ""
+}
+
+func (m *GetBasketReply) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *GetBasketReply) GetLines() []*BasketLine {
+	if m != nil {
+		return m.Lines
+	}
+	return nil
+}
+
+func (m *GetBasketReply) GetCoupons() []string {
+	if m != nil {
+		return m.Coupons
+	}
+	return nil
+}
+
+// Request message that sends the target basketId and the code of a coupon (Pre defined in the server)
+type CouponRequest struct {
+	BasketId             string   `protobuf:"bytes,1,opt,name=basketId,proto3" json:"basketId,omitempty"`
+	Code                 string   `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CouponRequest) Reset()         { *m = CouponRequest{} }
+func (m *CouponRequest) String() string { return proto.CompactTextString(m) }
+func (*CouponRequest) ProtoMessage()    {}
+func (*CouponRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{23}
+}
+func (m *CouponRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CouponRequest.Unmarshal(m, b)
+}
+func (m *CouponRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CouponRequest.Marshal(b, m, deterministic)
+}
+func (dst *CouponRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CouponRequest.Merge(dst, src)
+}
+func (m *CouponRequest) XXX_Size() int {
+	return xxx_messageInfo_CouponRequest.Size(m)
+}
+func (m *CouponRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CouponRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CouponRequest proto.InternalMessageInfo
+
+func (m *CouponRequest) GetBasketId() string {
+	if m != nil {
+		return m.BasketId
+	}
+	return ""
+}
+
+func (m *CouponRequest) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+// Reply message when applying or removing a coupon
+type CouponReply struct {
+	Result               bool     `protobuf:"varint,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CouponReply) Reset()         { *m = CouponReply{} }
+func (m *CouponReply) String() string { return proto.CompactTextString(m) }
+func (*CouponReply) ProtoMessage()    {}
+func (*CouponReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{24}
+}
+func (m *CouponReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CouponReply.Unmarshal(m, b)
+}
+func (m *CouponReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CouponReply.Marshal(b, m, deterministic)
+}
+func (dst *CouponReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CouponReply.Merge(dst, src)
+}
+func (m *CouponReply) XXX_Size() int {
+	return xxx_messageInfo_CouponReply.Size(m)
+}
+func (m *CouponReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CouponReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CouponReply proto.InternalMessageInfo
+
+func (m *CouponReply) GetResult() bool {
+	if m != nil {
+		return m.Result
+	}
+	return false
+}
+
+// Request message that sets the units in stock of an item (Pre defined in the server)
+type StockLevelRequest struct {
+	ItemId               string   `protobuf:"bytes,1,opt,name=itemId,proto3" json:"itemId,omitempty"`
+	OnHand               int32    `protobuf:"varint,2,opt,name=onHand,proto3" json:"onHand,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StockLevelRequest) Reset()         { *m = StockLevelRequest{} }
+func (m *StockLevelRequest) String() string { return proto.CompactTextString(m) }
+func (*StockLevelRequest) ProtoMessage()    {}
+func (*StockLevelRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{25}
+}
+func (m *StockLevelRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StockLevelRequest.Unmarshal(m, b)
+}
+func (m *StockLevelRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StockLevelRequest.Marshal(b, m, deterministic)
+}
+func (dst *StockLevelRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StockLevelRequest.Merge(dst, src)
+}
+func (m *StockLevelRequest) XXX_Size() int {
+	return xxx_messageInfo_StockLevelRequest.Size(m)
+}
+func (m *StockLevelRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StockLevelRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StockLevelRequest proto.InternalMessageInfo
+
+func (m *StockLevelRequest) GetItemId() string {
+	if m != nil {
+		return m.ItemId
+	}
+	return ""
+}
+
+func (m *StockLevelRequest) GetOnHand() int32 {
+	if m != nil {
+		return m.OnHand
+	}
+	return 0
+}
+
+// Request message that provides the itemId to obtain the stock of
+type GetStockLevelRequest struct {
+	ItemId               string   `protobuf:"bytes,1,opt,name=itemId,proto3" json:"itemId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStockLevelRequest) Reset()         { *m = GetStockLevelRequest{} }
+func (m *GetStockLevelRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStockLevelRequest) ProtoMessage()    {}
+func (*GetStockLevelRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{26}
+}
+func (m *GetStockLevelRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStockLevelRequest.Unmarshal(m, b)
+}
+func (m *GetStockLevelRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStockLevelRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetStockLevelRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStockLevelRequest.Merge(dst, src)
+}
+func (m *GetStockLevelRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStockLevelRequest.Size(m)
+}
+func (m *GetStockLevelRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStockLevelRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStockLevelRequest proto.InternalMessageInfo
+
+func (m *GetStockLevelRequest) GetItemId() string {
+	if m != nil {
+		return m.ItemId
+	}
+	return ""
+}
+
+// Reply message with the stock of an item: the units on hand, the ones reserved by the baskets and the ones still
+// available to be scanned. An item that is not tracked has no stock level and its units are not limited
+type StockLevelReply struct {
+	ItemId               string   `protobuf:"bytes,1,opt,name=itemId,proto3" json:"itemId,omitempty"`
+	Tracked              bool     `protobuf:"varint,2,opt,name=tracked,proto3" json:"tracked,omitempty"`
+	OnHand               int32    `protobuf:"varint,3,opt,name=onHand,proto3" json:"onHand,omitempty"`
+	Reserved             int32    `protobuf:"varint,4,opt,name=reserved,proto3" json:"reserved,omitempty"`
+	Available            int32    `protobuf:"varint,5,opt,name=available,proto3" json:"available,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StockLevelReply) Reset()         { *m = StockLevelReply{} }
+func (m *StockLevelReply) String() string { return proto.CompactTextString(m) }
+func (*StockLevelReply) ProtoMessage()    {}
+func (*StockLevelReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{27}
+}
+func (m *StockLevelReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StockLevelReply.Unmarshal(m, b)
+}
+func (m *StockLevelReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StockLevelReply.Marshal(b, m, deterministic)
+}
+func (dst *StockLevelReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StockLevelReply.Merge(dst, src)
+}
+func (m *StockLevelReply) XXX_Size() int {
+	return xxx_messageInfo_StockLevelReply.Size(m)
+}
+func (m *StockLevelReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_StockLevelReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StockLevelReply proto.InternalMessageInfo
+
+func (m *StockLevelReply) GetItemId() string {
+	if m != nil {
+		return m.ItemId
+	}
+	return ""
+}
+
+func (m *StockLevelReply) GetTracked() bool {
+	if m != nil {
+		return m.Tracked
+	}
+	return false
+}
+
+func (m *StockLevelReply) GetOnHand() int32 {
+	if m != nil {
+		return m.OnHand
+	}
+	return 0
+}
+
+func (m *StockLevelReply) GetReserved() int32 {
+	if m != nil {
+		return m.Reserved
+	}
+	return 0
+}
+
+func (m *StockLevelReply) GetAvailable() int32 {
+	if m != nil {
+		return m.Available
+	}
+	return 0
+}
+
+// Request message that provides the basketId to check out
+type CheckoutRequest struct {
+	BasketId             string   `protobuf:"bytes,1,opt,name=basketId,proto3" json:"basketId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckoutRequest) Reset()         { *m = CheckoutRequest{} }
+func (m *CheckoutRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckoutRequest) ProtoMessage()    {}
+func (*CheckoutRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{28}
+}
+func (m *CheckoutRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckoutRequest.Unmarshal(m, b)
+}
+func (m *CheckoutRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckoutRequest.Marshal(b, m, deterministic)
+}
+func (dst *CheckoutRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckoutRequest.Merge(dst, src)
+}
+func (m *CheckoutRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckoutRequest.Size(m)
+}
+func (m *CheckoutRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckoutRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckoutRequest proto.InternalMessageInfo
+
+func (m *CheckoutRequest) GetBasketId() string {
+	if m != nil {
+		return m.BasketId
+	}
+	return ""
+}
+
+// Request message that provides the number of the order to obtain
+type GetOrderRequest struct {
+	OrderNumber          string   `protobuf:"bytes,1,opt,name=orderNumber,proto3" json:"orderNumber,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetOrderRequest) Reset()         { *m = GetOrderRequest{} }
+func (m *GetOrderRequest) String() string { return proto.CompactTextString(m) }
+func (*GetOrderRequest) ProtoMessage()    {}
+func (*GetOrderRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{29}
+}
+func (m *GetOrderRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetOrderRequest.Unmarshal(m, b)
+}
+func (m *GetOrderRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetOrderRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetOrderRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetOrderRequest.Merge(dst, src)
+}
+func (m *GetOrderRequest) XXX_Size() int {
+	return xxx_messageInfo_GetOrderRequest.Size(m)
+}
+func (m *GetOrderRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetOrderRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetOrderRequest proto.InternalMessageInfo
+
+func (m *GetOrderRequest) GetOrderNumber() string {
+	if m != nil {
+		return m.OrderNumber
+	}
+	return ""
+}
+
+// Request message with the range of checkout times to list the orders of, from included and to excluded, an unset time
+// leaves that end of the range open
+type ListOrdersRequest struct {
+	From                 *timestamp.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To                   *timestamp.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ListOrdersRequest) Reset()         { *m = ListOrdersRequest{} }
+func (m *ListOrdersRequest) String() string { return proto.CompactTextString(m) }
+func (*ListOrdersRequest) ProtoMessage()    {}
+func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{30}
+}
+func (m *ListOrdersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOrdersRequest.Unmarshal(m, b)
+}
+func (m *ListOrdersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOrdersRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListOrdersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOrdersRequest.Merge(dst, src)
+}
+func (m *ListOrdersRequest) XXX_Size() int {
+	return xxx_messageInfo_ListOrdersRequest.Size(m)
+}
+func (m *ListOrdersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOrdersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOrdersRequest proto.InternalMessageInfo
+
+func (m *ListOrdersRequest) GetFrom() *timestamp.Timestamp {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *ListOrdersRequest) GetTo() *timestamp.Timestamp {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+// Reply message with an order: the basket it was checked out from, when the basket was created and checked out, the
+// version of the configuration it was priced with, its coupons and its breakdown as it was priced at checkout
+type OrderReply struct {
+	OrderNumber          string                `protobuf:"bytes,1,opt,name=orderNumber,proto3" json:"orderNumber,omitempty"`
+	BasketId             string                `protobuf:"bytes,2,opt,name=basketId,proto3" json:"basketId,omitempty"`
+	OpenedAt             *timestamp.Timestamp  `protobuf:"bytes,3,opt,name=openedAt,proto3" json:"openedAt,omitempty"`
+	CheckedOutAt         *timestamp.Timestamp  `protobuf:"bytes,4,opt,name=checkedOutAt,proto3" json:"checkedOutAt,omitempty"`
+	ConfigVersion        string                `protobuf:"bytes,5,opt,name=configVersion,proto3" json:"configVersion,omitempty"`
+	Coupons              []string              `protobuf:"bytes,6,rep,name=coupons,proto3" json:"coupons,omitempty"`
+	Breakdown            *BasketBreakdownReply `protobuf:"bytes,7,opt,name=breakdown,proto3" json:"breakdown,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *OrderReply) Reset()         { *m = OrderReply{} }
+func (m *OrderReply) String() string { return proto.CompactTextString(m) }
+func (*OrderReply) ProtoMessage()    {}
+func (*OrderReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{31}
+}
+func (m *OrderReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OrderReply.Unmarshal(m, b)
+}
+func (m *OrderReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OrderReply.Marshal(b, m, deterministic)
+}
+func (dst *OrderReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OrderReply.Merge(dst, src)
+}
+func (m *OrderReply) XXX_Size() int {
+	return xxx_messageInfo_OrderReply.Size(m)
+}
+func (m *OrderReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_OrderReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OrderReply proto.InternalMessageInfo
+
+func (m *OrderReply) GetOrderNumber() string {
+	if m != nil {
+		return m.OrderNumber
+	}
+	return ""
+}
+
+func (m *OrderReply) GetBasketId() string {
+	if m != nil {
+		return m.BasketId
+	}
+	return ""
+}
+
+func (m *OrderReply) GetOpenedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.OpenedAt
+	}
+	return nil
+}
+
+func (m *OrderReply) GetCheckedOutAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CheckedOutAt
+	}
+	return nil
+}
+
+func (m *OrderReply) GetConfigVersion() string {
+	if m != nil {
+		return m.ConfigVersion
+	}
+	return ""
+}
+
+func (m *OrderReply) GetCoupons() []string {
+	if m != nil {
+		return m.Coupons
+	}
+	return nil
+}
+
+func (m *OrderReply) GetBreakdown() *BasketBreakdownReply {
+	if m != nil {
+		return m.Breakdown
+	}
+	return nil
+}
+
+// Reply message with the listed orders
+type ListOrdersReply struct {
+	Orders               []*OrderReply `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListOrdersReply) Reset()         { *m = ListOrdersReply{} }
+func (m *ListOrdersReply) String() string { return proto.CompactTextString(m) }
+func (*ListOrdersReply) ProtoMessage()    {}
+func (*ListOrdersReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkout_17a4d38b269a5928, []int{32}
+}
+func (m *ListOrdersReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOrdersReply.Unmarshal(m, b)
+}
+func (m *ListOrdersReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOrdersReply.Marshal(b, m, deterministic)
+}
+func (dst *ListOrdersReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOrdersReply.Merge(dst, src)
+}
+func (m *ListOrdersReply) XXX_Size() int {
+	return xxx_messageInfo_ListOrdersReply.Size(m)
+}
+func (m *ListOrdersReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOrdersReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOrdersReply proto.InternalMessageInfo
+
+func (m *ListOrdersReply) GetOrders() []*OrderReply {
+	if m != nil {
+		return m.Orders
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*BasketReply)(nil), "checkout.BasketReply")
+	proto.RegisterType((*ItemRequest)(nil), "checkout.ItemRequest")
+	proto.RegisterType((*WeightedItemRequest)(nil), "checkout.WeightedItemRequest")
+	proto.RegisterType((*ItemReply)(nil), "checkout.ItemReply")
+	proto.RegisterType((*TotalAmountRequest)(nil), "checkout.TotalAmountRequest")
+	proto.RegisterType((*Money)(nil), "checkout.Money")
+	proto.RegisterType((*TotalAmountReply)(nil), "checkout.TotalAmountReply")
+	proto.RegisterType((*RemoveBasketRequest)(nil), "checkout.RemoveBasketRequest")
+	proto.RegisterType((*RemoveBasketReply)(nil), "checkout.RemoveBasketReply")
+	proto.RegisterType((*BasketBreakdownRequest)(nil), "checkout.BasketBreakdownRequest")
+	proto.RegisterType((*BreakdownLine)(nil), "checkout.BreakdownLine")
+	proto.RegisterType((*AppliedRule)(nil), "checkout.AppliedRule")
+	proto.RegisterType((*SkippedRule)(nil), "checkout.SkippedRule")
+	proto.RegisterType((*ItemUnits)(nil), "checkout.ItemUnits")
+	proto.RegisterType((*BasketBreakdownReply)(nil), "checkout.BasketBreakdownReply")
+	proto.RegisterType((*TaxLine)(nil), "checkout.TaxLine")
+	proto.RegisterType((*PromotionAssignment)(nil), "checkout.PromotionAssignment")
+	proto.RegisterType((*ItemQuantityRequest)(nil), "checkout.ItemQuantityRequest")
+	proto.RegisterType((*ClearBasketRequest)(nil), "checkout.ClearBasketRequest")
+	proto.RegisterType((*ClearBasketReply)(nil), "checkout.ClearBasketReply")
+	proto.RegisterType((*GetBasketRequest)(nil), "checkout.GetBasketRequest")
+	proto.RegisterType((*BasketLine)(nil), "checkout.BasketLine")
+	proto.RegisterType((*GetBasketReply)(nil), "checkout.GetBasketReply")
+	proto.RegisterType((*CouponRequest)(nil), "checkout.CouponRequest")
+	proto.RegisterType((*CouponReply)(nil), "checkout.CouponReply")
+	proto.RegisterType((*StockLevelRequest)(nil), "checkout.StockLevelRequest")
+	proto.RegisterType((*GetStockLevelRequest)(nil), "checkout.GetStockLevelRequest")
+	proto.RegisterType((*StockLevelReply)(nil), "checkout.StockLevelReply")
+	proto.RegisterType((*CheckoutRequest)(nil), "checkout.CheckoutRequest")
+	proto.RegisterType((*GetOrderRequest)(nil), "checkout.GetOrderRequest")
+	proto.RegisterType((*ListOrdersRequest)(nil), "checkout.ListOrdersRequest")
+	proto.RegisterType((*OrderReply)(nil), "checkout.OrderReply")
+	proto.RegisterType((*ListOrdersReply)(nil), "checkout.ListOrdersReply")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// CheckoutClient is the client API for Checkout service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CheckoutClient interface {
+	// Creates a new Basket in the server, receives a BasketRequest message and produces a BasketReply
+	CreateBasket(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*BasketReply, error)
+	// Scans an Item and adds it to the Basket which is referenced in the ItemRequest message. Returns an ItemReply
+	ScanItem(ctx context.Context, in *ItemRequest, opts ...grpc.CallOption) (*ItemReply, error)
+	// Scans a weighed or measured quantity of an Item sold by weight or measure into the Basket referenced in the
+	// WeightedItemRequest message, either given the item and the quantity or the variable measure barcode printed by the
+	// scale. Returns an ItemReply
+	ScanWeightedItem(ctx context.Context, in *WeightedItemRequest, opts ...grpc.CallOption) (*ItemReply, error)
+	// Returns the total cost of a given basket, referenced in the TotalAmountRequest message and returns the amount in the TotalAmountReply
+	GetTotalAmount(ctx context.Context, in *TotalAmountRequest, opts ...grpc.CallOption) (*TotalAmountReply, error)
+	// Removes the basket referenced in the RemoveBasketRequest message. Returns whether it was successful or not.
+	RemoveBasket(ctx context.Context, in *RemoveBasketRequest, opts ...grpc.CallOption) (*RemoveBasketReply, error)
+	// Returns every line of the basket referenced in the BasketBreakdownRequest, the promotions applied to it and the final total
+	GetBasketBreakdown(ctx context.Context, in *BasketBreakdownRequest, opts ...grpc.CallOption) (*BasketBreakdownReply, error)
+	// Removes a unit of the Item referenced in the ItemRequest message from its Basket, ie: to undo a mis-scan. Returns an ItemReply
+	RemoveItem(ctx context.Context, in *ItemRequest, opts ...grpc.CallOption) (*ItemReply, error)
+	// Sets the number of units of an Item in a Basket, a quantity of 0 removes the Item from the Basket. Returns an ItemReply
+	SetItemQuantity(ctx context.Context, in *ItemQuantityRequest, opts ...grpc.CallOption) (*ItemReply, error)
+	// Removes every Item from the basket referenced in the ClearBasketRequest, the basket can still be used afterwards
+	ClearBasket(ctx context.Context, in *ClearBasketRequest, opts ...grpc.CallOption) (*ClearBasketReply, error)
+	// Returns the contents of the basket referenced in the GetBasketRequest: when it was created and the scanned Items
+	GetBasket(ctx context.Context, in *GetBasketRequest, opts ...grpc.CallOption) (*GetBasketReply, error)
+	// Applies the coupon referenced in the CouponRequest to its Basket, activating the promotions linked to it for the Basket
+	ApplyCoupon(ctx context.Context, in *CouponRequest, opts ...grpc.CallOption) (*CouponReply, error)
+	// Removes the coupon referenced in the CouponRequest from its Basket
+	RemoveCoupon(ctx context.Context, in *CouponRequest, opts ...grpc.CallOption) (*CouponReply, error)
+	// Sets the units in stock of the Item referenced in the StockLevelRequest, the units reserved by the baskets are kept.
+	// Returns the resulting StockLevelReply
+	SetStockLevel(ctx context.Context, in *StockLevelRequest, opts ...grpc.CallOption) (*StockLevelReply, error)
+	// Returns the units in stock of the Item referenced in the GetStockLevelRequest and the ones reserved by the baskets
+	GetStockLevel(ctx context.Context, in *GetStockLevelRequest, opts ...grpc.CallOption) (*StockLevelReply, error)
+	// Checks out the basket referenced in the CheckoutRequest: it's priced once and stored as an order that can't be changed,
+	// the basket can't be changed anymore either. Returns the OrderReply with the number of the new order
+	Checkout(ctx context.Context, in *CheckoutRequest, opts ...grpc.CallOption) (*OrderReply, error)
+	// Returns the order referenced in the GetOrderRequest
+	GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*OrderReply, error)
+	// Returns the orders checked out within the range of the ListOrdersRequest, sorted by checkout time
+	ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersReply, error)
+}
+
+type checkoutClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCheckoutClient(cc *grpc.ClientConn) CheckoutClient {
+	return &checkoutClient{cc}
+}
+
+func (c *checkoutClient) CreateBasket(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*BasketReply, error) {
+	out := new(BasketReply)
+	err := c.cc.Invoke(ctx, "/checkout.Checkout/CreateBasket", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutClient) ScanItem(ctx context.Context, in *ItemRequest, opts ...grpc.CallOption) (*ItemReply, error) {
+	out := new(ItemReply)
+	err := c.cc.Invoke(ctx, "/checkout.Checkout/ScanItem", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutClient) ScanWeightedItem(ctx context.Context, in *WeightedItemRequest, opts ...grpc.CallOption) (*ItemReply, error) {
+	out := new(ItemReply)
+	err := c.cc.Invoke(ctx, "/checkout.Checkout/ScanWeightedItem", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutClient) GetTotalAmount(ctx context.Context, in *TotalAmountRequest, opts ...grpc.CallOption) (*TotalAmountReply, error) {
+	out := new(TotalAmountReply)
+	err := c.cc.Invoke(ctx, "/checkout.Checkout/GetTotalAmount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutClient) RemoveBasket(ctx context.Context, in *RemoveBasketRequest, opts ...grpc.CallOption) (*RemoveBasketReply, error) {
+	out := new(RemoveBasketReply)
+	err := c.cc.Invoke(ctx, "/checkout.Checkout/RemoveBasket", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutClient) GetBasketBreakdown(ctx context.Context, in *BasketBreakdownRequest, opts ...grpc.CallOption) (*BasketBreakdownReply, error) {
+	out := new(BasketBreakdownReply)
+	err := c.cc.Invoke(ctx, "/checkout.Checkout/GetBasketBreakdown", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutClient) RemoveItem(ctx context.Context, in *ItemRequest, opts ...grpc.CallOption) (*ItemReply, error) {
+	out := new(ItemReply)
+	err := c.cc.Invoke(ctx, "/checkout.Checkout/RemoveItem", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutClient) SetItemQuantity(ctx context.Context, in *ItemQuantityRequest, opts ...grpc.CallOption) (*ItemReply, error) {
+	out := new(ItemReply)
+	err := c.cc.Invoke(ctx, "/checkout.Checkout/SetItemQuantity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutClient) ClearBasket(ctx context.Context, in *ClearBasketRequest, opts ...grpc.CallOption) (*ClearBasketReply, error) {
+	out := new(ClearBasketReply)
+	err := c.cc.Invoke(ctx, "/checkout.Checkout/ClearBasket", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutClient) GetBasket(ctx context.Context, in *GetBasketRequest, opts ...grpc.CallOption) (*GetBasketReply, error) {
+	out := new(GetBasketReply)
+	err := c.cc.Invoke(ctx, "/checkout.Checkout/GetBasket", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutClient) ApplyCoupon(ctx context.Context, in *CouponRequest, opts ...grpc.CallOption) (*CouponReply, error) {
+	out := new(CouponReply)
+	err := c.cc.Invoke(ctx, "/checkout.Checkout/ApplyCoupon", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutClient) RemoveCoupon(ctx context.Context, in *CouponRequest, opts ...grpc.CallOption) (*CouponReply, error) {
+	out := new(CouponReply)
+	err := c.cc.Invoke(ctx, "/checkout.Checkout/RemoveCoupon", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutClient) SetStockLevel(ctx context.Context, in *StockLevelRequest, opts ...grpc.CallOption) (*StockLevelReply, error) {
+	out := new(StockLevelReply)
+	err := c.cc.Invoke(ctx, "/checkout.Checkout/SetStockLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutClient) GetStockLevel(ctx context.Context, in *GetStockLevelRequest, opts ...grpc.CallOption) (*StockLevelReply, error) {
+	out := new(StockLevelReply)
+	err := c.cc.Invoke(ctx, "/checkout.Checkout/GetStockLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutClient) Checkout(ctx context.Context, in *CheckoutRequest, opts ...grpc.CallOption) (*OrderReply, error) {
+	out := new(OrderReply)
+	err := c.cc.Invoke(ctx, "/checkout.Checkout/Checkout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutClient) GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*OrderReply, error) {
+	out := new(OrderReply)
+	err := c.cc.Invoke(ctx, "/checkout.Checkout/GetOrder", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutClient) ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersReply, error) {
+	out := new(ListOrdersReply)
+	err := c.cc.Invoke(ctx, "/checkout.Checkout/ListOrders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CheckoutServer is the server API for Checkout service.
+type CheckoutServer interface {
+	// Creates a new Basket in the server, receives a BasketRequest message and produces a BasketReply
+	CreateBasket(context.Context, *empty.Empty) (*BasketReply, error)
+	// Scans an Item and adds it to the Basket which is referenced in the ItemRequest message. Returns an ItemReply
+	ScanItem(context.Context, *ItemRequest) (*ItemReply, error)
+	// Scans a weighed or measured quantity of an Item sold by weight or measure into the Basket referenced in the
+	// WeightedItemRequest message, either given the item and the quantity or the variable measure barcode printed by the
+	// scale. Returns an ItemReply
+	ScanWeightedItem(context.Context, *WeightedItemRequest) (*ItemReply, error)
+	// Returns the total cost of a given basket, referenced in the TotalAmountRequest message and returns the amount in the TotalAmountReply
+	GetTotalAmount(context.Context, *TotalAmountRequest) (*TotalAmountReply, error)
+	// Removes the basket referenced in the RemoveBasketRequest message. Returns whether it was successful or not.
+	RemoveBasket(context.Context, *RemoveBasketRequest) (*RemoveBasketReply, error)
+	// Returns every line of the basket referenced in the BasketBreakdownRequest, the promotions applied to it and the final total
+	GetBasketBreakdown(context.Context, *BasketBreakdownRequest) (*BasketBreakdownReply, error)
+	// Removes a unit of the Item referenced in the ItemRequest message from its Basket, ie: to undo a mis-scan. Returns an ItemReply
+	RemoveItem(context.Context, *ItemRequest) (*ItemReply, error)
+	// Sets the number of units of an Item in a Basket, a quantity of 0 removes the Item from the Basket. Returns an ItemReply
+	SetItemQuantity(context.Context, *ItemQuantityRequest) (*ItemReply, error)
+	// Removes every Item from the basket referenced in the ClearBasketRequest, the basket can still be used afterwards
+	ClearBasket(context.Context, *ClearBasketRequest) (*ClearBasketReply, error)
+	// Returns the contents of the basket referenced in the GetBasketRequest: when it was created and the scanned Items
+	GetBasket(context.Context, *GetBasketRequest) (*GetBasketReply, error)
+	// Applies the coupon referenced in the CouponRequest to its Basket, activating the promotions linked to it for the Basket
+	ApplyCoupon(context.Context, *CouponRequest) (*CouponReply, error)
+	// Removes the coupon referenced in the CouponRequest from its Basket
+	RemoveCoupon(context.Context, *CouponRequest) (*CouponReply, error)
+	// Sets the units in stock of the Item referenced in the StockLevelRequest, the units reserved by the baskets are kept.
+	// Returns the resulting StockLevelReply
+	SetStockLevel(context.Context, *StockLevelRequest) (*StockLevelReply, error)
+	// Returns the units in stock of the Item referenced in the GetStockLevelRequest and the ones reserved by the baskets
+	GetStockLevel(context.Context, *GetStockLevelRequest) (*StockLevelReply, error)
+	// Checks out the basket referenced in the CheckoutRequest: it's priced once and stored as an order that can't be changed,
+	// the basket can't be changed anymore either. Returns the OrderReply with the number of the new order
+	Checkout(context.Context, *CheckoutRequest) (*OrderReply, error)
+	// Returns the order referenced in the GetOrderRequest
+	GetOrder(context.Context, *GetOrderRequest) (*OrderReply, error)
+	// Returns the orders checked out within the range of the ListOrdersRequest, sorted by checkout time
+	ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersReply, error)
+}
+
+func RegisterCheckoutServer(s *grpc.Server, srv CheckoutServer) {
+	s.RegisterService(&_Checkout_serviceDesc, srv)
+}
+
+func _Checkout_CreateBasket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServer).CreateBasket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/checkout.Checkout/CreateBasket",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServer).CreateBasket(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Checkout_ScanItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServer).ScanItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/checkout.Checkout/ScanItem",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServer).ScanItem(ctx, req.(*ItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Checkout_ScanWeightedItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WeightedItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServer).ScanWeightedItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/checkout.Checkout/ScanWeightedItem",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServer).ScanWeightedItem(ctx, req.(*WeightedItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Checkout_GetTotalAmount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TotalAmountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServer).GetTotalAmount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/checkout.Checkout/GetTotalAmount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServer).GetTotalAmount(ctx, req.(*TotalAmountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Checkout_RemoveBasket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveBasketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServer).RemoveBasket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/checkout.Checkout/RemoveBasket",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServer).RemoveBasket(ctx, req.(*RemoveBasketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Checkout_GetBasketBreakdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BasketBreakdownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServer).GetBasketBreakdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/checkout.Checkout/GetBasketBreakdown",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServer).GetBasketBreakdown(ctx, req.(*BasketBreakdownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Checkout_RemoveItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServer).RemoveItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/checkout.Checkout/RemoveItem",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServer).RemoveItem(ctx, req.(*ItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Checkout_SetItemQuantity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ItemQuantityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServer).SetItemQuantity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/checkout.Checkout/SetItemQuantity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServer).SetItemQuantity(ctx, req.(*ItemQuantityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Checkout_ClearBasket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearBasketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServer).ClearBasket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/checkout.Checkout/ClearBasket",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServer).ClearBasket(ctx, req.(*ClearBasketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Checkout_GetBasket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBasketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServer).GetBasket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/checkout.Checkout/GetBasket",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServer).GetBasket(ctx, req.(*GetBasketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Checkout_ApplyCoupon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CouponRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServer).ApplyCoupon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/checkout.Checkout/ApplyCoupon",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServer).ApplyCoupon(ctx, req.(*CouponRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Checkout_RemoveCoupon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CouponRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServer).RemoveCoupon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/checkout.Checkout/RemoveCoupon",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServer).RemoveCoupon(ctx, req.(*CouponRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Checkout_SetStockLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StockLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServer).SetStockLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/checkout.Checkout/SetStockLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServer).SetStockLevel(ctx, req.(*StockLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Checkout_GetStockLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStockLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServer).GetStockLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/checkout.Checkout/GetStockLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServer).GetStockLevel(ctx, req.(*GetStockLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Checkout_Checkout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServer).Checkout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/checkout.Checkout/Checkout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServer).Checkout(ctx, req.(*CheckoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Checkout_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/checkout.Checkout/GetOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServer).GetOrder(ctx, req.(*GetOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Checkout_ListOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServer).ListOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/checkout.Checkout/ListOrders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServer).ListOrders(ctx, req.(*ListOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
 var _Checkout_serviceDesc = grpc.ServiceDesc{
@@ -497,6 +2443,10 @@ var _Checkout_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ScanItem",
 			Handler:    _Checkout_ScanItem_Handler,
 		},
+		{
+			MethodName: "ScanWeightedItem",
+			Handler:    _Checkout_ScanWeightedItem_Handler,
+		},
 		{
 			MethodName: "GetTotalAmount",
 			Handler:    _Checkout_GetTotalAmount_Handler,
@@ -505,34 +2455,156 @@ var _Checkout_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveBasket",
 			Handler:    _Checkout_RemoveBasket_Handler,
 		},
+		{
+			MethodName: "GetBasketBreakdown",
+			Handler:    _Checkout_GetBasketBreakdown_Handler,
+		},
+		{
+			MethodName: "RemoveItem",
+			Handler:    _Checkout_RemoveItem_Handler,
+		},
+		{
+			MethodName: "SetItemQuantity",
+			Handler:    _Checkout_SetItemQuantity_Handler,
+		},
+		{
+			MethodName: "ClearBasket",
+			Handler:    _Checkout_ClearBasket_Handler,
+		},
+		{
+			MethodName: "GetBasket",
+			Handler:    _Checkout_GetBasket_Handler,
+		},
+		{
+			MethodName: "ApplyCoupon",
+			Handler:    _Checkout_ApplyCoupon_Handler,
+		},
+		{
+			MethodName: "RemoveCoupon",
+			Handler:    _Checkout_RemoveCoupon_Handler,
+		},
+		{
+			MethodName: "SetStockLevel",
+			Handler:    _Checkout_SetStockLevel_Handler,
+		},
+		{
+			MethodName: "GetStockLevel",
+			Handler:    _Checkout_GetStockLevel_Handler,
+		},
+		{
+			MethodName: "Checkout",
+			Handler:    _Checkout_Checkout_Handler,
+		},
+		{
+			MethodName: "GetOrder",
+			Handler:    _Checkout_GetOrder_Handler,
+		},
+		{
+			MethodName: "ListOrders",
+			Handler:    _Checkout_ListOrders_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/v1/checkout.proto",
 }
 
-func init() { proto.RegisterFile("api/v1/checkout.proto", fileDescriptor_checkout_3ebee9caf288178b) }
-
-var fileDescriptor_checkout_3ebee9caf288178b = []byte{
-	// 335 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x52, 0x41, 0x4f, 0xf2, 0x40,
-	0x10, 0xfd, 0xe0, 0x4b, 0x48, 0x99, 0x12, 0xa3, 0x4b, 0x20, 0x64, 0xd1, 0x84, 0xec, 0x49, 0x2f,
-	0xad, 0xa8, 0x07, 0x6f, 0x06, 0x09, 0x31, 0x24, 0x7a, 0xa9, 0xfe, 0x81, 0x02, 0x23, 0x12, 0x5a,
-	0xb6, 0x6e, 0xa7, 0x24, 0xfc, 0x1c, 0xff, 0xa9, 0xe9, 0xb6, 0xa5, 0x8b, 0x8a, 0x9a, 0x78, 0x7c,
-	0x6f, 0xde, 0x7b, 0xd3, 0x9d, 0x57, 0x68, 0xf9, 0xd1, 0xc2, 0x5d, 0xf7, 0xdd, 0xe9, 0x0b, 0x4e,
-	0x97, 0x32, 0x21, 0x27, 0x52, 0x92, 0x24, 0xb3, 0x0a, 0xcc, 0xbb, 0x73, 0x29, 0xe7, 0x01, 0xba,
-	0x9a, 0x9f, 0x24, 0xcf, 0x2e, 0x86, 0x11, 0x6d, 0x32, 0x99, 0x38, 0x03, 0xfb, 0xd6, 0x8f, 0x97,
-	0x48, 0x1e, 0x46, 0xc1, 0x86, 0x71, 0xb0, 0x26, 0x1a, 0x8e, 0x67, 0x9d, 0x4a, 0xaf, 0x72, 0x5a,
-	0xf7, 0xb6, 0x58, 0x0c, 0xc0, 0x1e, 0x13, 0x86, 0x1e, 0xbe, 0x26, 0x18, 0xd3, 0x77, 0x52, 0xd6,
-	0x86, 0xda, 0x82, 0x30, 0x1c, 0xcf, 0x3a, 0x55, 0x3d, 0xc9, 0x91, 0x18, 0x41, 0x3d, 0x8b, 0x48,
-	0x77, 0xb5, 0xa1, 0xa6, 0x30, 0x4e, 0x02, 0xd2, 0x76, 0xcb, 0xcb, 0x11, 0xeb, 0x81, 0x1d, 0xa3,
-	0x5a, 0xa3, 0x1a, 0x29, 0x25, 0x55, 0x9e, 0x60, 0x52, 0xe2, 0x1c, 0xd8, 0x93, 0x24, 0x3f, 0x18,
-	0x84, 0x32, 0x59, 0xd1, 0x2f, 0x3e, 0x48, 0x5c, 0xc1, 0xe1, 0x8e, 0x23, 0xdd, 0xdf, 0x03, 0x9b,
-	0x4a, 0x4e, 0x5b, 0xfe, 0x7b, 0x26, 0x25, 0xfa, 0xd0, 0xf4, 0x30, 0x94, 0x6b, 0x2c, 0x4e, 0xf4,
-	0xf3, 0xa2, 0x07, 0x38, 0xda, 0xb5, 0xfc, 0xe9, 0xa5, 0x17, 0x6f, 0x55, 0xb0, 0x86, 0x79, 0x91,
-	0xec, 0x06, 0x1a, 0x43, 0x85, 0x3e, 0xe5, 0xd9, 0xac, 0xed, 0x64, 0xcd, 0x3a, 0x45, 0xb3, 0xce,
-	0x28, 0x6d, 0x96, 0xb7, 0x9c, 0xed, 0xbf, 0x60, 0x7c, 0x85, 0xf8, 0xc7, 0xae, 0xc1, 0x7a, 0x9c,
-	0xfa, 0xab, 0xb4, 0x02, 0x66, 0x88, 0x8c, 0x56, 0x79, 0xf3, 0x23, 0x9d, 0x39, 0xef, 0xe1, 0xe0,
-	0x0e, 0xc9, 0x38, 0x21, 0x3b, 0x2e, 0x85, 0x9f, 0xbb, 0xe0, 0x7c, 0xcf, 0xb4, 0x48, 0x6b, 0x98,
-	0x47, 0x62, 0x27, 0xa5, 0xfa, 0x8b, 0x7b, 0xf3, 0xee, 0xbe, 0xb1, 0x4e, 0x9b, 0xd4, 0xf4, 0xf3,
-	0x2f, 0xdf, 0x03, 0x00, 0x00, 0xff, 0xff, 0x94, 0x0c, 0xa0, 0x1c, 0x09, 0x03, 0x00, 0x00,
+func init() { proto.RegisterFile("api/v1/checkout.proto", fileDescriptor_checkout_17a4d38b269a5928) }
+
+var fileDescriptor_checkout_17a4d38b269a5928 = []byte{
+	// 1512 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0x5b, 0x6f, 0xdb, 0xc6,
+	0x12, 0x8e, 0x24, 0x4b, 0x96, 0x46, 0xf6, 0xb1, 0xbd, 0xbe, 0x1c, 0x86, 0xc9, 0xc9, 0xf1, 0x21,
+	0x12, 0x24, 0x27, 0x6d, 0xe4, 0xc6, 0x29, 0x8a, 0xa4, 0x6d, 0xea, 0x2a, 0x42, 0xea, 0x18, 0x70,
+	0x73, 0xa1, 0xd3, 0x0b, 0xd0, 0xa7, 0x15, 0xb5, 0x56, 0x08, 0x93, 0x5c, 0x85, 0x5c, 0xaa, 0xf6,
+	0x53, 0x81, 0xfe, 0x87, 0xfe, 0x85, 0x3e, 0x15, 0xfd, 0x01, 0x45, 0x1f, 0xfa, 0x1f, 0xfa, 0xd8,
+	0x3f, 0x53, 0xec, 0x8d, 0x5c, 0xea, 0xde, 0x24, 0x6f, 0x9c, 0xeb, 0xce, 0xcc, 0x7e, 0x3b, 0x33,
+	0x84, 0x6d, 0x3c, 0xf0, 0xf7, 0x86, 0x77, 0xf7, 0xbc, 0x57, 0xc4, 0x3b, 0xa3, 0x29, 0x6b, 0x0d,
+	0x62, 0xca, 0x28, 0xaa, 0x6b, 0xda, 0xbe, 0xd2, 0xa7, 0xb4, 0x1f, 0x90, 0x3d, 0xc1, 0xef, 0xa6,
+	0xa7, 0x7b, 0x24, 0x1c, 0xb0, 0x0b, 0xa9, 0x66, 0xff, 0x77, 0x54, 0xc8, 0xfc, 0x90, 0x24, 0x0c,
+	0x87, 0x03, 0xa9, 0xe0, 0xfc, 0x1f, 0x9a, 0x8f, 0x70, 0x72, 0x46, 0x98, 0x4b, 0x06, 0xc1, 0x05,
+	0xb2, 0xa1, 0xde, 0x15, 0xe4, 0x51, 0xcf, 0x2a, 0xed, 0x96, 0x6e, 0x35, 0xdc, 0x8c, 0x76, 0xda,
+	0xd0, 0x3c, 0x62, 0x24, 0x74, 0xc9, 0xeb, 0x94, 0x24, 0x6c, 0x96, 0x2a, 0xda, 0x81, 0x9a, 0xcf,
+	0x48, 0x78, 0xd4, 0xb3, 0xca, 0x42, 0xa2, 0x28, 0xe7, 0x07, 0xd8, 0xfc, 0x86, 0xf8, 0xfd, 0x57,
+	0x8c, 0xf4, 0xde, 0xd2, 0x15, 0xb7, 0x79, 0x9d, 0xe2, 0x88, 0xf9, 0xec, 0xc2, 0xaa, 0x48, 0x1b,
+	0x4d, 0x23, 0x0b, 0x96, 0xbb, 0x38, 0xf6, 0x68, 0x8f, 0x58, 0x4b, 0x42, 0xa4, 0x49, 0xe7, 0x08,
+	0x1a, 0xf2, 0x60, 0x9e, 0xec, 0x0e, 0xd4, 0x62, 0x92, 0xa4, 0x01, 0x13, 0x87, 0xd6, 0x5d, 0x45,
+	0xa1, 0xeb, 0xd0, 0x4c, 0x48, 0x3c, 0x24, 0xf1, 0xe3, 0x38, 0xa6, 0xb1, 0x3c, 0xf7, 0x51, 0xd9,
+	0x2a, 0xb9, 0x26, 0xdb, 0xf9, 0x00, 0xd0, 0x4b, 0xca, 0x70, 0xd0, 0x0e, 0x69, 0x1a, 0xb1, 0x05,
+	0x52, 0x71, 0x3e, 0x81, 0xea, 0x97, 0x34, 0x22, 0xe2, 0x60, 0x2c, 0xac, 0x84, 0x4a, 0xc5, 0x55,
+	0x14, 0x37, 0xf6, 0xd2, 0x38, 0x26, 0x91, 0x77, 0xa1, 0xb2, 0xcd, 0x68, 0xe7, 0x3b, 0x58, 0x2f,
+	0x1c, 0xc7, 0x13, 0xd8, 0x85, 0x26, 0xcb, 0x79, 0xca, 0x99, 0xc9, 0x42, 0x37, 0xa0, 0x2a, 0x48,
+	0xe1, 0xae, 0xb9, 0xbf, 0xd6, 0xca, 0x60, 0x24, 0x22, 0x71, 0xa5, 0xd4, 0xb9, 0x0b, 0x9b, 0x2e,
+	0x09, 0xe9, 0x90, 0x68, 0x2c, 0xcc, 0x4f, 0xe6, 0x05, 0x6c, 0x14, 0x4d, 0xde, 0xbe, 0xa2, 0x1f,
+	0xc2, 0x8e, 0x74, 0xf6, 0x28, 0x26, 0xf8, 0xac, 0x47, 0xbf, 0x8f, 0x16, 0x09, 0xe4, 0xaf, 0x12,
+	0xac, 0x66, 0x06, 0xc7, 0x7e, 0x44, 0x0c, 0xc8, 0x94, 0x0a, 0x90, 0x41, 0xb0, 0x14, 0xe1, 0x90,
+	0xa8, 0xd2, 0x8a, 0xef, 0x31, 0x18, 0x55, 0x0d, 0x18, 0xdd, 0x81, 0x46, 0x1a, 0xf9, 0xec, 0x79,
+	0xec, 0x7b, 0x12, 0x48, 0x13, 0x0a, 0x98, 0x6b, 0xf0, 0x5a, 0xf7, 0x63, 0x9a, 0x24, 0x56, 0x75,
+	0x4a, 0xad, 0x85, 0x94, 0x83, 0x33, 0x24, 0x38, 0x49, 0x63, 0x62, 0xd5, 0x24, 0x38, 0x15, 0xc9,
+	0xe3, 0xe3, 0xde, 0xac, 0x65, 0x19, 0x1f, 0xff, 0x76, 0x7e, 0x2b, 0x43, 0xb3, 0x3d, 0x18, 0x04,
+	0x3e, 0xe9, 0xb9, 0x69, 0x20, 0xe2, 0x8d, 0xd3, 0x80, 0x3c, 0xe5, 0x79, 0xa8, 0x4a, 0x68, 0x1a,
+	0x39, 0xb0, 0x82, 0x4f, 0x4f, 0x89, 0xa7, 0x5e, 0x97, 0xca, 0xb3, 0xc0, 0x43, 0xd7, 0x61, 0x95,
+	0xfb, 0x4d, 0xda, 0x8a, 0xa9, 0x92, 0x2e, 0x32, 0xd1, 0x7b, 0x50, 0xef, 0xf9, 0x89, 0x27, 0x50,
+	0x35, 0x25, 0xf1, 0x4c, 0x01, 0xed, 0x41, 0xdd, 0xa3, 0x51, 0x92, 0x86, 0xa4, 0x67, 0x55, 0x77,
+	0x2b, 0xb7, 0x9a, 0xfb, 0x9b, 0xb9, 0x32, 0x3f, 0xf4, 0x2b, 0xee, 0xdb, 0xcd, 0x94, 0x78, 0x05,
+	0x12, 0x86, 0xbd, 0x33, 0xd2, 0x13, 0x15, 0xa8, 0xbb, 0x9a, 0x34, 0x6b, 0xb3, 0x5c, 0xac, 0xcd,
+	0x3d, 0x00, 0x7d, 0x20, 0xe9, 0x59, 0xf5, 0xe9, 0xc7, 0x18, 0x6a, 0xbc, 0x63, 0x9d, 0x9c, 0xf9,
+	0x83, 0xc1, 0x02, 0xb5, 0x13, 0xc8, 0xc5, 0x09, 0x8d, 0x74, 0x9b, 0x91, 0x94, 0xf3, 0x00, 0x1a,
+	0x99, 0xef, 0xa9, 0xc0, 0xda, 0x82, 0xaa, 0xa8, 0x9f, 0xb0, 0xad, 0xba, 0x92, 0x70, 0xfe, 0xac,
+	0xc0, 0xd6, 0x18, 0x9e, 0xe7, 0x34, 0x59, 0x74, 0x07, 0xaa, 0x81, 0x1f, 0x11, 0xee, 0x8a, 0xa7,
+	0xf8, 0xef, 0x3c, 0xc5, 0x02, 0xc6, 0x5d, 0xa9, 0x85, 0x1e, 0xc0, 0x0a, 0xce, 0xd1, 0x91, 0x58,
+	0x15, 0x61, 0xb5, 0x9d, 0x5b, 0x19, 0xd8, 0x71, 0x0b, 0xaa, 0x39, 0x5c, 0x97, 0x66, 0xc2, 0x35,
+	0xeb, 0x20, 0xd5, 0x59, 0x1d, 0x84, 0x07, 0x92, 0xe4, 0xa5, 0x4e, 0xac, 0xda, 0x68, 0x20, 0xc6,
+	0x45, 0xb8, 0x05, 0x55, 0xf4, 0x10, 0x00, 0x27, 0x89, 0xdf, 0x8f, 0x42, 0x12, 0x49, 0xf0, 0x37,
+	0xf7, 0xff, 0x93, 0x1b, 0x3e, 0x8f, 0x69, 0x48, 0x99, 0x4f, 0xa3, 0x76, 0xa6, 0xe4, 0x1a, 0x06,
+	0xe8, 0x7f, 0x50, 0x89, 0x08, 0xb3, 0xea, 0x93, 0xc3, 0xe3, 0x32, 0xae, 0xc2, 0xf0, 0xb9, 0xd5,
+	0x98, 0xa2, 0xc2, 0xf0, 0x39, 0xba, 0x09, 0x55, 0x86, 0xcf, 0x49, 0x62, 0x81, 0x08, 0x7c, 0x23,
+	0x57, 0x7a, 0x89, 0xcf, 0x65, 0xc5, 0x85, 0xdc, 0xf9, 0xb9, 0x04, 0xcb, 0x8a, 0xc5, 0x2f, 0x92,
+	0xe1, 0xf3, 0x4e, 0x80, 0x93, 0x44, 0x5f, 0xa4, 0xa6, 0xf9, 0x63, 0x8e, 0x31, 0xcb, 0x9a, 0x0d,
+	0xff, 0xd6, 0xa1, 0x56, 0xe6, 0x87, 0xba, 0x34, 0x23, 0xd4, 0xc5, 0xfa, 0x8c, 0xf3, 0x6b, 0x09,
+	0x36, 0x27, 0xd4, 0x8e, 0x83, 0x35, 0x16, 0x57, 0x54, 0xda, 0xad, 0xdc, 0x6a, 0xb8, 0x92, 0x40,
+	0x57, 0xa1, 0x41, 0x86, 0x38, 0x48, 0x31, 0x7f, 0x5e, 0x12, 0xc6, 0x39, 0x03, 0x5d, 0x03, 0x20,
+	0xe7, 0xaf, 0x70, 0x9a, 0x30, 0x7f, 0x48, 0x44, 0xfc, 0x75, 0xd7, 0xe0, 0x88, 0x42, 0xf8, 0x21,
+	0xe9, 0x3d, 0x4b, 0x65, 0xbf, 0xa8, 0xbb, 0x19, 0x8d, 0x6e, 0x42, 0x2d, 0xc1, 0x43, 0x3f, 0xea,
+	0x4f, 0x8b, 0x57, 0x89, 0x1d, 0x02, 0x9b, 0xfc, 0xa9, 0xbd, 0x50, 0xed, 0xf7, 0x5d, 0x2e, 0x07,
+	0x46, 0x57, 0xe7, 0x73, 0xbb, 0x13, 0x10, 0x1c, 0x2f, 0x3e, 0xea, 0x6e, 0xc3, 0x7a, 0xc1, 0x62,
+	0xc6, 0xa4, 0x73, 0x5a, 0xb0, 0x7e, 0x48, 0xd8, 0xe2, 0xbe, 0x7f, 0x2c, 0x01, 0x48, 0xed, 0x77,
+	0x3a, 0xba, 0x8c, 0x46, 0xba, 0x34, 0x79, 0xc8, 0x54, 0x8d, 0x21, 0xf3, 0x4b, 0x09, 0xfe, 0x65,
+	0x44, 0x3d, 0xaf, 0x47, 0xdd, 0x87, 0x86, 0x17, 0x13, 0x0e, 0x8c, 0x36, 0x53, 0x8b, 0x85, 0xdd,
+	0x92, 0x8b, 0x66, 0x4b, 0x2f, 0x9a, 0xad, 0x97, 0x7a, 0xd1, 0x74, 0x73, 0x65, 0x74, 0x5b, 0x77,
+	0x37, 0xd9, 0xa7, 0xb6, 0x8c, 0xee, 0x96, 0xd5, 0x40, 0xb7, 0x36, 0x0b, 0x96, 0x3d, 0x9a, 0x0e,
+	0x68, 0xc4, 0x3b, 0x14, 0x47, 0xaa, 0x26, 0x9d, 0x03, 0x58, 0xed, 0x88, 0xcf, 0x45, 0x20, 0x82,
+	0x60, 0x49, 0x2c, 0x82, 0xaa, 0x72, 0xfc, 0xdb, 0xb9, 0x01, 0x4d, 0xed, 0x60, 0xd6, 0x5d, 0x76,
+	0x60, 0xe3, 0x84, 0x51, 0xef, 0xec, 0x98, 0x0c, 0x49, 0xa0, 0xcf, 0x9a, 0x76, 0x43, 0x3b, 0x50,
+	0xa3, 0xd1, 0x13, 0x1c, 0xe9, 0xd7, 0xa3, 0x28, 0xa7, 0x05, 0x5b, 0x87, 0x84, 0x2d, 0xec, 0xc7,
+	0xf9, 0xa9, 0x04, 0x6b, 0xa6, 0xb6, 0x0a, 0x70, 0xe2, 0x99, 0x16, 0x2c, 0xb3, 0x58, 0x0e, 0xd2,
+	0xb2, 0x1c, 0xa4, 0x8a, 0x34, 0xa2, 0xa9, 0x98, 0xd1, 0x88, 0x11, 0x48, 0xc4, 0xce, 0xd5, 0x13,
+	0xc0, 0xa8, 0xba, 0x19, 0xcd, 0x5b, 0x00, 0x1e, 0x62, 0x3f, 0xc0, 0xdd, 0x80, 0x08, 0x78, 0x54,
+	0xdd, 0x9c, 0xe1, 0xdc, 0x81, 0xb5, 0x8e, 0xba, 0xac, 0x45, 0x70, 0x7d, 0x0f, 0xd6, 0x0e, 0x09,
+	0x7b, 0x16, 0xf7, 0x48, 0xac, 0xd5, 0x77, 0xa1, 0x49, 0x39, 0xfd, 0x34, 0x0d, 0xbb, 0x24, 0x56,
+	0x16, 0x26, 0xcb, 0xa1, 0xb0, 0x71, 0xec, 0x27, 0xd2, 0x2a, 0xd1, 0x66, 0x2d, 0x58, 0x3a, 0x8d,
+	0x69, 0x68, 0x95, 0xe6, 0x02, 0x4d, 0xe8, 0xa1, 0xdb, 0x50, 0x66, 0x74, 0x01, 0x58, 0x96, 0x19,
+	0x75, 0xfe, 0x28, 0x03, 0xa8, 0x18, 0xd5, 0x3e, 0x3d, 0x3b, 0xc2, 0x42, 0xca, 0xe5, 0x11, 0xa4,
+	0x7d, 0x04, 0x75, 0x3a, 0x20, 0x91, 0x78, 0x15, 0x95, 0xb9, 0xc7, 0x67, 0xba, 0xe8, 0x33, 0x58,
+	0x11, 0xcf, 0x40, 0xb4, 0xcb, 0xb6, 0x5e, 0xb8, 0x66, 0xd9, 0x16, 0xf4, 0xf9, 0x4a, 0xe7, 0xd1,
+	0xe8, 0xd4, 0xef, 0x7f, 0x4d, 0xe2, 0xc4, 0xa7, 0x91, 0x7a, 0xda, 0x45, 0xa6, 0xf9, 0x9c, 0x6a,
+	0x85, 0xe7, 0x84, 0x3e, 0x85, 0x46, 0x57, 0xef, 0x16, 0x6a, 0xfc, 0x5e, 0x1b, 0x7d, 0x98, 0xc5,
+	0x0d, 0xc6, 0xcd, 0x0d, 0x9c, 0x03, 0x58, 0x33, 0xef, 0x8c, 0x97, 0xf1, 0x7d, 0xa8, 0x89, 0x9a,
+	0xc9, 0x11, 0x53, 0x78, 0xe6, 0x79, 0xb1, 0x5d, 0xa5, 0xb3, 0xff, 0x7b, 0x03, 0xea, 0x1a, 0x59,
+	0xe8, 0x00, 0x56, 0x3a, 0xa2, 0x5b, 0xc8, 0x63, 0xd1, 0xce, 0x58, 0x15, 0x1e, 0xf3, 0xbf, 0x5b,
+	0x7b, 0x7b, 0x34, 0x40, 0xe1, 0xd3, 0xb9, 0x84, 0xee, 0x43, 0xfd, 0xc4, 0xc3, 0x91, 0xd8, 0x75,
+	0xb7, 0x8b, 0xfb, 0xa1, 0x02, 0x94, 0xbd, 0x39, 0xca, 0x96, 0x96, 0x4f, 0x60, 0x9d, 0x5b, 0x9a,
+	0xff, 0xa7, 0xc8, 0x58, 0x43, 0x26, 0xfc, 0xb7, 0x4e, 0xf3, 0x74, 0x2c, 0xba, 0xa9, 0xf1, 0xb7,
+	0x86, 0xae, 0x1a, 0xeb, 0xc4, 0xd8, 0x3f, 0xa3, 0x6d, 0x4f, 0x91, 0x6a, 0x6f, 0x2b, 0xe6, 0x8f,
+	0x96, 0x19, 0xd3, 0x84, 0x7f, 0x36, 0xfb, 0xca, 0x34, 0xb1, 0xf4, 0xf6, 0x2d, 0xa0, 0xac, 0xd3,
+	0x67, 0x97, 0x8a, 0x76, 0x67, 0xdc, 0xb7, 0x74, 0x3b, 0x07, 0x11, 0xce, 0x25, 0xf4, 0x31, 0x80,
+	0x3c, 0xf0, 0x0d, 0x6a, 0x7f, 0x08, 0x6b, 0x27, 0x84, 0x99, 0xd3, 0xdf, 0x4c, 0x73, 0xc2, 0x56,
+	0x30, 0xcd, 0xd1, 0x11, 0x34, 0x8d, 0x51, 0x6d, 0xd6, 0x7d, 0x7c, 0xe6, 0xdb, 0xf6, 0x14, 0xa9,
+	0x74, 0xd5, 0x81, 0x46, 0x56, 0x29, 0x64, 0xa8, 0x8e, 0x8e, 0x77, 0xdb, 0x9a, 0x28, 0x93, 0x4e,
+	0x0e, 0xe4, 0xdf, 0xdb, 0x85, 0x1c, 0x37, 0xc8, 0x58, 0xe7, 0x0b, 0x13, 0xcc, 0xde, 0x1e, 0x17,
+	0x48, 0x07, 0x9f, 0xeb, 0xdb, 0x7f, 0x63, 0x0f, 0x47, 0xb0, 0x7a, 0x62, 0x0e, 0x20, 0x64, 0x20,
+	0x64, 0x6c, 0x2c, 0xd9, 0x97, 0x27, 0x0b, 0x35, 0x14, 0x57, 0x0b, 0xb3, 0x0c, 0x5d, 0x2b, 0xa4,
+	0xfe, 0x0f, 0xbd, 0x3d, 0x34, 0xde, 0xbd, 0xa1, 0x38, 0x32, 0x65, 0xec, 0x89, 0xdd, 0x43, 0x9a,
+	0xeb, 0x09, 0x63, 0x9a, 0x8f, 0x4c, 0x9d, 0xa9, 0xe6, 0x5f, 0x00, 0xe4, 0x7d, 0xcb, 0xac, 0xc9,
+	0xd8, 0x04, 0xb2, 0x2f, 0x4f, 0x16, 0x0a, 0x3f, 0xdd, 0x9a, 0xe8, 0x4c, 0xf7, 0xfe, 0x1e, 0x00,
+	0x86, 0x8e, 0x52, 0xa6, 0xa8, 0x13, 0x00, 0x00,
 }
@@ -0,0 +1,112 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//Number of decimal places a Decimal can hold
+const decimalPlaces = 6
+
+//Scale of a Decimal, 1 is stored as 1000000
+const decimalScale = 1000000
+
+//Decimal is an exact, unitless decimal number stored as an integer number of millionths.
+//It is used to read amounts such as "7.50" from the yaml configuration files without going through a float,
+//which can't represent most decimal fractions exactly
+type Decimal int64
+
+//Creates a Decimal holding the given integer
+func DecimalFromInt(n int64) Decimal {
+	return Decimal(n * decimalScale)
+}
+
+//Parses a decimal number such as "7.50", "-3" or "0.125" into an exact Decimal
+//Exponents, thousands separators and more than 6 decimal places are not supported and produce an error
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New("an empty string is not a valid decimal number")
+	}
+
+	negative := false
+	if s[0] == '-' || s[0] == '+' {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	if intPart == "" && fracPart == "" {
+		return 0, fmt.Errorf("'%s' is not a valid decimal number", s)
+	}
+	if len(fracPart) > decimalPlaces {
+		return 0, fmt.Errorf("'%s' has more than %d decimal places", s, decimalPlaces)
+	}
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, fmt.Errorf("'%s' is not a valid decimal number", s)
+	}
+
+	var units int64
+	if intPart != "" {
+		var err error
+		if units, err = strconv.ParseInt(intPart, 10, 64); err != nil {
+			return 0, fmt.Errorf("'%s' is out of range: %v", s, err)
+		}
+	}
+	var fraction int64
+	if fracPart != "" {
+		fraction, _ = strconv.ParseInt(fracPart+strings.Repeat("0", decimalPlaces-len(fracPart)), 10, 64)
+	}
+	if units > (1<<63-1-fraction)/decimalScale {
+		return 0, fmt.Errorf("'%s' is out of range", s)
+	}
+
+	d := Decimal(units*decimalScale + fraction)
+	if negative {
+		d = -d
+	}
+	return d, nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+//Reads the raw text of the yaml scalar so "7.50" is parsed exactly instead of being decoded as a float
+func (d *Decimal) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+//Returns the shortest representation of the decimal, ie: "7.5" or "3"
+func (d Decimal) String() string {
+	sign := ""
+	v := int64(d)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	s := fmt.Sprintf("%s%d", sign, v/decimalScale)
+	if fraction := v % decimalScale; fraction != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%06d", fraction), "0")
+	}
+	return s
+}
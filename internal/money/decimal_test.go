@@ -0,0 +1,69 @@
+package money
+
+import "testing"
+
+func TestParseDecimal(t *testing.T) {
+
+	//ARRANGE
+	expected := map[string]Decimal{
+		"7.50":     7500000,
+		"20.00":    20000000,
+		"5":        5000000,
+		"0.125":    125000,
+		"-3.1":     -3100000,
+		".5":       500000,
+		"0.000001": 1,
+	}
+
+	for s, e := range expected {
+		//ACT
+		d, err := ParseDecimal(s)
+
+		//ASSERT
+		if err != nil {
+			t.Errorf("Parsing '%s' shouldn't have produced an error, got: %+v", s, err)
+		}
+		if d != e {
+			t.Errorf("Parsing '%s' should've produced %d, got: %d", s, e, d)
+		}
+	}
+
+}
+
+func TestParseDecimalInvalidInput(t *testing.T) {
+
+	//ARRANGE
+	invalid := []string{"", "abc", "7,50", "1e3", "0.0000001", ".", "1.2.3", "99999999999999999999"}
+
+	for _, s := range invalid {
+		//ACT
+		_, err := ParseDecimal(s)
+
+		//ASSERT
+		if err == nil {
+			t.Errorf("Parsing '%s' should've produced an error", s)
+		}
+	}
+
+}
+
+func TestDecimalString(t *testing.T) {
+
+	//ARRANGE
+	expected := map[Decimal]string{
+		7500000:  "7.5",
+		20000000: "20",
+		-125000:  "-0.125",
+	}
+
+	for d, e := range expected {
+		//ACT
+		s := d.String()
+
+		//ASSERT
+		if s != e {
+			t.Errorf("The decimal %d should be formatted as %s, got: %s", d, e, s)
+		}
+	}
+
+}
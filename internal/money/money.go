@@ -0,0 +1,91 @@
+package money
+
+import (
+	"fmt"
+	"strings"
+)
+
+//Currency used when the configuration doesn't specify one
+const DefaultCurrency = "EUR"
+
+//Number of decimal places of the minor unit of the currencies that don't use cents
+//Any currency not listed here is considered to have 2 decimal places
+var minorUnitDigits = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+	"CLP": 0,
+	"ISK": 0,
+	"BHD": 3,
+	"KWD": 3,
+	"JOD": 3,
+}
+
+//Money is an exact amount of a currency expressed as an integer number of minor units (ie: cents for EUR)
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+//Creates a Money from an amount of minor units, New(750, "EUR") is 7.50 EUR
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+//Converts a Decimal expressed in major units (ie: 7.50) into Money of the given currency
+//It returns an error if the decimal has more precision than the currency's minor unit, ie: 7.505 EUR
+func FromDecimal(d Decimal, currency string) (Money, error) {
+	factor := pow10(decimalPlaces - MinorUnitDigits(currency))
+	if int64(d)%factor != 0 {
+		return Money{}, fmt.Errorf("%s can't be expressed in %s without rounding", d, currency)
+	}
+	return Money{Amount: int64(d) / factor, Currency: currency}, nil
+}
+
+//Returns the number of decimal places of the minor unit of the given currency
+func MinorUnitDigits(currency string) int {
+	if digits, exs := minorUnitDigits[strings.ToUpper(currency)]; exs {
+		return digits
+	}
+	return 2
+}
+
+//Converts the amount into a Subtotal in order to carry out further calculations without losing precision
+func (m Money) Subtotal() Subtotal {
+	return Subtotal{Micros: m.Amount * microsPerUnit, Currency: m.Currency}
+}
+
+//Multiplies the amount by a quantity, ie: the unit price of an item by the number of scanned items
+func (m Money) Times(quantity int) Subtotal {
+	return m.Subtotal().Times(quantity)
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+//Formats the amount in major units followed by the currency code, ie: "7.50 EUR"
+func (m Money) String() string {
+	digits := MinorUnitDigits(m.Currency)
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	s := fmt.Sprintf("%s%d", sign, amount/pow10(digits))
+	if digits > 0 {
+		s += fmt.Sprintf(".%0*d", digits, amount%pow10(digits))
+	}
+	if m.Currency != "" {
+		s += " " + m.Currency
+	}
+	return s
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
@@ -0,0 +1,158 @@
+package money
+
+import "testing"
+
+func TestFromDecimal(t *testing.T) {
+
+	//ARRANGE
+	d, _ := ParseDecimal("7.50")
+
+	//ACT
+	m, err := FromDecimal(d, "EUR")
+
+	//ASSERT
+	if err != nil {
+		t.Errorf("Converting 7.50 into EUR shouldn't have produced an error, got: %+v", err)
+	}
+	if m != New(750, "EUR") {
+		t.Errorf("7.50 EUR should be 750 minor units, got: %+v", m)
+	}
+
+}
+
+func TestFromDecimalZeroDecimalsCurrency(t *testing.T) {
+
+	//ARRANGE
+	d, _ := ParseDecimal("1500")
+
+	//ACT
+	m, _ := FromDecimal(d, "JPY")
+
+	//ASSERT
+	if m != New(1500, "JPY") {
+		t.Errorf("1500 JPY should be 1500 minor units, got: %+v", m)
+	}
+
+}
+
+func TestFromDecimalTooPrecise(t *testing.T) {
+
+	//ARRANGE
+	d, _ := ParseDecimal("7.505")
+
+	//ACT
+	_, err := FromDecimal(d, "EUR")
+
+	//ASSERT
+	if err == nil {
+		t.Errorf("7.505 can't be represented in EUR cents, an error should've been produced")
+	}
+
+}
+
+func TestMoneyString(t *testing.T) {
+
+	//ARRANGE
+	expected := map[Money]string{
+		New(750, "EUR"):   "7.50 EUR",
+		New(-5, "EUR"):    "-0.05 EUR",
+		New(1500, "JPY"):  "1500 JPY",
+		New(12345, "KWD"): "12.345 KWD",
+	}
+
+	for m, e := range expected {
+		//ACT
+		s := m.String()
+
+		//ASSERT
+		if s != e {
+			t.Errorf("%+v should be formatted as %s, got: %s", m, e, s)
+		}
+	}
+
+}
+
+func TestSubtotalPercentIsExact(t *testing.T) {
+
+	//ARRANGE
+	mug := New(750, "EUR")
+
+	//ACT
+	discounted := mug.Times(1).Percent(95)
+
+	//ASSERT
+	if discounted.Micros != 712500000 {
+		t.Errorf("95%% of 7.50 should be exactly 7.125, got: %s", discounted)
+	}
+
+}
+
+func TestSubtotalRatio(t *testing.T) {
+
+	//ARRANGE
+	mug := New(1210, "EUR")
+	rate, _ := ParseDecimal("5.5")
+
+	//ACT
+	included := mug.Subtotal().Ratio(DecimalFromInt(21), DecimalFromInt(121))
+	added := mug.Subtotal().Ratio(rate, DecimalFromInt(100))
+
+	//ASSERT
+	if included.Micros != 210000000 {
+		t.Errorf("The 21%% tax included in 12.10 should be exactly 2.10, got: %s", included)
+	}
+
+	if added.Micros != 66550000 {
+		t.Errorf("5.5%% of 12.10 should be exactly 0.6655, got: %s", added)
+	}
+
+}
+
+func TestSubtotalAddMixedCurrenciesPanics(t *testing.T) {
+
+	//ARRANGE
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Adding EUR and USD amounts should've panicked")
+		}
+	}()
+
+	//ACT
+	New(100, "EUR").Subtotal().Add(New(100, "USD").Subtotal())
+
+}
+
+func TestSubtotalAddZeroValue(t *testing.T) {
+
+	//ARRANGE
+	s := Subtotal{}
+
+	//ACT
+	s = s.Add(New(100, "EUR").Subtotal())
+
+	//ASSERT
+	if s.Currency != "EUR" || s.Micros != 100000000 {
+		t.Errorf("Adding to the zero value should keep the currency of the other amount, got: %s", s)
+	}
+
+}
+
+func TestSubtotalFromDecimal(t *testing.T) {
+
+	//ARRANGE
+	d, _ := ParseDecimal("25.005")
+
+	//ACT
+	eur := SubtotalFromDecimal(d, "EUR")
+	jpy := SubtotalFromDecimal(DecimalFromInt(300), "JPY")
+
+	//ASSERT
+	if eur.Micros != 2500500000 || eur.Currency != "EUR" {
+		t.Errorf("25.005 EUR should be exactly 2500.5 cents, got: %s", eur)
+	}
+
+	if jpy.Round(HalfUp) != New(300, "JPY") {
+		t.Errorf("300 JPY should be 300 minor units, got: %s", jpy)
+	}
+
+}
@@ -0,0 +1,68 @@
+package money
+
+import (
+	"fmt"
+	"strings"
+)
+
+//Rounding strategy used to convert a Subtotal into minor units
+type RoundingMode int
+
+const (
+	//Rounds halves away from zero, 0.125 is rounded to 0.13. It's the default mode
+	HalfUp RoundingMode = iota
+	//Rounds halves to the nearest even minor unit (banker's rounding), 0.125 is rounded to 0.12
+	HalfEven
+	//Always rounds towards negative infinity, 0.129 is rounded to 0.12
+	Floor
+)
+
+var roundingModeNames = map[RoundingMode]string{
+	HalfUp:   "half-up",
+	HalfEven: "half-even",
+	Floor:    "floor",
+}
+
+//Parses the name of a rounding mode as used in the server flags: half-up, half-even or floor
+func ParseRoundingMode(name string) (RoundingMode, error) {
+	for mode, n := range roundingModeNames {
+		if strings.EqualFold(n, strings.TrimSpace(name)) {
+			return mode, nil
+		}
+	}
+	return HalfUp, fmt.Errorf("unknown rounding mode '%s', expected one of half-up, half-even or floor", name)
+}
+
+func (r RoundingMode) String() string {
+	return roundingModeNames[r]
+}
+
+//Divides n by d, rounding the quotient with this rounding mode
+func (r RoundingMode) divide(n, d int64) int64 {
+	q, rem := n/d, n%d
+	if rem == 0 {
+		return q
+	}
+	sign := int64(1)
+	if rem < 0 {
+		sign = -1
+		rem = -rem
+	}
+	switch r {
+	case Floor:
+		if sign < 0 {
+			return q - 1
+		}
+		return q
+	case HalfEven:
+		if 2*rem > d || (2*rem == d && q%2 != 0) {
+			return q + sign
+		}
+		return q
+	default:
+		if 2*rem >= d {
+			return q + sign
+		}
+		return q
+	}
+}
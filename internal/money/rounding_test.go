@@ -0,0 +1,55 @@
+package money
+
+import "testing"
+
+func TestRound(t *testing.T) {
+
+	//ARRANGE
+	cases := []struct {
+		micros   int64
+		mode     RoundingMode
+		expected int64
+	}{
+		{712500000, HalfUp, 713},
+		{712500000, HalfEven, 712},
+		{713500000, HalfEven, 714},
+		{712900000, Floor, 712},
+		{712499999, HalfUp, 712},
+		{-712500000, HalfUp, -713},
+		{-712500000, HalfEven, -712},
+		{-712100000, Floor, -713},
+		{5700000000, HalfUp, 5700},
+	}
+
+	for _, c := range cases {
+		//ACT
+		m := Subtotal{Micros: c.micros, Currency: "EUR"}.Round(c.mode)
+
+		//ASSERT
+		if m.Amount != c.expected {
+			t.Errorf("Rounding %d micros with %s should've produced %d, got: %d", c.micros, c.mode, c.expected, m.Amount)
+		}
+	}
+
+}
+
+func TestParseRoundingMode(t *testing.T) {
+
+	//ARRANGE
+	expected := map[string]RoundingMode{"half-up": HalfUp, "HALF-EVEN": HalfEven, "floor": Floor}
+
+	for name, e := range expected {
+		//ACT
+		mode, err := ParseRoundingMode(name)
+
+		//ASSERT
+		if err != nil || mode != e {
+			t.Errorf("Parsing '%s' should've produced %s, got: %s (%v)", name, e, mode, err)
+		}
+	}
+
+	if _, err := ParseRoundingMode("ceiling"); err == nil {
+		t.Errorf("Parsing an unknown rounding mode should've produced an error")
+	}
+
+}
@@ -0,0 +1,90 @@
+package money
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+//Number of micros in a minor unit
+const microsPerUnit = 1000000
+
+//Subtotal is an intermediate amount expressed in millionths of a minor unit of a currency.
+//Rule calculations are carried out on Subtotals so percentages don't need to be rounded on every step, the
+//rounding to minor units happens only once, when the final amount is produced with Round
+//The zero value is a valid zero amount of any currency
+type Subtotal struct {
+	Micros   int64
+	Currency string
+}
+
+//Returns a zero Subtotal of the given currency
+func Zero(currency string) Subtotal {
+	return Subtotal{Currency: currency}
+}
+
+//Converts a Decimal expressed in major units (ie: 25.00) into a Subtotal of the given currency
+//A Subtotal can hold any Decimal exactly, so unlike FromDecimal no precision is lost nor an error returned
+func SubtotalFromDecimal(d Decimal, currency string) Subtotal {
+	return Subtotal{Micros: int64(d) * pow10(MinorUnitDigits(currency)), Currency: currency}
+}
+
+//Adds two subtotals. Both must be of the same currency unless one of them has no currency set
+func (s Subtotal) Add(o Subtotal) Subtotal {
+	return Subtotal{Micros: s.Micros + o.Micros, Currency: s.mergeCurrency(o)}
+}
+
+//Subtracts o from s. Both must be of the same currency unless one of them has no currency set
+func (s Subtotal) Sub(o Subtotal) Subtotal {
+	return Subtotal{Micros: s.Micros - o.Micros, Currency: s.mergeCurrency(o)}
+}
+
+//Multiplies the subtotal by an integer quantity
+func (s Subtotal) Times(quantity int) Subtotal {
+	return Subtotal{Micros: s.Micros * int64(quantity), Currency: s.Currency}
+}
+
+//Multiplies the subtotal by a decimal quantity, ie: the price of a kilogram by the 0.350 kg weighed
+//The result is exact down to a millionth of a minor unit, any precision below that is truncated
+func (s Subtotal) TimesDecimal(quantity Decimal) Subtotal {
+	return s.Ratio(quantity, DecimalFromInt(1))
+}
+
+//Returns the given percentage of the subtotal, ie: Percent(95) of 60.00 is 57.00
+//The result is exact down to a millionth of a minor unit, any precision below that is truncated
+func (s Subtotal) Percent(percentage int) Subtotal {
+	return Subtotal{Micros: s.Micros * int64(percentage) / 100, Currency: s.Currency}
+}
+
+//Returns the subtotal multiplied by numerator / denominator, ie: Ratio(21, 121) of 12.10 is the 2.10 of tax included in it
+//The result is exact down to a millionth of a minor unit, any precision below that is truncated
+func (s Subtotal) Ratio(numerator Decimal, denominator Decimal) Subtotal {
+	share := new(big.Int).Mul(big.NewInt(s.Micros), big.NewInt(int64(numerator)))
+	return Subtotal{Micros: share.Quo(share, big.NewInt(int64(denominator))).Int64(), Currency: s.Currency}
+}
+
+func (s Subtotal) IsZero() bool {
+	return s.Micros == 0
+}
+
+//Rounds the subtotal to the minor unit of its currency using the given rounding mode
+func (s Subtotal) Round(mode RoundingMode) Money {
+	return Money{Amount: mode.divide(s.Micros, microsPerUnit), Currency: s.Currency}
+}
+
+func (s Subtotal) String() string {
+	return fmt.Sprintf("%s %s", Decimal(s.Micros).String(), s.Currency)
+}
+
+//Adding amounts of different currencies is a programming error, the parser guarantees all the configured
+//items share the same currency
+func (s Subtotal) mergeCurrency(o Subtotal) string {
+	switch {
+	case s.Currency == "":
+		return o.Currency
+	case o.Currency == "" || strings.EqualFold(s.Currency, o.Currency):
+		return s.Currency
+	default:
+		panic(fmt.Sprintf("money: can't operate %s with %s amounts", s.Currency, o.Currency))
+	}
+}
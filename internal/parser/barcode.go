@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//Length of a GTIN-14, the longest barcode, every other barcode is the GTIN-14 padded with leading zeros
+const gtin14Length = 14
+
+//Returns the GTIN-14 form of the given barcode: an EAN-8, UPC-A or EAN-13 is padded with leading zeros, so the UPC-A
+//040000000006 and the EAN-13 0040000000006 are the same GTIN-14 00040000000006. The check digit is not affected by the
+//padding. Anything that is not an 8, 12, 13 or 14 digits barcode is returned as is
+func NormaliseBarcode(code string) string {
+	if !isDigits(code) || len(code) != 8 && len(code) != 12 && len(code) != 13 && len(code) != gtin14Length {
+		return code
+	}
+	return strings.Repeat("0", gtin14Length-len(code)) + code
+}
+
+//Returns the ids of the items by the GTIN-14 form of their barcodes, so an item is found by any form of its barcodes
+//It's built once when the items are loaded, see NormaliseBarcode
+func (c ConfiguredItems) BarcodeIndex() map[string]string {
+	index := make(map[string]string)
+	for id, item := range c {
+		for _, b := range item.Barcodes {
+			index[NormaliseBarcode(b)] = id
+		}
+	}
+	return index
+}
+
+//Validates the barcodes of the item: every one of them must be an EAN-8, UPC-A, EAN-13 or GTIN-14 with a valid check
+//digit and can only be set once, in any of its forms, returns an error otherwise
+func (i ItemDefinition) validateBarcodes() error {
+	seen := make(map[string]bool, len(i.Barcodes))
+	for _, b := range i.Barcodes {
+		if !isDigits(b) || len(b) != 8 && len(b) != 12 && len(b) != 13 && len(b) != 14 {
+			return fmt.Errorf("the barcode '%s' must be an EAN-8, UPC-A, EAN-13 or GTIN-14 of 8, 12, 13 or 14 digits", b)
+		}
+		if !validCheckDigit(b) {
+			return fmt.Errorf("the check digit of the barcode %s is not valid", b)
+		}
+		if seen[NormaliseBarcode(b)] {
+			return fmt.Errorf("the barcode %s is set more than once", b)
+		}
+		seen[NormaliseBarcode(b)] = true
+	}
+	return nil
+}
+
+//Returns the items with a barcode or a variable barcode already used by another item, along with the problem found
+//The barcodes are compared in their GTIN-14 form, so a UPC-A and its EAN-13 twin are the same barcode
+//The items are checked sorted by id, so the first one keeps the barcode
+func duplicatedBarcodes(items ConfiguredItems) map[string]string {
+	ids := make([]string, 0, len(items))
+	for k := range items {
+		ids = append(ids, k)
+	}
+	sort.Strings(ids)
+	codes := make(map[string]string)
+	barcodes := make(map[string]string)
+	duplicated := make(map[string]string)
+	for _, k := range ids {
+		item := items[k]
+		if other, exs := codes[item.VariableBarcode.Code]; exs && item.VariableBarcode.Code != "" {
+			duplicated[k] = fmt.Sprintf("its variable barcode is already used by the item %s", other)
+			continue
+		}
+		for _, b := range item.Barcodes {
+			if other, exs := barcodes[NormaliseBarcode(b)]; exs {
+				duplicated[k] = fmt.Sprintf("its barcode %s is already used by the item %s", b, other)
+				break
+			}
+		}
+		if _, exs := duplicated[k]; exs {
+			continue
+		}
+		if item.VariableBarcode.Code != "" {
+			codes[item.VariableBarcode.Code] = k
+		}
+		for _, b := range item.Barcodes {
+			barcodes[NormaliseBarcode(b)] = k
+		}
+	}
+	return duplicated
+}
+
+//Returns true if the last digit of the code is the GS1 check digit of the rest of them: the digits are weighted 3 and 1
+//alternately from the right, and the check digit takes their sum to the next multiple of 10
+func validCheckDigit(code string) bool {
+	sum := 0
+	for i, weight := len(code)-2, 3; i >= 0; i, weight = i-1, 4-weight {
+		sum += int(code[i]-'0') * weight
+	}
+	return (10-sum%10)%10 == int(code[len(code)-1]-'0')
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
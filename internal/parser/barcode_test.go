@@ -0,0 +1,163 @@
+package parser
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseItemsDefinitionsBarcodes(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "item_definitions.yaml")
+	ioutil.WriteFile(path, []byte(`currency: EUR
+items:
+  MUG:
+    name: Company Coffee Mug
+    price: 7.50
+    barcodes: ["4006381333931", "96385074", "040000000006", "10012345678902"]
+  VOUCHER:
+    name: Company Voucher
+    price: 5.00
+`), 0644)
+
+	//ACT
+	items, err := ItemsParser{Strict: true}.ParseItemsDefinitions(path)
+
+	//ASSERT
+	if err != nil {
+		t.Fatalf("The barcodes should have been parsed, got: %v", err)
+	}
+
+	expected := []string{"4006381333931", "96385074", "040000000006", "10012345678902"}
+	if !reflect.DeepEqual(items["MUG"].Barcodes, expected) {
+		t.Errorf("The barcodes of the mug should have been parsed, expected: %v, got: %v", expected, items["MUG"].Barcodes)
+	}
+
+	index := items.BarcodeIndex()
+	if id, found := index[NormaliseBarcode("040000000006")]; !found || id != "MUG" {
+		t.Errorf("The mug should have been found by its UPC-A, got: %s", id)
+	}
+
+	if id, found := index[NormaliseBarcode("0040000000006")]; !found || id != "MUG" {
+		t.Errorf("The mug should have been found by the EAN-13 form of its UPC-A, got: %s", id)
+	}
+
+	if _, found := index[NormaliseBarcode("8437000000013")]; found {
+		t.Errorf("No item should have been found by an unknown barcode")
+	}
+
+}
+
+func TestParseItemsDefinitionsBarcodesNotValid(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "item_definitions.yaml")
+	ioutil.WriteFile(path, []byte(`currency: EUR
+items:
+  MUG:
+    name: Company Coffee Mug
+    price: 7.50
+    barcodes: ["4006381333931"]
+  TSHIRT:
+    name: Company T-Shirt
+    price: 20.00
+    barcodes: ["8437000000020", "4006381333931"]
+  VOUCHER:
+    name: Company Voucher
+    price: 5.00
+    barcodes: ["4006381333932"]
+  BOOK:
+    name: Book
+    price: 10.00
+    barcodes: ["12345"]
+  PEN:
+    name: Pen
+    price: 1.00
+    barcodes: ["96385074", "96385074"]
+  TSHIRT-XL:
+    name: Company T-Shirt XL
+    price: 22.00
+    barcodes: ["0040000000006"]
+  VOUCHER-XL:
+    name: Company Voucher XL
+    price: 10.00
+    barcodes: ["040000000006"]
+`), 0644)
+	expected := []ConfigProblem{
+		{File: path, Line: 7, Message: "the item TSHIRT is not valid: its barcode 4006381333931 is already used by the item MUG"},
+		{File: path, Line: 11, Message: "the item VOUCHER is not valid: the check digit of the barcode 4006381333932 is not valid"},
+		{File: path, Line: 15, Message: "the item BOOK is not valid: the barcode '12345' must be an EAN-8, UPC-A, EAN-13 or GTIN-14 of 8, 12, 13 or 14 digits"},
+		{File: path, Line: 19, Message: "the item PEN is not valid: the barcode 96385074 is set more than once"},
+		{File: path, Line: 27, Message: "the item VOUCHER-XL is not valid: its barcode 040000000006 is already used by the item TSHIRT-XL"},
+	}
+
+	//ACT
+	_, err := ItemsParser{Strict: true}.ParseItemsDefinitions(path)
+	lenient, lenientErr := ItemsParser{}.ParseItemsDefinitions(path)
+
+	//ASSERT
+	configErr, ok := err.(*ConfigError)
+	if !ok || len(configErr.Problems) != len(expected) {
+		t.Fatalf("Every problem should have been reported, expected: %+v, got: %v", expected, err)
+	}
+	for i := range expected {
+		if configErr.Problems[i] != expected[i] {
+			t.Errorf("Expected the problem %s, got: %s", expected[i], configErr.Problems[i])
+		}
+	}
+
+	if _, exs := lenient["MUG"]; lenientErr != nil || len(lenient) != 2 || !exs {
+		t.Errorf("Only the first item with the barcode should have been kept, got: %+v", lenient)
+	}
+
+}
+
+func TestValidCheckDigit(t *testing.T) {
+
+	//ARRANGE
+	codes := map[string]bool{
+		"96385074":       true,
+		"040000000006":   true,
+		"4006381333931":  true,
+		"10012345678902": true,
+		"4006381333930":  false,
+		"96385075":       false,
+	}
+
+	for code, expected := range codes {
+		//ACT
+		valid := validCheckDigit(code)
+
+		//ASSERT
+		if valid != expected {
+			t.Errorf("The check digit of %s should be valid: %t, got: %t", code, expected, valid)
+		}
+	}
+
+}
+
+func TestNormaliseBarcode(t *testing.T) {
+
+	//ARRANGE
+	codes := map[string]string{
+		"96385074":       "00000096385074",
+		"040000000006":   "00040000000006",
+		"0040000000006":  "00040000000006",
+		"10012345678902": "10012345678902",
+		"MUG":            "MUG",
+		"12345":          "12345",
+	}
+
+	for code, expected := range codes {
+		//ACT
+		normalised := NormaliseBarcode(code)
+
+		//ASSERT
+		if normalised != expected {
+			t.Errorf("The GTIN-14 of %s should be %s, got: %s", code, expected, normalised)
+		}
+	}
+
+}
@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dagozba/golangsmallshop/internal/money"
+)
+
+//Basket level discount given once the amount spent reaches a Threshold, ie: 10% off orders over 100.00 or 5.00 off
+//when spending 50.00. Only one of DiscountPercentage and DiscountAmount can be set
+//The amount spent is the already discounted amount of the basket items, leaving out the ExcludedItems, which are not
+//discounted either. MaxDiscount caps the discount, 0 means there's no cap
+//All the amounts are expressed in the currency of the item definitions
+type BasketRule struct {
+	RuleName           string        `yaml:"ruleName"`
+	Threshold          money.Decimal `yaml:"threshold"`
+	DiscountPercentage int           `yaml:"discountPercentage"`
+	DiscountAmount     money.Decimal `yaml:"discountAmount"`
+	ExcludedItems      []string      `yaml:"excludedItems"`
+	MaxDiscount        money.Decimal `yaml:"maxDiscount"`
+	RuleOptions        `yaml:",inline"`
+}
+
+//Validates the given BasketRule, returns an error otherwise
+func (r BasketRule) validateBasketRuleInput() error {
+
+	if r.Threshold < 0 {
+		return errors.New("the threshold can't be below zero")
+	}
+
+	if r.DiscountPercentage < 0 || r.DiscountPercentage > 100 {
+		return errors.New("the discount percentage must be higher than 0% and lower or equals than 100%")
+	}
+
+	if r.DiscountAmount < 0 {
+		return errors.New("the discount amount can't be below zero")
+	}
+
+	if r.DiscountPercentage == 0 && r.DiscountAmount == 0 {
+		return errors.New("either a discount percentage or a discount amount must be set")
+	}
+
+	if r.DiscountPercentage != 0 && r.DiscountAmount != 0 {
+		return errors.New("a discount percentage and a discount amount can't be set at the same time")
+	}
+
+	if r.MaxDiscount < 0 {
+		return errors.New("the maximum discount can't be below zero")
+	}
+
+	excluded := make(map[string]bool)
+	for _, i := range r.ExcludedItems {
+		if i == "" {
+			return errors.New("an excluded item can't be nil")
+		}
+		if excluded[i] {
+			return fmt.Errorf("the item %s is excluded more than once", i)
+		}
+		excluded[i] = true
+	}
+
+	return r.validateSchedule()
+}
+
+//Validates the amounts of the rule can be expressed in the currency of the items
+func (r BasketRule) validateAmounts(items ConfiguredItems) error {
+	amounts := []struct {
+		name  string
+		value money.Decimal
+	}{{"threshold", r.Threshold}, {"discount amount", r.DiscountAmount}, {"maximum discount", r.MaxDiscount}}
+	for _, a := range amounts {
+		if _, err := money.FromDecimal(a.value, items.Currency()); err != nil {
+			return fmt.Errorf("the %s is not valid: %v", a.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRulesFileBasket(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  basketRules:
+  - ruleName: 10% off over 100
+    threshold: 100.00
+    discountPercentage: 10
+    maxDiscount: 20.00
+    excludedItems:
+    - VOUCHER
+`), 0644)
+	expected := BasketRule{RuleName: "10% off over 100", Threshold: money.DecimalFromInt(100), DiscountPercentage: 10,
+		MaxDiscount: money.DecimalFromInt(20), ExcludedItems: []string{"VOUCHER"}}
+
+	//ACT
+	rules, err := RuleParser{Strict: true}.ParseRulesFile(path)
+
+	//ASSERT
+	if err != nil || len(rules.BasketRules) != 1 || !reflect.DeepEqual(rules.BasketRules[0], expected) {
+		t.Errorf("The basket rule should have been parsed, expected: %+v, got: %+v (%v)", expected, rules.BasketRules, err)
+	}
+
+}
+
+func TestParseRulesFileBasketNotValid(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  basketRules:
+  - ruleName: Spend more
+    threshold: 50.00
+`), 0644)
+	expected := ConfigProblem{File: path, Line: 3,
+		Message: "the rule Spend more is not valid: either a discount percentage or a discount amount must be set"}
+
+	//ACT
+	_, err := RuleParser{Strict: true}.ParseRulesFile(path)
+
+	//ASSERT
+	if configErr, ok := err.(*ConfigError); !ok || len(configErr.Problems) != 1 || configErr.Problems[0] != expected {
+		t.Errorf("Expected the problem %s, got: %v", expected, err)
+	}
+
+}
+
+func TestValidateRulesBasketExcludedItems(t *testing.T) {
+
+	//ARRANGE
+	items, _ := ItemsParser{}.ParseItemsDefinitions("../../configs/item_definitions.yaml")
+	tooPrecise, _ := money.ParseDecimal("4.999")
+	rules := Rules{BasketRules: []BasketRule{
+		{RuleName: "Excluding books", DiscountPercentage: 10, ExcludedItems: []string{"BOOK"}},
+		{RuleName: "Too precise", DiscountAmount: tooPrecise},
+	}}
+	expected := []string{
+		"the rule Excluding books affects the item BOOK, which is not defined in the item definitions",
+		"the rule Too precise is not valid: the discount amount is not valid: 4.999 can't be expressed in EUR without rounding",
+	}
+
+	//ACT
+	err := ValidateRules(rules, items)
+
+	//ASSERT
+	configErr, ok := err.(*ConfigError)
+	if !ok || len(configErr.Problems) != len(expected) {
+		t.Fatalf("Expected the problems %v, got: %v", expected, err)
+	}
+	for i := range expected {
+		if configErr.Problems[i].Message != expected[i] {
+			t.Errorf("Expected the problem %s, got: %s", expected[i], configErr.Problems[i].Message)
+		}
+	}
+
+}
@@ -0,0 +1,177 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dagozba/golangsmallshop/internal/money"
+)
+
+//Modes of a tiered bulk rule
+const (
+	//The whole quantity is priced at the highest tier reached, ie: 10 units with a 10+ tier are all priced at that tier
+	BulkWholeQuantity = "whole"
+	//Every band of units is priced at its own tier, ie: with 3+ and 10+ tiers, units 3 to 9 are priced at the first tier
+	//and the 10th onwards at the second one
+	BulkGraduated = "graduated"
+)
+
+//A Bulk Rule discounts an item once a number of units is bought
+//It can be a single TriggerAmount and DiscountPercentage or an ordered list of Tiers priced according to its Mode,
+//whole quantity by default, but not both
+//A Measured rule discounts an item sold by weight or measure instead, its thresholds are units of measure of the item,
+//ie: a triggerAmount of 2 for an item sold by kg is reached once 2 kg are weighed. No other rule can be applied to them
+//Instead of an AffectedItem, the rule can select its items by category or tags, it's then applied to every one of them
+//on its own, see ItemSelector
+type BulkRule struct {
+	RuleName           string `yaml:"ruleName"`
+	AffectedItem       string `yaml:"affectedItem"`
+	ItemSelector       `yaml:",inline"`
+	TriggerAmount      int        `yaml:"triggerAmount"`
+	DiscountPercentage int        `yaml:"discountPercentage"`
+	Tiers              []BulkTier `yaml:"tiers"`
+	Mode               string     `yaml:"mode"`
+	Measured           bool       `yaml:"measured"`
+	RuleOptions        `yaml:",inline"`
+}
+
+//A tier of a Bulk Rule, which applies from Threshold units onwards
+//The units of the tier are priced either at DiscountPercentage off the item price or at a fixed UnitPrice, expressed
+//in the currency of the item definitions
+type BulkTier struct {
+	Threshold          int           `yaml:"threshold"`
+	DiscountPercentage int           `yaml:"discountPercentage"`
+	UnitPrice          money.Decimal `yaml:"unitPrice"`
+}
+
+//Returns the tiers of the rule sorted by threshold, a rule with a single TriggerAmount has a single tier
+func (r BulkRule) PriceTiers() []BulkTier {
+	if len(r.Tiers) == 0 {
+		return []BulkTier{{Threshold: r.TriggerAmount, DiscountPercentage: r.DiscountPercentage}}
+	}
+	return r.Tiers
+}
+
+//Returns the price of the given units of an item priced at the tier
+//Percentages are applied to the price of all the units at once, so the result is as exact as possible
+func (t BulkTier) PriceOf(itemPrice money.Money, units int) money.Subtotal {
+	if t.UnitPrice != 0 {
+		return money.SubtotalFromDecimal(t.UnitPrice, itemPrice.Currency).Times(units)
+	}
+	return itemPrice.Times(units).Percent(100 - t.DiscountPercentage)
+}
+
+//Returns the price of the given quantity of an item sold by weight or measure priced at the tier
+func (t BulkTier) PriceOfMeasure(itemPrice money.Money, quantity money.Decimal) money.Subtotal {
+	if t.UnitPrice != 0 {
+		return money.SubtotalFromDecimal(t.UnitPrice, itemPrice.Currency).TimesDecimal(quantity)
+	}
+	return itemPrice.Subtotal().TimesDecimal(quantity).Percent(100 - t.DiscountPercentage)
+}
+
+//Validates the given BulkRule, returns an error otherwise
+func (r BulkRule) validateBulkRuleInput() error {
+
+	if err := r.validateSelector(r.AffectedItem); err != nil {
+		return err
+	}
+
+	if r.Measured && (r.Stackable || r.ExclusiveGroup != "") {
+		return errors.New("a measured rule can't be stackable nor belong to an exclusive group")
+	}
+
+	if len(r.Tiers) > 0 {
+		if err := r.validateTiers(); err != nil {
+			return err
+		}
+		return r.validateSchedule()
+	}
+
+	if r.Mode != "" {
+		return errors.New("the mode can only be set along with tiers")
+	}
+
+	if r.TriggerAmount == 0 {
+		return errors.New("the discount trigger amount can't be zero")
+	}
+
+	if r.DiscountPercentage < 0 || r.DiscountPercentage >= 100 {
+		return errors.New("the discount percentage can't be lower than 0% or higher or equals than 100%")
+	}
+
+	return r.validateSchedule()
+}
+
+//The tiers must be sorted by threshold without repeating any, and every tier must be cheaper than the previous one of
+//the same kind. Tiers of different kinds can only be compared once the item price is known, see validateTierPrices
+func (r BulkRule) validateTiers() error {
+
+	if r.TriggerAmount != 0 || r.DiscountPercentage != 0 {
+		return errors.New("the trigger amount and discount percentage can't be set along with tiers")
+	}
+
+	if r.Mode != "" && r.Mode != BulkWholeQuantity && r.Mode != BulkGraduated {
+		return fmt.Errorf("unknown mode '%s', expected one of %s or %s", r.Mode, BulkWholeQuantity, BulkGraduated)
+	}
+
+	var previous, previousPercentage, previousPrice *BulkTier
+	for i := range r.Tiers {
+		t := &r.Tiers[i]
+		if t.Threshold <= 0 {
+			return fmt.Errorf("the threshold of the tier %d can't be zero or below", i+1)
+		}
+		if previous != nil && t.Threshold <= previous.Threshold {
+			return fmt.Errorf("the tier starting at %d overlaps with the tier starting at %d, the tiers must be sorted by threshold",
+				t.Threshold, previous.Threshold)
+		}
+		if t.DiscountPercentage != 0 && t.UnitPrice != 0 {
+			return fmt.Errorf("the tier starting at %d can't have both a discount percentage and a unit price", t.Threshold)
+		}
+		if t.DiscountPercentage < 0 || t.DiscountPercentage >= 100 {
+			return fmt.Errorf("the discount percentage of the tier starting at %d can't be lower than 0%% or higher or equals than 100%%", t.Threshold)
+		}
+		if t.UnitPrice < 0 {
+			return fmt.Errorf("the unit price of the tier starting at %d can't be below zero", t.Threshold)
+		}
+		if t.DiscountPercentage == 0 && t.UnitPrice == 0 {
+			return fmt.Errorf("the tier starting at %d must have either a discount percentage or a unit price", t.Threshold)
+		}
+
+		if t.UnitPrice != 0 {
+			if previousPrice != nil && t.UnitPrice >= previousPrice.UnitPrice {
+				return fmt.Errorf("the tier starting at %d must be cheaper than the tier starting at %d", t.Threshold, previousPrice.Threshold)
+			}
+			previousPrice = t
+		} else {
+			if previousPercentage != nil && t.DiscountPercentage <= previousPercentage.DiscountPercentage {
+				return fmt.Errorf("the tier starting at %d must be cheaper than the tier starting at %d", t.Threshold, previousPercentage.Threshold)
+			}
+			previousPercentage = t
+		}
+		previous = t
+	}
+
+	return nil
+}
+
+//Validates the unit prices of the tiers against the price of the affected item, returns an error if they can't be
+//expressed in the currency of the items, are not cheaper than the item or than the previous tier
+func (r BulkRule) validateTierPrices(items ConfiguredItems) error {
+	price := items[r.AffectedItem].Price
+	cheapest := price.Subtotal()
+	for i, t := range r.Tiers {
+		if t.UnitPrice != 0 {
+			if _, err := money.FromDecimal(t.UnitPrice, items.Currency()); err != nil {
+				return fmt.Errorf("the unit price of the tier starting at %d is not valid: %v", t.Threshold, err)
+			}
+		}
+		tierPrice := t.PriceOf(price, 1)
+		if tierPrice.Micros >= cheapest.Micros {
+			if i == 0 {
+				return fmt.Errorf("the tier starting at %d must be cheaper than the item price %s", t.Threshold, price)
+			}
+			return fmt.Errorf("the tier starting at %d must be cheaper than the tier starting at %d", t.Threshold, r.Tiers[i-1].Threshold)
+		}
+		cheapest = tierPrice
+	}
+	return nil
+}
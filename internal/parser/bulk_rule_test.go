@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseRulesFileBulkTiers(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  bulkRules:
+  - affectedItem: TSHIRT
+    ruleName: Tiered Rule
+    mode: graduated
+    tiers:
+    - threshold: 3
+      discountPercentage: 5
+    - threshold: 10
+      unitPrice: 17.00
+`), 0644)
+	unitPrice, _ := money.ParseDecimal("17")
+
+	//ACT
+	rules, err := RuleParser{Strict: true}.ParseRulesFile(path)
+
+	//ASSERT
+	if err != nil || len(rules.BulkRules) != 1 {
+		t.Fatalf("The tiered bulk rule should have been parsed, got: %+v (%v)", rules.BulkRules, err)
+	}
+	if r := rules.BulkRules[0]; r.Mode != BulkGraduated || len(r.Tiers) != 2 || r.Tiers[1] != (BulkTier{Threshold: 10, UnitPrice: unitPrice}) {
+		t.Errorf("The tiered bulk rule doesn't match, got: %+v", r)
+	}
+
+}
+
+func TestValidateBulkRuleTiers(t *testing.T) {
+
+	//ARRANGE
+	cases := map[string]BulkRule{
+		"the tier starting at 3 overlaps with the tier starting at 3, the tiers must be sorted by threshold": {
+			Tiers: []BulkTier{{Threshold: 3, DiscountPercentage: 5}, {Threshold: 3, DiscountPercentage: 10}}},
+		"the tier starting at 3 overlaps with the tier starting at 10, the tiers must be sorted by threshold": {
+			Tiers: []BulkTier{{Threshold: 10, DiscountPercentage: 10}, {Threshold: 3, DiscountPercentage: 5}}},
+		"the tier starting at 10 must be cheaper than the tier starting at 3": {
+			Tiers: []BulkTier{{Threshold: 3, DiscountPercentage: 10}, {Threshold: 10, DiscountPercentage: 5}}},
+		"the tier starting at 10 must have either a discount percentage or a unit price": {
+			Tiers: []BulkTier{{Threshold: 3, DiscountPercentage: 5}, {Threshold: 10}}},
+		"the trigger amount and discount percentage can't be set along with tiers": {
+			TriggerAmount: 3, Tiers: []BulkTier{{Threshold: 3, DiscountPercentage: 5}}},
+		"unknown mode 'banded', expected one of whole or graduated": {
+			Mode: "banded", Tiers: []BulkTier{{Threshold: 3, DiscountPercentage: 5}}},
+	}
+
+	for expected, rule := range cases {
+		rule.AffectedItem = "TSHIRT"
+
+		//ACT
+		err := rule.validateBulkRuleInput()
+
+		//ASSERT
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected the error %s, got: %v", expected, err)
+		}
+	}
+
+}
+
+func TestValidateRulesBulkTierPrices(t *testing.T) {
+
+	//ARRANGE
+	items, _ := ItemsParser{}.ParseItemsDefinitions("../../configs/item_definitions.yaml")
+	unitPrice, _ := money.ParseDecimal("19.50")
+	rules := Rules{BulkRules: []BulkRule{{RuleName: "Tiered Rule", AffectedItem: "TSHIRT",
+		Tiers: []BulkTier{{Threshold: 3, DiscountPercentage: 5}, {Threshold: 10, UnitPrice: unitPrice}}}}}
+	expected := "the rule Tiered Rule is not valid: the tier starting at 10 must be cheaper than the tier starting at 3"
+
+	//ACT
+	err := ValidateRules(rules, items)
+
+	//ASSERT
+	if configErr, ok := err.(*ConfigError); !ok || len(configErr.Problems) != 1 || configErr.Problems[0].Message != expected {
+		t.Errorf("Expected the problem %s, got: %v", expected, err)
+	}
+
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dagozba/golangsmallshop/internal/money"
+)
+
+//Multi item combo sold at a fixed BundlePrice or at DiscountPercentage off the price of its components,
+//ie: a T-shirt and a mug for 25.00. Only one of BundlePrice and DiscountPercentage can be set
+//The bundle price is expressed in the currency of the item definitions
+type BundleRule struct {
+	RuleName           string            `yaml:"ruleName"`
+	Components         []BundleComponent `yaml:"components"`
+	BundlePrice        money.Decimal     `yaml:"bundlePrice"`
+	DiscountPercentage int               `yaml:"discountPercentage"`
+	RuleOptions        `yaml:",inline"`
+}
+
+//An item of a bundle and the number of units of it the bundle contains
+type BundleComponent struct {
+	Item     string `yaml:"item"`
+	Quantity int    `yaml:"quantity"`
+}
+
+//Returns the ids of the items of the bundle
+func (r BundleRule) Items() []string {
+	var items []string
+	for _, c := range r.Components {
+		items = append(items, c.Item)
+	}
+	return items
+}
+
+//Validates the given BundleRule, returns an error otherwise
+func (r BundleRule) validateBundleRuleInput() error {
+
+	units := 0
+	items := make(map[string]bool)
+	for _, c := range r.Components {
+		if c.Item == "" {
+			return errors.New("the item of a component can't be nil")
+		}
+		if c.Quantity <= 0 {
+			return fmt.Errorf("the quantity of the item %s can't be zero or below", c.Item)
+		}
+		if items[c.Item] {
+			return fmt.Errorf("the item %s is included more than once", c.Item)
+		}
+		items[c.Item] = true
+		units += c.Quantity
+	}
+
+	if units < 2 {
+		return errors.New("a bundle must contain at least 2 units")
+	}
+
+	if r.BundlePrice < 0 {
+		return errors.New("the bundle price can't be below zero")
+	}
+
+	if r.DiscountPercentage < 0 || r.DiscountPercentage > 100 {
+		return errors.New("the discount percentage must be higher than 0% and lower or equals than 100%")
+	}
+
+	if r.BundlePrice == 0 && r.DiscountPercentage == 0 {
+		return errors.New("either a bundle price or a discount percentage must be set")
+	}
+
+	if r.BundlePrice != 0 && r.DiscountPercentage != 0 {
+		return errors.New("a bundle price and a discount percentage can't be set at the same time")
+	}
+
+	return r.validateSchedule()
+}
+
+//Validates the bundle price against the price of its components, returns an error if it can't be expressed in the
+//currency of the items or if the bundle would be more expensive than buying its components. A component with variants
+//is priced at its cheapest variant, so the bundle is never more expensive than any of the components it can be made of
+func (r BundleRule) validateBundlePrice(items ConfiguredItems) error {
+	if r.BundlePrice == 0 {
+		return nil
+	}
+
+	price, err := money.FromDecimal(r.BundlePrice, items.Currency())
+	if err != nil {
+		return fmt.Errorf("the bundle price is not valid: %v", err)
+	}
+
+	gross := money.Zero(items.Currency())
+	for _, c := range r.Components {
+		gross = gross.Add(items.cheapestPrice(c.Item).Times(c.Quantity))
+	}
+	if price.Subtotal().Micros > gross.Micros {
+		return fmt.Errorf("the bundle price %s is higher than the price of its components %s", price, gross.Round(money.HalfUp))
+	}
+	return nil
+}
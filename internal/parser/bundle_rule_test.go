@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseRulesFileBundle(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  bundleRules:
+  - ruleName: T-shirt + Mug
+    components:
+    - item: TSHIRT
+      quantity: 1
+    - item: MUG
+      quantity: 1
+    bundlePrice: 25.00
+`), 0644)
+
+	//ACT
+	rules, err := RuleParser{Strict: true}.ParseRulesFile(path)
+
+	//ASSERT
+	if err != nil || len(rules.BundleRules) != 1 {
+		t.Fatalf("The bundle rule should have been parsed, got: %+v (%v)", rules.BundleRules, err)
+	}
+	if b := rules.BundleRules[0]; b.BundlePrice != money.DecimalFromInt(25) || len(b.Components) != 2 || b.Components[1] != (BundleComponent{Item: "MUG", Quantity: 1}) {
+		t.Errorf("The bundle rule doesn't match, got: %+v", b)
+	}
+
+}
+
+func TestParseRulesFileBundleNotValid(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  bundleRules:
+  - ruleName: T-shirt + Mug
+    components:
+    - item: TSHIRT
+      quantity: 1
+    - item: MUG
+      quantity: 1
+    bundlePrice: 25.00
+    discountPercentage: 10
+`), 0644)
+	expected := ConfigProblem{File: path, Line: 3,
+		Message: "the rule T-shirt + Mug is not valid: a bundle price and a discount percentage can't be set at the same time"}
+
+	//ACT
+	_, err := RuleParser{Strict: true}.ParseRulesFile(path)
+
+	//ASSERT
+	if configErr, ok := err.(*ConfigError); !ok || len(configErr.Problems) != 1 || configErr.Problems[0] != expected {
+		t.Errorf("Expected the problem %s, got: %v", expected, err)
+	}
+
+}
+
+func TestValidateRulesBundlePriceVariants(t *testing.T) {
+
+	//ARRANGE
+	items := ConfiguredItems{
+		"TSHIRT":   {Name: "Company T-Shirt", Price: money.New(2000, "EUR"), VariantAxes: []string{"size"}, Variants: []string{"TSHIRT-M", "TSHIRT-S"}},
+		"TSHIRT-M": {Name: "Company T-Shirt (M)", Price: money.New(2000, "EUR"), Parent: "TSHIRT"},
+		"TSHIRT-S": {Name: "Company T-Shirt (S)", Price: money.New(1000, "EUR"), Parent: "TSHIRT"},
+		"MUG":      {Name: "Company Coffee Mug", Price: money.New(750, "EUR")},
+	}
+	rules := Rules{BundleRules: []BundleRule{{RuleName: "Pack", BundlePrice: money.DecimalFromInt(25),
+		Components: []BundleComponent{{Item: "TSHIRT", Quantity: 1}, {Item: "MUG", Quantity: 1}}}}}
+	expected := "the rule Pack is not valid: the bundle price 25.00 EUR is higher than the price of its components 17.50 EUR"
+
+	//ACT
+	err := ValidateRules(rules, items)
+
+	//ASSERT
+	if configErr, ok := err.(*ConfigError); !ok || len(configErr.Problems) != 1 || configErr.Problems[0].Message != expected {
+		t.Errorf("The bundle should be priced against the cheapest T-shirt, expected the problem %s, got: %v", expected, err)
+	}
+
+}
+
+func TestValidateRulesBundlePrice(t *testing.T) {
+
+	//ARRANGE
+	items, _ := ItemsParser{}.ParseItemsDefinitions("../../configs/item_definitions.yaml")
+	tooPrecise, _ := money.ParseDecimal("25.005")
+	cases := map[money.Decimal]string{
+		money.DecimalFromInt(25): "",
+		money.DecimalFromInt(30): "the rule Pack is not valid: the bundle price 30.00 EUR is higher than the price of its components 27.50 EUR",
+		tooPrecise:               "the rule Pack is not valid: the bundle price is not valid: 25.005 can't be expressed in EUR without rounding",
+	}
+
+	for price, expected := range cases {
+		rules := Rules{BundleRules: []BundleRule{{RuleName: "Pack", BundlePrice: price,
+			Components: []BundleComponent{{Item: "TSHIRT", Quantity: 1}, {Item: "MUG", Quantity: 1}}}}}
+
+		//ACT
+		err := ValidateRules(rules, items)
+
+		//ASSERT
+		if expected == "" && err != nil {
+			t.Errorf("The bundle price %s should be valid, got: %v", price, err)
+		}
+		if configErr, ok := err.(*ConfigError); expected != "" && (!ok || configErr.Problems[0].Message != expected) {
+			t.Errorf("Expected the problem %s, got: %v", expected, err)
+		}
+	}
+
+}
@@ -0,0 +1,47 @@
+package parser
+
+import "errors"
+
+//Cross item promotion: buying TriggerQuantity units of the TriggerItem gives RewardQuantity units of the RewardItem at
+//RewardDiscountPercentage off, ie: buy a T-shirt, get a mug free (100%) or buy 2 mugs, get a voucher at 50% off
+//MaxApplications caps the number of times it can be applied to a basket, 0 means there's no cap
+type BuyXGetYRule struct {
+	RuleName                 string `yaml:"ruleName"`
+	TriggerItem              string `yaml:"triggerItem"`
+	TriggerQuantity          int    `yaml:"triggerQuantity"`
+	RewardItem               string `yaml:"rewardItem"`
+	RewardQuantity           int    `yaml:"rewardQuantity"`
+	RewardDiscountPercentage int    `yaml:"rewardDiscountPercentage"`
+	MaxApplications          int    `yaml:"maxApplications"`
+	RuleOptions              `yaml:",inline"`
+}
+
+//Validates the given BuyXGetYRule, returns an error otherwise
+func (r BuyXGetYRule) validateBuyXGetYRuleInput() error {
+
+	if r.TriggerItem == "" {
+		return errors.New("the trigger item can't be nil")
+	}
+
+	if r.RewardItem == "" {
+		return errors.New("the reward item can't be nil")
+	}
+
+	if r.TriggerQuantity <= 0 {
+		return errors.New("the trigger quantity can't be zero or below")
+	}
+
+	if r.RewardQuantity <= 0 {
+		return errors.New("the reward quantity can't be zero or below")
+	}
+
+	if r.RewardDiscountPercentage <= 0 || r.RewardDiscountPercentage > 100 {
+		return errors.New("the reward discount percentage must be higher than 0% and lower or equals than 100%")
+	}
+
+	if r.MaxApplications < 0 {
+		return errors.New("the maximum number of applications can't be below zero")
+	}
+
+	return r.validateSchedule()
+}
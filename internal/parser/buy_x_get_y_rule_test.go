@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRulesFileBuyXGetY(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  buyXGetYRules:
+  - ruleName: Free Mug
+    triggerItem: TSHIRT
+    triggerQuantity: 1
+    rewardItem: MUG
+    rewardQuantity: 1
+    rewardDiscountPercentage: 100
+    maxApplications: 2
+`), 0644)
+	expected := BuyXGetYRule{RuleName: "Free Mug", TriggerItem: "TSHIRT", TriggerQuantity: 1, RewardItem: "MUG", RewardQuantity: 1,
+		RewardDiscountPercentage: 100, MaxApplications: 2}
+
+	//ACT
+	rules, err := RuleParser{Strict: true}.ParseRulesFile(path)
+
+	//ASSERT
+	if err != nil || len(rules.BuyXGetYRules) != 1 || !reflect.DeepEqual(rules.BuyXGetYRules[0], expected) {
+		t.Errorf("The Buy X get Y rule should have been parsed, expected: %+v, got: %+v (%v)", expected, rules.BuyXGetYRules, err)
+	}
+
+}
+
+func TestParseRulesFileBuyXGetYNotValid(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  buyXGetYRules:
+  - ruleName: Free Mug
+    triggerItem: TSHIRT
+    triggerQuantity: 1
+    rewardItem: MUG
+    rewardQuantity: 1
+    rewardDiscountPercentage: 120
+`), 0644)
+	expected := ConfigProblem{File: path, Line: 3,
+		Message: "the rule Free Mug is not valid: the reward discount percentage must be higher than 0% and lower or equals than 100%"}
+
+	//ACT
+	_, err := RuleParser{Strict: true}.ParseRulesFile(path)
+
+	//ASSERT
+	if configErr, ok := err.(*ConfigError); !ok || len(configErr.Problems) != 1 || configErr.Problems[0] != expected {
+		t.Errorf("Expected the problem %s, got: %v", expected, err)
+	}
+
+}
+
+func TestValidateRulesBuyXGetYTriggerNotDefined(t *testing.T) {
+
+	//ARRANGE
+	rules := Rules{BuyXGetYRules: []BuyXGetYRule{{RuleName: "Free Mug", TriggerItem: "BOOK", TriggerQuantity: 1, RewardItem: "MUG",
+		RewardQuantity: 1, RewardDiscountPercentage: 100}}}
+	items, _ := ItemsParser{}.ParseItemsDefinitions("../../configs/item_definitions.yaml")
+
+	//ACT
+	err := ValidateRules(rules, items)
+
+	//ASSERT
+	if configErr, ok := err.(*ConfigError); !ok || len(configErr.Problems) != 1 ||
+		configErr.Problems[0].Message != "the rule Free Mug affects the item BOOK, which is not defined in the item definitions" {
+		t.Errorf("The undefined trigger item should have been reported, got: %v", err)
+	}
+
+}
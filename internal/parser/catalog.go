@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"gopkg.in/yaml.v3"
+	"sort"
+	"strings"
+)
+
+//Kinds of value an attribute of an item can have
+const (
+	AttributeText    = "text"
+	AttributeNumber  = "number"
+	AttributeBoolean = "boolean"
+)
+
+//A typed attribute of an item, ie: size: M, weight: 0.350 or organic: true
+//The Kind is decided by how the value is written in the item definitions file, quoted numbers and booleans are text.
+//An attribute that is neither a text, a number nor a boolean has no kind and is rejected when the item is validated
+type Attribute struct {
+	Kind    string
+	Text    string
+	Number  money.Decimal
+	Boolean bool
+}
+
+//Decodes an attribute from a yaml scalar, a value that can't be decoded is left without a kind so it's reported along
+//with the item it belongs to instead of discarding the whole file
+func (a *Attribute) UnmarshalYAML(value *yaml.Node) error {
+	*a = Attribute{}
+	if value.Kind != yaml.ScalarNode {
+		return nil
+	}
+	switch value.ShortTag() {
+	case "!!str":
+		a.Kind, a.Text = AttributeText, value.Value
+	case "!!int", "!!float":
+		if n, err := money.ParseDecimal(value.Value); err == nil {
+			a.Kind, a.Number = AttributeNumber, n
+		}
+	case "!!bool":
+		var b bool
+		if err := value.Decode(&b); err == nil {
+			a.Kind, a.Boolean = AttributeBoolean, b
+		}
+	}
+	return nil
+}
+
+func (a Attribute) String() string {
+	switch a.Kind {
+	case AttributeNumber:
+		return a.Number.String()
+	case AttributeBoolean:
+		return fmt.Sprint(a.Boolean)
+	}
+	return a.Text
+}
+
+//Validates the category, tags and attributes of the item, returns an error otherwise
+func (i ItemDefinition) validateCatalog() error {
+
+	if i.Category != "" {
+		if err := validateCategory(i.Category); err != nil {
+			return err
+		}
+	}
+
+	if err := validateTags(i.Tags); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(i.Attributes))
+	for name := range i.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("the name of an attribute can't be empty")
+		}
+		if i.Attributes[name].Kind == "" {
+			return fmt.Errorf("the attribute %s must be a text, a number or a boolean", name)
+		}
+	}
+	return nil
+}
+
+//A category is a path of subcategories separated by slashes, from the most generic one, ie: merch/apparel/t-shirts
+func validateCategory(category string) error {
+	for _, c := range strings.Split(category, "/") {
+		if c == "" || strings.TrimSpace(c) != c {
+			return fmt.Errorf("the category '%s' must be a path like merch/apparel, without empty names nor surrounding spaces", category)
+		}
+	}
+	return nil
+}
+
+func validateTags(tags []string) error {
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		if strings.TrimSpace(t) == "" {
+			return errors.New("a tag can't be empty")
+		}
+		if seen[t] {
+			return fmt.Errorf("the tag %s is set more than once", t)
+		}
+		seen[t] = true
+	}
+	return nil
+}
+
+//Returns true if the item belongs to the given category or to any of its subcategories, ie: an item in merch/apparel
+//belongs to merch too, but not to merch/app
+func (i ItemDefinition) InCategory(category string) bool {
+	return i.Category == category || strings.HasPrefix(i.Category, category+"/")
+}
+
+//Returns true if the item has every one of the given tags
+func (i ItemDefinition) HasTags(tags []string) bool {
+	for _, t := range tags {
+		found := false
+		for _, it := range i.Tags {
+			if it == t {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
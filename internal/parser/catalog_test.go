@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseItemsDefinitionsCatalog(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "item_definitions.yaml")
+	ioutil.WriteFile(path, []byte(`currency: EUR
+items:
+  TSHIRT:
+    name: Company T-Shirt
+    price: 20.00
+    category: merch/apparel
+    tags: [logo, summer]
+    attributes:
+      size: M
+      weight: 0.180
+      organic: true
+      year: "2026"
+`), 0644)
+
+	//ACT
+	items, err := ItemsParser{Strict: true}.ParseItemsDefinitions(path)
+
+	//ASSERT
+	if err != nil {
+		t.Fatalf("The catalog of the item should have been parsed, got: %v", err)
+	}
+
+	tshirt := items["TSHIRT"]
+	if tshirt.Category != "merch/apparel" || !reflect.DeepEqual(tshirt.Tags, []string{"logo", "summer"}) {
+		t.Errorf("The category and tags should have been parsed, got: %s, %v", tshirt.Category, tshirt.Tags)
+	}
+
+	expected := map[string]Attribute{
+		"size":    {Kind: AttributeText, Text: "M"},
+		"weight":  {Kind: AttributeNumber, Number: money.Decimal(180000)},
+		"organic": {Kind: AttributeBoolean, Boolean: true},
+		"year":    {Kind: AttributeText, Text: "2026"},
+	}
+	if !reflect.DeepEqual(tshirt.Attributes, expected) {
+		t.Errorf("The attributes should have been parsed with their kind, expected: %+v, got: %+v", expected, tshirt.Attributes)
+	}
+
+}
+
+func TestParseItemsDefinitionsCatalogNotValid(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "item_definitions.yaml")
+	ioutil.WriteFile(path, []byte(`currency: EUR
+items:
+  MUG:
+    name: Company Coffee Mug
+    price: 7.50
+    category: merch//kitchen
+  TSHIRT:
+    name: Company T-Shirt
+    price: 20.00
+    tags: [logo, logo]
+  VOUCHER:
+    name: Company Voucher
+    price: 5.00
+    attributes:
+      sizes: [S, M]
+  CAP:
+    name: Company Cap
+    price: 9.00
+    category: merch/apparel
+`), 0644)
+	expected := []string{
+		":3: the item MUG is not valid: the category 'merch//kitchen' must be a path like merch/apparel",
+		":7: the item TSHIRT is not valid: the tag logo is set more than once",
+		":11: the item VOUCHER is not valid: the attribute sizes must be a text, a number or a boolean",
+	}
+
+	//ACT
+	_, strictErr := ItemsParser{Strict: true}.ParseItemsDefinitions(path)
+	items, lenientErr := ItemsParser{}.ParseItemsDefinitions(path)
+
+	//ASSERT
+	for _, problem := range expected {
+		if strictErr == nil || !strings.Contains(strictErr.Error(), problem) {
+			t.Errorf("Expected the problem %s, got: %v", problem, strictErr)
+		}
+	}
+
+	if lenientErr != nil || len(items) != 1 || items["CAP"].Category != "merch/apparel" {
+		t.Errorf("Only the invalid items should have been discarded, got: %+v", items)
+	}
+
+}
+
+func TestItemDefinitionInCategory(t *testing.T) {
+
+	//ARRANGE
+	item := ItemDefinition{Category: "merch/apparel/t-shirts", Tags: []string{"logo", "summer"}}
+	categories := map[string]bool{
+		"merch":                  true,
+		"merch/apparel":          true,
+		"merch/apparel/t-shirts": true,
+		"merch/app":              false,
+		"apparel":                false,
+	}
+
+	for category, expected := range categories {
+		//ACT
+		in := item.InCategory(category)
+
+		//ASSERT
+		if in != expected {
+			t.Errorf("The item should be in the category %s: %t, got: %t", category, expected, in)
+		}
+	}
+
+	if !item.HasTags([]string{"summer", "logo"}) || item.HasTags([]string{"logo", "sale"}) {
+		t.Errorf("The item should only have every tag it's tagged with")
+	}
+
+}
@@ -0,0 +1,162 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//A problem found in a configuration file, Line is 0 when the problem is not related to a specific line
+type ConfigProblem struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (p ConfigProblem) String() string {
+	switch {
+	case p.File == "":
+		return p.Message
+	case p.Line == 0:
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	default:
+		return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+	}
+}
+
+//Error returned by the parsers in Strict mode, it contains every problem found instead of just the first one
+type ConfigError struct {
+	Problems []ConfigProblem
+}
+
+func (e *ConfigError) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0].String()
+	}
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("%d problems found in the configuration:", len(e.Problems)))
+	for _, p := range e.Problems {
+		lines = append(lines, p.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+//Sorts the problems by file and line, as the items are checked in no particular order
+func sortProblems(problems []ConfigProblem) {
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].File != problems[j].File {
+			return problems[i].File < problems[j].File
+		}
+		return problems[i].Line < problems[j].Line
+	})
+}
+
+//Returns nil if there are no problems, a ConfigError with them otherwise
+func newConfigError(problems []ConfigProblem) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ConfigError{Problems: problems}
+}
+
+//Appends the problems of the given error, which is added as a problem of the file itself if it's not a ConfigError
+func appendProblems(problems []ConfigProblem, file string, err error) []ConfigProblem {
+	if err == nil {
+		return problems
+	}
+	if configErr, ok := err.(*ConfigError); ok {
+		return append(problems, configErr.Problems...)
+	}
+	return append(problems, ConfigProblem{File: file, Message: err.Error()})
+}
+
+var (
+	yamlLineRegexp         = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+	yamlUnknownFieldRegexp = regexp.MustCompile(`^field (\S+) not found in type .*$`)
+)
+
+//Turns an error returned by the yaml library into problems of the given file, keeping the line it refers to
+func yamlProblems(file string, err error) []ConfigProblem {
+	messages := []string{err.Error()}
+	if typeErr, ok := err.(*yaml.TypeError); ok {
+		messages = typeErr.Errors
+	}
+	problems := make([]ConfigProblem, 0, len(messages))
+	for _, m := range messages {
+		p := ConfigProblem{File: file, Message: m}
+		if match := yamlLineRegexp.FindStringSubmatch(m); match != nil {
+			p.Line, _ = strconv.Atoi(match[1])
+			p.Message = match[2]
+		}
+		p.Message = yamlUnknownFieldRegexp.ReplaceAllString(p.Message, "unknown field $1")
+		problems = append(problems, p)
+	}
+	return problems
+}
+
+//Parses the yaml document into a node tree, used to know the line of every entry, and decodes it into out rejecting
+//unknown fields
+//A syntax error is returned as the only problem, as nothing else can be checked in that case
+func decodeStrict(file string, d []byte, out interface{}) (*yaml.Node, []ConfigProblem) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(d, &doc); err != nil {
+		return nil, yamlProblems(file, err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(d))
+	dec.KnownFields(true)
+	if err := dec.Decode(out); err != nil && err != io.EOF {
+		return &doc, yamlProblems(file, err)
+	}
+	return &doc, nil
+}
+
+//Returns the node of the value found following the given keys from the root of the document, nil if there's none
+func findNode(doc *yaml.Node, keys ...string) *yaml.Node {
+	if doc == nil || len(doc.Content) == 0 {
+		return nil
+	}
+	n := doc.Content[0]
+	for _, key := range keys {
+		if n.Kind != yaml.MappingNode {
+			return nil
+		}
+		var value *yaml.Node
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i].Value == key {
+				value = n.Content[i+1]
+			}
+		}
+		if value == nil {
+			return nil
+		}
+		n = value
+	}
+	return n
+}
+
+//Returns the line of every entry of the sequence found following the given keys
+func sequenceLines(doc *yaml.Node, keys ...string) []int {
+	var lines []int
+	if n := findNode(doc, keys...); n != nil && n.Kind == yaml.SequenceNode {
+		for _, entry := range n.Content {
+			lines = append(lines, entry.Line)
+		}
+	}
+	return lines
+}
+
+//Returns the line of every key of the mapping found following the given keys
+func mappingKeyLines(doc *yaml.Node, keys ...string) map[string]int {
+	lines := make(map[string]int)
+	if n := findNode(doc, keys...); n != nil && n.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			lines[n.Content[i].Value] = n.Content[i].Line
+		}
+	}
+	return lines
+}
@@ -0,0 +1,257 @@
+package parser
+
+import (
+	"fmt"
+)
+
+//A rule of any kind along with where it was defined
+//item is the item whose price is changed by the rule, if it's the only rule allowed to change it, references are all
+//the items the rule depends on and counted the ones whose units it counts, the rest of the references are measured
+//items for a measured rule or the ones excluded from a basket rule. validateItems checks the rule against the item
+//definitions, if it needs to
+//A rule selecting its items by category or tags has an entry for every item selected, all of them with the same rule
+//number, or a single entry without any item if it doesn't select any, see ItemSelector
+type ruleEntry struct {
+	name          string
+	rule          int
+	item          string
+	references    []string
+	counted       []string
+	measured      bool
+	selector      ItemSelector
+	validate      func() error
+	validateItems func(items ConfiguredItems) error
+	options       RuleOptions
+	file          string
+	line          int
+}
+
+func (r ruleEntry) problem(format string, args ...interface{}) ConfigProblem {
+	return ConfigProblem{File: r.file, Line: r.line, Message: fmt.Sprintf(format, args...)}
+}
+
+//Returns where the rule was defined, to be appended to a message, or nothing if it's not known
+func (r ruleEntry) location() string {
+	return location(r.file, r.line)
+}
+
+func location(file string, line int) string {
+	if line == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%s:%d)", file, line)
+}
+
+//Returns every rule in the order they are executed: bundle rules, Buy X get Y rules, bulk rules, NxM rules, mix and match
+//rules and basket rules
+//Bundle, mix and match and basket rules don't affect any item on their own, as the units bundled or counted together
+//are not seen by the rest of the rules and the basket rules are executed once all the items have been priced
+//The items of the groups of the mix and match rules are checked against the item definitions with the groups, see
+//groupItemProblems
+func (r Rules) entries() []ruleEntry {
+	source := r.source
+	if source == nil {
+		source = &rulesSource{}
+	}
+	var entries []ruleEntry
+	rule := 0
+	for i, v := range r.BundleRules {
+		rule++
+		entries = append(entries, ruleEntry{name: v.RuleName, rule: rule, options: v.RuleOptions, references: uniqueItems(v.Items()...),
+			counted: uniqueItems(v.Items()...), validate: v.validateBundleRuleInput, validateItems: v.validateBundlePrice, file: source.file, line: lineAt(source.bundleLines, i)})
+	}
+	for i, v := range r.BuyXGetYRules {
+		rule++
+		entries = append(entries, ruleEntry{name: v.RuleName, rule: rule, options: v.RuleOptions, item: v.RewardItem, references: uniqueItems(v.TriggerItem, v.RewardItem),
+			counted: uniqueItems(v.TriggerItem, v.RewardItem), validate: v.validateBuyXGetYRuleInput, file: source.file, line: lineAt(source.buyXGetYLines, i)})
+	}
+	for i, v := range r.BulkRules {
+		rule++
+		for _, item := range entryItems(v.ItemSelector, v.AffectedItem) {
+			affected := v
+			affected.AffectedItem = item
+			e := ruleEntry{name: v.RuleName, rule: rule, options: v.RuleOptions, item: item, references: referencesOf(item), measured: v.Measured,
+				selector: v.ItemSelector, validate: v.validateBulkRuleInput, validateItems: affected.validateTierPrices, file: source.file, line: lineAt(source.bulkLines, i)}
+			if !v.Measured {
+				e.counted = e.references
+			}
+			entries = append(entries, e)
+		}
+	}
+	for i, v := range r.NxmRules {
+		rule++
+		for _, item := range entryItems(v.ItemSelector, v.AffectedItem) {
+			entries = append(entries, ruleEntry{name: v.RuleName, rule: rule, options: v.RuleOptions, item: item, references: referencesOf(item),
+				counted: referencesOf(item), selector: v.ItemSelector, validate: v.validateNxMRuleInput, file: source.file, line: lineAt(source.nxmLines, i)})
+		}
+	}
+	for i, v := range r.MixAndMatchRules {
+		rule++
+		entries = append(entries, ruleEntry{name: v.RuleName, rule: rule, options: v.RuleOptions, counted: r.MixAndMatchItems(v), selector: v.ItemSelector,
+			validate: v.validateMixAndMatchRuleInput, file: source.file, line: lineAt(source.mixAndMatchLines, i)})
+	}
+	for i, v := range r.BasketRules {
+		rule++
+		entries = append(entries, ruleEntry{name: v.RuleName, rule: rule, options: v.RuleOptions, references: v.ExcludedItems,
+			validate: v.validateBasketRuleInput, validateItems: v.validateAmounts, file: source.file, line: lineAt(source.basketLines, i)})
+	}
+	return entries
+}
+
+//Returns the items a rule has an entry for: the affected item or the items selected, a single empty item if it
+//doesn't select any, so the rule is still validated
+func entryItems(s ItemSelector, affectedItem string) []string {
+	items := s.AffectedItems(affectedItem)
+	if len(items) == 0 {
+		return []string{""}
+	}
+	return items
+}
+
+func referencesOf(item string) []string {
+	if item == "" {
+		return nil
+	}
+	return []string{item}
+}
+
+func uniqueItems(items ...string) []string {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, i := range items {
+		if !seen[i] {
+			seen[i] = true
+			unique = append(unique, i)
+		}
+	}
+	return unique
+}
+
+func lineAt(lines []int, i int) int {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return 0
+}
+
+//Returns a problem for every rule named like a previous one and for every rule affecting an item that is already
+//affected by a previous one with the same priority, unless any of them is stackable, as it wouldn't be clear which one
+//of them should be applied first
+func (r Rules) conflicts() []ConfigProblem {
+	var problems []ConfigProblem
+	names := make(map[string]ruleEntry)
+	items := make(map[string][]ruleEntry)
+	for _, e := range r.entries() {
+		if other, exs := names[e.name]; exs && e.name != "" && other.rule != e.rule {
+			problems = append(problems, e.problem("the rule name %s is already used by a previous rule%s", e.name, other.location()))
+		} else {
+			names[e.name] = e
+		}
+		if e.item == "" || e.options.Stackable {
+			continue
+		}
+		for _, other := range items[e.item] {
+			if other.options.Priority == e.options.Priority {
+				problems = append(problems, e.problem("the rule %s affects the item %s, which is already affected by the rule %s%s", e.name, e.item, other.name, other.location()))
+				break
+			}
+		}
+		items[e.item] = append(items[e.item], e)
+	}
+	return problems
+}
+
+//Returns a problem for every rule affecting a variant of an item already affected by a previous rule with the same
+//priority, or affecting an item with variants one of which is already affected, unless any of them is stackable, as
+//both of them would price the units of the variant
+func (r Rules) variantConflicts(items ConfiguredItems) []ConfigProblem {
+	var problems []ConfigProblem
+	owners := make(map[string][]ruleEntry)
+	for _, e := range r.entries() {
+		if e.item == "" || e.options.Stackable {
+			continue
+		}
+		reported := false
+		for _, sold := range items.ItemsOf(e.item) {
+			for _, other := range owners[sold] {
+				if !reported && other.item != e.item && other.options.Priority == e.options.Priority {
+					problems = append(problems, e.problem("the rule %s affects the variant %s of the item %s, which is already affected by the rule %s%s",
+						e.name, sold, items[sold].Parent, other.name, other.location()))
+					reported = true
+				}
+			}
+			owners[sold] = append(owners[sold], e)
+		}
+	}
+	return problems
+}
+
+//Checks the rules don't conflict with each other, only affect configured items and their prices are valid for them
+//and the coupons are linked to defined rules, the items of the item groups must be defined too. The items sold by weight or measure can only be affected by measured
+//rules and the measured rules can only affect them. The rules selecting their items by category or tags are checked
+//against the items they select, and must select at least one. Every problem found is returned in a ConfigError
+func ValidateRules(r Rules, items ConfiguredItems) error {
+	r = r.ResolveSelectors(items)
+	problems := append(r.conflicts(), r.couponProblems()...)
+	problems = append(problems, r.groupProblems()...)
+	problems = append(problems, r.groupItemProblems(items)...)
+	problems = append(problems, r.variantConflicts(items)...)
+	for _, e := range r.entries() {
+		if e.selector.IsSet() && len(e.selector.SelectedItems) == 0 {
+			sold := "sold by units"
+			if e.measured {
+				sold = "sold by weight or measure"
+			}
+			problems = append(problems, e.problem("the rule %s doesn't select any item, there's no item %s %s", e.name, sold, e.selector))
+			continue
+		}
+		defined := true
+		for _, item := range e.references {
+			if _, exs := items[item]; !exs {
+				defined = false
+				problems = append(problems, e.problem("the rule %s affects the item %s, which is not defined in the item definitions", e.name, item))
+			}
+		}
+		if !defined {
+			continue
+		}
+		for _, item := range e.counted {
+			if _, exs := items[item]; exs && items[item].IsMeasured() {
+				problems = append(problems, e.problem("the rule %s counts the units of the item %s, which is sold by %s, only measured bulk rules can affect it",
+					e.name, item, items[item].Unit))
+			}
+		}
+		if e.measured && !items[e.item].IsMeasured() {
+			problems = append(problems, e.problem("the rule %s is measured but the item %s is not sold by weight or measure", e.name, e.item))
+			continue
+		}
+		if e.validateItems == nil {
+			continue
+		}
+		if err := e.validateItems(items); err != nil {
+			problems = append(problems, e.problem("the rule %s is not valid: %v", e.name, err))
+		}
+	}
+	return newConfigError(problems)
+}
+
+//Parses both configuration files in Strict mode and checks the rules only affect defined items, the same way the server
+//does when it loads them. Every problem found in any of the files is returned in a single ConfigError
+//The rules are returned with their selectors resolved against the items, see Rules.ResolveSelectors
+func ValidateConfig(rulesPath string, itemsPath string) (Rules, ConfiguredItems, error) {
+	var problems []ConfigProblem
+	items, itemsErr := ItemsParser{Strict: true}.ParseItemsDefinitions(itemsPath)
+	problems = appendProblems(problems, itemsPath, itemsErr)
+	rules, rulesErr := RuleParser{Strict: true}.ParseRulesFile(rulesPath)
+	problems = appendProblems(problems, rulesPath, rulesErr)
+
+	//The rules can only be checked against the items if both files are valid, otherwise every rule affecting an item
+	//discarded because of another problem would be reported as well
+	if itemsErr == nil && rulesErr == nil {
+		problems = appendProblems(problems, rulesPath, ValidateRules(rules, items))
+	}
+	if err := newConfigError(problems); err != nil {
+		return Rules{}, nil, err
+	}
+	return rules.ResolveSelectors(items), items, nil
+}
@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateConfig(t *testing.T) {
+
+	//ARRANGE
+	rulesPath := "../../configs/rules.yaml"
+	itemsPath := "../../configs/item_definitions.yaml"
+
+	//ACT
+	rules, items, err := ValidateConfig(rulesPath, itemsPath)
+
+	//ASSERT
+	if err != nil {
+		t.Errorf("The configuration of the repository should be valid, got: %v", err)
+	}
+	if len(items) != 3 || len(rules.BulkRules)+len(rules.NxmRules) != 2 {
+		t.Errorf("Every item and rule should have been loaded, got: %+v and %+v", items, rules)
+	}
+
+}
+
+func TestValidateConfigRuleOnUndefinedItem(t *testing.T) {
+
+	//ARRANGE
+	rulesPath := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(rulesPath, []byte("rules:\n  nxmRules:\n  - affectedItem: VOUCHER\n    ruleName: \"2x1\"\n    buyN: 2\n    payM: 1\n  - affectedItem: BOOK\n    ruleName: \"Book 2x1\"\n    buyN: 2\n    payM: 1\n"), 0644)
+	expected := ConfigProblem{File: rulesPath, Line: 7, Message: "the rule Book 2x1 affects the item BOOK, which is not defined in the item definitions"}
+
+	//ACT
+	_, _, err := ValidateConfig(rulesPath, "../../configs/item_definitions.yaml")
+
+	//ASSERT
+	if configErr, ok := err.(*ConfigError); !ok || len(configErr.Problems) != 1 || configErr.Problems[0] != expected {
+		t.Errorf("Expected the problem %s, got: %v", expected, err)
+	}
+
+}
+
+func TestValidateConfigReportsEveryFile(t *testing.T) {
+
+	//ARRANGE
+	dir := t.TempDir()
+	rulesPath := filepath.Join(dir, "rules.yaml")
+	itemsPath := filepath.Join(dir, "item_definitions.yaml")
+	ioutil.WriteFile(rulesPath, []byte("rules:\n  nxmRules:\n  - affectedItem: VOUCHER\n    buyN: 0\n    payM: 1\n"), 0644)
+	ioutil.WriteFile(itemsPath, []byte("items:\n  VOUCHER:\n    name: Voucher\n    price: 5.001\n"), 0644)
+
+	//ACT
+	_, _, err := ValidateConfig(rulesPath, itemsPath)
+
+	//ASSERT
+	if configErr, ok := err.(*ConfigError); !ok || len(configErr.Problems) != 2 {
+		t.Errorf("The problems of both files should have been reported, got: %v", err)
+	}
+
+}
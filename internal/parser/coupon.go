@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+//A Coupon is a code that activates a rule only for the baskets it's applied to, ie: SPRING10 for a 10% off basket rule
+//A rule linked to a coupon is never applied to a basket without it, a rule can be linked to several coupons
+//The coupon can only be applied between ValidFrom and ValidUntil, any of them can be left out to leave the window open
+//MaxRedemptions limits the number of baskets the coupon can be applied to, 0 means there's no limit
+//A SingleUsePerBasket coupon can't be applied again to a basket it's already applied to, applying any other coupon
+//again has no effect
+type Coupon struct {
+	Code               string    `yaml:"code"`
+	RuleName           string    `yaml:"ruleName"`
+	ValidFrom          time.Time `yaml:"validFrom"`
+	ValidUntil         time.Time `yaml:"validUntil"`
+	MaxRedemptions     int       `yaml:"maxRedemptions"`
+	SingleUsePerBasket bool      `yaml:"singleUsePerBasket"`
+}
+
+//Validates the given Coupon, returns an error otherwise
+func (c Coupon) validateCouponInput() error {
+
+	if c.Code == "" {
+		return errors.New("the code can't be nil")
+	}
+
+	if c.RuleName == "" {
+		return errors.New("the rule name can't be nil")
+	}
+
+	if c.MaxRedemptions < 0 {
+		return errors.New("the maximum number of redemptions can't be below zero")
+	}
+
+	if !c.ValidFrom.IsZero() && !c.ValidUntil.IsZero() && !c.ValidUntil.After(c.ValidFrom) {
+		return errors.New("the end of the validity window must be after its start")
+	}
+
+	return nil
+}
+
+//Returns the coupons by code
+func (r Rules) CouponsByCode() map[string]Coupon {
+	coupons := make(map[string]Coupon, len(r.Coupons))
+	for _, c := range r.Coupons {
+		coupons[c.Code] = c
+	}
+	return coupons
+}
+
+//Returns a problem for every coupon whose code is already used by a previous coupon or that is linked to a rule that
+//is not defined
+func (r Rules) couponProblems() []ConfigProblem {
+	source := r.source
+	if source == nil {
+		source = &rulesSource{}
+	}
+	rules := make(map[string]bool)
+	for _, e := range r.entries() {
+		rules[e.name] = true
+	}
+
+	var problems []ConfigProblem
+	codes := make(map[string]int)
+	for i, c := range r.Coupons {
+		line := lineAt(source.couponLines, i)
+		if previous, exs := codes[c.Code]; exs {
+			problems = append(problems, ConfigProblem{File: source.file, Line: line,
+				Message: fmt.Sprintf("the coupon code %s is already used by a previous coupon%s", c.Code, location(source.file, previous))})
+		} else {
+			codes[c.Code] = line
+		}
+		if c.RuleName != "" && !rules[c.RuleName] {
+			problems = append(problems, ConfigProblem{File: source.file, Line: line,
+				Message: fmt.Sprintf("the coupon %s is linked to the rule %s, which is not defined", c.Code, c.RuleName)})
+		}
+	}
+	return problems
+}
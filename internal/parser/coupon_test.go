@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseRulesFileCoupons(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  basketRules:
+  - ruleName: Spring
+    threshold: 0
+    discountPercentage: 10
+  coupons:
+  - code: SPRING10
+    ruleName: Spring
+    validFrom: 2026-03-01T00:00:00Z
+    validUntil: 2026-06-01T00:00:00Z
+    maxRedemptions: 100
+    singleUsePerBasket: true
+`), 0644)
+	expected := Coupon{Code: "SPRING10", RuleName: "Spring", ValidFrom: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		ValidUntil: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), MaxRedemptions: 100, SingleUsePerBasket: true}
+
+	//ACT
+	rules, err := RuleParser{Strict: true}.ParseRulesFile(path)
+
+	//ASSERT
+	if err != nil || len(rules.Coupons) != 1 || rules.Coupons[0] != expected {
+		t.Errorf("The coupon should have been parsed, expected: %+v, got: %+v (%v)", expected, rules.Coupons, err)
+	}
+
+	if rules.CouponsByCode()["SPRING10"] != expected {
+		t.Errorf("The coupon should be found by its code, got: %+v", rules.CouponsByCode())
+	}
+
+}
+
+func TestParseRulesFileCouponsNotValid(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  basketRules:
+  - ruleName: Spring
+    threshold: 0
+    discountPercentage: 10
+  coupons:
+  - code: SPRING10
+    ruleName: Spring
+    validFrom: 2026-06-01T00:00:00Z
+    validUntil: 2026-03-01T00:00:00Z
+  - code: SPRING10
+    ruleName: Summer
+`), 0644)
+	expected := []ConfigProblem{
+		{File: path, Line: 7, Message: "the coupon SPRING10 is not valid: the end of the validity window must be after its start"},
+		{File: path, Line: 11, Message: "the coupon code SPRING10 is already used by a previous coupon (" + path + ":7)"},
+		{File: path, Line: 11, Message: "the coupon SPRING10 is linked to the rule Summer, which is not defined"},
+	}
+
+	//ACT
+	_, err := RuleParser{Strict: true}.ParseRulesFile(path)
+
+	//ASSERT
+	configErr, ok := err.(*ConfigError)
+	if !ok || len(configErr.Problems) != len(expected) {
+		t.Fatalf("Every problem should have been reported, expected: %+v, got: %v", expected, err)
+	}
+	for i := range expected {
+		if configErr.Problems[i] != expected[i] {
+			t.Errorf("Expected the problem %s, got: %s", expected[i], configErr.Problems[i])
+		}
+	}
+
+}
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+//Validates the items of an item group, returns an error otherwise
+func validateItemGroup(items []string) error {
+	if len(items) == 0 {
+		return errors.New("an item group must contain at least one item")
+	}
+	seen := make(map[string]bool, len(items))
+	for _, i := range items {
+		if i == "" {
+			return errors.New("an item of the group can't be nil")
+		}
+		if seen[i] {
+			return fmt.Errorf("the item %s is included more than once", i)
+		}
+		seen[i] = true
+	}
+	return nil
+}
+
+//Returns the items of the given group, sorted by id
+func (r Rules) GroupItems(group string) []string {
+	items := append([]string(nil), r.ItemGroups[group]...)
+	sort.Strings(items)
+	return items
+}
+
+//Returns the items counted together by the given mix and match rule: the items it selects or the items of its group
+func (r Rules) MixAndMatchItems(v MixAndMatchRule) []string {
+	if v.ItemSelector.IsSet() {
+		return v.SelectedItems
+	}
+	return r.GroupItems(v.Group)
+}
+
+//Returns the names of the item groups, sorted
+func (r Rules) groupNames() []string {
+	names := make([]string, 0, len(r.ItemGroups))
+	for name := range r.ItemGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//Returns a problem for every item group that is not valid and for every rule using a group that is not defined
+func (r Rules) groupProblems() []ConfigProblem {
+	source := r.source
+	if source == nil {
+		source = &rulesSource{}
+	}
+	var problems []ConfigProblem
+	for _, name := range r.groupNames() {
+		if err := validateItemGroup(r.ItemGroups[name]); err != nil {
+			problems = append(problems, ConfigProblem{File: source.file, Line: source.groupLines[name],
+				Message: fmt.Sprintf("the item group %s is not valid: %v", name, err)})
+		}
+	}
+	for i, v := range r.MixAndMatchRules {
+		if _, exs := r.ItemGroups[v.Group]; !exs && v.Group != "" {
+			problems = append(problems, ConfigProblem{File: source.file, Line: lineAt(source.mixAndMatchLines, i),
+				Message: fmt.Sprintf("the rule %s uses the item group %s, which is not defined", v.RuleName, v.Group)})
+		}
+	}
+	return problems
+}
+
+//Returns a problem for every item of a group that is not defined in the item definitions
+func (r Rules) groupItemProblems(items ConfiguredItems) []ConfigProblem {
+	source := r.source
+	if source == nil {
+		source = &rulesSource{}
+	}
+	var problems []ConfigProblem
+	for _, name := range r.groupNames() {
+		for _, item := range r.ItemGroups[name] {
+			if _, exs := items[item]; !exs {
+				problems = append(problems, ConfigProblem{File: source.file, Line: source.groupLines[name],
+					Message: fmt.Sprintf("the item group %s contains the item %s, which is not defined in the item definitions", name, item)})
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//Selects the items a rule affects by their category or tags instead of by their id
+//AffectedCategory selects the items of the category and of its subcategories and AffectedTags the items with every one
+//of the tags, both can be set together to select the items matching both of them
+//SelectedItems are the ids of the selected items sorted by id, they are resolved once when the rules are loaded along
+//with the item definitions, so the items are not matched again every time a basket is priced, see Rules.ResolveSelectors
+type ItemSelector struct {
+	AffectedCategory string   `yaml:"affectedCategory"`
+	AffectedTags     []string `yaml:"affectedTags"`
+	SelectedItems    []string `yaml:"-"`
+}
+
+//Returns true if the rule selects its items by category or tags
+func (s ItemSelector) IsSet() bool {
+	return s.AffectedCategory != "" || len(s.AffectedTags) > 0
+}
+
+//Returns the items affected by a rule with the given affected item: the selected items if the rule has a selector or
+//the affected item otherwise
+func (s ItemSelector) AffectedItems(affectedItem string) []string {
+	if s.IsSet() {
+		return s.SelectedItems
+	}
+	return []string{affectedItem}
+}
+
+//Validates the selector of a rule affecting the given item, the rule must either affect an item or select them,
+//returns an error otherwise
+func (s ItemSelector) validateSelector(affectedItem string) error {
+	if !s.IsSet() {
+		if affectedItem == "" {
+			return errors.New("the affected item can't be nil")
+		}
+		return nil
+	}
+	if affectedItem != "" {
+		return errors.New("the affected item can't be set along with an affected category or affected tags")
+	}
+	if s.AffectedCategory != "" {
+		if err := validateCategory(s.AffectedCategory); err != nil {
+			return err
+		}
+	}
+	return validateTags(s.AffectedTags)
+}
+
+//Describes the items selected, ie: in the category merch with the tags sale
+func (s ItemSelector) String() string {
+	var parts []string
+	if s.AffectedCategory != "" {
+		parts = append(parts, fmt.Sprintf("in the category %s", s.AffectedCategory))
+	}
+	if len(s.AffectedTags) > 0 {
+		parts = append(parts, fmt.Sprintf("with the tags %s", strings.Join(s.AffectedTags, ", ")))
+	}
+	return strings.Join(parts, " ")
+}
+
+//Returns the ids of the items matching the selector sorted by id, only the items sold by weight or measure if the rule
+//is measured and only the ones sold by units otherwise. The variants are not selected on their own, as they have the
+//category and tags of their parent, which is selected instead so they are counted together. Returns nil if the selector
+//is not set
+func (c ConfiguredItems) selectItems(s ItemSelector, measured bool) []string {
+	if !s.IsSet() {
+		return nil
+	}
+	selected := []string{}
+	for id, item := range c {
+		if item.Parent != "" || item.IsMeasured() != measured || s.AffectedCategory != "" && !item.InCategory(s.AffectedCategory) || !item.HasTags(s.AffectedTags) {
+			continue
+		}
+		selected = append(selected, id)
+	}
+	sort.Strings(selected)
+	return selected
+}
+
+//Returns true if any of the rules selects its items by category or tags
+func (r Rules) HasSelectors() bool {
+	for _, v := range r.BulkRules {
+		if v.ItemSelector.IsSet() {
+			return true
+		}
+	}
+	for _, v := range r.NxmRules {
+		if v.ItemSelector.IsSet() {
+			return true
+		}
+	}
+	for _, v := range r.MixAndMatchRules {
+		if v.ItemSelector.IsSet() {
+			return true
+		}
+	}
+	return false
+}
+
+//Returns a copy of the rules with the items of every selector resolved from the given item definitions
+//The rules are not modified, so the same rules can be resolved again against new item definitions
+func (r Rules) ResolveSelectors(items ConfiguredItems) Rules {
+	r.BulkRules = append([]BulkRule(nil), r.BulkRules...)
+	for i, v := range r.BulkRules {
+		r.BulkRules[i].SelectedItems = items.selectItems(v.ItemSelector, v.Measured)
+	}
+	r.NxmRules = append([]NxMRule(nil), r.NxmRules...)
+	for i, v := range r.NxmRules {
+		r.NxmRules[i].SelectedItems = items.selectItems(v.ItemSelector, false)
+	}
+	r.MixAndMatchRules = append([]MixAndMatchRule(nil), r.MixAndMatchRules...)
+	for i, v := range r.MixAndMatchRules {
+		r.MixAndMatchRules[i].SelectedItems = items.selectItems(v.ItemSelector, false)
+	}
+	return r
+}
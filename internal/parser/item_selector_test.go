@@ -0,0 +1,172 @@
+package parser
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func getCatalogItems() ConfiguredItems {
+	return ConfiguredItems{
+		"TSHIRT": {Name: "T-Shirt", Price: money.New(2000, "EUR"), Unit: UnitEach, Category: "merch/apparel", Tags: []string{"logo", "summer"}},
+		"CAP":    {Name: "Cap", Price: money.New(900, "EUR"), Unit: UnitEach, Category: "merch/apparel", Tags: []string{"summer"}},
+		"MUG":    {Name: "Mug", Price: money.New(750, "EUR"), Unit: UnitEach, Category: "merch/kitchen", Tags: []string{"logo"}},
+		"COFFEE": {Name: "Coffee", Price: money.New(2400, "EUR"), Unit: UnitKilogram, Category: "merch/kitchen"},
+	}
+}
+
+func TestParseRulesFileSelectors(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  bulkRules:
+  - ruleName: Apparel 3 or more
+    affectedCategory: merch/apparel
+    triggerAmount: 3
+    discountPercentage: 10
+  nxmRules:
+  - ruleName: Logo 2x1
+    affectedTags: [logo]
+    buyN: 2
+    payM: 1
+  mixAndMatchRules:
+  - ruleName: Any 3 summer items
+    affectedCategory: merch
+    affectedTags: [summer]
+    buyN: 3
+    payM: 2
+`), 0644)
+
+	//ACT
+	rules, err := RuleParser{Strict: true}.ParseRulesFile(path)
+
+	//ASSERT
+	if err != nil {
+		t.Fatalf("The rules selecting their items should have been parsed, got: %v", err)
+	}
+	if rules.BulkRules[0].AffectedCategory != "merch/apparel" || !reflect.DeepEqual(rules.NxmRules[0].AffectedTags, []string{"logo"}) ||
+		rules.MixAndMatchRules[0].AffectedCategory != "merch" || !reflect.DeepEqual(rules.MixAndMatchRules[0].AffectedTags, []string{"summer"}) {
+		t.Errorf("The selectors should have been parsed, got: %+v", rules)
+	}
+	if rules.BulkRules[0].SelectedItems != nil {
+		t.Errorf("The selectors should not be resolved until the items are known, got: %v", rules.BulkRules[0].SelectedItems)
+	}
+
+}
+
+func TestParseRulesFileSelectorsNotValid(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  bulkRules:
+  - ruleName: Both
+    affectedItem: TSHIRT
+    affectedCategory: merch/apparel
+    triggerAmount: 3
+    discountPercentage: 10
+  nxmRules:
+  - ruleName: Bad category
+    affectedCategory: /merch
+    buyN: 2
+    payM: 1
+  mixAndMatchRules:
+  - ruleName: Group and tags
+    group: merchandise
+    affectedTags: [logo]
+    buyN: 3
+    payM: 2
+  itemGroups:
+    merchandise: [TSHIRT, MUG]
+`), 0644)
+	expected := []ConfigProblem{
+		{File: path, Line: 3, Message: "the rule Both is not valid: the affected item can't be set along with an affected category or affected tags"},
+		{File: path, Line: 9, Message: "the rule Bad category is not valid: the category '/merch' must be a path like merch/apparel, without empty names nor surrounding spaces"},
+		{File: path, Line: 14, Message: "the rule Group and tags is not valid: the item group can't be set along with an affected category or affected tags"},
+	}
+
+	//ACT
+	_, err := RuleParser{Strict: true}.ParseRulesFile(path)
+	lenient, lenientErr := RuleParser{}.ParseRulesFile(path)
+
+	//ASSERT
+	configErr, ok := err.(*ConfigError)
+	if !ok || len(configErr.Problems) != len(expected) {
+		t.Fatalf("Every problem should have been reported, expected: %+v, got: %v", expected, err)
+	}
+	for i := range expected {
+		if configErr.Problems[i] != expected[i] {
+			t.Errorf("Expected the problem %s, got: %s", expected[i], configErr.Problems[i])
+		}
+	}
+
+	if lenientErr != nil || len(lenient.BulkRules)+len(lenient.NxmRules)+len(lenient.MixAndMatchRules) != 0 {
+		t.Errorf("Every invalid rule should have been discarded, got: %+v", lenient)
+	}
+
+}
+
+func TestResolveSelectors(t *testing.T) {
+
+	//ARRANGE
+	rules := Rules{
+		BulkRules: []BulkRule{
+			{RuleName: "Kitchen by the kilo", ItemSelector: ItemSelector{AffectedCategory: "merch/kitchen"}, Measured: true, TriggerAmount: 2, DiscountPercentage: 10},
+			{RuleName: "T-Shirt", AffectedItem: "TSHIRT", TriggerAmount: 3, DiscountPercentage: 5},
+		},
+		NxmRules:         []NxMRule{{RuleName: "Merch 2x1", ItemSelector: ItemSelector{AffectedCategory: "merch"}, BuyN: 2, PayM: 1}},
+		MixAndMatchRules: []MixAndMatchRule{{RuleName: "Summer logo", ItemSelector: ItemSelector{AffectedTags: []string{"summer", "logo"}}, BuyN: 3, PayM: 2}},
+	}
+
+	//ACT
+	resolved := rules.ResolveSelectors(getCatalogItems())
+
+	//ASSERT
+	if !reflect.DeepEqual(resolved.BulkRules[0].SelectedItems, []string{"COFFEE"}) || resolved.BulkRules[1].SelectedItems != nil {
+		t.Errorf("A measured rule should only select the items sold by weight or measure, got: %+v", resolved.BulkRules)
+	}
+	if !reflect.DeepEqual(resolved.NxmRules[0].SelectedItems, []string{"CAP", "MUG", "TSHIRT"}) {
+		t.Errorf("Every item sold by units of the category and its subcategories should have been selected, got: %v", resolved.NxmRules[0].SelectedItems)
+	}
+	if !reflect.DeepEqual(resolved.MixAndMatchItems(resolved.MixAndMatchRules[0]), []string{"TSHIRT"}) {
+		t.Errorf("Only the items with every tag should have been selected, got: %v", resolved.MixAndMatchRules[0].SelectedItems)
+	}
+	if rules.NxmRules[0].SelectedItems != nil {
+		t.Errorf("The given rules should not have been modified")
+	}
+
+}
+
+func TestValidateRulesSelectors(t *testing.T) {
+
+	//ARRANGE
+	rules := Rules{
+		BulkRules: []BulkRule{{RuleName: "Apparel bulk", ItemSelector: ItemSelector{AffectedCategory: "merch/apparel"}, TriggerAmount: 3, DiscountPercentage: 10}},
+		NxmRules: []NxMRule{
+			{RuleName: "Cap 2x1", AffectedItem: "CAP", BuyN: 2, PayM: 1},
+			{RuleName: "Sale 2x1", ItemSelector: ItemSelector{AffectedTags: []string{"sale"}}, BuyN: 2, PayM: 1},
+		},
+	}
+	expected := []string{
+		"the rule Cap 2x1 affects the item CAP, which is already affected by the rule Apparel bulk",
+		"the rule Sale 2x1 doesn't select any item, there's no item sold by units with the tags sale",
+	}
+
+	//ACT
+	err := ValidateRules(rules, getCatalogItems())
+
+	//ASSERT
+	configErr, ok := err.(*ConfigError)
+	if !ok || len(configErr.Problems) != len(expected) {
+		t.Fatalf("Every problem should have been reported, expected: %v, got: %v", expected, err)
+	}
+	for i := range expected {
+		if configErr.Problems[i].Message != expected[i] {
+			t.Errorf("Expected the problem %s, got: %s", expected[i], configErr.Problems[i].Message)
+		}
+	}
+
+}
@@ -3,28 +3,77 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"github.com/dagozba/golangsmallshop/internal/money"
 	"github.com/sirupsen/logrus"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 	"io/ioutil"
 	"path/filepath"
+	"sort"
 )
 
+//TaxClass is the tax class of the item and TaxRate the percentage of tax of the class, TaxIncluded is true when the
+//Price already includes the tax, see taxTable
+//Unit is what the item is sold by and Price is the price of one of its units, ie: 32.00 per kg. Items sold by weight or
+//measure can be scanned with the VariableBarcode printed by the scales
+//Barcodes are the EAN-8, EAN-13, UPC-A or GTIN-14 codes the item can be scanned with instead of its id
+//Category is the path of the category of the item, ie: merch/apparel, Tags are free-form labels and Attributes typed
+//values describing it, ie: size, colour or brand. The rules can select the items they affect by category or tags
+//An item with VariantAxes, ie: size and colour, is only sold through its Variants, the ids of the items defined under
+//it, which have the id of the item as their Parent, see generatedVariant
+//Stock is the number of units of the item in stock when the server starts, the stock of the items without it is not
+//tracked, see pricer.Inventory
 type ItemDefinition struct {
-	Name  string  `yaml:"name"`
-	Price float32 `yaml:"price"`
+	Name            string
+	Price           money.Money
+	TaxClass        string
+	TaxRate         money.Decimal
+	TaxIncluded     bool
+	Unit            string
+	VariableBarcode VariableBarcode
+	Barcodes        []string
+	Category        string
+	Tags            []string
+	Attributes      map[string]Attribute
+	Parent          string
+	VariantAxes     []string
+	Variants        []string
+	Stock           *int
+}
+
+//The prices are read as exact decimals and converted into the catalog currency once it is known
+type generatedItemDefinition struct {
+	Name            string                      `yaml:"name"`
+	Price           money.Decimal               `yaml:"price"`
+	TaxClass        string                      `yaml:"taxClass"`
+	Unit            string                      `yaml:"unit"`
+	VariableBarcode VariableBarcode             `yaml:"variableBarcode"`
+	Barcodes        []string                    `yaml:"barcodes"`
+	Category        string                      `yaml:"category"`
+	Tags            []string                    `yaml:"tags"`
+	Attributes      map[string]Attribute        `yaml:"attributes"`
+	VariantAxes     []string                    `yaml:"variantAxes"`
+	Variants        map[string]generatedVariant `yaml:"variants"`
+	Stock           *int                        `yaml:"stock"`
 }
 
 type generatedItemDefinitions struct {
-	Items ConfiguredItems `yaml:"items"`
+	Currency string                             `yaml:"currency"`
+	TaxMode  string                             `yaml:"taxMode"`
+	TaxRates map[string]money.Decimal           `yaml:"taxRates"`
+	Items    map[string]generatedItemDefinition `yaml:"items"`
 }
 
 type ConfiguredItems map[string]ItemDefinition
 
 type IParser interface {
-  ParseItemsDefinitions(p string) (ConfiguredItems, error)
+	ParseItemsDefinitions(p string) (ConfiguredItems, error)
 }
 
-type ItemsParser struct {}
+//In Strict mode, a file that is not valid yaml, has unknown fields, no items or any invalid item is rejected as a whole
+//with a ConfigError listing every problem, instead of discarding the invalid items
+type ItemsParser struct {
+	Strict bool
+}
 
 //Parses the configs/item_definitions.yaml to configure the system with available products.
 //If the yaml format is not valid, it will not populate the Items map
@@ -34,25 +83,233 @@ func (pa ItemsParser) ParseItemsDefinitions(p string) (ConfiguredItems, error) {
 	if err != nil {
 		return nil, err
 	}
+	return pa.ParseItemsData(p, d)
+}
+
+//Same as ParseItemsDefinitions with the contents of the file in the given path already read, so the caller knows the
+//exact contents the items were parsed from
+func (pa ItemsParser) ParseItemsData(p string, d []byte) (ConfiguredItems, error) {
+	if pa.Strict {
+		return pa.parseStrict(p, d)
+	}
 	var g generatedItemDefinitions
 	yaml.Unmarshal(d, &g)
-	return pa.validateInput(g.Items), nil
+	return pa.validateInput(g), nil
 }
 
 //Validates the input and discards any non valid items
-func (pa ItemsParser) validateInput(items ConfiguredItems) ConfiguredItems {
+//All the prices are expressed in the currency defined at the top of the file, EUR if none is defined
+//An invalid tax mode is discarded in favour of inclusive prices and invalid tax rates as a whole, so none of the items
+//has tax
+func (pa ItemsParser) validateInput(g generatedItemDefinitions) ConfiguredItems {
+	currency := catalogCurrency(g.Currency)
+	included, err := parseTaxMode(g.TaxMode)
+	if err != nil {
+		logrus.Warn(fmt.Errorf("the tax mode failed to be validated, the prices include the tax: %v", err))
+	}
+	taxes := taxTable{rates: g.TaxRates, included: included}
+	if err := validateTaxRates(g.TaxRates); err != nil {
+		logrus.Warn(fmt.Errorf("the tax rates failed to be validated, no tax is applied: %v", err))
+		taxes.rates = nil
+	}
 	validatedItems := ConfiguredItems{}
-	for k, v := range items {
-		if err := v.validateItemInput(); err != nil {
+	for k, gv := range g.Items {
+		v, err := newItemDefinition(ItemDefinition{Name: gv.Name, TaxClass: gv.TaxClass, Unit: gv.Unit, VariableBarcode: gv.VariableBarcode,
+			Barcodes: gv.Barcodes, Category: gv.Category, Tags: gv.Tags, Attributes: gv.Attributes, VariantAxes: gv.VariantAxes, Stock: gv.Stock},
+			gv.Price, currency, taxes)
+		if err == nil {
+			err = v.validateVariantAxes(len(gv.Variants))
+		}
+		if err != nil {
 			logrus.Warn(fmt.Errorf("the item %s failed to be validated: , %v", k, err))
 		} else {
-			logrus.Infof("Loaded item %s with price %.2f from configuration file", k, v.Price)
+			logrus.Infof("Loaded item %s with price %s from configuration file", k, v.Price)
 			validatedItems[k] = v
 		}
 	}
+	for _, k := range sortedIds(validatedItems) {
+		parent := validatedItems[k]
+		variants := make(map[string]ItemDefinition)
+		for id, gv := range g.Items[k].Variants {
+			v, err := newVariantDefinition(k, parent, ItemDefinition{Name: gv.Name, Barcodes: gv.Barcodes, Attributes: gv.Attributes, Stock: gv.Stock}, gv.Price)
+			if err != nil {
+				logrus.Warn(fmt.Errorf("the variant %s of the item %s failed to be validated: , %v", id, k, err))
+				continue
+			}
+			variants[id] = v
+		}
+		problems := validatedItems.variantProblems(parent, variants)
+		for id, v := range variants {
+			if problem, exs := problems[id]; exs {
+				logrus.Warn(fmt.Errorf("the variant %s of the item %s failed to be validated: , %s", id, k, problem))
+				continue
+			}
+			logrus.Infof("Loaded variant %s of item %s with price %s from configuration file", id, k, v.Price)
+			validatedItems[id] = v
+		}
+	}
+	for k, problem := range duplicatedBarcodes(validatedItems) {
+		logrus.Warn(fmt.Errorf("the item %s failed to be validated: , %s", k, problem))
+		delete(validatedItems, k)
+	}
+	validatedItems.linkVariants()
 	return validatedItems
 }
 
+//The prices are read as they were written, so an invalid price can be reported along with its line
+type strictItemDefinition struct {
+	Name            string                   `yaml:"name"`
+	Price           yaml.Node                `yaml:"price"`
+	TaxClass        string                   `yaml:"taxClass"`
+	Unit            string                   `yaml:"unit"`
+	VariableBarcode VariableBarcode          `yaml:"variableBarcode"`
+	Barcodes        []string                 `yaml:"barcodes"`
+	Category        string                   `yaml:"category"`
+	Tags            []string                 `yaml:"tags"`
+	Attributes      map[string]Attribute     `yaml:"attributes"`
+	VariantAxes     []string                 `yaml:"variantAxes"`
+	Variants        map[string]strictVariant `yaml:"variants"`
+	Stock           *int                     `yaml:"stock"`
+}
+
+type strictItemDefinitions struct {
+	Currency string                          `yaml:"currency"`
+	TaxMode  string                          `yaml:"taxMode"`
+	TaxRates map[string]money.Decimal        `yaml:"taxRates"`
+	Items    map[string]strictItemDefinition `yaml:"items"`
+}
+
+//Parses and validates the whole file, collecting every problem found along with the line it was found in
+func (ItemsParser) parseStrict(file string, d []byte) (ConfiguredItems, error) {
+	var g strictItemDefinitions
+	doc, problems := decodeStrict(file, d, &g)
+	if doc == nil {
+		return nil, newConfigError(problems)
+	}
+	if len(g.Items) == 0 && len(problems) == 0 {
+		problems = append(problems, ConfigProblem{File: file, Message: "no items are defined"})
+	}
+
+	currency := catalogCurrency(g.Currency)
+	rootLines := mappingKeyLines(doc)
+	included, err := parseTaxMode(g.TaxMode)
+	if err != nil {
+		problems = append(problems, ConfigProblem{File: file, Line: rootLines["taxMode"], Message: err.Error()})
+	}
+	if err := validateTaxRates(g.TaxRates); err != nil {
+		problems = append(problems, ConfigProblem{File: file, Line: rootLines["taxRates"], Message: fmt.Sprintf("the tax rates are not valid: %v", err)})
+	}
+	taxes := taxTable{rates: g.TaxRates, included: included}
+	lines := mappingKeyLines(doc, "items")
+	items := ConfiguredItems{}
+	for k, gv := range g.Items {
+		line := lines[k]
+		price, err := money.ParseDecimal(gv.Price.Value)
+		if err != nil {
+			if gv.Price.Line != 0 {
+				line = gv.Price.Line
+			}
+			problems = append(problems, ConfigProblem{File: file, Line: line, Message: fmt.Sprintf("the price of the item %s is not valid: %v", k, err)})
+			continue
+		}
+		v, err := newItemDefinition(ItemDefinition{Name: gv.Name, TaxClass: gv.TaxClass, Unit: gv.Unit, VariableBarcode: gv.VariableBarcode,
+			Barcodes: gv.Barcodes, Category: gv.Category, Tags: gv.Tags, Attributes: gv.Attributes, VariantAxes: gv.VariantAxes, Stock: gv.Stock},
+			price, currency, taxes)
+		if err == nil {
+			err = v.validateVariantAxes(len(gv.Variants))
+		}
+		if err != nil {
+			problems = append(problems, ConfigProblem{File: file, Line: line, Message: fmt.Sprintf("the item %s is not valid: %v", k, err)})
+			continue
+		}
+		items[k] = v
+	}
+	for _, k := range sortedIds(items) {
+		parent := items[k]
+		variantLines := mappingKeyLines(doc, "items", k, "variants")
+		variants := make(map[string]ItemDefinition)
+		for id, gv := range g.Items[k].Variants {
+			lines[id] = variantLines[id]
+			var price money.Decimal
+			if gv.Price.Value != "" {
+				if price, err = money.ParseDecimal(gv.Price.Value); err != nil {
+					problems = append(problems, ConfigProblem{File: file, Line: gv.Price.Line, Message: fmt.Sprintf("the price of the variant %s is not valid: %v", id, err)})
+					continue
+				}
+			}
+			v, err := newVariantDefinition(k, parent, ItemDefinition{Name: gv.Name, Barcodes: gv.Barcodes, Attributes: gv.Attributes, Stock: gv.Stock}, price)
+			if err != nil {
+				problems = append(problems, ConfigProblem{File: file, Line: lines[id], Message: fmt.Sprintf("the variant %s of the item %s is not valid: %v", id, k, err)})
+				continue
+			}
+			variants[id] = v
+		}
+		variantProblems := items.variantProblems(parent, variants)
+		for id, v := range variants {
+			if problem, exs := variantProblems[id]; exs {
+				problems = append(problems, ConfigProblem{File: file, Line: lines[id], Message: fmt.Sprintf("the variant %s of the item %s is not valid: %s", id, k, problem)})
+				continue
+			}
+			items[id] = v
+		}
+	}
+	for k, problem := range duplicatedBarcodes(items) {
+		problems = append(problems, ConfigProblem{File: file, Line: lines[k], Message: fmt.Sprintf("the item %s is not valid: %s", k, problem)})
+	}
+	if len(problems) > 0 {
+		sortProblems(problems)
+		return nil, newConfigError(problems)
+	}
+	items.linkVariants()
+	return items, nil
+}
+
+//Returns the ids of the items sorted
+func sortedIds(items ConfiguredItems) []string {
+	ids := make([]string, 0, len(items))
+	for k := range items {
+		ids = append(ids, k)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func catalogCurrency(currency string) string {
+	if currency == "" {
+		return money.DefaultCurrency
+	}
+	return currency
+}
+
+//Completes the given item definition with its price in the given currency and its tax according to the given tax table,
+//returns an error if it's not valid
+func newItemDefinition(v ItemDefinition, price money.Decimal, currency string, taxes taxTable) (ItemDefinition, error) {
+	amount, err := money.FromDecimal(price, currency)
+	if err != nil {
+		return ItemDefinition{}, err
+	}
+	v.Price = amount
+	v.TaxClass = taxClassOf(v.TaxClass)
+	if v.TaxRate, err = taxes.rateOf(v.TaxClass); err != nil {
+		return ItemDefinition{}, err
+	}
+	v.TaxIncluded = taxes.included
+	v.Unit = unitOf(v.Unit)
+	if v.VariableBarcode.Code != "" && v.VariableBarcode.Embeds == "" {
+		v.VariableBarcode.Embeds = EmbedsMeasure
+	}
+	return v, v.validateItemInput()
+}
+
+//Returns the currency the configured items are priced in
+//All the items share the currency defined in the item definitions file
+func (c ConfiguredItems) Currency() string {
+	for _, v := range c {
+		return v.Price.Currency
+	}
+	return money.DefaultCurrency
+}
+
 //Checks whether the given ItemDefinition is valid, returns an error otherwise
 func (i ItemDefinition) validateItemInput() error {
 
@@ -60,9 +317,21 @@ func (i ItemDefinition) validateItemInput() error {
 		return errors.New("the name of the configured item can't be nil or empty")
 	}
 
-	if i.Price <= 0 {
+	if i.Price.Amount <= 0 {
 		return errors.New("the price of a product can't be 0 or lower")
 	}
 
-	return nil
+	if err := i.validateMeasure(); err != nil {
+		return err
+	}
+
+	if err := i.validateBarcodes(); err != nil {
+		return err
+	}
+
+	if err := i.validateStock(); err != nil {
+		return err
+	}
+
+	return i.validateCatalog()
 }
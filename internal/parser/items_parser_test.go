@@ -1,22 +1,56 @@
 package parser
 
-import "testing"
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
 
 func TestParseItemsDefinitions(t *testing.T) {
 
 	//ARRANGE
 	c := ConfiguredItems{
-		"VOUCHER": ItemDefinition {
-			Name:  "Company Voucher",
-			Price: 5.00,
+		"VOUCHER": ItemDefinition{
+			Name:        "Company Voucher",
+			Price:       money.New(500, "EUR"),
+			TaxClass:    ZeroTax,
+			TaxIncluded: true,
+			Unit:        UnitEach,
+			Barcodes:    []string{"8437000000013"},
+			Category:    "gifts",
 		},
-		"TSHIRT": ItemDefinition {
-			Name:  "Company T-Shirt",
-			Price: 20.00,
+		"TSHIRT": ItemDefinition{
+			Name:        "Company T-Shirt",
+			Price:       money.New(2000, "EUR"),
+			TaxClass:    StandardTax,
+			TaxRate:     money.DecimalFromInt(21),
+			TaxIncluded: true,
+			Unit:        UnitEach,
+			Barcodes:    []string{"8437000000020"},
+			Category:    "merch/apparel",
+			Tags:        []string{"logo"},
+			Attributes: map[string]Attribute{
+				"size":   {Kind: AttributeText, Text: "M"},
+				"colour": {Kind: AttributeText, Text: "navy"},
+				"brand":  {Kind: AttributeText, Text: "Company"},
+			},
 		},
-		"MUG": ItemDefinition {
-			Name:  "Company Coffee Mug",
-			Price: 7.5,
+		"MUG": ItemDefinition{
+			Name:        "Company Coffee Mug",
+			Price:       money.New(750, "EUR"),
+			TaxClass:    StandardTax,
+			TaxRate:     money.DecimalFromInt(21),
+			TaxIncluded: true,
+			Unit:        UnitEach,
+			Barcodes:    []string{"8437000000037", "96385074"},
+			Category:    "merch/kitchen",
+			Tags:        []string{"logo"},
+			Attributes: map[string]Attribute{
+				"capacity":       {Kind: AttributeNumber, Number: money.Decimal(350000)},
+				"dishwasherSafe": {Kind: AttributeBoolean, Boolean: true},
+			},
 		},
 	}
 	itemsParser := &ItemsParser{}
@@ -29,10 +63,70 @@ func TestParseItemsDefinitions(t *testing.T) {
 		if pcv, exs := pc[k]; !exs {
 			t.Errorf("Item key %s missing from the parsed map", k)
 		} else {
-			if v != pcv {
+			if !reflect.DeepEqual(v, pcv) {
 				t.Errorf("The contents of the item with key %s don't match, expected: %+v, got: %+v", k, v, pcv)
 			}
 		}
 	}
 
 }
+
+func TestParseItemsDefinitionsLenient(t *testing.T) {
+
+	//ARRANGE
+	g := generatedItemDefinitions{Items: map[string]generatedItemDefinition{
+		"MUG":  {Name: "Company Coffee Mug", Price: money.DecimalFromInt(7)},
+		"FREE": {Name: "Free Item", Price: 0},
+	}}
+
+	//ACT
+	items := ItemsParser{}.validateInput(g)
+
+	//ASSERT
+	if len(items) != 1 {
+		t.Errorf("The invalid item should have been discarded, got: %+v", items)
+	}
+
+}
+
+func TestParseItemsDefinitionsStrict(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "item_definitions.yaml")
+	ioutil.WriteFile(path, []byte(`currency: EUR
+items:
+  MUG:
+    name: Company Coffee Mug
+    price: 7.50
+  FREE:
+    name: Free Item
+    price: 0
+  BOOK:
+    name: Book
+    price: ten
+    colour: blue
+`), 0644)
+	expected := []ConfigProblem{
+		{File: path, Line: 6, Message: "the item FREE is not valid: the price of a product can't be 0 or lower"},
+		{File: path, Line: 11, Message: "the price of the item BOOK is not valid: 'ten' is not a valid decimal number"},
+		{File: path, Line: 12, Message: "unknown field colour"},
+	}
+
+	//ACT
+	_, err := ItemsParser{Strict: true}.ParseItemsDefinitions(path)
+
+	//ASSERT
+	configErr, ok := err.(*ConfigError)
+	if !ok {
+		t.Fatalf("A ConfigError should have been returned, got: %v", err)
+	}
+	if len(configErr.Problems) != len(expected) {
+		t.Fatalf("Every problem should have been reported, expected: %+v, got: %+v", expected, configErr.Problems)
+	}
+	for i := range expected {
+		if configErr.Problems[i] != expected[i] {
+			t.Errorf("Expected the problem %s, got: %s", expected[i], configErr.Problems[i])
+		}
+	}
+
+}
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"strconv"
+)
+
+//Units the items are sold by, the price of an item is the price of one of its units
+//Items sold by each are counted, the rest of them are weighed or measured
+const (
+	UnitEach     = "each"
+	UnitKilogram = "kg"
+	UnitGram     = "g"
+	UnitMetre    = "m"
+	UnitLitre    = "l"
+)
+
+//Values a variable measure barcode can embed
+const (
+	//The weight or measure in thousandths of the unit of the item, ie: 00350 is 0.350 kg
+	EmbedsMeasure = "measure"
+	//The price in minor units of the currency, ie: 00420 is 4.20 EUR, the quantity is the price divided by the unit price
+	EmbedsPrice = "price"
+)
+
+//An EAN-13 variable measure barcode, as printed by the scales of the shop: a 7 digit Code starting with 2, which
+//identifies the item, followed by 5 digits embedding its measure or price and the check digit, ie: 2012345003507
+type VariableBarcode struct {
+	Code   string `yaml:"code"`
+	Embeds string `yaml:"embeds"`
+}
+
+//Returns true if the item is weighed or measured instead of counted
+func (i ItemDefinition) IsMeasured() bool {
+	return unitOf(i.Unit) != UnitEach
+}
+
+//Returns the quantity of the item embedded in the value of one of its variable barcodes
+//A price that is not a whole number of units is truncated to a millionth of the unit
+func (i ItemDefinition) QuantityOf(value int64) money.Decimal {
+	if i.VariableBarcode.Embeds == EmbedsPrice {
+		return money.Decimal(int64(money.DecimalFromInt(value)) / i.Price.Amount)
+	}
+	return money.DecimalFromInt(value) / 1000
+}
+
+//Returns the unit of an item, each if it's not set
+func unitOf(unit string) string {
+	if unit == "" {
+		return UnitEach
+	}
+	return unit
+}
+
+func isUnit(unit string) bool {
+	return unit == UnitEach || unit == UnitKilogram || unit == UnitGram || unit == UnitMetre || unit == UnitLitre
+}
+
+//Validates the unit and the variable barcode of the item, returns an error otherwise
+func (i ItemDefinition) validateMeasure() error {
+
+	if !isUnit(i.Unit) {
+		return fmt.Errorf("unknown unit '%s', expected each, kg, g, m or l", i.Unit)
+	}
+
+	b := i.VariableBarcode
+	if b.Code == "" {
+		return nil
+	}
+	if !i.IsMeasured() {
+		return errors.New("only the items sold by weight or measure can have a variable barcode")
+	}
+	if len(b.Code) != 7 || !isDigits(b.Code) || b.Code[0] != '2' {
+		return fmt.Errorf("the variable barcode code '%s' must be 7 digits starting with 2", b.Code)
+	}
+	if b.Embeds != EmbedsMeasure && b.Embeds != EmbedsPrice {
+		return fmt.Errorf("unknown embedded value '%s', expected measure or price", b.Embeds)
+	}
+
+	return nil
+}
+
+//Splits an EAN-13 variable measure barcode into the 7 digit code of the item and the value embedded in it, returns an
+//error if it's not a valid variable measure barcode
+func SplitVariableBarcode(barcode string) (string, int64, error) {
+	if len(barcode) != 13 || !isDigits(barcode) || barcode[0] != '2' {
+		return "", 0, fmt.Errorf("'%s' is not a variable measure EAN-13 barcode", barcode)
+	}
+	if !validCheckDigit(barcode) {
+		return "", 0, fmt.Errorf("the check digit of the barcode %s is not valid", barcode)
+	}
+	value, _ := strconv.ParseInt(barcode[7:12], 10, 64)
+	return barcode[:7], value, nil
+}
+
+//Returns the id and definition of the item with the given variable barcode code, false if there's none
+func (c ConfiguredItems) ByVariableCode(code string) (string, ItemDefinition, bool) {
+	for id, item := range c {
+		if item.VariableBarcode.Code == code {
+			return id, item, true
+		}
+	}
+	return "", ItemDefinition{}, false
+}
@@ -0,0 +1,222 @@
+package parser
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseItemsDefinitionsMeasured(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "item_definitions.yaml")
+	ioutil.WriteFile(path, []byte(`currency: EUR
+items:
+  CHEESE:
+    name: Manchego Cheese
+    price: 18.90
+    unit: kg
+    variableBarcode:
+      code: "2012345"
+  RIBBON:
+    name: Gift Ribbon
+    price: 2.50
+    unit: m
+    variableBarcode:
+      code: "2054321"
+      embeds: price
+  MUG:
+    name: Company Coffee Mug
+    price: 7.50
+`), 0644)
+
+	//ACT
+	items, err := ItemsParser{Strict: true}.ParseItemsDefinitions(path)
+
+	//ASSERT
+	if err != nil {
+		t.Fatalf("The measured items should have been parsed, got: %v", err)
+	}
+
+	if !items["CHEESE"].IsMeasured() || items["CHEESE"].Unit != UnitKilogram || items["CHEESE"].VariableBarcode.Embeds != EmbedsMeasure {
+		t.Errorf("The cheese should be sold by kg with the measure embedded in its barcode by default, got: %+v", items["CHEESE"])
+	}
+
+	if items["MUG"].IsMeasured() || items["MUG"].Unit != UnitEach {
+		t.Errorf("The mug should be sold by units, got: %+v", items["MUG"])
+	}
+
+	id, item, found := items.ByVariableCode("2054321")
+	if !found || id != "RIBBON" || item.VariableBarcode.Embeds != EmbedsPrice {
+		t.Errorf("The ribbon should have been found by its variable barcode, got: %s %+v", id, item)
+	}
+
+}
+
+func TestParseItemsDefinitionsMeasuredNotValid(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "item_definitions.yaml")
+	ioutil.WriteFile(path, []byte(`currency: EUR
+items:
+  CHEESE:
+    name: Manchego Cheese
+    price: 18.90
+    unit: kg
+    variableBarcode:
+      code: "2012345"
+  HAM:
+    name: Serrano Ham
+    price: 32.00
+    unit: kg
+    variableBarcode:
+      code: "2012345"
+  FLOUR:
+    name: Flour
+    price: 1.20
+    unit: lb
+  MUG:
+    name: Company Coffee Mug
+    price: 7.50
+    variableBarcode:
+      code: "2099999"
+  RIBBON:
+    name: Gift Ribbon
+    price: 2.50
+    unit: m
+    variableBarcode:
+      code: "12345"
+      embeds: length
+`), 0644)
+	expected := []ConfigProblem{
+		{File: path, Line: 9, Message: "the item HAM is not valid: its variable barcode is already used by the item CHEESE"},
+		{File: path, Line: 15, Message: "the item FLOUR is not valid: unknown unit 'lb', expected each, kg, g, m or l"},
+		{File: path, Line: 19, Message: "the item MUG is not valid: only the items sold by weight or measure can have a variable barcode"},
+		{File: path, Line: 24, Message: "the item RIBBON is not valid: the variable barcode code '12345' must be 7 digits starting with 2"},
+	}
+
+	//ACT
+	_, err := ItemsParser{Strict: true}.ParseItemsDefinitions(path)
+	lenient, lenientErr := ItemsParser{}.ParseItemsDefinitions(path)
+
+	//ASSERT
+	configErr, ok := err.(*ConfigError)
+	if !ok || len(configErr.Problems) != len(expected) {
+		t.Fatalf("Every problem should have been reported, expected: %+v, got: %v", expected, err)
+	}
+	for i := range expected {
+		if configErr.Problems[i] != expected[i] {
+			t.Errorf("Expected the problem %s, got: %s", expected[i], configErr.Problems[i])
+		}
+	}
+
+	if _, exs := lenient["CHEESE"]; lenientErr != nil || len(lenient) != 1 || !exs {
+		t.Errorf("Only the first item with the barcode should have been kept, got: %+v", lenient)
+	}
+
+}
+
+func TestSplitVariableBarcode(t *testing.T) {
+
+	//ARRANGE
+	barcodes := map[string]string{
+		"2012345003507": "",
+		"2012345003506": "the check digit of the barcode 2012345003506 is not valid",
+		"4012345003509": "'4012345003509' is not a variable measure EAN-13 barcode",
+		"201234500350":  "'201234500350' is not a variable measure EAN-13 barcode",
+		"20123450035a9": "'20123450035a9' is not a variable measure EAN-13 barcode",
+	}
+
+	for barcode, expected := range barcodes {
+		//ACT
+		code, value, err := SplitVariableBarcode(barcode)
+
+		//ASSERT
+		if expected == "" && (err != nil || code != "2012345" || value != 350) {
+			t.Errorf("The barcode %s should have been split into 2012345 and 350, got: %s, %d, %v", barcode, code, value, err)
+		}
+		if expected != "" && (err == nil || err.Error() != expected) {
+			t.Errorf("The barcode %s should have been rejected, expected: '%s', got: %v", barcode, expected, err)
+		}
+	}
+
+}
+
+func TestQuantityOf(t *testing.T) {
+
+	//ARRANGE
+	byMeasure := ItemDefinition{Price: money.New(1890, "EUR"), Unit: UnitKilogram, VariableBarcode: VariableBarcode{Code: "2012345", Embeds: EmbedsMeasure}}
+	byPrice := ItemDefinition{Price: money.New(250, "EUR"), Unit: UnitMetre, VariableBarcode: VariableBarcode{Code: "2054321", Embeds: EmbedsPrice}}
+	expectedMeasure, _ := money.ParseDecimal("0.35")
+	expectedPrice, _ := money.ParseDecimal("1.5")
+
+	//ACT
+	measure := byMeasure.QuantityOf(350)
+	price := byPrice.QuantityOf(375)
+
+	//ASSERT
+	if measure != expectedMeasure {
+		t.Errorf("The embedded measure should be in thousandths of a kg, expected: %s, got: %s", expectedMeasure, measure)
+	}
+
+	if price != expectedPrice {
+		t.Errorf("The embedded price should be divided by the unit price, expected: %s, got: %s", expectedPrice, price)
+	}
+
+}
+
+func TestValidateRulesMeasuredItems(t *testing.T) {
+
+	//ARRANGE
+	items := ConfiguredItems{
+		"CHEESE": {Price: money.New(1890, "EUR"), Unit: UnitKilogram},
+		"MUG":    {Price: money.New(750, "EUR"), Unit: UnitEach},
+	}
+	rules := Rules{
+		BulkRules: []BulkRule{
+			{RuleName: "Cheese by the wheel", AffectedItem: "CHEESE", TriggerAmount: 2, DiscountPercentage: 10, Measured: true},
+			{RuleName: "Mugs by weight", AffectedItem: "MUG", TriggerAmount: 2, DiscountPercentage: 10, Measured: true},
+		},
+		NxmRules: []NxMRule{{RuleName: "Cheese 2x1", AffectedItem: "CHEESE", BuyN: 2, PayM: 1}},
+	}
+	expected := []string{
+		"the rule Mugs by weight is measured but the item MUG is not sold by weight or measure",
+		"the rule Cheese 2x1 counts the units of the item CHEESE, which is sold by kg, only measured bulk rules can affect it",
+	}
+
+	//ACT
+	err := ValidateRules(rules, items)
+
+	//ASSERT
+	configErr, ok := err.(*ConfigError)
+	if !ok {
+		t.Fatalf("Expected the problems %v, got: %v", expected, err)
+	}
+	for _, e := range expected {
+		found := false
+		for _, p := range configErr.Problems {
+			found = found || p.Message == e
+		}
+		if !found {
+			t.Errorf("Expected the problem %s, got: %v", e, configErr.Problems)
+		}
+	}
+
+}
+
+func TestValidateBulkRuleMeasured(t *testing.T) {
+
+	//ARRANGE
+	rule := BulkRule{RuleName: "Cheese by the wheel", AffectedItem: "CHEESE", TriggerAmount: 2, DiscountPercentage: 10, Measured: true,
+		RuleOptions: RuleOptions{Stackable: true}}
+
+	//ACT
+	err := rule.validateBulkRuleInput()
+
+	//ASSERT
+	if err == nil || err.Error() != "a measured rule can't be stackable nor belong to an exclusive group" {
+		t.Errorf("A stackable measured rule should have been rejected, got: %v", err)
+	}
+
+}
@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+)
+
+//How the units discounted by a Mix and Match Rule are selected
+const (
+	//The cheapest units of the group are free, the default
+	SelectCheapest = "cheapest"
+	//The most expensive units of the group are free
+	SelectMostExpensive = "mostExpensive"
+	//The units are selected to give the customer the lowest total once the rules executed after it price the units it
+	//leaves: the most expensive units are free, paying with either the most expensive or the cheapest units left, or
+	//the cheapest units are free, whichever is cheaper
+	SelectCustomer = "customer"
+)
+
+//Buy N pay M across every item of an item Group, ie: any 3 items of the merchandise range and the cheapest one free
+//The units of every item of the group are counted together and, for every set of BuyN units, BuyN - PayM units are
+//free, which ones is decided by the Selection, cheapest by default
+//Instead of a Group, the rule can select its items by category or tags, see ItemSelector
+type MixAndMatchRule struct {
+	RuleName     string `yaml:"ruleName"`
+	Group        string `yaml:"group"`
+	ItemSelector `yaml:",inline"`
+	BuyN         int    `yaml:"buyN"`
+	PayM         int    `yaml:"payM"`
+	Selection    string `yaml:"selection"`
+	RuleOptions  `yaml:",inline"`
+}
+
+//Validates the given MixAndMatchRule, returns an error otherwise
+//The group is checked against the item groups of the file, see groupProblems
+func (r MixAndMatchRule) validateMixAndMatchRuleInput() error {
+
+	if r.Group == "" && !r.ItemSelector.IsSet() {
+		return errors.New("the item group can't be nil")
+	}
+
+	if r.ItemSelector.IsSet() {
+		if r.Group != "" {
+			return errors.New("the item group can't be set along with an affected category or affected tags")
+		}
+		if err := r.validateSelector(""); err != nil {
+			return err
+		}
+	}
+
+	if r.PayM <= 0 {
+		return errors.New("the pay amount can't be zero or below")
+	}
+
+	if r.BuyN <= r.PayM {
+		return errors.New("the amount to buy must be higher than the amount to pay")
+	}
+
+	if r.Selection != "" && r.Selection != SelectCheapest && r.Selection != SelectMostExpensive && r.Selection != SelectCustomer {
+		return fmt.Errorf("unknown selection '%s', expected cheapest, mostExpensive or customer", r.Selection)
+	}
+
+	return r.validateSchedule()
+}
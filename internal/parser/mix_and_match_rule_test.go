@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRulesFileMixAndMatch(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  itemGroups:
+    merchandise: [TSHIRT, MUG]
+  mixAndMatchRules:
+  - ruleName: Any 3 merchandise, cheapest free
+    group: merchandise
+    buyN: 3
+    payM: 2
+    selection: customer
+`), 0644)
+	expected := MixAndMatchRule{RuleName: "Any 3 merchandise, cheapest free", Group: "merchandise", BuyN: 3, PayM: 2, Selection: SelectCustomer}
+
+	//ACT
+	rules, err := RuleParser{Strict: true}.ParseRulesFile(path)
+
+	//ASSERT
+	if err != nil || len(rules.MixAndMatchRules) != 1 {
+		t.Fatalf("The mix and match rule should have been parsed, got: %+v (%v)", rules.MixAndMatchRules, err)
+	}
+	if !reflect.DeepEqual(rules.MixAndMatchRules[0], expected) {
+		t.Errorf("The mix and match rule doesn't match, expected: %+v, got: %+v", expected, rules.MixAndMatchRules[0])
+	}
+	if items := rules.GroupItems("merchandise"); !reflect.DeepEqual(items, []string{"MUG", "TSHIRT"}) {
+		t.Errorf("The items of the group should be sorted by id, got: %v", items)
+	}
+
+}
+
+func TestParseRulesFileMixAndMatchNotValid(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  itemGroups:
+    merchandise: [TSHIRT, MUG, TSHIRT]
+    stationery: [PEN]
+  mixAndMatchRules:
+  - ruleName: Any 3 merchandise, cheapest free
+    group: merchandise
+    buyN: 3
+    payM: 2
+  - ruleName: Any 2 books
+    group: books
+    buyN: 2
+    payM: 1
+  - ruleName: Pens
+    group: stationery
+    buyN: 2
+    payM: 1
+    selection: random
+`), 0644)
+	expected := []ConfigProblem{
+		{File: path, Line: 3, Message: "the item group merchandise is not valid: the item TSHIRT is included more than once"},
+		{File: path, Line: 10, Message: "the rule Any 2 books uses the item group books, which is not defined"},
+		{File: path, Line: 14, Message: "the rule Pens is not valid: unknown selection 'random', expected cheapest, mostExpensive or customer"},
+	}
+
+	//ACT
+	_, err := RuleParser{Strict: true}.ParseRulesFile(path)
+	lenient, lenientErr := RuleParser{}.ParseRulesFile(path)
+
+	//ASSERT
+	configErr, ok := err.(*ConfigError)
+	if !ok || len(configErr.Problems) != len(expected) {
+		t.Fatalf("Every problem should have been reported, expected: %+v, got: %v", expected, err)
+	}
+	for i := range expected {
+		if configErr.Problems[i] != expected[i] {
+			t.Errorf("Expected the problem %s, got: %s", expected[i], configErr.Problems[i])
+		}
+	}
+
+	if lenientErr != nil || len(lenient.MixAndMatchRules) != 0 || len(lenient.ItemGroups) != 1 {
+		t.Errorf("The invalid group and every rule without a valid group should have been discarded, got: %+v", lenient)
+	}
+
+}
+
+func TestValidateRulesItemGroups(t *testing.T) {
+
+	//ARRANGE
+	items, _ := ItemsParser{}.ParseItemsDefinitions("../../configs/item_definitions.yaml")
+	rules := Rules{
+		ItemGroups:       map[string][]string{"merchandise": {"TSHIRT", "MUG", "CAP"}},
+		MixAndMatchRules: []MixAndMatchRule{{RuleName: "Any 3 merchandise", Group: "merchandise", BuyN: 3, PayM: 2}},
+	}
+	expected := "the item group merchandise contains the item CAP, which is not defined in the item definitions"
+
+	//ACT
+	err := ValidateRules(rules, items)
+
+	//ASSERT
+	if configErr, ok := err.(*ConfigError); !ok || len(configErr.Problems) != 1 || configErr.Problems[0].Message != expected {
+		t.Errorf("Expected the problem %s, got: %v", expected, err)
+	}
+
+}
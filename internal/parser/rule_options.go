@@ -0,0 +1,15 @@
+package parser
+
+//Options shared by every rule type, resolving how a rule applies along with the rest of the rules
+//Priority: rules with a higher priority are executed first, rules with the same priority keep the order of their type
+//(bundle, Buy X get Y, bulk, NxM and mix and match rules for the items, basket rules for the basket) and the order they are defined in
+//Stackable: a stackable item rule applies its discount on top of the amount already charged for its items by the rest
+//of the rules, a stackable basket rule applies even if another basket rule has already been applied
+//ExclusiveGroup: only the first rule of a group giving a discount to the basket is applied, the rest of them are skipped
+//Schedule: when the rule is active, see Schedule
+type RuleOptions struct {
+	Priority       int    `yaml:"priority"`
+	Stackable      bool   `yaml:"stackable"`
+	ExclusiveGroup string `yaml:"exclusiveGroup"`
+	Schedule       `yaml:",inline"`
+}
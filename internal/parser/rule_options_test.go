@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRulesFileOptions(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  nxmRules:
+  - affectedItem: VOUCHER
+    ruleName: "2x1"
+    buyN: 2
+    payM: 1
+    priority: 10
+    exclusiveGroup: vouchers
+  bulkRules:
+  - affectedItem: VOUCHER
+    ruleName: "Loyalty"
+    triggerAmount: 1
+    discountPercentage: 10
+    stackable: true
+`), 0644)
+
+	//ACT
+	rules, err := RuleParser{Strict: true}.ParseRulesFile(path)
+
+	//ASSERT
+	if err != nil {
+		t.Fatalf("Rules affecting the same item should be allowed if one of them is stackable, got: %v", err)
+	}
+
+	expected := RuleOptions{Priority: 10, ExclusiveGroup: "vouchers"}
+	if !reflect.DeepEqual(rules.NxmRules[0].RuleOptions, expected) {
+		t.Errorf("The options of the rule should have been parsed, expected: %+v, got: %+v", expected, rules.NxmRules[0].RuleOptions)
+	}
+
+	if !rules.BulkRules[0].Stackable {
+		t.Errorf("The bulk rule should have been parsed as stackable")
+	}
+
+}
+
+func TestParseRulesFileConflictDifferentPriority(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  nxmRules:
+  - affectedItem: VOUCHER
+    ruleName: "2x1"
+    buyN: 2
+    payM: 1
+    priority: 1
+  bulkRules:
+  - affectedItem: VOUCHER
+    ruleName: "Bulk Rule"
+    triggerAmount: 3
+    discountPercentage: 5
+  - affectedItem: VOUCHER
+    ruleName: "Same priority"
+    triggerAmount: 5
+    discountPercentage: 10
+`), 0644)
+	expected := ConfigProblem{File: path, Line: 13,
+		Message: "the rule Same priority affects the item VOUCHER, which is already affected by the rule Bulk Rule (" + path + ":9)"}
+
+	//ACT
+	_, err := RuleParser{Strict: true}.ParseRulesFile(path)
+
+	//ASSERT
+	if configErr, ok := err.(*ConfigError); !ok || len(configErr.Problems) != 1 || configErr.Problems[0] != expected {
+		t.Errorf("Only the rules with the same priority should conflict, expected the problem %s, got: %v", expected, err)
+	}
+
+}
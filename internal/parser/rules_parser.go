@@ -4,28 +4,52 @@ import (
 	"errors"
 	"fmt"
 	"github.com/sirupsen/logrus"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 	"io/ioutil"
 	"path/filepath"
 )
 
-type BulkRule struct {
-	RuleName           string `yaml:"ruleName"`
-	AffectedItem       string `yaml:"affectedItem"`
-	TriggerAmount      int    `yaml:"triggerAmount"`
-	DiscountPercentage int    `yaml:"discountPercentage"`
-}
-
+//Instead of an AffectedItem, the rule can select its items by category or tags, it's then applied to every one of them
+//on its own, see ItemSelector
 type NxMRule struct {
 	RuleName     string `yaml:"ruleName"`
 	AffectedItem string `yaml:"affectedItem"`
-	BuyN         int    `yaml:"buyN"`
-	PayM         int    `yaml:"payM"`
+	ItemSelector `yaml:",inline"`
+	BuyN         int `yaml:"buyN"`
+	PayM         int `yaml:"payM"`
+	RuleOptions  `yaml:",inline"`
 }
 
+//ItemGroups are named sets of item ids the mix and match rules count together
 type Rules struct {
-	NxmRules  []NxMRule  `yaml:"nxmRules"`
-	BulkRules []BulkRule `yaml:"bulkRules"`
+	NxmRules         []NxMRule           `yaml:"nxmRules"`
+	BulkRules        []BulkRule          `yaml:"bulkRules"`
+	BuyXGetYRules    []BuyXGetYRule      `yaml:"buyXGetYRules"`
+	BundleRules      []BundleRule        `yaml:"bundleRules"`
+	MixAndMatchRules []MixAndMatchRule   `yaml:"mixAndMatchRules"`
+	BasketRules      []BasketRule        `yaml:"basketRules"`
+	Coupons          []Coupon            `yaml:"coupons"`
+	ItemGroups       map[string][]string `yaml:"itemGroups"`
+	TimeZone         string              `yaml:"timeZone"`
+	source           *rulesSource
+}
+
+//Where the rules were read from, only known for rules parsed in Strict mode
+type rulesSource struct {
+	file             string
+	nxmLines         []int
+	bulkLines        []int
+	buyXGetYLines    []int
+	bundleLines      []int
+	mixAndMatchLines []int
+	basketLines      []int
+	couponLines      []int
+	groupLines       map[string]int
+}
+
+//Returns the number of pricing rules of every type, the coupons are not rules but activate them
+func (r Rules) Count() int {
+	return len(r.NxmRules) + len(r.BulkRules) + len(r.BuyXGetYRules) + len(r.BundleRules) + len(r.MixAndMatchRules) + len(r.BasketRules)
 }
 
 type generatedRules struct {
@@ -37,15 +61,27 @@ type IRuleParser interface {
 	ParseRulesFile(p string) (Rules, error)
 }
 
-type RuleParser struct {}
+//In Strict mode, a file that is not valid yaml, has unknown fields, any invalid rule or rules in conflict with each other
+//is rejected as a whole with a ConfigError listing every problem, instead of discarding the invalid rules
+type RuleParser struct {
+	Strict bool
+}
 
 //Parses the given yaml in the given path in order to add it to the microservice configuration
 func (pa RuleParser) ParseRulesFile(p string) (Rules, error) {
 	path, _ := filepath.Abs(p)
 	d, err := ioutil.ReadFile(path)
-	var rules Rules
 	if err != nil {
-		return rules, fmt.Errorf("the rules file couldn't be loaded: %v", err)
+		return Rules{}, fmt.Errorf("the rules file couldn't be loaded: %v", err)
+	}
+	return pa.ParseRulesData(p, d)
+}
+
+//Same as ParseRulesFile with the contents of the file in the given path already read, so the caller knows the exact
+//contents the rules were parsed from
+func (pa RuleParser) ParseRulesData(p string, d []byte) (Rules, error) {
+	if pa.Strict {
+		return pa.parseStrict(p, d)
 	}
 	var a generatedRules
 	yaml.Unmarshal(d, &a)
@@ -73,15 +109,123 @@ func (RuleParser) validateAndReturnRules(rules Rules) Rules {
 		}
 	}
 
-	return Rules{BulkRules: validatedBulkRules, NxmRules: validatedNxMRules}
+	var validatedBuyXGetYRules []BuyXGetYRule
+	for _, v := range rules.BuyXGetYRules {
+		if err := v.validateBuyXGetYRuleInput(); err != nil {
+			logrus.Warn(fmt.Errorf("the rule %s failed to be validated: , %v", v.RuleName, err))
+		} else {
+			validatedBuyXGetYRules = append(validatedBuyXGetYRules, v)
+		}
+	}
+
+	var validatedBundleRules []BundleRule
+	for _, v := range rules.BundleRules {
+		if err := v.validateBundleRuleInput(); err != nil {
+			logrus.Warn(fmt.Errorf("the rule %s failed to be validated: , %v", v.RuleName, err))
+		} else {
+			validatedBundleRules = append(validatedBundleRules, v)
+		}
+	}
+
+	validatedGroups := make(map[string][]string)
+	for k, v := range rules.ItemGroups {
+		if err := validateItemGroup(v); err != nil {
+			logrus.Warn(fmt.Errorf("the item group %s failed to be validated: , %v", k, err))
+		} else {
+			validatedGroups[k] = v
+		}
+	}
+
+	var validatedMixAndMatchRules []MixAndMatchRule
+	for _, v := range rules.MixAndMatchRules {
+		if err := v.validateMixAndMatchRuleInput(); err != nil {
+			logrus.Warn(fmt.Errorf("the rule %s failed to be validated: , %v", v.RuleName, err))
+		} else if _, exs := validatedGroups[v.Group]; !exs && v.Group != "" {
+			logrus.Warn(fmt.Errorf("the rule %s failed to be validated: , the item group %s is not defined", v.RuleName, v.Group))
+		} else {
+			validatedMixAndMatchRules = append(validatedMixAndMatchRules, v)
+		}
+	}
+
+	var validatedBasketRules []BasketRule
+	for _, v := range rules.BasketRules {
+		if err := v.validateBasketRuleInput(); err != nil {
+			logrus.Warn(fmt.Errorf("the rule %s failed to be validated: , %v", v.RuleName, err))
+		} else {
+			validatedBasketRules = append(validatedBasketRules, v)
+		}
+	}
+
+	timeZone := rules.TimeZone
+	if _, err := rules.location(); err != nil {
+		logrus.Warn(fmt.Errorf("the schedules of the rules are checked in the local time zone: %v", err))
+		timeZone = ""
+	}
+
+	var validatedCoupons []Coupon
+	for _, v := range rules.Coupons {
+		if err := v.validateCouponInput(); err != nil {
+			logrus.Warn(fmt.Errorf("the coupon %s failed to be validated: , %v", v.Code, err))
+		} else {
+			validatedCoupons = append(validatedCoupons, v)
+		}
+	}
+
+	return Rules{BulkRules: validatedBulkRules, NxmRules: validatedNxMRules, BuyXGetYRules: validatedBuyXGetYRules,
+		BundleRules: validatedBundleRules, MixAndMatchRules: validatedMixAndMatchRules, BasketRules: validatedBasketRules,
+		Coupons: validatedCoupons, ItemGroups: validatedGroups, TimeZone: timeZone}
+}
+
+//Parses and validates the whole file, collecting every problem found along with the line it was found in
+func (RuleParser) parseStrict(file string, d []byte) (Rules, error) {
+	var a generatedRules
+	doc, problems := decodeStrict(file, d, &a)
+	if doc == nil {
+		return Rules{}, newConfigError(problems)
+	}
+	rules := a.Rules
+	rules.source = &rulesSource{
+		file:             file,
+		nxmLines:         sequenceLines(doc, "rules", "nxmRules"),
+		bulkLines:        sequenceLines(doc, "rules", "bulkRules"),
+		buyXGetYLines:    sequenceLines(doc, "rules", "buyXGetYRules"),
+		bundleLines:      sequenceLines(doc, "rules", "bundleRules"),
+		mixAndMatchLines: sequenceLines(doc, "rules", "mixAndMatchRules"),
+		basketLines:      sequenceLines(doc, "rules", "basketRules"),
+		couponLines:      sequenceLines(doc, "rules", "coupons"),
+		groupLines:       mappingKeyLines(doc, "rules", "itemGroups"),
+	}
+
+	for _, r := range rules.entries() {
+		if err := r.validate(); err != nil {
+			problems = append(problems, r.problem("the rule %s is not valid: %v", r.name, err))
+		}
+	}
+	for i, c := range rules.Coupons {
+		if err := c.validateCouponInput(); err != nil {
+			problems = append(problems, ConfigProblem{File: file, Line: lineAt(rules.source.couponLines, i),
+				Message: fmt.Sprintf("the coupon %s is not valid: %v", c.Code, err)})
+		}
+	}
+	problems = append(problems, rules.conflicts()...)
+	problems = append(problems, rules.couponProblems()...)
+	problems = append(problems, rules.groupProblems()...)
+	if _, err := rules.location(); err != nil {
+		problems = append(problems, ConfigProblem{File: file, Line: mappingKeyLines(doc, "rules")["timeZone"], Message: err.Error()})
+	}
+	if len(problems) > 0 {
+		sortProblems(problems)
+		return Rules{}, newConfigError(problems)
+	}
+	return rules, nil
 }
 
 //TODO: Rules structs and validations should go on separate files to avoid clumping everything up in the same file
 //Validates the given NxMRule, returns an error othweise
 func (r NxMRule) validateNxMRuleInput() error {
 
-	if r.AffectedItem == "" {
-		return errors.New("the affected item can't be nil")
+	if err := r.validateSelector(r.AffectedItem); err != nil {
+		return err
 	}
 
 	if r.PayM <= 0 {
@@ -96,23 +240,5 @@ func (r NxMRule) validateNxMRuleInput() error {
 		return errors.New("the amount to pay can't be higher than the amount to buy")
 	}
 
-	return nil
-}
-
-//Validates the given BulkRule, returns an error otherwise
-func (r BulkRule) validateBulkRuleInput() error {
-
-	if r.AffectedItem == "" {
-		return errors.New("the affected item can't be nil")
-	}
-
-	if r.TriggerAmount == 0 {
-		return errors.New("the discount trigger amount can't be zero")
-	}
-
-	if r.DiscountPercentage < 0 || r.DiscountPercentage >= 100 {
-		return errors.New("the discount percentage can't be lower than 0% or higher or equals than 100%")
-	}
-
-	return nil
+	return r.validateSchedule()
 }
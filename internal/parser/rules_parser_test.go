@@ -1,6 +1,11 @@
 package parser
 
-import "testing"
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
 
 func TestParseRulesFile(t *testing.T) {
 
@@ -30,15 +35,116 @@ func TestParseRulesFile(t *testing.T) {
 
 	//ASSERT
 	for i := range c.BulkRules {
-		if c.BulkRules[i] != pc.BulkRules[i] {
+		if !reflect.DeepEqual(c.BulkRules[i], pc.BulkRules[i]) {
 			t.Errorf("BulkRules slices are not equal")
 		}
 	}
 
 	for i := range c.NxmRules {
-		if c.NxmRules[i] != pc.NxmRules[i] {
+		if !reflect.DeepEqual(c.NxmRules[i], pc.NxmRules[i]) {
 			t.Errorf("NxMRules slices are not equal")
 		}
 	}
 
 }
+
+func TestParseRulesFileLenient(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte("rules:\n  nxmRules:\n  - affectedItem: VOUCHER\n    buyN: 1\n    payM: 2\n  - affectedItem: MUG\n    buyN: 2\n    payM: 1\n"), 0644)
+
+	//ACT
+	rules, err := RuleParser{}.ParseRulesFile(path)
+
+	//ASSERT
+	if err != nil || len(rules.NxmRules) != 1 {
+		t.Errorf("The invalid rule should have been discarded, got: %+v (%v)", rules, err)
+	}
+
+}
+
+func TestParseRulesFileStrict(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  nxmRules:
+  - affectedItem: VOUCHER
+    ruleName: "2x1"
+    buyN: 1
+    payM: 2
+  - affectedItem: MUG
+    ruleName: "2x1"
+    buyN: 2
+    payM: 1
+  bulkRules:
+  - affectedItem: MUG
+    ruleName: "Bulk Rule"
+    triggerAmount: 3
+    discount: 5
+`), 0644)
+	expected := []ConfigProblem{
+		{File: path, Line: 3, Message: "the rule 2x1 is not valid: the amount to pay can't be higher than the amount to buy"},
+		{File: path, Line: 7, Message: "the rule name 2x1 is already used by a previous rule (" + path + ":3)"},
+		{File: path, Line: 7, Message: "the rule 2x1 affects the item MUG, which is already affected by the rule Bulk Rule (" + path + ":12)"},
+		{File: path, Line: 15, Message: "unknown field discount"},
+	}
+
+	//ACT
+	_, err := RuleParser{Strict: true}.ParseRulesFile(path)
+
+	//ASSERT
+	configErr, ok := err.(*ConfigError)
+	if !ok {
+		t.Fatalf("A ConfigError should have been returned, got: %v", err)
+	}
+	if len(configErr.Problems) != len(expected) {
+		t.Fatalf("Every problem should have been reported, expected: %+v, got: %+v", expected, configErr.Problems)
+	}
+	for i := range expected {
+		if configErr.Problems[i] != expected[i] {
+			t.Errorf("Expected the problem %s, got: %s", expected[i], configErr.Problems[i])
+		}
+	}
+
+}
+
+func TestParseRulesFileStrictSyntaxError(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte("rules:\n  nxmRules: [\n"), 0644)
+
+	//ACT
+	_, err := RuleParser{Strict: true}.ParseRulesFile(path)
+
+	//ASSERT
+	if configErr, ok := err.(*ConfigError); !ok || len(configErr.Problems) != 1 || configErr.Problems[0].Line == 0 {
+		t.Errorf("The syntax error should have been reported along with its line, got: %v", err)
+	}
+
+}
+
+func TestRulesCount(t *testing.T) {
+
+	//ARRANGE
+	r := Rules{
+		NxmRules:         []NxMRule{{RuleName: "2x1"}},
+		BulkRules:        []BulkRule{{RuleName: "Bulk"}},
+		BuyXGetYRules:    []BuyXGetYRule{{RuleName: "Free mug"}},
+		BundleRules:      []BundleRule{{RuleName: "Pack"}},
+		MixAndMatchRules: []MixAndMatchRule{{RuleName: "Any 3"}},
+		BasketRules:      []BasketRule{{RuleName: "Spend 50"}, {RuleName: "Spend 100"}},
+		Coupons:          []Coupon{{Code: "SPRING10"}},
+	}
+
+	//ACT
+	count := r.Count()
+
+	//ASSERT
+	if count != 7 {
+		t.Errorf("Every rule but the coupons should have been counted, expected 7, got %d", count)
+	}
+
+}
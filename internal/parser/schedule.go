@@ -0,0 +1,135 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+//When a rule is active, a rule without any of the fields set is always active
+//ValidFrom and ValidUntil are absolute times, a time without an offset is read as UTC
+//DaysOfWeek (ie: saturday, sunday) and the StartTime and EndTime of the day ("HH:MM", EndTime excluded, up to "24:00")
+//are checked in the time zone of the rules file, see Rules.TimeZone
+type Schedule struct {
+	ValidFrom  time.Time `yaml:"validFrom"`
+	ValidUntil time.Time `yaml:"validUntil"`
+	DaysOfWeek []string  `yaml:"daysOfWeek"`
+	StartTime  string    `yaml:"startTime"`
+	EndTime    string    `yaml:"endTime"`
+}
+
+//Returns true if the schedule is active at the given time
+//The days and times of the day are checked in the location of the given time, see Rules.Location
+func (s Schedule) ActiveAt(t time.Time) bool {
+	if !s.ValidFrom.IsZero() && t.Before(s.ValidFrom) {
+		return false
+	}
+	if !s.ValidUntil.IsZero() && !t.Before(s.ValidUntil) {
+		return false
+	}
+	if len(s.DaysOfWeek) > 0 {
+		day := strings.ToLower(t.Weekday().String())
+		found := false
+		for _, d := range s.DaysOfWeek {
+			found = found || strings.ToLower(d) == day
+		}
+		if !found {
+			return false
+		}
+	}
+	if s.StartTime != "" {
+		start, _ := minuteOfDay(s.StartTime)
+		end, _ := minuteOfDay(s.EndTime)
+		now := t.Hour()*60 + t.Minute()
+		if now < start || now >= end {
+			return false
+		}
+	}
+	return true
+}
+
+//Returns true if any of the fields of the schedule is set
+func (s Schedule) IsScheduled() bool {
+	return !s.ValidFrom.IsZero() || !s.ValidUntil.IsZero() || len(s.DaysOfWeek) > 0 || s.StartTime != ""
+}
+
+//Validates the given Schedule, returns an error otherwise
+func (s Schedule) validateSchedule() error {
+
+	if !s.ValidFrom.IsZero() && !s.ValidUntil.IsZero() && !s.ValidUntil.After(s.ValidFrom) {
+		return errors.New("the end of the validity window must be after its start")
+	}
+
+	days := make(map[string]bool)
+	for _, d := range s.DaysOfWeek {
+		if !isWeekday(d) {
+			return fmt.Errorf("unknown day of the week '%s'", d)
+		}
+		if days[strings.ToLower(d)] {
+			return fmt.Errorf("the day %s is set more than once", d)
+		}
+		days[strings.ToLower(d)] = true
+	}
+
+	if (s.StartTime == "") != (s.EndTime == "") {
+		return errors.New("the start and end times of the day must be set together")
+	}
+	if s.StartTime == "" {
+		return nil
+	}
+	start, err := minuteOfDay(s.StartTime)
+	if err != nil {
+		return fmt.Errorf("the start time is not valid: %v", err)
+	}
+	end, err := minuteOfDay(s.EndTime)
+	if err != nil {
+		return fmt.Errorf("the end time is not valid: %v", err)
+	}
+	if end <= start {
+		return errors.New("the end time must be after the start time, a window can't go past midnight")
+	}
+
+	return nil
+}
+
+//Parses a time of the day as "HH:MM" into the minutes since midnight, "24:00" being the end of the day
+func minuteOfDay(s string) (int, error) {
+	var hour, minute int
+	if n, err := fmt.Sscanf(s, "%d:%d", &hour, &minute); err != nil || n != 2 || len(s) != 5 {
+		return 0, fmt.Errorf("'%s' is not a time of the day in HH:MM format", s)
+	}
+	if hour < 0 || minute < 0 || minute > 59 || hour > 24 || hour == 24 && minute != 0 {
+		return 0, fmt.Errorf("'%s' is not a time of the day between 00:00 and 24:00", s)
+	}
+	return hour*60 + minute, nil
+}
+
+func isWeekday(d string) bool {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), d) {
+			return true
+		}
+	}
+	return false
+}
+
+//Returns the time zone the schedules of the rules are checked in, the local time zone of the server if it's not set
+//or it's not valid
+func (r Rules) Location() *time.Location {
+	if loc, err := r.location(); err == nil {
+		return loc
+	}
+	return time.Local
+}
+
+func (r Rules) location() (*time.Location, error) {
+	if r.TimeZone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(r.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone '%s'", r.TimeZone)
+	}
+	return loc, nil
+}
@@ -0,0 +1,145 @@
+package parser
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRulesFileSchedule(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  timeZone: Europe/Madrid
+  bulkRules:
+  - affectedItem: MUG
+    ruleName: "Happy Hour"
+    triggerAmount: 1
+    discountPercentage: 20
+    validFrom: 2026-06-21T00:00:00+02:00
+    validUntil: 2026-09-23T00:00:00+02:00
+    daysOfWeek: [saturday, Sunday]
+    startTime: "17:00"
+    endTime: "20:00"
+`), 0644)
+
+	//ACT
+	rules, err := RuleParser{Strict: true}.ParseRulesFile(path)
+
+	//ASSERT
+	if err != nil {
+		t.Fatalf("The scheduled rule should have been parsed, got: %v", err)
+	}
+
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	if rules.Location().String() != madrid.String() {
+		t.Errorf("The time zone should have been parsed, expected: %s, got: %s", madrid, rules.Location())
+	}
+
+	schedule := rules.BulkRules[0].Schedule
+	expected := Schedule{DaysOfWeek: []string{"saturday", "Sunday"}, StartTime: "17:00", EndTime: "20:00"}
+	if !schedule.ValidFrom.Equal(time.Date(2026, 6, 20, 22, 0, 0, 0, time.UTC)) ||
+		!schedule.ValidUntil.Equal(time.Date(2026, 9, 22, 22, 0, 0, 0, time.UTC)) {
+		t.Errorf("The validity window should have been parsed, got: %s - %s", schedule.ValidFrom, schedule.ValidUntil)
+	}
+	schedule.ValidFrom, schedule.ValidUntil = time.Time{}, time.Time{}
+	if !reflect.DeepEqual(schedule, expected) {
+		t.Errorf("The schedule should have been parsed, expected: %+v, got: %+v", expected, schedule)
+	}
+
+}
+
+func TestParseRulesFileInvalidSchedule(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	ioutil.WriteFile(path, []byte(`rules:
+  timeZone: Mars/Olympus
+  bulkRules:
+  - affectedItem: MUG
+    ruleName: "Happy Hour"
+    triggerAmount: 1
+    discountPercentage: 20
+    startTime: "22:00"
+    endTime: "02:00"
+`), 0644)
+
+	//ACT
+	_, strictErr := RuleParser{Strict: true}.ParseRulesFile(path)
+	rules, lenientErr := RuleParser{}.ParseRulesFile(path)
+
+	//ASSERT
+	if strictErr == nil || !strings.Contains(strictErr.Error(), ":2: unknown time zone 'Mars/Olympus'") ||
+		!strings.Contains(strictErr.Error(), "a window can't go past midnight") {
+		t.Errorf("The time zone and the time window should have been rejected, got: %v", strictErr)
+	}
+
+	if lenientErr != nil || len(rules.BulkRules) != 0 || rules.TimeZone != "" {
+		t.Errorf("The invalid rule and time zone should have been discarded, got: %+v, %v", rules, lenientErr)
+	}
+
+}
+
+func TestValidateSchedule(t *testing.T) {
+
+	//ARRANGE
+	from := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	schedules := map[string]Schedule{
+		"the end of the validity window must be after its start":                              {ValidFrom: from, ValidUntil: from},
+		"unknown day of the week 'funday'":                                                    {DaysOfWeek: []string{"monday", "funday"}},
+		"the day Monday is set more than once":                                                {DaysOfWeek: []string{"monday", "Monday"}},
+		"the start and end times of the day must be set together":                             {StartTime: "10:00"},
+		"the start time is not valid: '9:00' is not a time of the day in HH:MM format":        {StartTime: "9:00", EndTime: "10:00"},
+		"the end time is not valid: '24:30' is not a time of the day between 00:00 and 24:00": {StartTime: "09:00", EndTime: "24:30"},
+		"": {ValidFrom: from, DaysOfWeek: []string{"friday"}, StartTime: "18:00", EndTime: "24:00"},
+	}
+
+	for expected, s := range schedules {
+		//ACT
+		err := s.validateSchedule()
+
+		//ASSERT
+		if expected == "" && err != nil || expected != "" && (err == nil || err.Error() != expected) {
+			t.Errorf("The schedule %+v should have been validated, expected: '%s', got: %v", s, expected, err)
+		}
+	}
+
+}
+
+func TestScheduleActiveAt(t *testing.T) {
+
+	//ARRANGE
+	schedule := Schedule{ValidFrom: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), ValidUntil: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+		DaysOfWeek: []string{"Saturday"}, StartTime: "17:00", EndTime: "20:00"}
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	//Saturday 2026-06-13
+	times := map[time.Time]bool{
+		time.Date(2026, 6, 13, 17, 0, 0, 0, madrid):  true,
+		time.Date(2026, 6, 13, 19, 59, 0, 0, madrid): true,
+		time.Date(2026, 6, 13, 20, 0, 0, 0, madrid):  false,
+		time.Date(2026, 6, 13, 16, 59, 0, 0, madrid): false,
+		time.Date(2026, 6, 14, 18, 0, 0, 0, madrid):  false,
+		time.Date(2026, 7, 4, 18, 0, 0, 0, madrid):   false,
+		//The same instant as 17:30 in Madrid, but the days and times are checked in the location of the time
+		time.Date(2026, 6, 13, 15, 30, 0, 0, time.UTC): false,
+	}
+
+	for at, expected := range times {
+		//ACT
+		active := schedule.ActiveAt(at)
+
+		//ASSERT
+		if active != expected {
+			t.Errorf("The schedule should be active at %s: %t, got: %t", at, expected, active)
+		}
+	}
+
+	if !(Schedule{}).ActiveAt(time.Now()) {
+		t.Errorf("A rule without a schedule should always be active")
+	}
+
+}
@@ -0,0 +1,22 @@
+package parser
+
+import "errors"
+
+//Validates the stock of the item, only the items sold by units can have stock, returns an error otherwise
+func (i ItemDefinition) validateStock() error {
+	if i.Stock == nil {
+		return nil
+	}
+	if *i.Stock < 0 {
+		return errors.New("the stock of an item can't be negative")
+	}
+	if i.IsMeasured() {
+		return errors.New("only the items sold by units can have stock")
+	}
+	return nil
+}
+
+//Returns true if the stock of the item is tracked
+func (i ItemDefinition) HasStock() bool {
+	return i.Stock != nil
+}
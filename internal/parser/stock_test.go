@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseItemsDefinitionsStock(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "item_definitions.yaml")
+	ioutil.WriteFile(path, []byte(`currency: EUR
+items:
+  MUG:
+    name: Company Coffee Mug
+    price: 7.50
+    stock: 12
+  VOUCHER:
+    name: Company Voucher
+    price: 5.00
+  TSHIRT:
+    name: Company T-Shirt
+    price: 20.00
+    variantAxes: [size]
+    variants:
+      TSHIRT-S:
+        attributes: {size: S}
+        stock: 0
+      TSHIRT-L:
+        attributes: {size: L}
+`), 0644)
+
+	//ACT
+	items, err := ItemsParser{Strict: true}.ParseItemsDefinitions(path)
+
+	//ASSERT
+	if err != nil {
+		t.Fatalf("The stock should have been parsed, got: %v", err)
+	}
+
+	if !items["MUG"].HasStock() || *items["MUG"].Stock != 12 {
+		t.Errorf("The mug should have 12 units in stock, got: %v", items["MUG"].Stock)
+	}
+
+	if items["VOUCHER"].HasStock() || items["TSHIRT-L"].HasStock() {
+		t.Errorf("The stock of the items without it should not be tracked, got: %v, %v", items["VOUCHER"].Stock, items["TSHIRT-L"].Stock)
+	}
+
+	if !items["TSHIRT-S"].HasStock() || *items["TSHIRT-S"].Stock != 0 {
+		t.Errorf("The small T-shirt should be out of stock, got: %v", items["TSHIRT-S"].Stock)
+	}
+
+}
+
+func TestParseItemsDefinitionsStockNotValid(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "item_definitions.yaml")
+	ioutil.WriteFile(path, []byte(`currency: EUR
+items:
+  MUG:
+    name: Company Coffee Mug
+    price: 7.50
+    stock: -1
+  CHEESE:
+    name: Manchego Cheese
+    price: 18.90
+    unit: kg
+    stock: 10
+  TSHIRT:
+    name: Company T-Shirt
+    price: 20.00
+    stock: 5
+    variantAxes: [size]
+    variants:
+      TSHIRT-S:
+        attributes: {size: S}
+`), 0644)
+	expected := []string{
+		":3: the item MUG is not valid: the stock of an item can't be negative",
+		":7: the item CHEESE is not valid: only the items sold by units can have stock",
+		":12: the item TSHIRT is not valid: an item with variants can't have stock, its variants have it instead",
+	}
+
+	//ACT
+	_, err := ItemsParser{Strict: true}.ParseItemsDefinitions(path)
+
+	//ASSERT
+	for _, problem := range expected {
+		if err == nil || !strings.Contains(err.Error(), problem) {
+			t.Errorf("Expected the problem %s, got: %v", problem, err)
+		}
+	}
+
+}
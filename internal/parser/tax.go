@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"strings"
+)
+
+//Tax classes an item can belong to, items without a tax class are standard
+const (
+	StandardTax = "standard"
+	ReducedTax  = "reduced"
+	ZeroTax     = "zero"
+)
+
+//The tax table of the item definitions file: the tax rate of every class, as a percentage, and whether the prices of
+//the items include the tax (inclusive, the default) or the tax is added to them (exclusive)
+//A file without tax rates has no tax, the zero class never has tax
+type taxTable struct {
+	rates    map[string]money.Decimal
+	included bool
+}
+
+//Parses the tax mode of the file, returns true if the prices include the tax
+func parseTaxMode(mode string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", "inclusive":
+		return true, nil
+	case "exclusive":
+		return false, nil
+	}
+	return true, fmt.Errorf("unknown tax mode '%s', expected inclusive or exclusive", mode)
+}
+
+//Validates the tax rates of the file, returns an error otherwise
+func validateTaxRates(rates map[string]money.Decimal) error {
+	for class, rate := range rates {
+		if !isTaxClass(class) {
+			return fmt.Errorf("unknown tax class '%s', expected standard, reduced or zero", class)
+		}
+		if rate < 0 || rate >= money.DecimalFromInt(100) {
+			return fmt.Errorf("the tax rate of the class %s must be between 0 and 100", class)
+		}
+		if class == ZeroTax && rate != 0 {
+			return errors.New("the tax rate of the zero class can't be other than 0")
+		}
+	}
+	return nil
+}
+
+//Returns the tax rate of the given class, returns an error if the class is not known or the table has rates but none
+//for the class
+func (t taxTable) rateOf(class string) (money.Decimal, error) {
+	if !isTaxClass(class) {
+		return 0, fmt.Errorf("unknown tax class '%s', expected standard, reduced or zero", class)
+	}
+	rate, exs := t.rates[class]
+	if !exs && class != ZeroTax && len(t.rates) > 0 {
+		return 0, fmt.Errorf("no tax rate is defined for the tax class %s", class)
+	}
+	return rate, nil
+}
+
+func isTaxClass(class string) bool {
+	return class == StandardTax || class == ReducedTax || class == ZeroTax
+}
+
+//Returns the tax class of an item, the standard one if it's not set
+func taxClassOf(class string) string {
+	if class == "" {
+		return StandardTax
+	}
+	return class
+}
+
+//Returns true if the prices of the configured items include the tax
+//All the items share the tax mode defined in the item definitions file
+func (c ConfiguredItems) TaxIncluded() bool {
+	for _, v := range c {
+		return v.TaxIncluded
+	}
+	return true
+}
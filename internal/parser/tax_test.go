@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseItemsDefinitionsTaxExclusive(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "item_definitions.yaml")
+	ioutil.WriteFile(path, []byte(`currency: EUR
+taxMode: exclusive
+taxRates:
+  standard: 21
+  reduced: 5.5
+items:
+  MUG:
+    name: Company Coffee Mug
+    price: 7.50
+    taxClass: reduced
+  VOUCHER:
+    name: Company Voucher
+    price: 5.00
+    taxClass: zero
+`), 0644)
+	reduced, _ := money.ParseDecimal("5.5")
+
+	//ACT
+	items, err := ItemsParser{Strict: true}.ParseItemsDefinitions(path)
+
+	//ASSERT
+	if err != nil {
+		t.Fatalf("The tax table should have been parsed, got: %v", err)
+	}
+
+	if items["MUG"].TaxRate != reduced || items["MUG"].TaxClass != ReducedTax || items["VOUCHER"].TaxRate != 0 {
+		t.Errorf("Every item should have the rate of its tax class, got: %+v", items)
+	}
+
+	if items.TaxIncluded() {
+		t.Errorf("The prices should not include the tax")
+	}
+
+}
+
+func TestParseItemsDefinitionsInvalidTax(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "item_definitions.yaml")
+	ioutil.WriteFile(path, []byte(`currency: EUR
+taxMode: included
+taxRates:
+  standard: 21
+  zero: 4
+items:
+  MUG:
+    name: Company Coffee Mug
+    price: 7.50
+    taxClass: reduced
+  BOOK:
+    name: Book
+    price: 10.00
+    taxClass: luxury
+`), 0644)
+	expected := []ConfigProblem{
+		{File: path, Line: 2, Message: "unknown tax mode 'included', expected inclusive or exclusive"},
+		{File: path, Line: 3, Message: "the tax rates are not valid: the tax rate of the zero class can't be other than 0"},
+		{File: path, Line: 7, Message: "the item MUG is not valid: no tax rate is defined for the tax class reduced"},
+		{File: path, Line: 11, Message: "the item BOOK is not valid: unknown tax class 'luxury', expected standard, reduced or zero"},
+	}
+
+	//ACT
+	_, err := ItemsParser{Strict: true}.ParseItemsDefinitions(path)
+	lenient, lenientErr := ItemsParser{}.ParseItemsDefinitions(path)
+
+	//ASSERT
+	configErr, ok := err.(*ConfigError)
+	if !ok || len(configErr.Problems) != len(expected) {
+		t.Fatalf("Every problem should have been reported, expected: %+v, got: %v", expected, err)
+	}
+	for i := range expected {
+		if configErr.Problems[i] != expected[i] {
+			t.Errorf("Expected the problem %s, got: %s", expected[i], configErr.Problems[i])
+		}
+	}
+
+	if lenientErr != nil || len(lenient) != 1 || lenient["MUG"].TaxRate != 0 || !lenient.TaxIncluded() {
+		t.Errorf("The invalid tax table should have been discarded along with the item of an unknown class, got: %+v", lenient)
+	}
+
+}
@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"gopkg.in/yaml.v3"
+	"sort"
+	"strings"
+)
+
+//A variant of an item, ie: a size and colour of a T-shirt, defined under the item it's a variant of, its parent
+//It's scanned and priced as an item on its own, inheriting everything but its barcodes and stock from its parent: a
+//variant without a name is named after its parent and the values of its variant axes, ie: Company T-Shirt (M, navy),
+//and one without a price has the price of its parent. Its attributes are added to the ones of its parent and must set a
+//value for every variant axis of the parent
+type generatedVariant struct {
+	Name       string               `yaml:"name"`
+	Price      money.Decimal        `yaml:"price"`
+	Barcodes   []string             `yaml:"barcodes"`
+	Attributes map[string]Attribute `yaml:"attributes"`
+	Stock      *int                 `yaml:"stock"`
+}
+
+//The prices are read as they were written, so an invalid price can be reported along with its line
+type strictVariant struct {
+	Name       string               `yaml:"name"`
+	Price      yaml.Node            `yaml:"price"`
+	Barcodes   []string             `yaml:"barcodes"`
+	Attributes map[string]Attribute `yaml:"attributes"`
+	Stock      *int                 `yaml:"stock"`
+}
+
+//Returns true if the item is only sold through its variants, which are the items scanned instead of it
+func (i ItemDefinition) HasVariants() bool {
+	return len(i.VariantAxes) > 0
+}
+
+//Returns the items sold as the given item: its variants if it has them, the item itself otherwise
+//The rules affecting an item with variants count the units of all its variants together
+func (c ConfiguredItems) ItemsOf(item string) []string {
+	if c[item].HasVariants() {
+		return c[item].Variants
+	}
+	return []string{item}
+}
+
+//Returns the price of the cheapest item sold as the given item, see ItemsOf
+func (c ConfiguredItems) cheapestPrice(item string) money.Money {
+	sold := c.ItemsOf(item)
+	cheapest := c[sold[0]].Price
+	for _, i := range sold[1:] {
+		if c[i].Price.Amount < cheapest.Amount {
+			cheapest = c[i].Price
+		}
+	}
+	return cheapest
+}
+
+//Validates the variant axes of an item with the given number of variants, returns an error otherwise
+func (i ItemDefinition) validateVariantAxes(variants int) error {
+	if !i.HasVariants() {
+		if variants > 0 {
+			return errors.New("an item with variants must have at least one variant axis")
+		}
+		return nil
+	}
+	if variants == 0 {
+		return errors.New("an item with variant axes must have at least one variant")
+	}
+	if i.IsMeasured() {
+		return errors.New("only the items sold by units can have variants")
+	}
+	if len(i.Barcodes) > 0 {
+		return errors.New("an item with variants can't have barcodes, its variants are scanned instead")
+	}
+	if i.Stock != nil {
+		return errors.New("an item with variants can't have stock, its variants have it instead")
+	}
+	seen := make(map[string]bool, len(i.VariantAxes))
+	for _, axis := range i.VariantAxes {
+		if strings.TrimSpace(axis) == "" {
+			return errors.New("a variant axis can't be empty")
+		}
+		if seen[axis] {
+			return fmt.Errorf("the variant axis %s is set more than once", axis)
+		}
+		seen[axis] = true
+	}
+	return nil
+}
+
+//Completes the given variant of the given parent item with what it inherits from it, returns an error if it's not valid
+//A price of zero is no price, so the variant has the price of its parent
+func newVariantDefinition(parentId string, parent ItemDefinition, v ItemDefinition, price money.Decimal) (ItemDefinition, error) {
+	v.Parent = parentId
+	v.Price = parent.Price
+	if price != 0 {
+		amount, err := money.FromDecimal(price, parent.Price.Currency)
+		if err != nil {
+			return ItemDefinition{}, err
+		}
+		v.Price = amount
+	}
+	v.TaxClass, v.TaxRate, v.TaxIncluded, v.Unit = parent.TaxClass, parent.TaxRate, parent.TaxIncluded, parent.Unit
+	v.Category, v.Tags = parent.Category, parent.Tags
+
+	var values []string
+	for _, axis := range parent.VariantAxes {
+		a, exs := v.Attributes[axis]
+		if !exs {
+			return ItemDefinition{}, fmt.Errorf("the variant axis %s must be set in the attributes of the variant", axis)
+		}
+		values = append(values, a.String())
+	}
+	attributes := make(map[string]Attribute, len(parent.Attributes)+len(v.Attributes))
+	for k, a := range parent.Attributes {
+		attributes[k] = a
+	}
+	for k, a := range v.Attributes {
+		attributes[k] = a
+	}
+	v.Attributes = attributes
+	if v.Name == "" {
+		v.Name = fmt.Sprintf("%s (%s)", parent.Name, strings.Join(values, ", "))
+	}
+	return v, v.validateItemInput()
+}
+
+//Returns the problem found with every variant of the given parent that can't be added to the items along with the
+//rest of them, by variant id: its id is already used by another item or another variant of the parent has the same
+//values for every variant axis. The variants are checked sorted by id, so the first one is kept
+func (c ConfiguredItems) variantProblems(parent ItemDefinition, variants map[string]ItemDefinition) map[string]string {
+	ids := make([]string, 0, len(variants))
+	for id := range variants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	problems := make(map[string]string)
+	combinations := make(map[string]string)
+	for _, id := range ids {
+		if _, exs := c[id]; exs {
+			problems[id] = "its id is already used by another item"
+			continue
+		}
+		var values []string
+		for _, axis := range parent.VariantAxes {
+			values = append(values, variants[id].Attributes[axis].String())
+		}
+		key := strings.Join(values, "\x00")
+		if other, exs := combinations[key]; exs {
+			problems[id] = fmt.Sprintf("its %s are the same as the ones of the variant %s", strings.Join(parent.VariantAxes, ", "), other)
+			continue
+		}
+		combinations[key] = id
+	}
+	return problems
+}
+
+//Sets the variants of every item with variants from the parents of the items, sorted by id
+func (c ConfiguredItems) linkVariants() {
+	variants := make(map[string][]string)
+	for id, item := range c {
+		if item.Parent != "" {
+			variants[item.Parent] = append(variants[item.Parent], id)
+		}
+	}
+	for id, item := range c {
+		if item.HasVariants() {
+			item.Variants = variants[id]
+			sort.Strings(item.Variants)
+			c[id] = item
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package parser
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseItemsDefinitionsVariants(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "item_definitions.yaml")
+	ioutil.WriteFile(path, []byte(`currency: EUR
+taxRates:
+  standard: 21
+items:
+  TSHIRT:
+    name: Company T-Shirt
+    price: 20.00
+    category: merch/apparel
+    tags: [logo]
+    attributes:
+      brand: Company
+    variantAxes: [size, colour]
+    variants:
+      TSHIRT-S-NAVY:
+        attributes: {size: S, colour: navy}
+        barcodes: ["8437000000044"]
+      TSHIRT-XL-NAVY:
+        name: Company T-Shirt XL
+        price: 22.00
+        attributes: {size: XL, colour: navy}
+`), 0644)
+
+	//ACT
+	items, err := ItemsParser{Strict: true}.ParseItemsDefinitions(path)
+
+	//ASSERT
+	if err != nil {
+		t.Fatalf("The variants should have been parsed, got: %v", err)
+	}
+
+	if parent := items["TSHIRT"]; !parent.HasVariants() || !reflect.DeepEqual(parent.Variants, []string{"TSHIRT-S-NAVY", "TSHIRT-XL-NAVY"}) {
+		t.Errorf("The parent should have its variants, got: %+v", parent)
+	}
+	if !reflect.DeepEqual(items.ItemsOf("TSHIRT"), []string{"TSHIRT-S-NAVY", "TSHIRT-XL-NAVY"}) || !reflect.DeepEqual(items.ItemsOf("TSHIRT-S-NAVY"), []string{"TSHIRT-S-NAVY"}) {
+		t.Errorf("The items sold as the parent should be its variants, got: %v", items.ItemsOf("TSHIRT"))
+	}
+
+	expected := ItemDefinition{
+		Name:        "Company T-Shirt (S, navy)",
+		Price:       money.New(2000, "EUR"),
+		TaxClass:    StandardTax,
+		TaxRate:     money.DecimalFromInt(21),
+		TaxIncluded: true,
+		Unit:        UnitEach,
+		Barcodes:    []string{"8437000000044"},
+		Category:    "merch/apparel",
+		Tags:        []string{"logo"},
+		Attributes: map[string]Attribute{
+			"brand":  {Kind: AttributeText, Text: "Company"},
+			"size":   {Kind: AttributeText, Text: "S"},
+			"colour": {Kind: AttributeText, Text: "navy"},
+		},
+		Parent: "TSHIRT",
+	}
+	if !reflect.DeepEqual(items["TSHIRT-S-NAVY"], expected) {
+		t.Errorf("The variant should inherit from its parent, expected: %+v, got: %+v", expected, items["TSHIRT-S-NAVY"])
+	}
+	if xl := items["TSHIRT-XL-NAVY"]; xl.Name != "Company T-Shirt XL" || xl.Price != money.New(2200, "EUR") {
+		t.Errorf("The name and price of the variant should override the ones of its parent, got: %s, %s", xl.Name, xl.Price)
+	}
+
+}
+
+func TestParseItemsDefinitionsVariantsNotValid(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "item_definitions.yaml")
+	ioutil.WriteFile(path, []byte(`currency: EUR
+items:
+  MUG:
+    name: Company Coffee Mug
+    price: 7.50
+  TSHIRT:
+    name: Company T-Shirt
+    price: 20.00
+    variantAxes: [size]
+    variants:
+      TSHIRT-S:
+        attributes: {size: S}
+      TSHIRT-SMALL:
+        attributes: {size: S}
+      TSHIRT-M:
+        attributes: {colour: navy}
+      MUG:
+        attributes: {size: L}
+  CAP:
+    name: Company Cap
+    price: 9.00
+    variants:
+      CAP-RED:
+        attributes: {colour: red}
+`), 0644)
+	expected := []string{
+		":13: the variant TSHIRT-SMALL of the item TSHIRT is not valid: its size are the same as the ones of the variant TSHIRT-S",
+		":15: the variant TSHIRT-M of the item TSHIRT is not valid: the variant axis size must be set in the attributes of the variant",
+		":17: the variant MUG of the item TSHIRT is not valid: its id is already used by another item",
+		":19: the item CAP is not valid: an item with variants must have at least one variant axis",
+	}
+
+	//ACT
+	_, strictErr := ItemsParser{Strict: true}.ParseItemsDefinitions(path)
+	items, lenientErr := ItemsParser{}.ParseItemsDefinitions(path)
+
+	//ASSERT
+	for _, problem := range expected {
+		if strictErr == nil || !strings.Contains(strictErr.Error(), problem) {
+			t.Errorf("Expected the problem %s, got: %v", problem, strictErr)
+		}
+	}
+
+	if lenientErr != nil || len(items) != 3 || !reflect.DeepEqual(items["TSHIRT"].Variants, []string{"TSHIRT-S"}) || items["MUG"].Parent != "" {
+		t.Errorf("Only the invalid items and variants should have been discarded, got: %+v", items)
+	}
+
+}
+
+func TestValidateRulesVariants(t *testing.T) {
+
+	//ARRANGE
+	items := ConfiguredItems{
+		"TSHIRT":   {Name: "T-Shirt", Price: money.New(2000, "EUR"), Unit: UnitEach, VariantAxes: []string{"size"}, Variants: []string{"TSHIRT-S", "TSHIRT-L"}},
+		"TSHIRT-S": {Name: "T-Shirt (S)", Price: money.New(2000, "EUR"), Unit: UnitEach, Parent: "TSHIRT"},
+		"TSHIRT-L": {Name: "T-Shirt (L)", Price: money.New(2000, "EUR"), Unit: UnitEach, Parent: "TSHIRT"},
+	}
+	rules := Rules{
+		BulkRules: []BulkRule{{RuleName: "Small bulk", AffectedItem: "TSHIRT-S", TriggerAmount: 3, DiscountPercentage: 10}},
+		NxmRules: []NxMRule{
+			{RuleName: "T-Shirts 3x2", AffectedItem: "TSHIRT", BuyN: 3, PayM: 2},
+			{RuleName: "Large 2x1", AffectedItem: "TSHIRT-L", BuyN: 2, PayM: 1, RuleOptions: RuleOptions{Priority: 1}},
+		},
+	}
+	expected := "the rule T-Shirts 3x2 affects the variant TSHIRT-S of the item TSHIRT, which is already affected by the rule Small bulk"
+
+	//ACT
+	err := ValidateRules(rules, items)
+
+	//ASSERT
+	if configErr, ok := err.(*ConfigError); !ok || len(configErr.Problems) != 1 || configErr.Problems[0].Message != expected {
+		t.Errorf("Expected the problem %s, got: %v", expected, err)
+	}
+
+}
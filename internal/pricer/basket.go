@@ -0,0 +1,182 @@
+package pricer
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/rules"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+//A basket and the items scanned into it
+//Baskets are handed out by the BasketStore as copies, any change must go through BasketStore.Update so it is done
+//while holding the basket lock
+//UpdatedAt is the time of the last change made to the basket items, used to expire idle baskets
+//Coupons are the coupons applied to the basket, in the order they were applied
+//Items are the units of the items counted and Measures the quantity of the items sold by weight or measure, in units
+//of the item, an item is only in one of them
+//OrderNumber is the number of the order the basket was checked out as, a checked out basket can't be changed anymore
+type Basket struct {
+	Id          string                   `json:"id"`
+	CreatedAt   time.Time                `json:"createdAt"`
+	UpdatedAt   time.Time                `json:"updatedAt"`
+	Items       map[string]int           `json:"items"`
+	Measures    map[string]money.Decimal `json:"measures,omitempty"`
+	Coupons     []AppliedCoupon          `json:"coupons,omitempty"`
+	OrderNumber string                   `json:"orderNumber,omitempty"`
+}
+
+//A coupon applied to a basket and when it was applied, which decides the baskets that get it when it runs out
+type AppliedCoupon struct {
+	Code      string    `json:"code"`
+	AppliedAt time.Time `json:"appliedAt"`
+}
+
+//The contents of a basket as seen from outside the Pricer
+type BasketContents struct {
+	BasketId  string
+	CreatedAt time.Time
+	Lines     []BasketLine
+	Coupons   []string
+}
+
+//A scanned item and the number of units of it in the basket
+//The items sold by weight or measure have no units but the Measure weighed or measured in their Unit instead
+type BasketLine struct {
+	ItemId   string
+	Name     string
+	Quantity int
+	Measure  money.Decimal
+	Unit     string
+}
+
+func newBasket(id string, now time.Time) Basket {
+	return Basket{Id: id, CreatedAt: now, UpdatedAt: now, Items: make(map[string]int)}
+}
+
+//Returns the time of the last change made to the basket, baskets stored before UpdatedAt existed only have CreatedAt
+func (b Basket) lastActivity() time.Time {
+	if b.UpdatedAt.After(b.CreatedAt) {
+		return b.UpdatedAt
+	}
+	return b.CreatedAt
+}
+
+//Executes all the rules of the configuration on the basket items, the item rules first and the basket rules after them
+//in the order picked by the optimiser, which is the configured one when the optimiser is nil
+//Only the rules active for the basket coupons and whose schedule is active at the given time are executed
+//The returned subtotal is the exact sum of every rule's subtotal, it hasn't been rounded yet
+func (b Basket) executeRules(config PricingConfig, optimiser *rules.Optimiser, at time.Time) (rules.RuleResult, *rules.Assignment) {
+	codes := b.couponCodes()
+	executors := rules.ScheduledExecutors(rules.ActiveExecutors(config.Executors, config.Coupons, codes), at)
+	basketExecutors := rules.ScheduledBasketExecutors(rules.ActiveBasketExecutors(config.BasketExecutors, config.Coupons, codes), at)
+	result, assignment := optimiser.Execute(executors, basketExecutors, config.Items, b.Items, b.Measures)
+	if assignment != nil {
+		log.Infof("Promotions of basket %s executed in the order %v, %d orders evaluated (exhaustive: %t, timed out: %t)",
+			b.Id, assignment.Rules, assignment.Evaluated, assignment.Exhaustive, assignment.TimedOut)
+	}
+	return result, assignment
+}
+
+//Adds an item to the basket
+func (b *Basket) addItem(i string) {
+	b.Items[i]++
+}
+
+//Adds the weighed or measured quantity of an item to the basket
+func (b *Basket) addMeasure(i string, quantity money.Decimal) {
+	if b.Measures == nil {
+		b.Measures = make(map[string]money.Decimal)
+	}
+	b.Measures[i] += quantity
+}
+
+//Returns true if the basket has units or a quantity of the item
+func (b Basket) holds(i string) bool {
+	_, measured := b.Measures[i]
+	return b.Items[i] > 0 || measured
+}
+
+//Removes a unit of the item from the basket, the line is removed when no units are left
+//The whole quantity of an item sold by weight or measure is removed, as it can't be removed by units
+//returns false if the item wasn't in the basket
+func (b *Basket) removeItem(i string) bool {
+	if _, exs := b.Measures[i]; exs {
+		delete(b.Measures, i)
+		return true
+	}
+	q, exs := b.Items[i]
+	if !exs {
+		return false
+	}
+	if q <= 1 {
+		delete(b.Items, i)
+	} else {
+		b.Items[i]--
+	}
+	return true
+}
+
+//Sets the number of units of the item in the basket, a quantity of 0 removes the line so the rules don't see it
+func (b *Basket) setItemQuantity(i string, quantity int) {
+	if quantity == 0 {
+		delete(b.Items, i)
+	} else {
+		b.Items[i] = quantity
+	}
+}
+
+//Removes all the items of the basket
+func (b *Basket) clearItems() {
+	b.Items = make(map[string]int)
+	b.Measures = nil
+}
+
+//Returns the codes of the coupons applied to the basket
+func (b Basket) couponCodes() []string {
+	var codes []string
+	for _, c := range b.Coupons {
+		codes = append(codes, c.Code)
+	}
+	return codes
+}
+
+//Returns the coupon applied to the basket with the given code, if any
+func (b Basket) coupon(code string) (AppliedCoupon, bool) {
+	for _, c := range b.Coupons {
+		if c.Code == code {
+			return c, true
+		}
+	}
+	return AppliedCoupon{}, false
+}
+
+//Removes the coupon with the given code from the basket, returns false if it wasn't applied to it
+func (b *Basket) removeCoupon(code string) bool {
+	for i, c := range b.Coupons {
+		if c.Code == code {
+			b.Coupons = append(b.Coupons[:i:i], b.Coupons[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+//Returns a deep copy of the basket, so it can be read or modified without affecting the stored one
+func (b Basket) copy() Basket {
+	items := make(map[string]int, len(b.Items))
+	for k, v := range b.Items {
+		items[k] = v
+	}
+	b.Items = items
+	if b.Measures != nil {
+		measures := make(map[string]money.Decimal, len(b.Measures))
+		for k, v := range b.Measures {
+			measures[k] = v
+		}
+		b.Measures = measures
+	}
+	if b.Coupons != nil {
+		b.Coupons = append([]AppliedCoupon(nil), b.Coupons...)
+	}
+	return b
+}
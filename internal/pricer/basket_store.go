@@ -0,0 +1,207 @@
+package pricer
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+//Abstraction over the storage of the baskets, injected into the Pricer so it can be replaced by a durable
+//implementation or mocked in tests
+//Get and List return copies of the stored baskets, Update is the only way to modify a basket: the given function is
+//executed while holding the basket lock and its changes are only stored if it doesn't return an error
+//DeleteIf removes the basket only if the given condition holds for it, checking it while holding the basket lock so the
+//basket can't be modified between the check and its removal, and returns whether it was removed
+//The store also keeps the redemptions of the coupons: the baskets every coupon has been applied to, which outlive the
+//baskets so a coupon can't be redeemed again by removing them. Redeem records a redemption of the coupon by the basket
+//unless the coupon already has limit redemptions, returning ErrCouponExhausted, and returns false if the basket had
+//already redeemed it. Unredeem gives the redemption of the basket back and Redemptions returns the ids of the baskets
+//that have redeemed the coupon, sorted by id
+type BasketStore interface {
+	Create(b Basket) error
+	Get(basketId string) (Basket, error)
+	Update(basketId string, update func(b *Basket) error) error
+	Delete(basketId string) error
+	DeleteIf(basketId string, condition func(b Basket) bool) (bool, error)
+	List() ([]Basket, error)
+	Redeem(code string, basketId string, limit int) (bool, error)
+	Unredeem(code string, basketId string) error
+	Redemptions(code string) ([]string, error)
+}
+
+//In memory implementation of the BasketStore, the baskets are lost when the server stops
+//In a normal microservices environment, this would not be ideal at all as we would want our microservices to be stateless
+//and all the session state should be stored in an session store. In AWS, it would probably be DynamoDB or ElasticCache.
+//The whole idea of this application was to test as many things as possible from just Golang, so I went for concurrent
+//access to an in memory map
+type MemoryBasketStore struct {
+	baskets         map[string]*lockedBasket
+	basketsLock     *sync.RWMutex
+	redemptions     map[string]map[string]bool
+	redemptionsLock *sync.Mutex
+}
+
+//Every basket has its own lock so updating a basket doesn't block the rest of them
+//A basket is flagged as deleted once it's removed, so an update waiting for its lock meanwhile doesn't modify it
+//The basket lock is always taken before the lock of the baskets map
+type lockedBasket struct {
+	basket    Basket
+	itemsLock *sync.RWMutex
+	deleted   bool
+}
+
+func NewMemoryBasketStore() *MemoryBasketStore {
+	return &MemoryBasketStore{baskets: make(map[string]*lockedBasket), basketsLock: new(sync.RWMutex),
+		redemptions: make(map[string]map[string]bool), redemptionsLock: new(sync.Mutex)}
+}
+
+func (s *MemoryBasketStore) Create(b Basket) error {
+	s.basketsLock.Lock()
+	defer s.basketsLock.Unlock()
+	if _, exs := s.baskets[b.Id]; exs {
+		return errors.New("a basket with the same id already exists")
+	}
+	s.baskets[b.Id] = &lockedBasket{basket: b.copy(), itemsLock: new(sync.RWMutex)}
+	return nil
+}
+
+func (s *MemoryBasketStore) Get(basketId string) (Basket, error) {
+	lb := s.getLockedBasket(basketId)
+	if lb == nil {
+		return Basket{}, ErrBasketNotFound
+	}
+	lb.itemsLock.RLock()
+	defer lb.itemsLock.RUnlock()
+	return lb.basket.copy(), nil
+}
+
+//The update is applied to a copy of the basket, which replaces the stored one only if no error is returned
+func (s *MemoryBasketStore) Update(basketId string, update func(b *Basket) error) error {
+	lb := s.getLockedBasket(basketId)
+	if lb == nil {
+		return ErrBasketNotFound
+	}
+	lb.itemsLock.Lock()
+	defer lb.itemsLock.Unlock()
+	if lb.deleted {
+		return ErrBasketNotFound
+	}
+	b := lb.basket.copy()
+	if err := update(&b); err != nil {
+		return err
+	}
+	lb.basket = b
+	return nil
+}
+
+func (s *MemoryBasketStore) Delete(basketId string) error {
+	_, err := s.DeleteIf(basketId, func(Basket) bool { return true })
+	return err
+}
+
+func (s *MemoryBasketStore) DeleteIf(basketId string, condition func(b Basket) bool) (bool, error) {
+	lb := s.getLockedBasket(basketId)
+	if lb == nil {
+		return false, nil
+	}
+	lb.itemsLock.Lock()
+	defer lb.itemsLock.Unlock()
+	if lb.deleted || !condition(lb.basket.copy()) {
+		return false, nil
+	}
+	s.basketsLock.Lock()
+	defer s.basketsLock.Unlock()
+	lb.deleted = true
+	delete(s.baskets, basketId)
+	return true, nil
+}
+
+//Returns all the stored baskets sorted by creation time
+func (s *MemoryBasketStore) List() ([]Basket, error) {
+	s.basketsLock.RLock()
+	locked := make([]*lockedBasket, 0, len(s.baskets))
+	for _, lb := range s.baskets {
+		locked = append(locked, lb)
+	}
+	s.basketsLock.RUnlock()
+
+	baskets := make([]Basket, 0, len(locked))
+	for _, lb := range locked {
+		lb.itemsLock.RLock()
+		baskets = append(baskets, lb.basket.copy())
+		lb.itemsLock.RUnlock()
+	}
+	sort.Slice(baskets, func(i, j int) bool {
+		if baskets[i].CreatedAt.Equal(baskets[j].CreatedAt) {
+			return baskets[i].Id < baskets[j].Id
+		}
+		return baskets[i].CreatedAt.Before(baskets[j].CreatedAt)
+	})
+	return baskets, nil
+}
+
+func (s *MemoryBasketStore) Redeem(code string, basketId string, limit int) (bool, error) {
+	return s.redeem(code, basketId, limit, func() error { return nil })
+}
+
+func (s *MemoryBasketStore) Unredeem(code string, basketId string) error {
+	return s.unredeem(code, basketId, func() error { return nil })
+}
+
+func (s *MemoryBasketStore) Redemptions(code string) ([]string, error) {
+	s.redemptionsLock.Lock()
+	defer s.redemptionsLock.Unlock()
+	baskets := make([]string, 0, len(s.redemptions[code]))
+	for id := range s.redemptions[code] {
+		baskets = append(baskets, id)
+	}
+	sort.Strings(baskets)
+	return baskets, nil
+}
+
+//Records the redemption, executing persist while holding the redemptions lock only if it's a new one, the redemption
+//is not recorded if persist fails
+func (s *MemoryBasketStore) redeem(code string, basketId string, limit int, persist func() error) (bool, error) {
+	s.redemptionsLock.Lock()
+	defer s.redemptionsLock.Unlock()
+	redeemed := s.redemptions[code]
+	if redeemed[basketId] {
+		return false, nil
+	}
+	if len(redeemed) >= limit {
+		return false, ErrCouponExhausted
+	}
+	if err := persist(); err != nil {
+		return false, err
+	}
+	if redeemed == nil {
+		redeemed = make(map[string]bool)
+		s.redemptions[code] = redeemed
+	}
+	redeemed[basketId] = true
+	return true, nil
+}
+
+//Gives the redemption back, executing persist while holding the redemptions lock only if the basket had redeemed the
+//coupon, the redemption is kept if persist fails
+func (s *MemoryBasketStore) unredeem(code string, basketId string, persist func() error) error {
+	s.redemptionsLock.Lock()
+	defer s.redemptionsLock.Unlock()
+	if !s.redemptions[code][basketId] {
+		return nil
+	}
+	if err := persist(); err != nil {
+		return err
+	}
+	delete(s.redemptions[code], basketId)
+	if len(s.redemptions[code]) == 0 {
+		delete(s.redemptions, code)
+	}
+	return nil
+}
+
+func (s *MemoryBasketStore) getLockedBasket(basketId string) *lockedBasket {
+	s.basketsLock.RLock()
+	defer s.basketsLock.RUnlock()
+	return s.baskets[basketId]
+}
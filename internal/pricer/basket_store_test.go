@@ -0,0 +1,151 @@
+package pricer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryBasketStoreUpdate(t *testing.T) {
+
+	//ARRANGE
+	store := NewMemoryBasketStore()
+	store.Create(newBasket("BASKET", time.Now()))
+
+	//ACT
+	err := store.Update("BASKET", func(b *Basket) error {
+		b.addItem("MUG")
+		return nil
+	})
+
+	//ASSERT
+	if err != nil {
+		t.Errorf("Updating the basket shouldn't have produced an error, got: %+v", err)
+	}
+	if b, _ := store.Get("BASKET"); b.Items["MUG"] != 1 {
+		t.Errorf("The update should have been stored, got: %+v", b)
+	}
+
+}
+
+func TestMemoryBasketStoreFailedUpdateIsDiscarded(t *testing.T) {
+
+	//ARRANGE
+	store := NewMemoryBasketStore()
+	store.Create(newBasket("BASKET", time.Now()))
+
+	//ACT
+	err := store.Update("BASKET", func(b *Basket) error {
+		b.addItem("MUG")
+		return errors.New("failed update")
+	})
+
+	//ASSERT
+	if err == nil {
+		t.Errorf("The error of the update function should have been returned")
+	}
+	if b, _ := store.Get("BASKET"); len(b.Items) != 0 {
+		t.Errorf("A failed update shouldn't modify the stored basket, got: %+v", b)
+	}
+
+}
+
+func TestMemoryBasketStoreGetReturnsCopy(t *testing.T) {
+
+	//ARRANGE
+	store := NewMemoryBasketStore()
+	store.Create(newBasket("BASKET", time.Now()))
+
+	//ACT
+	b, _ := store.Get("BASKET")
+	b.addItem("MUG")
+
+	//ASSERT
+	if stored, _ := store.Get("BASKET"); len(stored.Items) != 0 {
+		t.Errorf("Modifying a basket obtained with Get shouldn't modify the stored one, got: %+v", stored)
+	}
+
+}
+
+func TestMemoryBasketStoreNonExistentBasket(t *testing.T) {
+
+	//ARRANGE
+	store := NewMemoryBasketStore()
+
+	//ACT
+	_, getErr := store.Get("FAKEBASKETID")
+	updateErr := store.Update("FAKEBASKETID", func(b *Basket) error { return nil })
+
+	//ASSERT
+	if getErr != ErrBasketNotFound || updateErr != ErrBasketNotFound {
+		t.Errorf("Accessing a non existent basket should produce ErrBasketNotFound, got: %v and %v", getErr, updateErr)
+	}
+
+}
+
+func TestMemoryBasketStoreList(t *testing.T) {
+
+	//ARRANGE
+	store := NewMemoryBasketStore()
+	store.Create(newBasket("FIRST", time.Now()))
+	store.Create(newBasket("SECOND", time.Now()))
+	store.Delete("FIRST")
+
+	//ACT
+	baskets, _ := store.List()
+
+	//ASSERT
+	if len(baskets) != 1 || baskets[0].Id != "SECOND" {
+		t.Errorf("Only the basket that hasn't been deleted should be listed, got: %+v", baskets)
+	}
+
+}
+
+func TestMemoryBasketStoreDeleteIf(t *testing.T) {
+
+	//ARRANGE
+	store := NewMemoryBasketStore()
+	store.Create(newBasket("BASKET", time.Now()))
+
+	//ACT
+	kept, keptErr := store.DeleteIf("BASKET", func(b Basket) bool { return len(b.Items) > 0 })
+	deleted, deletedErr := store.DeleteIf("BASKET", func(b Basket) bool { return len(b.Items) == 0 })
+
+	//ASSERT
+	if kept || keptErr != nil {
+		t.Errorf("The basket shouldn't have been removed if the condition doesn't hold, got: %t, %v", kept, keptErr)
+	}
+	if !deleted || deletedErr != nil {
+		t.Errorf("The basket should have been removed if the condition holds, got: %t, %v", deleted, deletedErr)
+	}
+	if _, err := store.Get("BASKET"); err != ErrBasketNotFound {
+		t.Errorf("The basket should have been removed from the store, got: %v", err)
+	}
+
+}
+
+func TestMemoryBasketStoreUpdateWaitingForDeletedBasket(t *testing.T) {
+
+	//ARRANGE
+	store := NewMemoryBasketStore()
+	store.Create(newBasket("BASKET", time.Now()))
+	updated := make(chan error)
+
+	//ACT
+	store.DeleteIf("BASKET", func(b Basket) bool {
+		go func() {
+			updated <- store.Update("BASKET", func(b *Basket) error {
+				b.addItem("MUG")
+				return nil
+			})
+		}()
+		time.Sleep(10 * time.Millisecond)
+		return true
+	})
+
+	//ASSERT
+	if err := <-updated; err != ErrBasketNotFound {
+		t.Errorf("An update waiting for a basket being removed shouldn't modify it, got: %v", err)
+	}
+
+}
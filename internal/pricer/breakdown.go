@@ -0,0 +1,188 @@
+package pricer
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"github.com/dagozba/golangsmallshop/internal/rules"
+	log "github.com/sirupsen/logrus"
+	"sort"
+	"time"
+)
+
+//Itemised explanation of how the total of a basket was obtained
+//Gross is the amount of the lines before any promotion, at the configured prices, and Total the amount paid once the
+//promotions are applied, Net and Tax are the parts of the Total without tax and of tax, split by tax class in Taxes
+type Breakdown struct {
+	BasketId     string
+	Lines        []BreakdownLine
+	AppliedRules []AppliedRule
+	SkippedRules []rules.SkippedRule
+	Assignment   *PromotionAssignment
+	Gross        money.Money
+	Net          money.Money
+	Tax          money.Money
+	Total        money.Money
+	Taxes        []TaxLine
+}
+
+//A line of the basket before applying any promotion
+//The lines of the items sold by weight or measure have the Measure weighed or measured in their Unit instead of units
+//and the UnitPrice is the price of one unit of measure
+type BreakdownLine struct {
+	ItemId    string
+	Name      string
+	Quantity  int
+	Measure   money.Decimal
+	Unit      string
+	UnitPrice money.Money
+	Gross     money.Money
+}
+
+//A promotion applied to the basket and the discount it produced
+//Consumed are the units of other items needed to get the discount, ie: the T-shirts that made a mug free
+//Stacked rules discounted the amount already charged by other rules instead of the item price
+//Measure is the quantity discounted of an item sold by weight or measure
+//Discounted are the units selected for the discount by a rule discounting several items, ie: the cheapest item of a
+//mix and match, the affected item is empty for them
+type AppliedRule struct {
+	RuleName     string
+	AffectedItem string
+	Units        int
+	Measure      money.Decimal
+	Discount     money.Money
+	Consumed     []rules.ItemUnits
+	Discounted   []rules.ItemUnits
+	Stacked      bool
+}
+
+//The order of execution of the rules picked by the Optimiser, see rules.Assignment
+type PromotionAssignment struct {
+	Rules      []string
+	Evaluated  int
+	Exhaustive bool
+	TimedOut   bool
+	Saving     money.Money
+}
+
+//Returns a deep copy of the breakdown, so it can be read or modified without affecting the stored one, see Order
+func (b Breakdown) copy() Breakdown {
+	if b.Lines != nil {
+		b.Lines = append([]BreakdownLine(nil), b.Lines...)
+	}
+	if b.AppliedRules != nil {
+		applied := make([]AppliedRule, len(b.AppliedRules))
+		for i, r := range b.AppliedRules {
+			if r.Consumed != nil {
+				r.Consumed = append([]rules.ItemUnits(nil), r.Consumed...)
+			}
+			if r.Discounted != nil {
+				r.Discounted = append([]rules.ItemUnits(nil), r.Discounted...)
+			}
+			applied[i] = r
+		}
+		b.AppliedRules = applied
+	}
+	if b.SkippedRules != nil {
+		b.SkippedRules = append([]rules.SkippedRule(nil), b.SkippedRules...)
+	}
+	if b.Assignment != nil {
+		assignment := *b.Assignment
+		if assignment.Rules != nil {
+			assignment.Rules = append([]string(nil), assignment.Rules...)
+		}
+		b.Assignment = &assignment
+	}
+	if b.Taxes != nil {
+		b.Taxes = append([]TaxLine(nil), b.Taxes...)
+	}
+	return b
+}
+
+//Calculates the total of the given basket the same way GetTotalAmount does, but also returns every line of the basket
+//and every discount applied by the pricing rules, so the customer can be told why the basket costs what it costs
+//The lines are sorted by item id and the applied rules keep the order in which the rules were executed
+//The rules that would have given a discount but were not applied, ie: because of their exclusive group, are returned
+//as skipped along with the reason, and the assignment picked by the optimiser if the Pricer has one
+//if the basket doesn't exist, any of its items is not configured anymore or any of its coupons can't be used anymore,
+//an error is returned
+func (p Pricer) GetBasketBreakdown(basketId string) (Breakdown, error) {
+	log.Infof("Getting the price breakdown of basket %s", basketId)
+	basket, err := p.getBasket(basketId)
+	if err != nil {
+		return Breakdown{}, err
+	}
+
+	config := p.Config()
+	at := p.pricedAt(basket, config)
+	if err := p.checkPriceable(basket, config, at); err != nil {
+		return Breakdown{}, err
+	}
+	return p.breakdown(basket, config, at), nil
+}
+
+//Prices the basket with the given configuration at the given time and explains how its total was obtained
+func (p Pricer) breakdown(basket Basket, config PricingConfig, at time.Time) Breakdown {
+	result, assignment := basket.executeRules(config, p.Optimiser, at)
+	taxes := taxSummary(result, config.Items, p.Rounding)
+	breakdown := Breakdown{BasketId: basket.Id, Net: taxes.Net, Tax: taxes.Tax, Total: taxes.Gross, Taxes: taxes.Lines,
+		SkippedRules: result.Skipped}
+	if assignment != nil {
+		breakdown.Assignment = &PromotionAssignment{Rules: assignment.Rules, Evaluated: assignment.Evaluated,
+			Exhaustive: assignment.Exhaustive, TimedOut: assignment.TimedOut, Saving: assignment.Saving.Round(p.Rounding)}
+	}
+
+	gross := money.Zero(config.Items.Currency())
+	for id, quantity := range basket.Items {
+		item := config.Items[id]
+		lineGross := item.Price.Times(quantity)
+		gross = gross.Add(lineGross)
+		breakdown.Lines = append(breakdown.Lines, BreakdownLine{
+			ItemId:    id,
+			Name:      item.Name,
+			Quantity:  quantity,
+			Unit:      parser.UnitEach,
+			UnitPrice: item.Price,
+			Gross:     lineGross.Round(p.Rounding),
+		})
+	}
+	for id, measure := range basket.Measures {
+		item := config.Items[id]
+		lineGross := item.Price.Subtotal().TimesDecimal(measure)
+		gross = gross.Add(lineGross)
+		breakdown.Lines = append(breakdown.Lines, BreakdownLine{
+			ItemId:    id,
+			Name:      item.Name,
+			Measure:   measure,
+			Unit:      item.Unit,
+			UnitPrice: item.Price,
+			Gross:     lineGross.Round(p.Rounding),
+		})
+	}
+	sort.Slice(breakdown.Lines, func(i, j int) bool { return breakdown.Lines[i].ItemId < breakdown.Lines[j].ItemId })
+	breakdown.Gross = gross.Round(p.Rounding)
+	breakdown.AppliedRules = p.roundAdjustments(result.Adjustments, breakdown.Gross.Amount-result.Subtotal.Round(p.Rounding).Amount)
+
+	return breakdown
+}
+
+//Rounds every discount to the minor unit of the currency
+//As each discount is rounded separately, their sum may be a cent away from the difference between the gross amount
+//and the total, which is only rounded once. That difference is assigned to the biggest discount so the breakdown always adds up
+func (p Pricer) roundAdjustments(adjustments []rules.Adjustment, totalDiscount int64) []AppliedRule {
+	var applied []AppliedRule
+	var roundedDiscount int64
+	biggest := -1
+	for i, a := range adjustments {
+		discount := a.Discount.Round(p.Rounding)
+		roundedDiscount += discount.Amount
+		if biggest < 0 || discount.Amount > applied[biggest].Discount.Amount {
+			biggest = i
+		}
+		applied = append(applied, AppliedRule{RuleName: a.RuleName, AffectedItem: a.AffectedItem, Units: a.Units, Measure: a.Measure, Discount: discount, Consumed: a.Consumed,
+			Discounted: a.Discounted, Stacked: a.Stacked})
+	}
+	if biggest >= 0 {
+		applied[biggest].Discount.Amount += totalDiscount - roundedDiscount
+	}
+	return applied
+}
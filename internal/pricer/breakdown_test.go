@@ -0,0 +1,253 @@
+package pricer
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"github.com/dagozba/golangsmallshop/internal/rules"
+	"reflect"
+	"testing"
+)
+
+func TestGetBasketBreakdown(t *testing.T) {
+
+	//ARRANGE
+	itemsParserMock := new(MockedItemsParser)
+	rulesStrategyFactory := rules.RuleStrategyFactory{RuleExecutors: []rules.RuleStrategyExecutor{
+		rules.BulkRuleStrategy{
+			Rule: parser.BulkRule{
+				RuleName:           "Bulk Rule",
+				AffectedItem:       "TSHIRT",
+				TriggerAmount:      3,
+				DiscountPercentage: 5}},
+		rules.DefaultRuleStrategy{}},
+	}
+	pricer := NewPricer(rulesStrategyFactory, itemsParserMock, NewMemoryBasketStore())
+	pricer.LoadItems("DUMMYPATH")
+
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("TSHIRT", bId)
+	pricer.ScanItem("TSHIRT", bId)
+	pricer.ScanItem("TSHIRT", bId)
+	pricer.ScanItem("MUG", bId)
+
+	expectedLines := []BreakdownLine{
+		{ItemId: "MUG", Name: "Company Coffee Mug", Quantity: 1, Unit: parser.UnitEach, UnitPrice: money.New(750, "EUR"), Gross: money.New(750, "EUR")},
+		{ItemId: "TSHIRT", Name: "Company T-Shirt", Quantity: 3, Unit: parser.UnitEach, UnitPrice: money.New(2000, "EUR"), Gross: money.New(6000, "EUR")},
+	}
+	expectedRule := AppliedRule{RuleName: "Bulk Rule", AffectedItem: "TSHIRT", Units: 3, Discount: money.New(300, "EUR")}
+
+	//ACT
+	breakdown, err := pricer.GetBasketBreakdown(bId)
+
+	//ASSERT
+	if err != nil {
+		t.Errorf("No errors should've been produced by the breakdown, got: %+v", err)
+	}
+
+	if len(breakdown.Lines) != len(expectedLines) {
+		t.Fatalf("The breakdown should have %d lines, got: %+v", len(expectedLines), breakdown.Lines)
+	}
+	for i := range expectedLines {
+		if breakdown.Lines[i] != expectedLines[i] {
+			t.Errorf("Breakdown line %d doesn't match, expected: %+v, got: %+v", i, expectedLines[i], breakdown.Lines[i])
+		}
+	}
+
+	if len(breakdown.AppliedRules) != 1 || !reflect.DeepEqual(breakdown.AppliedRules[0], expectedRule) {
+		t.Errorf("The Bulk Rule should be the only applied rule, expected: %+v, got: %+v", expectedRule, breakdown.AppliedRules)
+	}
+
+	if breakdown.Gross != money.New(6750, "EUR") || breakdown.Total != money.New(6450, "EUR") {
+		t.Errorf("The gross and total amounts don't match, expected: 67.50 EUR and 64.50 EUR, got: %s and %s", breakdown.Gross, breakdown.Total)
+	}
+
+}
+
+func TestGetBasketBreakdownDiscountsAddUp(t *testing.T) {
+
+	//ARRANGE
+	itemsParserMock := new(MockedItemsParser)
+	//A mug at 5% off costs exactly 7.125, which is rounded once to 7.13. The discount alone, 0.375, would round to 0.38
+	//so it has to be reported as 0.37 for the breakdown to add up
+	rulesStrategyFactory := rules.RuleStrategyFactory{RuleExecutors: []rules.RuleStrategyExecutor{
+		rules.BulkRuleStrategy{Rule: parser.BulkRule{RuleName: "Mug Rule", AffectedItem: "MUG", TriggerAmount: 1, DiscountPercentage: 5}},
+	}}
+	pricer := NewPricer(rulesStrategyFactory, itemsParserMock, NewMemoryBasketStore())
+	pricer.LoadItems("DUMMYPATH")
+
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("MUG", bId)
+
+	//ACT
+	breakdown, _ := pricer.GetBasketBreakdown(bId)
+
+	//ASSERT
+	if breakdown.Total != money.New(713, "EUR") {
+		t.Errorf("The total should be rounded once, expected: 7.13 EUR, got: %s", breakdown.Total)
+	}
+
+	if len(breakdown.AppliedRules) != 1 || breakdown.AppliedRules[0].Discount != money.New(37, "EUR") {
+		t.Errorf("The discount should be adjusted so the breakdown adds up, expected: 0.37 EUR, got: %+v", breakdown.AppliedRules)
+	}
+
+}
+
+func TestGetBasketBreakdownBuyXGetY(t *testing.T) {
+
+	//ARRANGE
+	itemsParserMock := new(MockedItemsParser)
+	rulesStrategyFactory := rules.RuleStrategyFactory{RuleExecutors: rules.BuildRuleExecutors(parser.Rules{
+		BuyXGetYRules: []parser.BuyXGetYRule{{RuleName: "Free Mug", TriggerItem: "TSHIRT", TriggerQuantity: 1,
+			RewardItem: "MUG", RewardQuantity: 1, RewardDiscountPercentage: 100}},
+	})}
+	pricer := NewPricer(rulesStrategyFactory, itemsParserMock, NewMemoryBasketStore())
+	pricer.LoadItems("DUMMYPATH")
+
+	bId, _ := pricer.CreateBasket()
+	pricer.SetItemQuantity("TSHIRT", bId, 2)
+	pricer.ScanItem("MUG", bId)
+
+	expectedRule := AppliedRule{RuleName: "Free Mug", AffectedItem: "MUG", Units: 1, Discount: money.New(750, "EUR"),
+		Consumed: []rules.ItemUnits{{ItemId: "TSHIRT", Units: 1}}}
+
+	//ACT
+	breakdown, _ := pricer.GetBasketBreakdown(bId)
+
+	//ASSERT
+	if len(breakdown.AppliedRules) != 1 || !reflect.DeepEqual(breakdown.AppliedRules[0], expectedRule) {
+		t.Errorf("The mug should be free for one of the T-shirts, expected: %+v, got: %+v", expectedRule, breakdown.AppliedRules)
+	}
+
+	if breakdown.Gross != money.New(4750, "EUR") || breakdown.Total != money.New(4000, "EUR") {
+		t.Errorf("The gross and total amounts don't match, expected: 47.50 EUR and 40.00 EUR, got: %s and %s", breakdown.Gross, breakdown.Total)
+	}
+
+}
+
+func TestGetBasketBreakdownBasketRule(t *testing.T) {
+
+	//ARRANGE
+	itemsParserMock := new(MockedItemsParser)
+	r := parser.Rules{
+		BulkRules:   []parser.BulkRule{{RuleName: "Bulk Rule", AffectedItem: "TSHIRT", TriggerAmount: 3, DiscountPercentage: 5}},
+		BasketRules: []parser.BasketRule{{RuleName: "10% off", DiscountPercentage: 10, ExcludedItems: []string{"MUG"}}},
+	}
+	rulesStrategyFactory := rules.RuleStrategyFactory{RuleExecutors: rules.BuildRuleExecutors(r), BasketExecutors: rules.BuildBasketExecutors(r)}
+	pricer := NewPricer(rulesStrategyFactory, itemsParserMock, NewMemoryBasketStore())
+	pricer.LoadItems("DUMMYPATH")
+
+	bId, _ := pricer.CreateBasket()
+	pricer.SetItemQuantity("TSHIRT", bId, 3)
+	pricer.ScanItem("MUG", bId)
+
+	//The basket rule is applied to the 57.00 of the T-shirts after the bulk rule, the mug is excluded
+	expectedRule := AppliedRule{RuleName: "10% off", Units: 3, Discount: money.New(570, "EUR")}
+
+	//ACT
+	breakdown, _ := pricer.GetBasketBreakdown(bId)
+
+	//ASSERT
+	if len(breakdown.AppliedRules) != 2 || !reflect.DeepEqual(breakdown.AppliedRules[1], expectedRule) {
+		t.Errorf("The basket rule should be applied after the bulk rule, expected: %+v, got: %+v", expectedRule, breakdown.AppliedRules)
+	}
+
+	if breakdown.Total != money.New(5880, "EUR") {
+		t.Errorf("The total doesn't match, expected: 58.80 EUR, got: %s", breakdown.Total)
+	}
+
+}
+
+func TestGetBasketBreakdownStackedAndSkippedRules(t *testing.T) {
+
+	//ARRANGE
+	itemsParserMock := new(MockedItemsParser)
+	r := parser.Rules{
+		NxmRules: []parser.NxMRule{{RuleName: "2x1", AffectedItem: "VOUCHER", BuyN: 2, PayM: 1}},
+		BulkRules: []parser.BulkRule{
+			{RuleName: "Loyalty", AffectedItem: "VOUCHER", TriggerAmount: 1, DiscountPercentage: 10,
+				RuleOptions: parser.RuleOptions{Stackable: true}},
+			{RuleName: "Bulk Rule", AffectedItem: "TSHIRT", TriggerAmount: 3, DiscountPercentage: 5,
+				RuleOptions: parser.RuleOptions{ExclusiveGroup: "summer"}},
+		},
+		BasketRules: []parser.BasketRule{{RuleName: "10% off", DiscountPercentage: 10,
+			RuleOptions: parser.RuleOptions{ExclusiveGroup: "summer"}}},
+	}
+	rulesStrategyFactory := rules.RuleStrategyFactory{RuleExecutors: rules.BuildRuleExecutors(r), BasketExecutors: rules.BuildBasketExecutors(r)}
+	pricer := NewPricer(rulesStrategyFactory, itemsParserMock, NewMemoryBasketStore())
+	pricer.LoadItems("DUMMYPATH")
+
+	bId, _ := pricer.CreateBasket()
+	pricer.SetItemQuantity("VOUCHER", bId, 4)
+	pricer.SetItemQuantity("TSHIRT", bId, 3)
+
+	//The loyalty discount takes 10% off the 10.00 charged by the 2x1, the basket rule is skipped as the bulk rule of
+	//its exclusive group has already been applied
+	expectedStacked := AppliedRule{RuleName: "Loyalty", AffectedItem: "VOUCHER", Units: 4, Discount: money.New(100, "EUR"), Stacked: true}
+	expectedSkipped := []rules.SkippedRule{{RuleName: "10% off", Reason: "the rule Bulk Rule of the exclusive group summer has already been applied"}}
+
+	//ACT
+	breakdown, _ := pricer.GetBasketBreakdown(bId)
+
+	//ASSERT
+	if len(breakdown.AppliedRules) != 3 || !reflect.DeepEqual(breakdown.AppliedRules[2], expectedStacked) {
+		t.Errorf("The stacked rule should be applied last, expected: %+v, got: %+v", expectedStacked, breakdown.AppliedRules)
+	}
+
+	if !reflect.DeepEqual(breakdown.SkippedRules, expectedSkipped) {
+		t.Errorf("The basket rule should have been skipped, expected: %+v, got: %+v", expectedSkipped, breakdown.SkippedRules)
+	}
+
+	if breakdown.Total != money.New(6600, "EUR") {
+		t.Errorf("The total doesn't match, expected: 66.00 EUR, got: %s", breakdown.Total)
+	}
+
+}
+
+func TestGetBasketBreakdownOptimised(t *testing.T) {
+
+	//ARRANGE
+	itemsParserMock := new(MockedItemsParser)
+	r := parser.Rules{
+		NxmRules:  []parser.NxMRule{{RuleName: "3x2", AffectedItem: "VOUCHER", BuyN: 3, PayM: 2, RuleOptions: parser.RuleOptions{Priority: 1}}},
+		BulkRules: []parser.BulkRule{{RuleName: "40% off", AffectedItem: "VOUCHER", TriggerAmount: 3, DiscountPercentage: 40}},
+	}
+	rulesStrategyFactory := rules.RuleStrategyFactory{RuleExecutors: rules.BuildRuleExecutors(r)}
+	pricer := NewPricer(rulesStrategyFactory, itemsParserMock, NewMemoryBasketStore())
+	pricer.Optimiser = &rules.Optimiser{}
+	pricer.LoadItems("DUMMYPATH")
+
+	bId, _ := pricer.CreateBasket()
+	pricer.SetItemQuantity("VOUCHER", bId, 4)
+
+	//40% off the 4 vouchers is 12.00, the 3x2 first would charge 15.00
+	expected := &PromotionAssignment{Rules: []string{"40% off", "3x2"}, Evaluated: 2, Exhaustive: true, Saving: money.New(300, "EUR")}
+
+	//ACT
+	breakdown, _ := pricer.GetBasketBreakdown(bId)
+	total, _ := pricer.GetTotalAmount(bId)
+
+	//ASSERT
+	if !reflect.DeepEqual(breakdown.Assignment, expected) {
+		t.Errorf("The assignment doesn't match, expected: %+v, got: %+v", expected, breakdown.Assignment)
+	}
+
+	if breakdown.Total != money.New(1200, "EUR") || total != breakdown.Total {
+		t.Errorf("The total doesn't match, expected: 12.00 EUR, got: %s and %s", breakdown.Total, total)
+	}
+
+}
+
+func TestGetBasketBreakdownNonExistentBasket(t *testing.T) {
+
+	//ARRANGE
+	pricer := NewPricer(rules.RuleStrategyFactory{}, nil, NewMemoryBasketStore())
+
+	//ACT
+	_, err := pricer.GetBasketBreakdown("FAKEBASKETID")
+
+	//ASSERT
+	if err == nil {
+		t.Errorf("An error should've been produced by getting the breakdown of a non existent basket")
+	}
+
+}
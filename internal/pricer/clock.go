@@ -0,0 +1,71 @@
+package pricer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+//Source of the current time of the Pricer, a fixed clock can be injected to freeze the time, ie: in tests
+type Clock interface {
+	Now() time.Time
+}
+
+//Clock that always returns the same time
+type FixedClock time.Time
+
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+//Decides the time the schedules of the rules and the validity of the coupons are checked at when a basket is priced,
+//which matters when a promotion starts or ends while the basket is open
+//PriceAtTotalTime, the default, checks them when the total is requested, so a promotion that has ended is not applied
+//even if it was active when the items were scanned
+//PriceAtScanTime checks them at the last change made to the basket, ie: the last item scanned, so the customer pays
+//what the promotions were when the basket was filled, even if it's priced after they have ended
+type SchedulePolicy int
+
+const (
+	PriceAtTotalTime SchedulePolicy = iota
+	PriceAtScanTime
+)
+
+var schedulePolicyNames = map[SchedulePolicy]string{
+	PriceAtTotalTime: "total",
+	PriceAtScanTime:  "scan",
+}
+
+//Parses the name of a schedule policy as used in the server flags: total or scan
+func ParseSchedulePolicy(name string) (SchedulePolicy, error) {
+	for policy, n := range schedulePolicyNames {
+		if strings.EqualFold(n, strings.TrimSpace(name)) {
+			return policy, nil
+		}
+	}
+	return PriceAtTotalTime, fmt.Errorf("unknown promotion time '%s', expected one of total or scan", name)
+}
+
+func (s SchedulePolicy) String() string {
+	return schedulePolicyNames[s]
+}
+
+//Returns the current time of the Pricer clock, the system time if there's no clock
+func (p Pricer) now() time.Time {
+	if p.Clock == nil {
+		return time.Now()
+	}
+	return p.Clock.Now()
+}
+
+//Returns the time the basket is priced at according to the schedule policy, in the time zone of the configuration
+func (p Pricer) pricedAt(b Basket, config PricingConfig) time.Time {
+	at := p.now()
+	if p.SchedulePolicy == PriceAtScanTime {
+		at = b.lastActivity()
+	}
+	if config.Location == nil {
+		return at.In(time.Local)
+	}
+	return at.In(config.Location)
+}
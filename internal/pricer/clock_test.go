@@ -0,0 +1,104 @@
+package pricer
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"testing"
+	"time"
+)
+
+func TestGetTotalAmountPromotionEndedAtTotalTime(t *testing.T) {
+
+	//ARRANGE
+	until := time.Date(2026, 6, 1, 20, 0, 0, 0, time.UTC)
+	r := parser.Rules{BulkRules: []parser.BulkRule{{RuleName: "Happy Hour", AffectedItem: "MUG", TriggerAmount: 1, DiscountPercentage: 20,
+		RuleOptions: parser.RuleOptions{Schedule: parser.Schedule{ValidUntil: until}}}}}
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, r, items)
+	pricer.SchedulePolicy = PriceAtTotalTime
+	pricer.Clock = FixedClock(until.Add(-time.Minute))
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("MUG", bId)
+	during, _ := pricer.GetTotalAmount(bId)
+
+	//ACT
+	pricer.Clock = FixedClock(until.Add(time.Minute))
+	after, err := pricer.GetTotalAmount(bId)
+
+	//ASSERT
+	if err != nil || during != money.New(600, "EUR") || after != money.New(750, "EUR") {
+		t.Errorf("The promotion should only be applied while it's active, expected: 6.00 and 7.50, got: %s and %s (%v)", during, after, err)
+	}
+
+}
+
+func TestGetTotalAmountPromotionEndedAtScanTime(t *testing.T) {
+
+	//ARRANGE
+	until := time.Date(2026, 6, 1, 20, 0, 0, 0, time.UTC)
+	r := parser.Rules{BulkRules: []parser.BulkRule{{RuleName: "Happy Hour", AffectedItem: "MUG", TriggerAmount: 1, DiscountPercentage: 20,
+		RuleOptions: parser.RuleOptions{Schedule: parser.Schedule{ValidUntil: until}}}}}
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, r, items)
+	pricer.SchedulePolicy = PriceAtScanTime
+	pricer.Clock = FixedClock(until.Add(-time.Minute))
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("MUG", bId)
+
+	//ACT
+	pricer.Clock = FixedClock(until.Add(time.Minute))
+	total, err := pricer.GetTotalAmount(bId)
+	breakdown, _ := pricer.GetBasketBreakdown(bId)
+	pricer.ScanItem("MUG", bId)
+	afterScan, _ := pricer.GetTotalAmount(bId)
+
+	//ASSERT
+	if err != nil || total != money.New(600, "EUR") || breakdown.Total != total {
+		t.Errorf("The promotion active when the basket was filled should have been applied, expected: 6.00, got: %s (%v)", total, err)
+	}
+
+	if afterScan != money.New(1500, "EUR") {
+		t.Errorf("The promotion should not be applied once an item is scanned after it has ended, expected: 15.00, got: %s", afterScan)
+	}
+
+}
+
+func TestGetTotalAmountScheduleTimeZone(t *testing.T) {
+
+	//ARRANGE
+	r := parser.Rules{TimeZone: "Pacific/Auckland", BulkRules: []parser.BulkRule{{RuleName: "Saturday Mugs", AffectedItem: "MUG",
+		TriggerAmount: 1, DiscountPercentage: 20,
+		RuleOptions: parser.RuleOptions{Schedule: parser.Schedule{DaysOfWeek: []string{"saturday"}}}}}}
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, r, items)
+	//Friday in UTC, but already Saturday in Auckland
+	pricer.Clock = FixedClock(time.Date(2026, 6, 12, 20, 0, 0, 0, time.UTC))
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("MUG", bId)
+
+	//ACT
+	total, _ := pricer.GetTotalAmount(bId)
+
+	//ASSERT
+	if total != money.New(600, "EUR") {
+		t.Errorf("The day of the week should have been checked in the configured time zone, expected: 6.00, got: %s", total)
+	}
+
+}
+
+func TestParseSchedulePolicy(t *testing.T) {
+
+	//ACT
+	scan, err := ParseSchedulePolicy(" Scan")
+	_, errUnknown := ParseSchedulePolicy("checkout")
+
+	//ASSERT
+	if err != nil || scan != PriceAtScanTime || scan.String() != "scan" {
+		t.Errorf("The scan policy should have been parsed, got: %s (%v)", scan, err)
+	}
+
+	if errUnknown == nil {
+		t.Errorf("An unknown policy should have been rejected")
+	}
+
+}
@@ -0,0 +1,63 @@
+package pricer
+
+import (
+	"fmt"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"github.com/dagozba/golangsmallshop/internal/rules"
+	"time"
+)
+
+//The configuration the baskets are priced with: the configured items and the rules executed on them
+//A PricingConfig is never modified once it is in use, reloading the configuration replaces it as a whole, so a
+//calculation that has started with a configuration finishes with it even if a new one is loaded meanwhile
+//Executors are executed in the item phase and BasketExecutors in the basket phase, see rules.RuleStrategyFactory
+//Coupons are the coupons that can be applied to the baskets by code, the rules linked to them are only executed on the
+//baskets they are applied to
+//Location is the time zone the schedules of the rules are checked in, the local time zone of the server if it's nil
+//Version identifies the contents of the config files it was loaded from, see ConfigVersion, and it's recorded in the
+//orders priced with it
+type PricingConfig struct {
+	Items           parser.ConfiguredItems
+	Executors       []rules.RuleStrategyExecutor
+	BasketExecutors []rules.BasketRuleStrategyExecutor
+	Coupons         map[string]parser.Coupon
+	Location        *time.Location
+	Version         string
+	//The ids of the items by the GTIN-14 form of their barcodes, built by SetConfig, see parser.NormaliseBarcode
+	Barcodes map[string]string
+}
+
+//Builds a complete configuration from the given rules and items, checking they are consistent with each other:
+//all the items must share the same currency and the rules can't conflict with each other or affect items that are not
+//configured, see parser.ValidateRules. The items selected by category or tags by the rules are resolved once here
+func NewPricingConfig(r parser.Rules, items parser.ConfiguredItems) (PricingConfig, error) {
+	currency := items.Currency()
+	for id, item := range items {
+		if item.Price.Currency != currency {
+			return PricingConfig{}, fmt.Errorf("the item %s is priced in %s but the rest of the items are priced in %s", id, item.Price.Currency, currency)
+		}
+	}
+	if err := parser.ValidateRules(r, items); err != nil {
+		return PricingConfig{}, err
+	}
+	r = r.ResolveSelectors(items)
+	return PricingConfig{Items: items, Executors: rules.BuildRuleExecutors(r), BasketExecutors: rules.BuildBasketExecutors(r),
+		Coupons: r.CouponsByCode(), Location: r.Location()}, nil
+}
+
+//Returns the configuration in use
+//Before any configuration has been loaded, it's made of the StrategyFactory executors and no items
+func (p Pricer) Config() PricingConfig {
+	if c, ok := p.config.Load().(PricingConfig); ok {
+		return c
+	}
+	return PricingConfig{Executors: p.StrategyFactory.RuleExecutors, BasketExecutors: p.StrategyFactory.BasketExecutors,
+		Coupons: p.StrategyFactory.Coupons, Location: p.StrategyFactory.Location}
+}
+
+//Replaces the configuration in use, the items with stock that were not tracked yet start being tracked
+func (p *Pricer) SetConfig(c PricingConfig) {
+	c.Barcodes = c.Items.BarcodeIndex()
+	p.config.Store(c)
+	p.Stock.load(c.Items)
+}
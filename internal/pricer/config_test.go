@@ -0,0 +1,107 @@
+package pricer
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"github.com/dagozba/golangsmallshop/internal/rules"
+	"testing"
+)
+
+func TestNewPricingConfig(t *testing.T) {
+
+	//ARRANGE
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	r := parser.Rules{
+		BulkRules: []parser.BulkRule{{RuleName: "Bulk Rule", AffectedItem: "TSHIRT", TriggerAmount: 3, DiscountPercentage: 5}},
+		NxmRules:  []parser.NxMRule{{RuleName: "NxM Rule", AffectedItem: "VOUCHER", BuyN: 2, PayM: 1}},
+	}
+
+	//ACT
+	config, err := NewPricingConfig(r, items)
+
+	//ASSERT
+	if err != nil {
+		t.Errorf("A consistent configuration shouldn't have produced an error, got: %+v", err)
+	}
+	if len(config.Executors) != 3 || len(config.Items) != 3 {
+		t.Errorf("The configuration should have 3 items and 3 executors (1 Bulk, 1 NxM and Default), got: %+v", config)
+	}
+
+}
+
+func TestNewPricingConfigResolvesSelectors(t *testing.T) {
+
+	//ARRANGE
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	for _, id := range []string{"TSHIRT", "MUG"} {
+		item := items[id]
+		item.Tags = []string{"logo"}
+		items[id] = item
+	}
+	r := parser.Rules{
+		NxmRules: []parser.NxMRule{{RuleName: "Logo 2x1", ItemSelector: parser.ItemSelector{AffectedTags: []string{"logo"}}, BuyN: 2, PayM: 1}},
+	}
+
+	//ACT
+	config, err := NewPricingConfig(r, items)
+
+	//ASSERT
+	if err != nil || len(config.Executors) != 3 {
+		t.Errorf("The rule should have been executed for the T-shirt and the mug, got: %d executors, %v", len(config.Executors), err)
+	}
+
+}
+
+func TestNewPricingConfigInconsistent(t *testing.T) {
+
+	//ARRANGE
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	mixedCurrencies, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	mixedCurrencies["BOOK"] = parser.ItemDefinition{Name: "Book", Price: money.New(1000, "USD")}
+	tests := []struct {
+		name  string
+		rules parser.Rules
+		items parser.ConfiguredItems
+	}{
+		{"Rule on a non configured item", parser.Rules{
+			NxmRules: []parser.NxMRule{{RuleName: "NxM Rule", AffectedItem: "BOOK", BuyN: 2, PayM: 1}},
+		}, items},
+		{"Two rules on the same item", parser.Rules{
+			BulkRules: []parser.BulkRule{{RuleName: "Bulk Rule", AffectedItem: "MUG", TriggerAmount: 3, DiscountPercentage: 5}},
+			NxmRules:  []parser.NxMRule{{RuleName: "NxM Rule", AffectedItem: "MUG", BuyN: 2, PayM: 1}},
+		}, items},
+		{"Rule selecting no item", parser.Rules{
+			NxmRules: []parser.NxMRule{{RuleName: "NxM Rule", ItemSelector: parser.ItemSelector{AffectedCategory: "books"}, BuyN: 2, PayM: 1}},
+		}, items},
+		{"Items in different currencies", parser.Rules{}, mixedCurrencies},
+	}
+
+	for _, test := range tests {
+		//ACT
+		_, err := NewPricingConfig(test.rules, test.items)
+
+		//ASSERT
+		if err == nil {
+			t.Errorf("%s: an error should've been produced", test.name)
+		}
+	}
+
+}
+
+func TestSetConfigSharedByCopies(t *testing.T) {
+
+	//ARRANGE
+	pricer := NewPricer(rules.RuleStrategyFactory{}, nil, NewMemoryBasketStore())
+	copied := *pricer
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	config, _ := NewPricingConfig(parser.Rules{}, items)
+
+	//ACT
+	pricer.SetConfig(config)
+
+	//ASSERT
+	if len(copied.Config().Items) != 3 {
+		t.Errorf("A copy of the Pricer taken before the configuration was set should use it, got: %+v", copied.Config().Items)
+	}
+
+}
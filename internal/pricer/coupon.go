@@ -0,0 +1,158 @@
+package pricer
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+//Applies the coupon with the given code to the basket, so the rules linked to it are executed on the basket
+//returns an error if the basket doesn't exist, the coupon doesn't exist, it's not valid at this time, it has been
+//redeemed by the maximum number of baskets or it's single use and it has already been applied to the basket
+//The redemption is recorded by the basket store before the coupon is applied and given back if it can't be applied,
+//so the coupon is never applied to more baskets than allowed, see BasketStore.Redeem
+func (p *Pricer) ApplyCoupon(code string, basketId string) (bool, error) {
+	log.Infof("Applying coupon %s to basket %s", code, basketId)
+	if _, err := p.getBasket(basketId); err != nil {
+		return false, err
+	}
+	coupon, exs := p.Config().Coupons[code]
+	if !exs {
+		return false, newCouponError(ErrCouponNotFound, basketId, code)
+	}
+	now := p.now()
+	if err := checkValidity(coupon, now); err != nil {
+		return false, newCouponError(err, basketId, code)
+	}
+	redeemed, err := p.redeem(coupon, basketId)
+	if err != nil {
+		return false, err
+	}
+
+	err = p.applyUpdate(basketId, func(b *Basket) error {
+		if _, applied := b.coupon(code); applied {
+			if coupon.SingleUsePerBasket {
+				return ErrCouponAlreadyApplied
+			}
+			return nil
+		}
+		b.Coupons = append(b.Coupons, AppliedCoupon{Code: code, AppliedAt: now})
+		return nil
+	})
+	if err != nil {
+		if redeemed {
+			p.unredeem(code, basketId)
+		}
+		return false, newCouponError(err, basketId, code)
+	}
+	log.Infof("Coupon %s applied to the basket %s", code, basketId)
+	return true, nil
+}
+
+//Removes the coupon with the given code from the basket, the rules linked to it are not executed on the basket anymore
+//and the redemption of the basket is given back, so it doesn't count towards the maximum number of redemptions of the
+//coupon. Removing the basket, or letting it expire, doesn't give it back
+//returns an error if the basket doesn't exist or the coupon hasn't been applied to it
+func (p *Pricer) RemoveCoupon(code string, basketId string) (bool, error) {
+	log.Infof("Removing coupon %s from basket %s", code, basketId)
+	err := p.applyUpdate(basketId, func(b *Basket) error {
+		if !b.removeCoupon(code) {
+			return ErrCouponNotInBasket
+		}
+		return nil
+	})
+	if err != nil {
+		return false, newCouponError(err, basketId, code)
+	}
+	p.unredeem(code, basketId)
+	log.Infof("Coupon %s removed from the basket %s", code, basketId)
+	return true, nil
+}
+
+//Checks every coupon applied to the basket can still be used at the given time, returns an error for the first one that
+//can't: it doesn't exist anymore or it's not valid at that time
+//The maximum number of redemptions is not checked, as the basket redeemed the coupon when it was applied
+func (p Pricer) checkCoupons(b Basket, config PricingConfig, now time.Time) error {
+	for _, applied := range b.Coupons {
+		coupon, exs := config.Coupons[applied.Code]
+		if !exs {
+			return newCouponError(ErrCouponNotFound, b.Id, applied.Code)
+		}
+		if err := checkValidity(coupon, now); err != nil {
+			return newCouponError(err, b.Id, applied.Code)
+		}
+	}
+	return nil
+}
+
+//Records the redemption of the coupon by the basket, only for the coupons with a maximum number of redemptions
+//The orders checked out with the coupon whose redemption is not kept by the basket store, ie: the basket store was
+//emptied by a restart but the orders were not, are taken from the maximum, so every order keeps counting
+//returns true if it's a new redemption, so it can be given back if the coupon can't be applied
+func (p Pricer) redeem(coupon parser.Coupon, basketId string) (bool, error) {
+	if coupon.MaxRedemptions == 0 {
+		return false, nil
+	}
+	ordered, err := p.orderedRedemptions(coupon.Code)
+	if err != nil {
+		return false, newPricerError(err, basketId, "")
+	}
+	limit := coupon.MaxRedemptions - ordered
+	if limit <= 0 {
+		return false, newCouponError(ErrCouponExhausted, basketId, coupon.Code)
+	}
+	redeemed, err := p.Baskets.Redeem(coupon.Code, basketId, limit)
+	if errors.Is(err, ErrCouponExhausted) {
+		return false, newCouponError(err, basketId, coupon.Code)
+	} else if err != nil {
+		return false, newPricerError(err, basketId, "")
+	}
+	return redeemed, nil
+}
+
+//Returns the number of baskets checked out with the coupon that are not among the redemptions kept by the basket store
+//There are none without an order store
+func (p Pricer) orderedRedemptions(code string) (int, error) {
+	if p.Orders == nil {
+		return 0, nil
+	}
+	ordered, err := p.Orders.Redemptions(code)
+	if err != nil || len(ordered) == 0 {
+		return 0, err
+	}
+	redeemed, err := p.Baskets.Redemptions(code)
+	if err != nil {
+		return 0, err
+	}
+	kept := make(map[string]bool, len(redeemed))
+	for _, id := range redeemed {
+		kept[id] = true
+	}
+	n := 0
+	for _, id := range ordered {
+		if !kept[id] {
+			n++
+		}
+	}
+	return n, nil
+}
+
+//Gives the redemption of the coupon by the basket back, it's only logged if it fails, so the coupon keeps counting it
+func (p Pricer) unredeem(code string, basketId string) {
+	if err := p.Baskets.Unredeem(code, basketId); err != nil {
+		log.Errorf("The redemption of the coupon '%s' by the basket '%s' couldn't be given back: %v", code, basketId, err)
+	}
+}
+
+//Returns an error if the coupon can't be used at the given time
+func checkValidity(c parser.Coupon, now time.Time) error {
+	if !c.ValidFrom.IsZero() && now.Before(c.ValidFrom) {
+		return fmt.Errorf("%w, it can be used from %s", ErrCouponNotValidYet, c.ValidFrom.Format(time.RFC3339))
+	}
+	if !c.ValidUntil.IsZero() && !now.Before(c.ValidUntil) {
+		return fmt.Errorf("%w, it could be used until %s", ErrCouponExpired, c.ValidUntil.Format(time.RFC3339))
+	}
+	return nil
+}
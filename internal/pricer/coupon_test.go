@@ -0,0 +1,326 @@
+package pricer
+
+import (
+	"errors"
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"testing"
+	"time"
+)
+
+func TestApplyCoupon(t *testing.T) {
+
+	//ARRANGE
+	r := parser.Rules{BasketRules: []parser.BasketRule{{RuleName: "Spring", DiscountPercentage: 10}}, Coupons: []parser.Coupon{{Code: "SPRING10", RuleName: "Spring"}}}
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, r, items)
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("TSHIRT", bId)
+	before, _ := pricer.GetTotalAmount(bId)
+
+	//ACT
+	result, err := pricer.ApplyCoupon("SPRING10", bId)
+	after, _ := pricer.GetTotalAmount(bId)
+	contents, _ := pricer.GetBasket(bId)
+
+	//ASSERT
+	if !result || err != nil {
+		t.Fatalf("The coupon should have been applied, got: %v", err)
+	}
+
+	if before != money.New(2000, "EUR") || after != money.New(1800, "EUR") {
+		t.Errorf("The rule linked to the coupon should only be applied with it, expected: 20.00 and 18.00, got: %s and %s", before, after)
+	}
+
+	if len(contents.Coupons) != 1 || contents.Coupons[0] != "SPRING10" {
+		t.Errorf("The basket should show the applied coupon, got: %v", contents.Coupons)
+	}
+
+}
+
+func TestRemoveCoupon(t *testing.T) {
+
+	//ARRANGE
+	r := parser.Rules{BasketRules: []parser.BasketRule{{RuleName: "Spring", DiscountPercentage: 10}}, Coupons: []parser.Coupon{{Code: "SPRING10", RuleName: "Spring"}}}
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, r, items)
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("TSHIRT", bId)
+	pricer.ApplyCoupon("SPRING10", bId)
+
+	//ACT
+	result, err := pricer.RemoveCoupon("SPRING10", bId)
+	_, errAgain := pricer.RemoveCoupon("SPRING10", bId)
+	total, _ := pricer.GetTotalAmount(bId)
+
+	//ASSERT
+	if !result || err != nil || total != money.New(2000, "EUR") {
+		t.Errorf("The coupon should have been removed, got: %v (%s)", err, total)
+	}
+
+	var pricerErr *PricerError
+	if !errors.Is(errAgain, ErrCouponNotInBasket) || !errors.As(errAgain, &pricerErr) || pricerErr.CouponCode != "SPRING10" {
+		t.Errorf("Removing a coupon that isn't applied should have failed, got: %v", errAgain)
+	}
+
+}
+
+func TestApplyCouponNotValid(t *testing.T) {
+
+	//ARRANGE
+	now := time.Now()
+	tests := []struct {
+		name     string
+		coupon   parser.Coupon
+		code     string
+		expected error
+	}{
+		{"Unknown coupon", parser.Coupon{Code: "SPRING10", RuleName: "Spring"}, "SUMMER10", ErrCouponNotFound},
+		{"Expired coupon", parser.Coupon{Code: "SPRING10", RuleName: "Spring", ValidUntil: now.Add(-time.Hour)}, "SPRING10", ErrCouponExpired},
+		{"Future coupon", parser.Coupon{Code: "SPRING10", RuleName: "Spring", ValidFrom: now.Add(time.Hour)}, "SPRING10", ErrCouponNotValidYet},
+	}
+
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+
+	for _, test := range tests {
+		r := parser.Rules{BasketRules: []parser.BasketRule{{RuleName: "Spring", DiscountPercentage: 10}}, Coupons: []parser.Coupon{test.coupon}}
+		pricer := newTestPricer(t, r, items)
+		bId, _ := pricer.CreateBasket()
+
+		//ACT
+		_, err := pricer.ApplyCoupon(test.code, bId)
+
+		//ASSERT
+		if !errors.Is(err, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, err)
+		}
+	}
+
+}
+
+func TestApplyCouponSingleUsePerBasket(t *testing.T) {
+
+	//ARRANGE
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	spring := []parser.BasketRule{{RuleName: "Spring", DiscountPercentage: 10}}
+	single := newTestPricer(t, parser.Rules{BasketRules: spring, Coupons: []parser.Coupon{{Code: "SPRING10", RuleName: "Spring", SingleUsePerBasket: true}}}, items)
+	singleId, _ := single.CreateBasket()
+	single.ApplyCoupon("SPRING10", singleId)
+	multiple := newTestPricer(t, parser.Rules{BasketRules: spring, Coupons: []parser.Coupon{{Code: "SPRING10", RuleName: "Spring"}}}, items)
+	multipleId, _ := multiple.CreateBasket()
+	multiple.ApplyCoupon("SPRING10", multipleId)
+
+	//ACT
+	_, singleErr := single.ApplyCoupon("SPRING10", singleId)
+	_, multipleErr := multiple.ApplyCoupon("SPRING10", multipleId)
+
+	//ASSERT
+	if !errors.Is(singleErr, ErrCouponAlreadyApplied) {
+		t.Errorf("A single use coupon can't be applied twice to the same basket, got: %v", singleErr)
+	}
+
+	if b, _ := multiple.Baskets.Get(multipleId); multipleErr != nil || len(b.Coupons) != 1 {
+		t.Errorf("Applying the coupon again should have had no effect, got: %v (%+v)", multipleErr, b.Coupons)
+	}
+
+}
+
+func TestApplyCouponExhausted(t *testing.T) {
+
+	//ARRANGE
+	r := parser.Rules{BasketRules: []parser.BasketRule{{RuleName: "Spring", DiscountPercentage: 10}}, Coupons: []parser.Coupon{{Code: "SPRING10", RuleName: "Spring", MaxRedemptions: 1}}}
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, r, items)
+	first, _ := pricer.CreateBasket()
+	second, _ := pricer.CreateBasket()
+	third, _ := pricer.CreateBasket()
+	pricer.ApplyCoupon("SPRING10", first)
+
+	//ACT
+	_, err := pricer.ApplyCoupon("SPRING10", second)
+	pricer.RemoveBasket(first)
+	_, removedErr := pricer.ApplyCoupon("SPRING10", second)
+	pricer.ApplyCoupon("SPRING10", first)
+
+	//ASSERT
+	if !errors.Is(err, ErrCouponExhausted) {
+		t.Errorf("The coupon should have run out, got: %v", err)
+	}
+
+	if !errors.Is(removedErr, ErrCouponExhausted) {
+		t.Errorf("Removing the basket holding the coupon shouldn't give its redemption back, got: %v", removedErr)
+	}
+
+	if _, err := pricer.ApplyCoupon("SPRING10", third); !errors.Is(err, ErrCouponExhausted) {
+		t.Errorf("The coupon should still have run out, got: %v", err)
+	}
+
+}
+
+func TestRemoveCouponGivesRedemptionBack(t *testing.T) {
+
+	//ARRANGE
+	r := parser.Rules{BasketRules: []parser.BasketRule{{RuleName: "Spring", DiscountPercentage: 10}}, Coupons: []parser.Coupon{{Code: "SPRING10", RuleName: "Spring", MaxRedemptions: 1}}}
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, r, items)
+	first, _ := pricer.CreateBasket()
+	second, _ := pricer.CreateBasket()
+	pricer.ApplyCoupon("SPRING10", first)
+
+	//ACT
+	pricer.RemoveCoupon("SPRING10", first)
+	_, err := pricer.ApplyCoupon("SPRING10", second)
+
+	//ASSERT
+	if err != nil {
+		t.Errorf("The coupon should be available again once it's removed from the basket holding it, got: %v", err)
+	}
+
+}
+
+func TestCouponRedemptionsSurviveExpiry(t *testing.T) {
+
+	//ARRANGE
+	now := time.Now()
+	r := parser.Rules{BasketRules: []parser.BasketRule{{RuleName: "Spring", DiscountPercentage: 10}}, Coupons: []parser.Coupon{{Code: "SPRING10", RuleName: "Spring", MaxRedemptions: 1}}}
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, r, items)
+	pricer.Clock = FixedClock(now)
+	pricer.Janitor = NewBasketJanitor(pricer.Baskets, ExpiryPolicy{IdleTTL: 30 * time.Minute})
+	first, _ := pricer.CreateBasket()
+	pricer.ApplyCoupon("SPRING10", first)
+	pricer.Janitor.Sweep(now.Add(time.Hour))
+	pricer.Clock = FixedClock(now.Add(time.Hour))
+	second, _ := pricer.CreateBasket()
+
+	//ACT
+	_, err := pricer.ApplyCoupon("SPRING10", second)
+
+	//ASSERT
+	if pricer.Janitor.ExpiredCount() != 1 {
+		t.Fatalf("The basket holding the coupon should have expired")
+	}
+
+	if !errors.Is(err, ErrCouponExhausted) {
+		t.Errorf("The expired basket should still count as a redemption of the coupon, got: %v", err)
+	}
+
+}
+
+func TestCouponRedemptionsCountOrders(t *testing.T) {
+
+	//ARRANGE
+	now := time.Now()
+	r := parser.Rules{BasketRules: []parser.BasketRule{{RuleName: "Spring", DiscountPercentage: 10}}, Coupons: []parser.Coupon{{Code: "SPRING10", RuleName: "Spring", MaxRedemptions: 1}}}
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, r, items)
+	pricer.Orders = NewMemoryOrderStore()
+	pricer.Clock = FixedClock(now)
+	pricer.Janitor = NewBasketJanitor(pricer.Baskets, ExpiryPolicy{IdleTTL: 30 * time.Minute})
+	first, _ := pricer.CreateBasket()
+	pricer.ScanItem("TSHIRT", first)
+	pricer.ApplyCoupon("SPRING10", first)
+	pricer.Checkout(first)
+	pricer.Janitor.Sweep(now.Add(time.Hour))
+	//The basket store is emptied, as a memory store would be by a restart, but the orders are kept
+	pricer.Baskets = NewMemoryBasketStore()
+	pricer.Janitor = nil
+	second, _ := pricer.CreateBasket()
+
+	//ACT
+	_, err := pricer.ApplyCoupon("SPRING10", second)
+
+	//ASSERT
+	if _, getErr := pricer.GetBasket(first); getErr == nil {
+		t.Fatalf("The checked out basket should be gone")
+	}
+
+	if !errors.Is(err, ErrCouponExhausted) {
+		t.Errorf("The order checked out with the coupon should still count as a redemption, got: %v", err)
+	}
+
+}
+
+func TestApplyCouponConcurrently(t *testing.T) {
+
+	//ARRANGE
+	r := parser.Rules{BasketRules: []parser.BasketRule{{RuleName: "Spring", DiscountPercentage: 10}}, Coupons: []parser.Coupon{{Code: "SPRING10", RuleName: "Spring", MaxRedemptions: 3}}}
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, r, items)
+	var baskets []string
+	for i := 0; i < 10; i++ {
+		bId, _ := pricer.CreateBasket()
+		baskets = append(baskets, bId)
+	}
+	results := make(chan error, len(baskets))
+
+	//ACT
+	for _, bId := range baskets {
+		go func(bId string) {
+			_, err := pricer.ApplyCoupon("SPRING10", bId)
+			results <- err
+		}(bId)
+	}
+	applied := 0
+	for range baskets {
+		if err := <-results; err == nil {
+			applied++
+		} else if !errors.Is(err, ErrCouponExhausted) {
+			t.Errorf("The coupon should only be rejected because it has run out, got: %v", err)
+		}
+	}
+
+	//ASSERT
+	if applied != 3 {
+		t.Errorf("The coupon should have been applied to 3 baskets, got: %d", applied)
+	}
+
+	if redeemed, _ := pricer.Baskets.Redemptions("SPRING10"); len(redeemed) != 3 {
+		t.Errorf("The store should have recorded 3 redemptions, got: %v", redeemed)
+	}
+
+}
+
+func TestGetTotalAmountCouponNoLongerConfigured(t *testing.T) {
+
+	//ARRANGE
+	r := parser.Rules{BasketRules: []parser.BasketRule{{RuleName: "Spring", DiscountPercentage: 10}}, Coupons: []parser.Coupon{{Code: "SPRING10", RuleName: "Spring"}}}
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, r, items)
+	bId, _ := pricer.CreateBasket()
+	pricer.ApplyCoupon("SPRING10", bId)
+	config := pricer.Config()
+	config.Coupons = nil
+	pricer.SetConfig(config)
+
+	//ACT
+	_, err := pricer.GetTotalAmount(bId)
+
+	//ASSERT
+	if !errors.Is(err, ErrCouponNotFound) {
+		t.Errorf("A coupon removed from the configuration should be rejected, got: %v", err)
+	}
+
+}
+
+func TestGetTotalAmountCouponItemRule(t *testing.T) {
+
+	//ARRANGE
+	r := parser.Rules{NxmRules: []parser.NxMRule{{RuleName: "2x1", AffectedItem: "VOUCHER", BuyN: 2, PayM: 1}},
+		Coupons: []parser.Coupon{{Code: "VOUCHERS", RuleName: "2x1"}}}
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, r, items)
+	bId, _ := pricer.CreateBasket()
+	pricer.SetItemQuantity("VOUCHER", bId, 2)
+
+	//ACT
+	before, _ := pricer.GetTotalAmount(bId)
+	pricer.ApplyCoupon("VOUCHERS", bId)
+	after, _ := pricer.GetTotalAmount(bId)
+
+	//ASSERT
+	if before != money.New(1000, "EUR") || after != money.New(500, "EUR") {
+		t.Errorf("The item rule linked to the coupon should only be applied with it, expected: 10.00 and 5.00, got: %s and %s", before, after)
+	}
+
+}
@@ -0,0 +1,114 @@
+package pricer
+
+import (
+	"errors"
+	log "github.com/sirupsen/logrus"
+)
+
+//Errors reported by the Pricer, the returned errors are PricerErrors wrapping one of these, so errors.Is can be used to
+//know what went wrong and errors.As to know the basket and item it went wrong with
+var (
+	ErrBasketNotFound    = errors.New("the specified basket doesn't exist")
+	ErrBasketExpired     = errors.New("the specified basket has expired")
+	ErrBasketCheckedOut  = errors.New("the specified basket has been checked out, a new basket must be created")
+	ErrBasketEmpty       = errors.New("the specified basket has no items to check out")
+	ErrItemNotConfigured = errors.New("the specified item is not configured in the server")
+	ErrItemNotInBasket   = errors.New("the specified item is not in the basket")
+	ErrInvalidQuantity   = errors.New("the quantity of an item can't be negative")
+	ErrItemMeasured      = errors.New("the specified item is sold by weight or measure, its quantity must be weighed")
+	ErrItemNotMeasured   = errors.New("the specified item is not sold by weight or measure, its units must be scanned")
+	ErrInvalidBarcode    = errors.New("the specified barcode is not a valid variable measure barcode")
+	ErrItemHasVariants   = errors.New("the specified item is sold through its variants, one of them must be scanned")
+	ErrInsufficientStock = errors.New("there isn't enough stock of the specified item")
+	ErrStockNotTracked   = errors.New("the stock of the items is not tracked by the server")
+
+	ErrCouponNotFound       = errors.New("the specified coupon doesn't exist")
+	ErrCouponNotValidYet    = errors.New("the specified coupon is not valid yet")
+	ErrCouponExpired        = errors.New("the specified coupon has expired")
+	ErrCouponExhausted      = errors.New("the specified coupon has been redeemed the maximum number of times")
+	ErrCouponAlreadyApplied = errors.New("the specified coupon has already been applied to the basket")
+	ErrCouponNotInBasket    = errors.New("the specified coupon has not been applied to the basket")
+
+	ErrOrderNotFound       = errors.New("the specified order doesn't exist")
+	ErrInvalidDateRange    = errors.New("the end of the date range can't be before its start")
+	ErrOrdersNotConfigured = errors.New("the orders are not stored by the server, the baskets can't be checked out")
+)
+
+//Error returned by the Pricer, it carries the basket and the item or coupon, if any, the operation was performed on, or
+//the order for the operations on the orders
+type PricerError struct {
+	Err         error
+	BasketId    string
+	ItemId      string
+	CouponCode  string
+	OrderNumber string
+}
+
+func (e *PricerError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PricerError) Unwrap() error {
+	return e.Err
+}
+
+//Wraps the error into a PricerError for the given basket and item and logs it, nil is returned as is
+func newPricerError(err error, basketId string, itemId string) error {
+	if err == nil {
+		return nil
+	}
+	e := &PricerError{Err: err, BasketId: basketId, ItemId: itemId}
+	switch {
+	case errors.Is(err, ErrBasketNotFound):
+		log.Errorf("The basket '%s' doesn't exist", basketId)
+	case errors.Is(err, ErrBasketExpired):
+		log.Errorf("The basket '%s' has expired", basketId)
+	case errors.Is(err, ErrBasketCheckedOut):
+		log.Errorf("The basket '%s' has been checked out", basketId)
+	case errors.Is(err, ErrBasketEmpty):
+		log.Errorf("The basket '%s' has no items to check out", basketId)
+	case errors.Is(err, ErrItemNotConfigured):
+		log.Errorf("The item '%s' has not been configured in the server", itemId)
+	case errors.Is(err, ErrItemNotInBasket):
+		log.Errorf("The item '%s' is not in the basket '%s'", itemId, basketId)
+	case errors.Is(err, ErrInvalidQuantity):
+		log.Errorf("The quantity of item '%s' is not valid", itemId)
+	case errors.Is(err, ErrItemMeasured), errors.Is(err, ErrItemNotMeasured), errors.Is(err, ErrItemHasVariants):
+		log.Errorf("The item '%s' can't be added that way: %v", itemId, err)
+	case errors.Is(err, ErrInvalidBarcode):
+		log.Errorf("The barcode scanned into the basket '%s' is not valid: %v", basketId, err)
+	case errors.Is(err, ErrInsufficientStock):
+		log.Errorf("The item '%s' can't be added to the basket '%s': %v", itemId, basketId, err)
+	case errors.Is(err, ErrStockNotTracked):
+		log.Errorf("The stock of the item '%s' can't be accessed: %v", itemId, err)
+	case errors.Is(err, ErrOrdersNotConfigured):
+		log.Errorf("The basket '%s' can't be checked out: %v", basketId, err)
+	default:
+		log.Errorf("The basket '%s' couldn't be accessed: %v", basketId, err)
+	}
+	return e
+}
+
+//Wraps the error into a PricerError for the given basket and coupon and logs it, nil is returned as is
+//Errors not related to the coupon, ie: the basket doesn't exist, are wrapped the same way newPricerError does
+func newCouponError(err error, basketId string, code string) error {
+	var pricerErr *PricerError
+	if err == nil || errors.As(err, &pricerErr) {
+		return err
+	}
+	if !isCouponError(err) {
+		return newPricerError(err, basketId, "")
+	}
+	log.Errorf("The coupon '%s' can't be used with the basket '%s': %v", code, basketId, err)
+	return &PricerError{Err: err, BasketId: basketId, CouponCode: code}
+}
+
+func isCouponError(err error) bool {
+	for _, e := range []error{ErrCouponNotFound, ErrCouponNotValidYet, ErrCouponExpired, ErrCouponExhausted,
+		ErrCouponAlreadyApplied, ErrCouponNotInBasket} {
+		if errors.Is(err, e) {
+			return true
+		}
+	}
+	return false
+}
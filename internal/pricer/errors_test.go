@@ -0,0 +1,59 @@
+package pricer
+
+import (
+	"errors"
+	"github.com/dagozba/golangsmallshop/internal/rules"
+	"testing"
+)
+
+func TestPricerErrors(t *testing.T) {
+
+	//ARRANGE
+	pricer := NewPricer(rules.RuleStrategyFactory{}, new(MockedItemsParser), NewMemoryBasketStore())
+	pricer.LoadItems("DUMMYPATH")
+	bId, _ := pricer.CreateBasket()
+	tests := []struct {
+		name     string
+		call     func() error
+		expected error
+		basketId string
+		itemId   string
+	}{
+		{"Scan into a non existent basket", func() error {
+			_, err := pricer.ScanItem("MUG", "FAKEBASKETID")
+			return err
+		}, ErrBasketNotFound, "FAKEBASKETID", "MUG"},
+		{"Scan a non configured item", func() error {
+			_, err := pricer.ScanItem("NONEXISTENT", bId)
+			return err
+		}, ErrItemNotConfigured, bId, "NONEXISTENT"},
+		{"Remove an item that isn't in the basket", func() error {
+			_, err := pricer.RemoveItem("MUG", bId)
+			return err
+		}, ErrItemNotInBasket, bId, "MUG"},
+		{"Set a negative quantity", func() error {
+			_, err := pricer.SetItemQuantity("MUG", bId, -1)
+			return err
+		}, ErrInvalidQuantity, bId, "MUG"},
+		{"Get the total of a non existent basket", func() error {
+			_, err := pricer.GetTotalAmount("FAKEBASKETID")
+			return err
+		}, ErrBasketNotFound, "FAKEBASKETID", ""},
+	}
+
+	for _, test := range tests {
+		//ACT
+		err := test.call()
+
+		//ASSERT
+		var pricerErr *PricerError
+		if !errors.Is(err, test.expected) || !errors.As(err, &pricerErr) {
+			t.Errorf("%s: expected a PricerError wrapping %v, got: %v", test.name, test.expected, err)
+			continue
+		}
+		if pricerErr.BasketId != test.basketId || pricerErr.ItemId != test.itemId {
+			t.Errorf("%s: expected the error to refer to basket '%s' and item '%s', got: %+v", test.name, test.basketId, test.itemId, pricerErr)
+		}
+	}
+
+}
@@ -0,0 +1,171 @@
+package pricer
+
+import (
+	log "github.com/sirupsen/logrus"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//The evicted baskets are remembered for this long, so a till coming back to an expired basket is told it has expired
+//instead of being told it never existed
+const expiredBasketsRetention = 24 * time.Hour
+
+//Limits to the life of a basket, a zero duration disables the corresponding limit
+//IdleTTL is measured from the last change made to the basket, reading a basket doesn't keep it alive
+//MaxLifetime is measured from the creation of the basket, no matter how active it is
+type ExpiryPolicy struct {
+	IdleTTL     time.Duration
+	MaxLifetime time.Duration
+}
+
+//Returns true if the basket has outlived any of the limits of the policy at the given time
+func (e ExpiryPolicy) hasExpired(b Basket, now time.Time) bool {
+	if e.IdleTTL > 0 && now.Sub(b.lastActivity()) >= e.IdleTTL {
+		return true
+	}
+	return e.MaxLifetime > 0 && now.Sub(b.CreatedAt) >= e.MaxLifetime
+}
+
+//Evicts the expired baskets from the basket store, either periodically from a background goroutine or as soon as an
+//expired basket is accessed, whatever happens first
+//The ids of the evicted baskets are kept for a while to tell expired baskets apart from baskets that never existed
+//The units reserved by the evicted baskets are released from the Stock, if it's tracked
+//Clock is the source of the time of the background sweeps, the system time if it's nil, it should be the Clock of the
+//Pricer so the baskets expire at the same time the Pricer finds them expired
+//A nil BasketJanitor is valid and never expires any basket
+type BasketJanitor struct {
+	Baskets BasketStore
+	Policy  ExpiryPolicy
+	Stock   *Inventory
+	Clock   Clock
+
+	expiredBaskets map[string]time.Time
+	expiredLock    *sync.Mutex
+	expiredCount   int64
+	stop           chan struct{}
+}
+
+func NewBasketJanitor(baskets BasketStore, policy ExpiryPolicy) *BasketJanitor {
+	return &BasketJanitor{Baskets: baskets, Policy: policy, expiredBaskets: make(map[string]time.Time), expiredLock: new(sync.Mutex)}
+}
+
+//Starts the background goroutine that sweeps the basket store every interval, until Stop is called
+func (j *BasketJanitor) Start(interval time.Duration) {
+	j.stop = make(chan struct{})
+	go func(stop chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				j.Sweep(j.now())
+			case <-stop:
+				return
+			}
+		}
+	}(j.stop)
+	log.Infof("Basket janitor started, sweeping every %s (idle ttl: %s, max lifetime: %s)", interval, j.Policy.IdleTTL, j.Policy.MaxLifetime)
+}
+
+//Stops the background goroutine started by Start
+func (j *BasketJanitor) Stop() {
+	if j.stop != nil {
+		close(j.stop)
+		j.stop = nil
+	}
+}
+
+//Evicts all the baskets that have expired at the given time and returns how many of them were evicted
+func (j *BasketJanitor) Sweep(now time.Time) int {
+	baskets, err := j.Baskets.List()
+	if err != nil {
+		log.Errorf("The baskets couldn't be listed to look for expired ones: %v", err)
+		return 0
+	}
+	evicted := 0
+	for _, b := range baskets {
+		if j.Policy.hasExpired(b, now) && j.evict(b.Id, now) {
+			evicted++
+		}
+	}
+	j.forgetExpiredBaskets(now)
+	if evicted > 0 {
+		log.Infof("Basket janitor evicted %d expired baskets, %d baskets have expired since the server started", evicted, j.ExpiredCount())
+	}
+	return evicted
+}
+
+//Returns the number of baskets that have expired since the janitor was created
+func (j *BasketJanitor) ExpiredCount() int64 {
+	if j == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&j.expiredCount)
+}
+
+//Returns ErrBasketExpired if the basket has expired at the given time
+func (j *BasketJanitor) check(b Basket, now time.Time) error {
+	if j != nil && j.Policy.hasExpired(b, now) {
+		return ErrBasketExpired
+	}
+	return nil
+}
+
+//Turns the error returned by the basket store for the given basket into ErrBasketExpired if the basket doesn't exist
+//because it has expired, evicting it if it hasn't been evicted yet. now is the time the basket was found expired at
+func (j *BasketJanitor) explain(basketId string, err error, now time.Time) error {
+	if j == nil {
+		return err
+	}
+	switch err {
+	case ErrBasketExpired:
+		j.evict(basketId, now)
+	case ErrBasketNotFound:
+		j.expiredLock.Lock()
+		_, expired := j.expiredBaskets[basketId]
+		j.expiredLock.Unlock()
+		if expired {
+			return ErrBasketExpired
+		}
+	}
+	return err
+}
+
+//Removes the basket from the store, checking again it has expired, against the last time it was modified, while holding
+//its lock, as it could have been modified since it was listed and it can't be modified while it's being removed
+func (j *BasketJanitor) evict(basketId string, now time.Time) bool {
+	evicted, err := j.Baskets.DeleteIf(basketId, func(b Basket) bool { return j.Policy.hasExpired(b, now) })
+	if err != nil {
+		log.Errorf("The expired basket '%s' couldn't be removed: %v", basketId, err)
+		return false
+	}
+	if !evicted {
+		return false
+	}
+	j.Stock.release(basketId)
+	j.expiredLock.Lock()
+	j.expiredBaskets[basketId] = now
+	j.expiredLock.Unlock()
+	atomic.AddInt64(&j.expiredCount, 1)
+	log.Infof("Basket '%s' has expired and has been removed", basketId)
+	return true
+}
+
+//Returns the current time of the janitor clock, the system time if there's no clock
+func (j *BasketJanitor) now() time.Time {
+	if j.Clock == nil {
+		return time.Now()
+	}
+	return j.Clock.Now()
+}
+
+func (j *BasketJanitor) forgetExpiredBaskets(now time.Time) {
+	j.expiredLock.Lock()
+	defer j.expiredLock.Unlock()
+	for id, expiredAt := range j.expiredBaskets {
+		if now.Sub(expiredAt) >= expiredBasketsRetention {
+			delete(j.expiredBaskets, id)
+		}
+	}
+}
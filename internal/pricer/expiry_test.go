@@ -0,0 +1,224 @@
+package pricer
+
+import (
+	"errors"
+	"github.com/dagozba/golangsmallshop/internal/rules"
+	"testing"
+	"time"
+)
+
+func TestExpiryPolicy(t *testing.T) {
+
+	//ARRANGE
+	now := time.Now()
+	policy := ExpiryPolicy{IdleTTL: 30 * time.Minute, MaxLifetime: 12 * time.Hour}
+	tests := []struct {
+		name     string
+		basket   Basket
+		expected bool
+	}{
+		{"Active basket", Basket{CreatedAt: now.Add(-time.Hour), UpdatedAt: now.Add(-time.Minute)}, false},
+		{"Idle basket", Basket{CreatedAt: now.Add(-time.Hour), UpdatedAt: now.Add(-31 * time.Minute)}, true},
+		{"Basket without UpdatedAt", Basket{CreatedAt: now.Add(-31 * time.Minute)}, true},
+		{"Active basket over its max lifetime", Basket{CreatedAt: now.Add(-13 * time.Hour), UpdatedAt: now}, true},
+	}
+
+	for _, test := range tests {
+		//ACT
+		expired := policy.hasExpired(test.basket, now)
+
+		//ASSERT
+		if expired != test.expected {
+			t.Errorf("%s: expected expired to be %t, got %t", test.name, test.expected, expired)
+		}
+	}
+
+}
+
+func TestExpiryPolicyDisabledLimits(t *testing.T) {
+
+	//ARRANGE
+	now := time.Now()
+	b := Basket{CreatedAt: now.Add(-1000 * time.Hour)}
+
+	//ACT
+	expired := ExpiryPolicy{}.hasExpired(b, now)
+
+	//ASSERT
+	if expired {
+		t.Errorf("A basket shouldn't expire if the policy has no limits")
+	}
+
+}
+
+func TestBasketJanitorSweep(t *testing.T) {
+
+	//ARRANGE
+	now := time.Now()
+	store := NewMemoryBasketStore()
+	store.Create(Basket{Id: "IDLE", CreatedAt: now.Add(-time.Hour), Items: map[string]int{}})
+	store.Create(Basket{Id: "ACTIVE", CreatedAt: now, Items: map[string]int{}})
+	janitor := NewBasketJanitor(store, ExpiryPolicy{IdleTTL: 30 * time.Minute})
+
+	//ACT
+	evicted := janitor.Sweep(now)
+
+	//ASSERT
+	if evicted != 1 || janitor.ExpiredCount() != 1 {
+		t.Errorf("Only the idle basket should have been evicted, got: %d evicted, %d counted", evicted, janitor.ExpiredCount())
+	}
+	if _, err := store.Get("IDLE"); err != ErrBasketNotFound {
+		t.Errorf("The idle basket should have been removed from the store")
+	}
+	if _, err := store.Get("ACTIVE"); err != nil {
+		t.Errorf("The active basket shouldn't have been removed from the store, got: %+v", err)
+	}
+
+}
+
+func TestExpiredBasketIsToldApartFromNonExistentBasket(t *testing.T) {
+
+	//ARRANGE
+	store := NewMemoryBasketStore()
+	store.Create(Basket{Id: "IDLE", CreatedAt: time.Now().Add(-time.Hour), Items: map[string]int{}})
+	janitor := NewBasketJanitor(store, ExpiryPolicy{IdleTTL: 30 * time.Minute})
+	pricer := NewPricer(rules.RuleStrategyFactory{}, new(MockedItemsParser), store)
+	pricer.Janitor = janitor
+	pricer.LoadItems("DUMMYPATH")
+	janitor.Sweep(time.Now())
+
+	//ACT
+	_, scanErr := pricer.ScanItem("MUG", "IDLE")
+	_, totalErr := pricer.GetTotalAmount("IDLE")
+	_, nonExistentErr := pricer.GetTotalAmount("FAKEBASKETID")
+
+	//ASSERT
+	if !errors.Is(scanErr, ErrBasketExpired) || !errors.Is(totalErr, ErrBasketExpired) {
+		t.Errorf("Accessing an expired basket should produce ErrBasketExpired, got: %v and %v", scanErr, totalErr)
+	}
+	if !errors.Is(nonExistentErr, ErrBasketNotFound) {
+		t.Errorf("Accessing a basket that never existed should produce ErrBasketNotFound, got: %v", nonExistentErr)
+	}
+
+}
+
+func TestBasketExpiresBeforeTheJanitorSweeps(t *testing.T) {
+
+	//ARRANGE
+	store := NewMemoryBasketStore()
+	store.Create(Basket{Id: "IDLE", CreatedAt: time.Now().Add(-time.Hour), Items: map[string]int{}})
+	janitor := NewBasketJanitor(store, ExpiryPolicy{IdleTTL: 30 * time.Minute})
+	pricer := NewPricer(rules.RuleStrategyFactory{}, new(MockedItemsParser), store)
+	pricer.Janitor = janitor
+	pricer.LoadItems("DUMMYPATH")
+
+	//ACT
+	_, err := pricer.ScanItem("MUG", "IDLE")
+
+	//ASSERT
+	if !errors.Is(err, ErrBasketExpired) {
+		t.Errorf("Scanning into an expired basket should produce ErrBasketExpired, got: %v", err)
+	}
+	if _, err := store.Get("IDLE"); err != ErrBasketNotFound {
+		t.Errorf("The expired basket should have been evicted when accessed")
+	}
+	if janitor.ExpiredCount() != 1 {
+		t.Errorf("The expired basket should have been counted, got: %d", janitor.ExpiredCount())
+	}
+
+}
+
+func TestScanningKeepsBasketAlive(t *testing.T) {
+
+	//ARRANGE
+	store := NewMemoryBasketStore()
+	store.Create(Basket{Id: "BASKET", CreatedAt: time.Now().Add(-20 * time.Minute), Items: map[string]int{}})
+	janitor := NewBasketJanitor(store, ExpiryPolicy{IdleTTL: 30 * time.Minute})
+	pricer := NewPricer(rules.RuleStrategyFactory{}, new(MockedItemsParser), store)
+	pricer.Janitor = janitor
+	pricer.LoadItems("DUMMYPATH")
+
+	//ACT
+	pricer.ScanItem("MUG", "BASKET")
+	evicted := janitor.Sweep(time.Now().Add(20 * time.Minute))
+
+	//ASSERT
+	if evicted != 0 {
+		t.Errorf("A basket modified recently shouldn't expire")
+	}
+
+}
+
+func TestExpiryUsesPricerClock(t *testing.T) {
+
+	//ARRANGE
+	now := time.Now()
+	store := NewMemoryBasketStore()
+	store.Create(Basket{Id: "BASKET", CreatedAt: now, Items: map[string]int{}})
+	janitor := NewBasketJanitor(store, ExpiryPolicy{IdleTTL: 30 * time.Minute})
+	pricer := NewPricer(rules.RuleStrategyFactory{}, new(MockedItemsParser), store)
+	pricer.Janitor = janitor
+	pricer.Clock = FixedClock(now.Add(time.Hour))
+	pricer.LoadItems("DUMMYPATH")
+
+	//ACT
+	_, err := pricer.ScanItem("MUG", "BASKET")
+
+	//ASSERT
+	if !errors.Is(err, ErrBasketExpired) {
+		t.Errorf("The basket should have expired at the time of the pricer clock, got: %v", err)
+	}
+	if _, err := store.Get("BASKET"); err != ErrBasketNotFound || janitor.ExpiredCount() != 1 {
+		t.Errorf("The basket should have been evicted at the time of the pricer clock, got: %v, %d expired", err, janitor.ExpiredCount())
+	}
+
+}
+
+func TestBasketJanitorSweepsWithItsClock(t *testing.T) {
+
+	//ARRANGE
+	now := time.Now()
+	store := NewMemoryBasketStore()
+	store.Create(Basket{Id: "BASKET", CreatedAt: now, Items: map[string]int{}})
+	janitor := NewBasketJanitor(store, ExpiryPolicy{IdleTTL: 30 * time.Minute})
+	janitor.Clock = FixedClock(now.Add(time.Hour))
+
+	//ACT
+	janitor.Start(time.Millisecond)
+	defer janitor.Stop()
+	deadline := time.Now().Add(time.Second)
+	for janitor.ExpiredCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	//ASSERT
+	if janitor.ExpiredCount() != 1 {
+		t.Errorf("The basket should have been evicted at the time of the janitor clock")
+	}
+
+}
+
+func TestBasketJanitorDoesNotEvictBasketModifiedSinceListed(t *testing.T) {
+
+	//ARRANGE
+	now := time.Now()
+	store := NewMemoryBasketStore()
+	store.Create(Basket{Id: "BASKET", CreatedAt: now.Add(-time.Hour), Items: map[string]int{}})
+	janitor := NewBasketJanitor(store, ExpiryPolicy{IdleTTL: 30 * time.Minute})
+	store.Update("BASKET", func(b *Basket) error {
+		b.UpdatedAt = now
+		return nil
+	})
+
+	//ACT
+	evicted := janitor.evict("BASKET", now)
+
+	//ASSERT
+	if evicted || janitor.ExpiredCount() != 0 {
+		t.Errorf("A basket modified after it was found expired shouldn't have been evicted")
+	}
+	if _, err := store.Get("BASKET"); err != nil {
+		t.Errorf("The basket should still be in the store, got: %v", err)
+	}
+
+}
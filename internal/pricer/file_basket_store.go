@@ -0,0 +1,217 @@
+package pricer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	putOperation      = "put"
+	deleteOperation   = "delete"
+	redeemOperation   = "redeem"
+	unredeemOperation = "unredeem"
+)
+
+//Durable implementation of the BasketStore backed by an append-only log of JSON lines
+//Every change to a basket appends its new state to the log, and every removal appends a delete record, so the baskets
+//can be rebuilt by replaying the log when the server starts again. The redemptions of the coupons are logged the same
+//way. The log is compacted on start up, keeping only the last state of the baskets that still exist and the redemptions
+//Reads are served from memory, so the log is only read once
+type FileBasketStore struct {
+	memory   *MemoryBasketStore
+	path     string
+	file     *os.File
+	fileLock *sync.Mutex
+}
+
+//A line of the log
+type basketLogRecord struct {
+	Operation string  `json:"op"`
+	BasketId  string  `json:"id"`
+	Basket    *Basket `json:"basket,omitempty"`
+	Coupon    string  `json:"coupon,omitempty"`
+}
+
+//Opens the basket log in the given path, creating it if it doesn't exist, and loads the baskets stored in it
+//A truncated last line, left by a crash while writing it, is discarded
+func OpenFileBasketStore(path string) (*FileBasketStore, error) {
+	path, _ = filepath.Abs(path)
+	s := &FileBasketStore{memory: NewMemoryBasketStore(), path: path, fileLock: new(sync.Mutex)}
+	if err := s.replay(); err != nil {
+		return nil, err
+	}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("the baskets log couldn't be opened: %v", err)
+	}
+	s.file = f
+	log.Infof("Loaded %d baskets from %s", len(s.memory.baskets), path)
+	return s, nil
+}
+
+func (s *FileBasketStore) Create(b Basket) error {
+	if err := s.memory.Create(b); err != nil {
+		return err
+	}
+	if err := s.append(basketLogRecord{Operation: putOperation, BasketId: b.Id, Basket: &b}); err != nil {
+		s.memory.Delete(b.Id)
+		return err
+	}
+	return nil
+}
+
+func (s *FileBasketStore) Get(basketId string) (Basket, error) {
+	return s.memory.Get(basketId)
+}
+
+//The new state of the basket is written to the log while holding the basket lock, so the order of the records of a
+//basket in the log is always the order in which the changes were made
+func (s *FileBasketStore) Update(basketId string, update func(b *Basket) error) error {
+	return s.memory.Update(basketId, func(b *Basket) error {
+		if err := update(b); err != nil {
+			return err
+		}
+		return s.append(basketLogRecord{Operation: putOperation, BasketId: basketId, Basket: b})
+	})
+}
+
+func (s *FileBasketStore) Delete(basketId string) error {
+	if err := s.memory.Delete(basketId); err != nil {
+		return err
+	}
+	return s.append(basketLogRecord{Operation: deleteOperation, BasketId: basketId})
+}
+
+func (s *FileBasketStore) DeleteIf(basketId string, condition func(b Basket) bool) (bool, error) {
+	deleted, err := s.memory.DeleteIf(basketId, condition)
+	if err != nil || !deleted {
+		return deleted, err
+	}
+	return true, s.append(basketLogRecord{Operation: deleteOperation, BasketId: basketId})
+}
+
+func (s *FileBasketStore) List() ([]Basket, error) {
+	return s.memory.List()
+}
+
+//The redemption is written to the log while holding the redemptions lock, so the redemptions of a coupon are logged in
+//the order they were made
+func (s *FileBasketStore) Redeem(code string, basketId string, limit int) (bool, error) {
+	return s.memory.redeem(code, basketId, limit, func() error {
+		return s.append(basketLogRecord{Operation: redeemOperation, BasketId: basketId, Coupon: code})
+	})
+}
+
+func (s *FileBasketStore) Unredeem(code string, basketId string) error {
+	return s.memory.unredeem(code, basketId, func() error {
+		return s.append(basketLogRecord{Operation: unredeemOperation, BasketId: basketId, Coupon: code})
+	})
+}
+
+func (s *FileBasketStore) Redemptions(code string) ([]string, error) {
+	return s.memory.Redemptions(code)
+}
+
+//Closes the underlying log file
+func (s *FileBasketStore) Close() error {
+	s.fileLock.Lock()
+	defer s.fileLock.Unlock()
+	return s.file.Close()
+}
+
+//Appends a record to the log and flushes it to disk before returning
+func (s *FileBasketStore) append(record basketLogRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	s.fileLock.Lock()
+	defer s.fileLock.Unlock()
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("the basket %s couldn't be stored: %v", record.BasketId, err)
+	}
+	return s.file.Sync()
+}
+
+//Rebuilds the in memory baskets from the records of the log
+func (s *FileBasketStore) replay() error {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("the baskets log couldn't be loaded: %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var record basketLogRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			log.Warnf("Discarding line %d of the baskets log %s as it is not valid: %v", line, s.path, err)
+			continue
+		}
+		switch record.Operation {
+		case putOperation:
+			if record.Basket == nil {
+				continue
+			}
+			b := *record.Basket
+			if b.Items == nil {
+				b.Items = make(map[string]int)
+			}
+			s.memory.baskets[b.Id] = &lockedBasket{basket: b, itemsLock: new(sync.RWMutex)}
+		case deleteOperation:
+			delete(s.memory.baskets, record.BasketId)
+		case redeemOperation:
+			if s.memory.redemptions[record.Coupon] == nil {
+				s.memory.redemptions[record.Coupon] = make(map[string]bool)
+			}
+			s.memory.redemptions[record.Coupon][record.BasketId] = true
+		case unredeemOperation:
+			delete(s.memory.redemptions[record.Coupon], record.BasketId)
+		}
+	}
+	return scanner.Err()
+}
+
+//Rewrites the log with a single record per existing basket and redemption, the new log replaces the old one atomically
+func (s *FileBasketStore) compact() error {
+	baskets, _ := s.memory.List()
+	tmp := s.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("the baskets log couldn't be compacted: %v", err)
+	}
+	w := bufio.NewWriter(f)
+	for i := range baskets {
+		line, _ := json.Marshal(basketLogRecord{Operation: putOperation, BasketId: baskets[i].Id, Basket: &baskets[i]})
+		w.Write(append(line, '\n'))
+	}
+	for code, baskets := range s.memory.redemptions {
+		for id := range baskets {
+			line, _ := json.Marshal(basketLogRecord{Operation: redeemOperation, BasketId: id, Coupon: code})
+			w.Write(append(line, '\n'))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("the baskets log couldn't be compacted: %v", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("the baskets log couldn't be compacted: %v", err)
+	}
+	f.Close()
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,117 @@
+package pricer
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"github.com/dagozba/golangsmallshop/internal/rules"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileBasketStoreSurvivesRestart(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "baskets.log")
+	rulesStrategyFactory := rules.RuleStrategyFactory{RuleExecutors: []rules.RuleStrategyExecutor{
+		rules.BulkRuleStrategy{Rule: parser.BulkRule{RuleName: "Mug Rule", AffectedItem: "MUG", TriggerAmount: 3, DiscountPercentage: 5}}}}
+
+	store, _ := OpenFileBasketStore(path)
+	pricer := NewPricer(rulesStrategyFactory, new(MockedItemsParser), store)
+	pricer.LoadItems("DUMMYPATH")
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("MUG", bId)
+	pricer.ScanItem("MUG", bId)
+	pricer.ScanItem("MUG", bId)
+	pricer.ScanItem("MUG", bId)
+	pricer.RemoveItem("MUG", bId)
+	expected, _ := pricer.GetTotalAmount(bId)
+	removedId, _ := pricer.CreateBasket()
+	pricer.RemoveBasket(removedId)
+	store.Close()
+
+	//ACT
+	reopened, err := OpenFileBasketStore(path)
+	restarted := NewPricer(rulesStrategyFactory, new(MockedItemsParser), reopened)
+	restarted.LoadItems("DUMMYPATH")
+	total, totalErr := restarted.GetTotalAmount(bId)
+
+	//ASSERT
+	if err != nil {
+		t.Fatalf("Reopening the basket store shouldn't have produced an error, got: %+v", err)
+	}
+	defer reopened.Close()
+
+	if totalErr != nil || total != expected {
+		t.Errorf("The basket should have survived the restart with the same total, expected: %s, got: %s (%v)", expected, total, totalErr)
+	}
+
+	if _, err := reopened.Get(removedId); err != ErrBasketNotFound {
+		t.Errorf("A removed basket shouldn't be loaded again after a restart")
+	}
+
+}
+
+func TestFileBasketStoreDiscardsTruncatedRecord(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "baskets.log")
+	store, _ := OpenFileBasketStore(path)
+	store.Create(newBasket("BASKET", time.Now()))
+	store.Update("BASKET", func(b *Basket) error {
+		b.addItem("MUG")
+		return nil
+	})
+	store.Close()
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(`{"op":"put","id":"BASKET","basket":{"id":"BAS`)
+	f.Close()
+
+	//ACT
+	reopened, err := OpenFileBasketStore(path)
+
+	//ASSERT
+	if err != nil {
+		t.Fatalf("A truncated record shouldn't prevent the store from opening, got: %+v", err)
+	}
+	defer reopened.Close()
+
+	if b, _ := reopened.Get("BASKET"); b.Items["MUG"] != 1 {
+		t.Errorf("The last complete state of the basket should have been loaded, got: %+v", b)
+	}
+
+}
+
+func TestFileBasketStoreRedemptionsSurviveRestart(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "baskets.log")
+	store, _ := OpenFileBasketStore(path)
+	store.Create(newBasket("KEPT", time.Now()))
+	store.Create(newBasket("REMOVED", time.Now()))
+	store.Create(newBasket("GIVEN_BACK", time.Now()))
+	store.Redeem("SPRING10", "KEPT", 3)
+	store.Redeem("SPRING10", "REMOVED", 3)
+	store.Redeem("SPRING10", "GIVEN_BACK", 3)
+	store.Unredeem("SPRING10", "GIVEN_BACK")
+	store.Delete("REMOVED")
+	store.Close()
+
+	//ACT
+	reopened, _ := OpenFileBasketStore(path)
+	reopened.Close()
+	//Opened twice so the redemptions are also read back from the compacted log
+	compacted, err := OpenFileBasketStore(path)
+	redeemed, _ := compacted.Redemptions("SPRING10")
+
+	//ASSERT
+	if err != nil {
+		t.Fatalf("Reopening the basket store shouldn't have produced an error, got: %+v", err)
+	}
+	defer compacted.Close()
+
+	if len(redeemed) != 2 || redeemed[0] != "KEPT" || redeemed[1] != "REMOVED" {
+		t.Errorf("The redemptions should have survived the restart, even the one of the removed basket, got: %v", redeemed)
+	}
+
+}
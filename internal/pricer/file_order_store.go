@@ -0,0 +1,103 @@
+package pricer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+//Durable implementation of the OrderStore backed by an append-only log of JSON lines, one line per order
+//As the orders are never modified nor removed, the log is never compacted and loading it back is just reading it
+//Reads are served from memory, so the log is only read once
+type FileOrderStore struct {
+	memory   *MemoryOrderStore
+	path     string
+	file     *os.File
+	fileLock *sync.Mutex
+}
+
+//Opens the orders log in the given path, creating it if it doesn't exist, and loads the orders stored in it
+//A truncated last line, left by a crash while writing it, is discarded
+func OpenFileOrderStore(path string) (*FileOrderStore, error) {
+	path, _ = filepath.Abs(path)
+	s := &FileOrderStore{memory: NewMemoryOrderStore(), path: path, fileLock: new(sync.Mutex)}
+	if err := s.replay(); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("the orders log couldn't be opened: %v", err)
+	}
+	s.file = f
+	log.Infof("Loaded %d orders from %s", len(s.memory.orders), path)
+	return s, nil
+}
+
+//The order is only kept in memory once it has been written to the log
+func (s *FileOrderStore) Create(o Order) error {
+	if _, err := s.memory.Get(o.Number); err == nil {
+		return fmt.Errorf("an order with the number %s already exists", o.Number)
+	}
+	line, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+	s.fileLock.Lock()
+	defer s.fileLock.Unlock()
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("the order %s couldn't be stored: %v", o.Number, err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("the order %s couldn't be stored: %v", o.Number, err)
+	}
+	return s.memory.Create(o)
+}
+
+func (s *FileOrderStore) Get(number string) (Order, error) {
+	return s.memory.Get(number)
+}
+
+func (s *FileOrderStore) List(from time.Time, to time.Time) ([]Order, error) {
+	return s.memory.List(from, to)
+}
+
+func (s *FileOrderStore) Redemptions(code string) ([]string, error) {
+	return s.memory.Redemptions(code)
+}
+
+//Closes the underlying log file
+func (s *FileOrderStore) Close() error {
+	s.fileLock.Lock()
+	defer s.fileLock.Unlock()
+	return s.file.Close()
+}
+
+//Loads the orders of the log into memory
+func (s *FileOrderStore) replay() error {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("the orders log couldn't be loaded: %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var o Order
+		if err := json.Unmarshal(scanner.Bytes(), &o); err != nil || o.Number == "" {
+			log.Warnf("Discarding line %d of the orders log %s as it is not valid: %v", line, s.path, err)
+			continue
+		}
+		s.memory.Create(o)
+	}
+	return scanner.Err()
+}
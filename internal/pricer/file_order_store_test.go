@@ -0,0 +1,65 @@
+package pricer
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileOrderStoreSurvivesRestart(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "orders.log")
+	store, _ := OpenFileOrderStore(path)
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, parser.Rules{}, items)
+	pricer.Orders = store
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("MUG", bId)
+	expected, _ := pricer.Checkout(bId)
+	store.Close()
+
+	//ACT
+	reopened, err := OpenFileOrderStore(path)
+	order, getErr := reopened.Get(expected.Number)
+
+	//ASSERT
+	if err != nil || getErr != nil {
+		t.Fatalf("The order should have been loaded again, got: %v, %v", err, getErr)
+	}
+	defer reopened.Close()
+
+	if order.BasketId != bId || order.Breakdown.Total != money.New(750, "EUR") || !order.CheckedOutAt.Equal(expected.CheckedOutAt) {
+		t.Errorf("The order should have survived the restart as it was, expected: %+v, got: %+v", expected, order)
+	}
+
+}
+
+func TestFileOrderStoreDiscardsTruncatedRecord(t *testing.T) {
+
+	//ARRANGE
+	path := filepath.Join(t.TempDir(), "orders.log")
+	store, _ := OpenFileOrderStore(path)
+	store.Create(Order{Number: "ORDER", CheckedOutAt: time.Now()})
+	store.Close()
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(`{"number":"TRUNCATED","basketId":`)
+	f.Close()
+
+	//ACT
+	reopened, err := OpenFileOrderStore(path)
+
+	//ASSERT
+	if err != nil {
+		t.Fatalf("A truncated record shouldn't prevent the store from opening, got: %v", err)
+	}
+	defer reopened.Close()
+
+	if orders, _ := reopened.List(time.Time{}, time.Time{}); len(orders) != 1 || orders[0].Number != "ORDER" {
+		t.Errorf("Only the complete order should have been loaded, got: %+v", orders)
+	}
+
+}
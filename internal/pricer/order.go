@@ -0,0 +1,156 @@
+package pricer
+
+import (
+	"errors"
+	"github.com/segmentio/ksuid"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+//The record of a sale: a basket checked out, priced once with the configuration in use at the time
+//The Breakdown has the lines of the basket, the promotions applied to it and its totals, as they were when the basket
+//was checked out, ConfigVersion is the version of the configuration it was priced with, see ConfigVersion
+//OpenedAt is when the basket was created and CheckedOutAt when it was checked out
+//An order is never modified once it's stored
+type Order struct {
+	Number        string    `json:"number"`
+	BasketId      string    `json:"basketId"`
+	OpenedAt      time.Time `json:"openedAt"`
+	CheckedOutAt  time.Time `json:"checkedOutAt"`
+	ConfigVersion string    `json:"configVersion,omitempty"`
+	Coupons       []string  `json:"coupons,omitempty"`
+	Breakdown     Breakdown `json:"breakdown"`
+}
+
+//Returns a deep copy of the order, so it can be read without affecting the stored one
+func (o Order) copy() Order {
+	if o.Coupons != nil {
+		o.Coupons = append([]string(nil), o.Coupons...)
+	}
+	o.Breakdown = o.Breakdown.copy()
+	return o
+}
+
+//Checks out the given basket: it's priced once with the current configuration and stored as an order with a new order
+//number, the units of its items are taken out of the stock, if it's tracked, and the basket can't be changed anymore
+//The basket is frozen before it's priced, so no item can be scanned into it meanwhile, and its units are sold at the
+//same time, so they can't be released if the basket is removed or expires meanwhile. The basket is opened again and
+//its units reserved again if it can't be priced or the order can't be stored
+//returns an error if the orders are not stored, the basket doesn't exist, has expired, has already been checked out,
+//has no items, any of its items is not configured anymore or any of its coupons can't be used anymore
+func (p *Pricer) Checkout(basketId string) (Order, error) {
+	log.Infof("Checking out basket %s", basketId)
+	if p.Orders == nil {
+		return Order{}, newPricerError(ErrOrdersNotConfigured, basketId, "")
+	}
+	number := ksuid.New().String()
+	now := p.now()
+	var sold map[string]int
+	err := p.applyUpdate(basketId, func(b *Basket) error {
+		if len(b.Items) == 0 && len(b.Measures) == 0 {
+			return ErrBasketEmpty
+		}
+		b.OrderNumber = number
+		sold = p.Stock.commit(basketId)
+		return nil
+	})
+	if err != nil {
+		p.Stock.uncommit(basketId, sold, true)
+		return Order{}, newPricerError(err, basketId, "")
+	}
+
+	order, err := p.placeOrder(basketId, number, now)
+	if err != nil {
+		p.reopen(basketId, number, sold)
+		return Order{}, err
+	}
+	log.Infof("Basket %s checked out as order %s, total %s", basketId, number, order.Breakdown.Total)
+	return order, nil
+}
+
+//Prices the frozen basket and stores it as an order with the given number
+func (p Pricer) placeOrder(basketId string, number string, now time.Time) (Order, error) {
+	basket, err := p.Baskets.Get(basketId)
+	if err != nil {
+		return Order{}, newPricerError(err, basketId, "")
+	}
+	config := p.Config()
+	at := p.pricedAt(basket, config)
+	if err := p.checkPriceable(basket, config, at); err != nil {
+		return Order{}, err
+	}
+	order := Order{Number: number, BasketId: basketId, OpenedAt: basket.CreatedAt, CheckedOutAt: now,
+		ConfigVersion: config.Version, Coupons: basket.couponCodes(), Breakdown: p.breakdown(basket, config, at)}
+	if err := p.Orders.Create(order); err != nil {
+		return Order{}, newPricerError(err, basketId, "")
+	}
+	return order, nil
+}
+
+//Opens again a basket frozen to be checked out as the order with the given number that couldn't be placed, the units
+//sold with it are put back in stock and reserved by the basket again, unless it has been removed meanwhile
+func (p Pricer) reopen(basketId string, number string, sold map[string]int) {
+	reopened := false
+	err := p.Baskets.Update(basketId, func(b *Basket) error {
+		if b.OrderNumber == number {
+			b.OrderNumber = ""
+			p.Stock.uncommit(basketId, sold, true)
+			reopened = true
+		}
+		return nil
+	})
+	if !reopened {
+		p.Stock.uncommit(basketId, sold, false)
+	}
+	if err != nil {
+		log.Errorf("The basket '%s' couldn't be opened again after failing to check it out: %v", basketId, err)
+	}
+}
+
+//Returns the order with the given number
+//returns an error if the orders are not stored or the order doesn't exist
+func (p Pricer) GetOrder(number string) (Order, error) {
+	log.Infof("Getting order %s", number)
+	if p.Orders == nil {
+		return Order{}, newOrderError(ErrOrdersNotConfigured, number)
+	}
+	order, err := p.Orders.Get(number)
+	if err != nil {
+		return Order{}, newOrderError(err, number)
+	}
+	return order, nil
+}
+
+//Returns the orders checked out within the given range, from included and to excluded, sorted by checkout time
+//A zero time leaves that end of the range open
+//returns an error if the orders are not stored, the range ends before it starts or the orders couldn't be listed
+func (p Pricer) ListOrders(from time.Time, to time.Time) ([]Order, error) {
+	log.Infof("Listing the orders checked out from %s to %s", from, to)
+	if p.Orders == nil {
+		return nil, newOrderError(ErrOrdersNotConfigured, "")
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return nil, newOrderError(ErrInvalidDateRange, "")
+	}
+	orders, err := p.Orders.List(from, to)
+	if err != nil {
+		return nil, newOrderError(err, "")
+	}
+	return orders, nil
+}
+
+//Wraps the error into a PricerError for the given order and logs it, nil is returned as is
+func newOrderError(err error, number string) error {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case errors.Is(err, ErrOrderNotFound):
+		log.Errorf("The order '%s' doesn't exist", number)
+	case errors.Is(err, ErrInvalidDateRange):
+		log.Errorf("The orders can't be listed: %v", err)
+	default:
+		log.Errorf("The orders couldn't be accessed: %v", err)
+	}
+	return &PricerError{Err: err, OrderNumber: number}
+}
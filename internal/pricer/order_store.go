@@ -0,0 +1,90 @@
+package pricer
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+//Abstraction over the storage of the orders, injected into the Pricer so it can be replaced by a durable
+//implementation or mocked in tests, the same way the BasketStore is
+//The orders are immutable, so they can only be created and read: List returns the orders checked out within the given
+//range, from included and to excluded, sorted by checkout time. A zero time leaves that end of the range open
+//Redemptions returns the ids of the baskets checked out with the coupon, sorted by id, so the coupons redeemed by the
+//orders keep counting once their baskets are gone
+type OrderStore interface {
+	Create(o Order) error
+	Get(number string) (Order, error)
+	List(from time.Time, to time.Time) ([]Order, error)
+	Redemptions(code string) ([]string, error)
+}
+
+//In memory implementation of the OrderStore, the orders are lost when the server stops
+//The orders are kept sorted by checkout time so a range can be listed without sorting them every time, and the baskets
+//by coupon so the redemptions are not counted going through every order
+//The orders are copied when they are stored and when they are returned, so they can't be modified through the order
+//given to Create or returned by Get or List
+type MemoryOrderStore struct {
+	orders     map[string]Order
+	sorted     []string
+	coupons    map[string][]string
+	ordersLock *sync.RWMutex
+}
+
+func NewMemoryOrderStore() *MemoryOrderStore {
+	return &MemoryOrderStore{orders: make(map[string]Order), coupons: make(map[string][]string), ordersLock: new(sync.RWMutex)}
+}
+
+func (s *MemoryOrderStore) Create(o Order) error {
+	s.ordersLock.Lock()
+	defer s.ordersLock.Unlock()
+	if _, exs := s.orders[o.Number]; exs {
+		return errors.New("an order with the same number already exists")
+	}
+	s.orders[o.Number] = o.copy()
+	for _, code := range o.Coupons {
+		s.coupons[code] = append(s.coupons[code], o.BasketId)
+	}
+	i := sort.Search(len(s.sorted), func(i int) bool { return s.orders[s.sorted[i]].CheckedOutAt.After(o.CheckedOutAt) })
+	s.sorted = append(s.sorted, "")
+	copy(s.sorted[i+1:], s.sorted[i:])
+	s.sorted[i] = o.Number
+	return nil
+}
+
+func (s *MemoryOrderStore) Get(number string) (Order, error) {
+	s.ordersLock.RLock()
+	defer s.ordersLock.RUnlock()
+	o, exs := s.orders[number]
+	if !exs {
+		return Order{}, ErrOrderNotFound
+	}
+	return o.copy(), nil
+}
+
+func (s *MemoryOrderStore) List(from time.Time, to time.Time) ([]Order, error) {
+	s.ordersLock.RLock()
+	defer s.ordersLock.RUnlock()
+	start := 0
+	if !from.IsZero() {
+		start = sort.Search(len(s.sorted), func(i int) bool { return !s.orders[s.sorted[i]].CheckedOutAt.Before(from) })
+	}
+	orders := []Order{}
+	for _, number := range s.sorted[start:] {
+		o := s.orders[number]
+		if !to.IsZero() && !o.CheckedOutAt.Before(to) {
+			break
+		}
+		orders = append(orders, o.copy())
+	}
+	return orders, nil
+}
+
+func (s *MemoryOrderStore) Redemptions(code string) ([]string, error) {
+	s.ordersLock.RLock()
+	defer s.ordersLock.RUnlock()
+	baskets := append([]string{}, s.coupons[code]...)
+	sort.Strings(baskets)
+	return baskets, nil
+}
@@ -0,0 +1,118 @@
+package pricer
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/rules"
+	"testing"
+	"time"
+)
+
+func TestMemoryOrderStoreList(t *testing.T) {
+
+	//ARRANGE
+	store := NewMemoryOrderStore()
+	start := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	store.Create(Order{Number: "C", CheckedOutAt: start.Add(2 * time.Hour)})
+	store.Create(Order{Number: "A", CheckedOutAt: start})
+	store.Create(Order{Number: "B", CheckedOutAt: start.Add(time.Hour)})
+	tests := []struct {
+		name     string
+		from     time.Time
+		to       time.Time
+		expected []string
+	}{
+		{"Whole range", time.Time{}, time.Time{}, []string{"A", "B", "C"}},
+		{"From included", start.Add(time.Hour), time.Time{}, []string{"B", "C"}},
+		{"To excluded", time.Time{}, start.Add(time.Hour), []string{"A"}},
+		{"Empty range", start.Add(3 * time.Hour), time.Time{}, []string{}},
+	}
+
+	for _, test := range tests {
+		//ACT
+		orders, _ := store.List(test.from, test.to)
+
+		//ASSERT
+		numbers := []string{}
+		for _, o := range orders {
+			numbers = append(numbers, o.Number)
+		}
+		if len(numbers) != len(test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, numbers)
+			continue
+		}
+		for i := range numbers {
+			if numbers[i] != test.expected[i] {
+				t.Errorf("%s: expected %v, got %v", test.name, test.expected, numbers)
+				break
+			}
+		}
+	}
+
+}
+
+func TestMemoryOrderStoreCopiesOrders(t *testing.T) {
+
+	//ARRANGE
+	store := NewMemoryOrderStore()
+	order := Order{Number: "A", Coupons: []string{"SPRING10"}, Breakdown: Breakdown{
+		Lines:        []BreakdownLine{{ItemId: "MUG", Quantity: 1}},
+		AppliedRules: []AppliedRule{{RuleName: "Mugs", Consumed: []rules.ItemUnits{{ItemId: "MUG", Units: 1}}}},
+		Assignment:   &PromotionAssignment{Rules: []string{"Mugs"}},
+	}}
+	store.Create(order)
+
+	//ACT
+	order.Coupons[0] = "CHANGED"
+	order.Breakdown.Lines[0].Quantity = 5
+	got, _ := store.Get("A")
+	got.Breakdown.AppliedRules[0].Consumed[0].Units = 5
+	got.Breakdown.Assignment.Rules[0] = "CHANGED"
+	listed, _ := store.List(time.Time{}, time.Time{})
+	listed[0].Breakdown.Lines[0].Quantity = 7
+	stored, _ := store.Get("A")
+
+	//ASSERT
+	b := stored.Breakdown
+	if stored.Coupons[0] != "SPRING10" || b.Lines[0].Quantity != 1 || b.AppliedRules[0].Consumed[0].Units != 1 || b.Assignment.Rules[0] != "Mugs" {
+		t.Errorf("The stored order should not have been modified, got: %+v", stored)
+	}
+
+}
+
+func TestMemoryOrderStoreRedemptions(t *testing.T) {
+
+	//ARRANGE
+	store := NewMemoryOrderStore()
+	store.Create(Order{Number: "A", BasketId: "2", Coupons: []string{"SPRING10"}})
+	store.Create(Order{Number: "B", BasketId: "1", Coupons: []string{"SPRING10", "SUMMER5"}})
+	store.Create(Order{Number: "C", BasketId: "3"})
+
+	//ACT
+	redeemed, err := store.Redemptions("SPRING10")
+	none, _ := store.Redemptions("WINTER")
+
+	//ASSERT
+	if err != nil || len(redeemed) != 2 || redeemed[0] != "1" || redeemed[1] != "2" {
+		t.Errorf("The baskets checked out with the coupon should have been returned, got: %v %v", redeemed, err)
+	}
+
+	if len(none) != 0 {
+		t.Errorf("No basket should have been checked out with an unknown coupon, got: %v", none)
+	}
+
+}
+
+func TestMemoryOrderStoreCreateTwice(t *testing.T) {
+
+	//ARRANGE
+	store := NewMemoryOrderStore()
+	store.Create(Order{Number: "A"})
+
+	//ACT
+	err := store.Create(Order{Number: "A"})
+
+	//ASSERT
+	if err == nil {
+		t.Errorf("An order should not be replaced once it's stored")
+	}
+
+}
@@ -0,0 +1,244 @@
+package pricer
+
+import (
+	"errors"
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"testing"
+	"time"
+)
+
+//Order store failing to store any order
+type failingOrderStore struct {
+	*MemoryOrderStore
+}
+
+func (failingOrderStore) Create(o Order) error {
+	return errors.New("disk full")
+}
+
+//Order store letting the janitor evict the expired baskets before storing an order, as if the basket of the order
+//expired while it was being checked out
+type evictingOrderStore struct {
+	*MemoryOrderStore
+	janitor *BasketJanitor
+}
+
+func (s evictingOrderStore) Create(o Order) error {
+	s.janitor.Sweep(time.Now().Add(time.Hour))
+	return s.MemoryOrderStore.Create(o)
+}
+
+func TestCheckout(t *testing.T) {
+	//ARRANGE
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	r := parser.Rules{NxmRules: []parser.NxMRule{{RuleName: "Vouchers 2x1", AffectedItem: "VOUCHER", BuyN: 2, PayM: 1}}}
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, r, items)
+	config := pricer.Config()
+	config.Version = "v1"
+	pricer.SetConfig(config)
+	pricer.Orders = NewMemoryOrderStore()
+	pricer.Clock = FixedClock(now)
+	bId, _ := pricer.CreateBasket()
+	pricer.SetItemQuantity("VOUCHER", bId, 2)
+	pricer.ScanItem("MUG", bId)
+
+	//ACT
+	order, err := pricer.Checkout(bId)
+	stored, getErr := pricer.GetOrder(order.Number)
+
+	//ASSERT
+	if err != nil || getErr != nil {
+		t.Fatalf("The basket should have been checked out, got: %v, %v", err, getErr)
+	}
+
+	if order.Number == "" || order.BasketId != bId || order.ConfigVersion != "v1" || !order.OpenedAt.Equal(now) || !order.CheckedOutAt.Equal(now) {
+		t.Errorf("The order should have a number, its basket, the config version and its timestamps, got: %+v", order)
+	}
+
+	b := order.Breakdown
+	if b.Total != money.New(1250, "EUR") || len(b.Lines) != 2 || len(b.AppliedRules) != 1 || b.AppliedRules[0].RuleName != "Vouchers 2x1" {
+		t.Errorf("The order should have the lines, promotions and total of the basket, got: %+v", b)
+	}
+
+	if stored.Number != order.Number || stored.Breakdown.Total != order.Breakdown.Total {
+		t.Errorf("The order should have been stored, got: %+v", stored)
+	}
+}
+
+func TestCheckoutFreezesBasket(t *testing.T) {
+	//ARRANGE
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, parser.Rules{}, items)
+	pricer.Orders = NewMemoryOrderStore()
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("MUG", bId)
+	pricer.Checkout(bId)
+
+	//ACT
+	_, scanErr := pricer.ScanItem("MUG", bId)
+	_, clearErr := pricer.ClearBasket(bId)
+	_, checkoutErr := pricer.Checkout(bId)
+	total, totalErr := pricer.GetTotalAmount(bId)
+
+	//ASSERT
+	if !errors.Is(scanErr, ErrBasketCheckedOut) || !errors.Is(clearErr, ErrBasketCheckedOut) || !errors.Is(checkoutErr, ErrBasketCheckedOut) {
+		t.Errorf("The checked out basket should not be changed anymore, got: %v, %v, %v", scanErr, clearErr, checkoutErr)
+	}
+
+	if totalErr != nil || total != money.New(750, "EUR") {
+		t.Errorf("The checked out basket should still be readable, got: %s, %v", total, totalErr)
+	}
+
+	if orders, _ := pricer.ListOrders(time.Time{}, time.Time{}); len(orders) != 1 {
+		t.Errorf("The basket should have been checked out only once, got: %+v", orders)
+	}
+}
+
+func TestCheckoutEmptyBasket(t *testing.T) {
+	//ARRANGE
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, parser.Rules{}, items)
+	pricer.Orders = NewMemoryOrderStore()
+	bId, _ := pricer.CreateBasket()
+
+	//ACT
+	_, err := pricer.Checkout(bId)
+	_, scanErr := pricer.ScanItem("MUG", bId)
+
+	//ASSERT
+	if !errors.Is(err, ErrBasketEmpty) || scanErr != nil {
+		t.Errorf("The empty basket should not have been checked out, got: %v, %v", err, scanErr)
+	}
+}
+
+func TestCheckoutOrdersNotConfigured(t *testing.T) {
+	//ARRANGE
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, parser.Rules{}, items)
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("MUG", bId)
+
+	//ACT
+	_, err := pricer.Checkout(bId)
+	_, listErr := pricer.ListOrders(time.Time{}, time.Time{})
+	_, scanErr := pricer.ScanItem("MUG", bId)
+
+	//ASSERT
+	if !errors.Is(err, ErrOrdersNotConfigured) || !errors.Is(listErr, ErrOrdersNotConfigured) {
+		t.Errorf("The basket should not be checked out without an order store, got: %v, %v", err, listErr)
+	}
+
+	if scanErr != nil {
+		t.Errorf("The basket should have been left open, got: %v", scanErr)
+	}
+}
+
+func TestCheckoutReopensBasketOnFailure(t *testing.T) {
+	//ARRANGE
+	stock := 3
+	items := parser.ConfiguredItems{
+		"MUG": {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), Stock: &stock},
+	}
+	pricer := newTestPricer(t, parser.Rules{}, items)
+	pricer.Stock, _ = NewInventory(pricer.Baskets)
+	pricer.SetConfig(pricer.Config())
+	pricer.Orders = failingOrderStore{NewMemoryOrderStore()}
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("MUG", bId)
+
+	//ACT
+	_, err := pricer.Checkout(bId)
+	_, scanErr := pricer.ScanItem("MUG", bId)
+
+	//ASSERT
+	if err == nil || scanErr != nil {
+		t.Errorf("The basket should have been opened again after failing to store the order, got: %v, %v", err, scanErr)
+	}
+
+	if l, _ := pricer.GetStockLevel("MUG"); l.OnHand != 3 || l.Reserved != 2 {
+		t.Errorf("No mug should have been sold, got: %+v", l)
+	}
+}
+
+func TestCheckoutSellsStock(t *testing.T) {
+	//ARRANGE
+	stock := 3
+	items := parser.ConfiguredItems{
+		"MUG": {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), Stock: &stock},
+	}
+	pricer := newTestPricer(t, parser.Rules{}, items)
+	pricer.Stock, _ = NewInventory(pricer.Baskets)
+	pricer.SetConfig(pricer.Config())
+	pricer.Orders = NewMemoryOrderStore()
+	bId, _ := pricer.CreateBasket()
+	pricer.SetItemQuantity("MUG", bId, 2)
+
+	//ACT
+	_, err := pricer.Checkout(bId)
+
+	//ASSERT
+	if l, _ := pricer.GetStockLevel("MUG"); err != nil || l != (StockLevel{ItemId: "MUG", Tracked: true, OnHand: 1, Available: 1}) {
+		t.Errorf("The mugs of the order should have been taken out of the stock, got: %+v, %v", l, err)
+	}
+}
+
+func TestCheckoutSellsStockOfBasketExpiredMeanwhile(t *testing.T) {
+	//ARRANGE
+	stock := 3
+	items := parser.ConfiguredItems{
+		"MUG": {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), Stock: &stock},
+	}
+	pricer := newTestPricer(t, parser.Rules{}, items)
+	pricer.Stock, _ = NewInventory(pricer.Baskets)
+	pricer.SetConfig(pricer.Config())
+	pricer.Janitor = NewBasketJanitor(pricer.Baskets, ExpiryPolicy{IdleTTL: 30 * time.Minute})
+	pricer.Janitor.Stock = pricer.Stock
+	pricer.Orders = evictingOrderStore{NewMemoryOrderStore(), pricer.Janitor}
+	bId, _ := pricer.CreateBasket()
+	pricer.SetItemQuantity("MUG", bId, 2)
+
+	//ACT
+	_, err := pricer.Checkout(bId)
+
+	//ASSERT
+	if l, _ := pricer.GetStockLevel("MUG"); err != nil || l != (StockLevel{ItemId: "MUG", Tracked: true, OnHand: 1, Available: 1}) {
+		t.Errorf("The mugs of the order should have been sold even though its basket expired meanwhile, got: %+v, %v", l, err)
+	}
+}
+
+func TestListOrders(t *testing.T) {
+	//ARRANGE
+	start := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	items, _ := new(MockedItemsParser).ParseItemsDefinitions("DUMMYPATH")
+	pricer := newTestPricer(t, parser.Rules{}, items)
+	pricer.Orders = NewMemoryOrderStore()
+	var numbers []string
+	for day := 0; day < 3; day++ {
+		pricer.Clock = FixedClock(start.AddDate(0, 0, day))
+		bId, _ := pricer.CreateBasket()
+		pricer.ScanItem("MUG", bId)
+		order, _ := pricer.Checkout(bId)
+		numbers = append(numbers, order.Number)
+	}
+
+	//ACT
+	orders, err := pricer.ListOrders(start.AddDate(0, 0, 1), time.Time{})
+	_, invalidErr := pricer.ListOrders(start, start.Add(-time.Hour))
+	_, notFoundErr := pricer.GetOrder("FAKEORDER")
+
+	//ASSERT
+	if err != nil || len(orders) != 2 || orders[0].Number != numbers[1] || orders[1].Number != numbers[2] {
+		t.Errorf("The orders of the last 2 days should have been listed, got: %+v, %v", orders, err)
+	}
+
+	if !errors.Is(invalidErr, ErrInvalidDateRange) {
+		t.Errorf("A range ending before it starts should not be valid, got: %v", invalidErr)
+	}
+
+	var pricerErr *PricerError
+	if !errors.Is(notFoundErr, ErrOrderNotFound) || !errors.As(notFoundErr, &pricerErr) || pricerErr.OrderNumber != "FAKEORDER" {
+		t.Errorf("The order should not have been found, got: %v", notFoundErr)
+	}
+}
@@ -1,140 +1,391 @@
 package pricer
 
 import (
-	"errors"
+	"fmt"
+	"github.com/dagozba/golangsmallshop/internal/money"
 	"github.com/dagozba/golangsmallshop/internal/parser"
 	"github.com/dagozba/golangsmallshop/internal/rules"
 	"github.com/segmentio/ksuid"
 	log "github.com/sirupsen/logrus"
-	"sync"
+	"sort"
+	"sync/atomic"
+	"time"
 )
 
 //Trying to follow the Inversion of Control principle through Dependency Injection using the "Constructor"
 //This allows for better unit testing as dependencies can be mocked or dummies can be created
+//Rounding is the rounding mode applied once to the sum of all the rule subtotals, half-up by default
+//Baskets is where the baskets are stored, see MemoryBasketStore and FileBasketStore
+//Janitor expires the abandoned baskets of the store, baskets never expire if it's nil
+//Optimiser picks the order of the competing rules giving the cheapest total, the rules are executed in the configured
+//order if it's nil
+//Clock is the source of the current time, the system time if it's nil, and SchedulePolicy decides the time the
+//schedules of the rules are checked at when a basket is priced
+//Orders is where the orders of the checked out baskets are stored, see MemoryOrderStore and FileOrderStore, the baskets
+//can't be checked out if it's nil
+//Stock tracks the stock of the items, reserving the units scanned into the baskets, the stock is not tracked if it's nil
+//The items and rules in use are kept in a PricingConfig that can be replaced at runtime, see Config and SetConfig
+//A Pricer is created with NewPricer, the optional dependencies are set on it afterwards
 type Pricer struct {
 	StrategyFactory rules.RuleStrategyFactory
 	ItemsParser     parser.IParser
-	ConfiguredItems parser.ConfiguredItems
+	Rounding        money.RoundingMode
+	Baskets         BasketStore
+	Orders          OrderStore
+	Janitor         *BasketJanitor
+	Optimiser       *rules.Optimiser
+	Clock           Clock
+	SchedulePolicy  SchedulePolicy
+	Stock           *Inventory
+	config          *atomic.Value
+}
+
+//Creates a Pricer storing its baskets in the given store, with the executors of the given factory and the given items
+//parser as its configuration until the items are loaded. The configuration in use is shared by every copy of the Pricer
+func NewPricer(factory rules.RuleStrategyFactory, itemsParser parser.IParser, baskets BasketStore) *Pricer {
+	return &Pricer{StrategyFactory: factory, ItemsParser: itemsParser, Baskets: baskets, config: new(atomic.Value)}
 }
 
 type Item struct {
 	id    string
 	name  string
-	price float32
+	price money.Money
 }
 
-type Basket struct {
-	items     map[string]int
-	itemsLock *sync.RWMutex
+//It begins parsing the item definitions defined in /configs/item_definitions.yaml
+//This would be stored in a database or a cloud configuration service, but I didn't want to include a Database access
+//for this exercise, the files can be reloaded at runtime using a ConfigReloader instead
+//The loaded items and the StrategyFactory executors become the configuration in use, once the items selected by
+//category or tags by the rules have been resolved against the loaded items
+//If the item definitions can't be parsed the error is returned and the configuration in use, if any, is kept
+func (p *Pricer) LoadItems(itemsFilePath string) error {
+	log.Info("Parsing initial Item Definitions for Pricer")
+	configuredItems, err := p.ItemsParser.ParseItemsDefinitions(itemsFilePath)
+	if err != nil {
+		return err
+	}
+	p.StrategyFactory.ResolveSelectors(configuredItems)
+	p.SetConfig(PricingConfig{Items: configuredItems, Executors: p.StrategyFactory.RuleExecutors, BasketExecutors: p.StrategyFactory.BasketExecutors,
+		Coupons: p.StrategyFactory.Coupons, Location: p.StrategyFactory.Location})
+	return nil
 }
 
-type BasketSession struct {
-	baskets     map[string]*Basket
-	basketsLock *sync.RWMutex
+//It creates a new UID as the basket identifier and stores a new Basket with it, where scanned items will be stored
+func (p Pricer) CreateBasket() (string, error) {
+	id := ksuid.New().String()
+	log.Infof("Generating basket with id '%s'", id)
+	if err := p.Baskets.Create(newBasket(id, p.now())); err != nil {
+		return "", newPricerError(err, id, "")
+	}
+	return id, nil
 }
 
-//The map where the created baskets is stored. In a normal microservices environment, this would not be ideal at all
-//as we would want our microservices to be stateless and all the session state should be stored in an session store
-//In AWS, it would probably be DynamoDB or ElasticCache.
-//The whole idea of this application was to test as many things as possible from just Golang, so I went for concurrent
-//access to an in memory map
-var basketSession = BasketSession{baskets: make(map[string]*Basket), basketsLock: new(sync.RWMutex)}
+//Stores an item in the given basket. returns an error if the basket doesn't exist, has expired or the item has not been defined by configuration
+//The item can be given by its id or by any of its barcodes, see resolveItem
+//The items sold by weight or measure can't be scanned by units, see ScanWeightedItem, and an item with variants can't be
+//scanned but its variants can, by their id or barcodes
+//When the stock is tracked the unit is reserved for the basket, returns an error if there are no units available
+func (p *Pricer) ScanItem(i string, basketId string) (bool, error) {
+	i = p.resolveItem(i)
+	log.Infof("Scanning item %s into basket %s", i, basketId)
+	err := p.updateStockedItem(i, basketId, false, func(b *Basket) error {
+		if err := p.checkCounted(i); err != nil {
+			return err
+		}
+		b.addItem(i)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	log.Infof("Item %s added to the basket %s", i, basketId)
+	return true, nil
+}
 
-//It begins parsing the item definitions defined in /configs/item_definitions.yaml
-//This would be stored in a database or a cloud configuration service so it could be modified at runtime, but I didn't wan
-//to include a Database access for this exercise as I wanted to try concurrent access to an in memory map
-func (p *Pricer) LoadItems(itemsFilePath string) error {
-	log.Info("Parsing initial Item Definitions for Pricer")
-	configuredItems, err := p.ItemsParser.ParseItemsDefinitions(itemsFilePath)
-	p.ConfiguredItems = configuredItems
-	return err
+//Stores the weighed or measured quantity of an item sold by weight or measure in the given basket, in units of the
+//item, ie: 0.350 for 350 g of an item sold by kg. Scanning the item again adds up the quantities
+//returns an error if the basket doesn't exist, the item has not been defined by configuration, it's not sold by weight
+//or measure or the quantity is not above zero. The item can be given by its id or by any of its barcodes
+func (p *Pricer) ScanWeightedItem(i string, basketId string, quantity money.Decimal) (bool, error) {
+	i = p.resolveItem(i)
+	log.Infof("Scanning %s of item %s into basket %s", quantity, i, basketId)
+	if quantity <= 0 {
+		return false, newPricerError(fmt.Errorf("%w, a weighed quantity must be above zero", ErrInvalidQuantity), basketId, i)
+	}
+	err := p.updateBasketItem(i, basketId, false, func(b *Basket) error {
+		if !p.Config().Items[i].IsMeasured() {
+			return ErrItemNotMeasured
+		}
+		b.addMeasure(i, quantity)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	log.Infof("%s of item %s added to the basket %s", quantity, i, basketId)
+	return true, nil
 }
 
-//Executes all the rules loaded from the yaml file on the basket items
-func (b Basket) executeRules(executors []rules.RuleStrategyExecutor, configuredItems parser.ConfiguredItems) int64 {
-	var total int64
-	b.itemsLock.Lock()
-	defer b.itemsLock.Unlock()
-	for _, executor := range executors {
-		total += executor.ExecuteRule(configuredItems, b.items)
+//Stores the item of an EAN-13 variable measure barcode in the given basket, with the quantity embedded in the barcode
+//see parser.VariableBarcode and ScanWeightedItem
+//returns an error if the basket doesn't exist, the barcode is not valid or no item has been defined with its code
+func (p *Pricer) ScanVariableBarcode(barcode string, basketId string) (bool, error) {
+	log.Infof("Scanning barcode %s into basket %s", barcode, basketId)
+	code, value, err := parser.SplitVariableBarcode(barcode)
+	if err != nil {
+		return false, newPricerError(fmt.Errorf("%w: %v", ErrInvalidBarcode, err), basketId, "")
 	}
-	return total
+	id, item, exs := p.Config().Items.ByVariableCode(code)
+	if !exs {
+		if _, err := p.getBasket(basketId); err != nil {
+			return false, err
+		}
+		return false, newPricerError(ErrItemNotConfigured, basketId, code)
+	}
+	return p.ScanWeightedItem(id, basketId, item.QuantityOf(value))
 }
 
-//Adds an item to the given basket
-func (b *Basket) addItemToBasket(i string) {
-	b.itemsLock.Lock()
-	defer b.itemsLock.Unlock()
-	if _, exs := b.items[i]; exs {
-		b.items[i]++
-	} else {
-		b.items[i] = 1
+//Removes a unit of an item from the given basket, ie: to undo a mis-scan
+//The whole quantity of an item sold by weight or measure is removed and the item can be given by any of its barcodes
+//returns an error if the basket doesn't exist, the item has not been defined by configuration or it isn't in the basket
+func (p *Pricer) RemoveItem(i string, basketId string) (bool, error) {
+	i = p.resolveItem(i)
+	log.Infof("Removing item %s from basket %s", i, basketId)
+	err := p.updateStockedItem(i, basketId, true, func(b *Basket) error {
+		if !b.removeItem(i) {
+			return ErrItemNotInBasket
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
 	}
+	log.Infof("Item %s removed from the basket %s", i, basketId)
+	return true, nil
 }
 
-func (bs BasketSession) getBasket(key string) *Basket {
-	bs.basketsLock.RLock()
-	defer bs.basketsLock.RUnlock()
-	return bs.baskets[key]
+//Sets the number of units of an item in the given basket, setting it to 0 removes the item from the basket
+//returns an error if the basket doesn't exist, the item has not been defined by configuration, it's sold by weight or
+//measure or through its variants, the quantity is negative or there are not enough units of it in stock
+//The item can be given by its id or by any of its barcodes
+func (p *Pricer) SetItemQuantity(i string, basketId string, quantity int) (bool, error) {
+	i = p.resolveItem(i)
+	log.Infof("Setting the quantity of item %s in basket %s to %d", i, basketId, quantity)
+	if quantity < 0 {
+		return false, newPricerError(ErrInvalidQuantity, basketId, i)
+	}
+	err := p.updateStockedItem(i, basketId, true, func(b *Basket) error {
+		if err := p.checkCounted(i); err != nil {
+			return err
+		}
+		b.setItemQuantity(i, quantity)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	log.Infof("Item %s quantity set to %d in the basket %s", i, quantity, basketId)
+	return true, nil
 }
 
-func (bs BasketSession) createBasket() string {
-	id := ksuid.New().String()
-	log.Infof("Generating basket with id '%s'", id)
-	bs.basketsLock.Lock()
-	defer bs.basketsLock.Unlock()
-	bs.baskets[id] = &Basket{items: make(map[string]int), itemsLock: new(sync.RWMutex)}
-	return id
+//Removes all the items of the given basket, keeping the basket so new items can be scanned into it
+//returns an error if the basket doesn't exist
+func (p *Pricer) ClearBasket(basketId string) (bool, error) {
+	log.Infof("Clearing basket %s", basketId)
+	var cleared map[string]int
+	err := p.updateBasket(basketId, "", func(b *Basket) error {
+		cleared = b.Items
+		b.clearItems()
+		p.Stock.release(basketId)
+		return nil
+	})
+	if err != nil {
+		p.Stock.restore(basketId, cleared)
+		return false, err
+	}
+	log.Infof("Basket %s has been cleared", basketId)
+	return true, nil
 }
 
-func (bs BasketSession) deleteBasket(basketId string) {
-	bs.basketsLock.Lock()
-	defer bs.basketsLock.Unlock()
-	delete(basketSession.baskets, basketId)
+//Returns the id of the item with the given id or barcode, the ids take precedence over the barcodes
+//The barcodes are looked up in their GTIN-14 form, so a UPC-A finds the item configured with its EAN-13 twin
+//An item that is not found is returned as is, so it's reported as not configured
+func (p Pricer) resolveItem(idOrBarcode string) string {
+	config := p.Config()
+	if _, exs := config.Items[idOrBarcode]; exs {
+		return idOrBarcode
+	}
+	if id, exs := config.Barcodes[parser.NormaliseBarcode(idOrBarcode)]; exs {
+		log.Infof("Barcode %s resolved to item %s", idOrBarcode, id)
+		return id
+	}
+	return idOrBarcode
 }
 
-//It creates a new UID as the basket identifier and adds it to the basketsSession map with a pointer to a Basket struct
-//where scanned items will be stored
-func (p Pricer) CreateBasket() string {
-	return basketSession.createBasket()
+//Checks the basket can be priced with the given configuration at the given time: all its items are configured and all
+//its coupons can be used, see checkCoupons
+//A new configuration may have removed an item already in the basket, which is reported instead of pricing it at zero
+func (p Pricer) checkPriceable(b Basket, config PricingConfig, at time.Time) error {
+	var missing []string
+	for id := range b.Items {
+		if _, exs := config.Items[id]; !exs {
+			missing = append(missing, id)
+		}
+	}
+	for id := range b.Measures {
+		if _, exs := config.Items[id]; !exs {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return newPricerError(ErrItemNotConfigured, b.Id, missing[0])
+	}
+	return p.checkCoupons(b, config, at)
 }
 
-//Stores an item in the given basket. returns an error if the basket doesn't exist or the item has not been defined by configuration
-func (p *Pricer) ScanItem(i string, basketId string) (bool, error) {
-	log.Infof("Scanning item %s into basket %s", i, basketId)
-	basket := basketSession.getBasket(basketId)
-	if basket == nil {
-		log.Errorf("The basket '%s' doesn't exist", basketId)
-		return false, errors.New("the specified basket doesn't exist")
+//Checks the given item can be counted by units: it's not sold by weight or measure nor through its variants
+func (p Pricer) checkCounted(i string) error {
+	item := p.Config().Items[i]
+	if item.IsMeasured() {
+		return ErrItemMeasured
 	}
-	if _, prs := p.ConfiguredItems[i]; prs == false {
-		log.Errorf("the item '%s' has not been configured in the server", i)
-		return false, errors.New("the specified item is not configured in the server")
+	if item.HasVariants() {
+		return ErrItemHasVariants
 	}
-	basket.addItemToBasket(i)
-	log.Infof("Item %s added to the basket %s", i, basketId)
-	return true, nil
+	return nil
+}
+
+//Checks the item has been defined by configuration and applies the given change to the basket while holding its lock
+//When held is true, an item already in the basket can be changed even if it's not configured anymore, so the items
+//removed by a new configuration can be taken out of the basket. A missing basket is reported before a missing item
+func (p *Pricer) updateBasketItem(i string, basketId string, held bool, update func(b *Basket) error) error {
+	_, configured := p.Config().Items[i]
+	return p.updateBasket(basketId, i, func(b *Basket) error {
+		if !configured && (!held || !b.holds(i)) {
+			return ErrItemNotConfigured
+		}
+		return update(b)
+	})
+}
+
+//Same as updateBasketItem, reserving the units of the item the basket has after the change, so the change is rejected
+//if there are not enough units in stock. The reservation is undone if the change can't be stored
+func (p *Pricer) updateStockedItem(i string, basketId string, held bool, update func(b *Basket) error) error {
+	reserved, previous := false, 0
+	err := p.updateBasketItem(i, basketId, held, func(b *Basket) error {
+		previous = b.Items[i]
+		if err := update(b); err != nil {
+			return err
+		}
+		if err := p.Stock.reserve(basketId, i, b.Items[i]); err != nil {
+			return err
+		}
+		reserved = true
+		return nil
+	})
+	if err != nil && reserved {
+		p.Stock.restore(basketId, map[string]int{i: previous})
+	}
+	return err
+}
+
+//Applies the given change to the basket while holding its lock, unless the basket has expired or has been checked out
+//The change counts as activity, so the basket idle time starts again
+func (p Pricer) updateBasket(basketId string, itemId string, update func(b *Basket) error) error {
+	return newPricerError(p.applyUpdate(basketId, update), basketId, itemId)
+}
+
+//Same as updateBasket, but the error is returned without wrapping it into a PricerError
+func (p Pricer) applyUpdate(basketId string, update func(b *Basket) error) error {
+	now := p.now()
+	err := p.Baskets.Update(basketId, func(b *Basket) error {
+		if err := p.Janitor.check(*b, now); err != nil {
+			return err
+		}
+		if b.OrderNumber != "" {
+			return ErrBasketCheckedOut
+		}
+		if err := update(b); err != nil {
+			return err
+		}
+		b.UpdatedAt = now
+		return nil
+	})
+	if err != nil {
+		return p.Janitor.explain(basketId, err, now)
+	}
+	return nil
+}
+
+//Returns a copy of the given basket, or an error if it doesn't exist or has expired
+func (p Pricer) getBasket(basketId string) (Basket, error) {
+	now := p.now()
+	basket, err := p.Baskets.Get(basketId)
+	if err == nil {
+		err = p.Janitor.check(basket, now)
+	}
+	if err != nil {
+		return basket, newPricerError(p.Janitor.explain(basketId, err, now), basketId, "")
+	}
+	return basket, nil
+}
+
+//Returns the items scanned into the given basket along with their configured names, sorted by item id
+//if the basket doesn't exist, an error is returned
+func (p Pricer) GetBasket(basketId string) (BasketContents, error) {
+	log.Infof("Getting the contents of basket %s", basketId)
+	basket, err := p.getBasket(basketId)
+	if err != nil {
+		return BasketContents{}, err
+	}
+	items := p.Config().Items
+	contents := BasketContents{BasketId: basketId, CreatedAt: basket.CreatedAt, Coupons: basket.couponCodes()}
+	for id, quantity := range basket.Items {
+		contents.Lines = append(contents.Lines, BasketLine{ItemId: id, Name: items[id].Name, Quantity: quantity, Unit: parser.UnitEach})
+	}
+	for id, measure := range basket.Measures {
+		contents.Lines = append(contents.Lines, BasketLine{ItemId: id, Name: items[id].Name, Measure: measure, Unit: items[id].Unit})
+	}
+	sort.Slice(contents.Lines, func(i, j int) bool { return contents.Lines[i].ItemId < contents.Lines[j].ItemId })
+	return contents, nil
 }
 
 //Calculates the total price for the items in the given basket by executing all the Pricing Rules in the RuleExecutors slice
+//and then the basket rules in the BasketExecutors slice
 //As all rules implement the RuleStrategyExecutor interface, by calling ExecuteRule any rule can be executed and the Pricer
 //delegates the rules creation and execution logic to the rules strategy factory.
-//if the basket doesn't exist, an error is returned
-func (p Pricer) GetTotalAmount(basketId string) (int64, error) {
+//The rules work with exact subtotals, the total is rounded to the currency's minor unit only once, here, using the
+//Pricer's rounding mode
+//When the configured prices don't include the tax, the total is the discounted amount plus its tax, see taxSummary
+//Only the rules whose schedule is active are executed, checked at the time given by the Pricer's schedule policy
+//The whole calculation uses the configuration in use when it starts, even if a new one is loaded meanwhile
+//if the basket doesn't exist or has expired, any of its items is not configured anymore, ie: a new configuration has
+//removed it, or any of its coupons can't be used anymore, an error is returned instead of pricing it
+func (p Pricer) GetTotalAmount(basketId string) (money.Money, error) {
 	log.Infof("Getting total amount of items with applied discounts in basket %s", basketId)
-	basket := basketSession.getBasket(basketId)
-	if basket == nil {
-		log.Errorf("The basket '%s' doesn't exist", basketId)
-		return 0, errors.New("the specified basket doesn't exist")
-	} else {
-		return basket.executeRules(p.StrategyFactory.RuleExecutors, p.ConfiguredItems), nil
+	basket, err := p.getBasket(basketId)
+	if err != nil {
+		return money.Money{}, err
+	}
+	config := p.Config()
+	at := p.pricedAt(basket, config)
+	if err := p.checkPriceable(basket, config, at); err != nil {
+		return money.Money{}, err
 	}
+	result, _ := basket.executeRules(config, p.Optimiser, at)
+	return taxSummary(result, config.Items, p.Rounding).Gross, nil
 }
 
-//Removes the basket from the basketSession map
-func (p Pricer) RemoveBasket(basketId string) bool {
+//Removes the basket from the basket store, removing a basket that doesn't exist is not an error
+//The units reserved by the basket are released
+//returns an error if the basket store couldn't remove it
+func (p Pricer) RemoveBasket(basketId string) (bool, error) {
 	log.Infof("Removing basket '%s'", basketId)
-	basketSession.deleteBasket(basketId)
+	if err := p.Baskets.Delete(basketId); err != nil {
+		return false, newPricerError(err, basketId, "")
+	}
+	p.Stock.release(basketId)
 	log.Infof("Basket '%s' has been removed", basketId)
-	return true
+	return true, nil
 }
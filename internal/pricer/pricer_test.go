@@ -1,55 +1,76 @@
 package pricer
 
 import (
+	"errors"
+	"github.com/dagozba/golangsmallshop/internal/money"
 	"github.com/dagozba/golangsmallshop/internal/parser"
 	"github.com/dagozba/golangsmallshop/internal/rules"
 	"github.com/stretchr/testify/mock"
 	"testing"
+	"time"
 )
 
 type MockedItemsParser struct {
 	mock.Mock
 }
 
-func (m MockedItemsParser) ParseItemsDefinitions(p string) (parser.ConfiguredItems, error) {
+func (m *MockedItemsParser) ParseItemsDefinitions(p string) (parser.ConfiguredItems, error) {
 	return parser.ConfiguredItems{
 		"VOUCHER": parser.ItemDefinition{
 			Name:  "Company Voucher",
-			Price: 5.00,
+			Price: money.New(500, "EUR"),
 		},
 		"TSHIRT": parser.ItemDefinition{
 			Name:  "Company T-Shirt",
-			Price: 20.00,
+			Price: money.New(2000, "EUR"),
 		},
 		"MUG": parser.ItemDefinition{
 			Name:  "Company Coffee Mug",
-			Price: 7.5,
+			Price: money.New(750, "EUR"),
 		},
 	}, nil
 }
 
+//Creates a Pricer storing its baskets in memory with the configuration made of the given rules and items, the test fails
+//if they are not consistent with each other
+func newTestPricer(t *testing.T, r parser.Rules, items parser.ConfiguredItems) *Pricer {
+	t.Helper()
+	config, err := NewPricingConfig(r, items)
+	if err != nil {
+		t.Fatalf("The configuration of the test is not valid: %v", err)
+	}
+	pricer := NewPricer(rules.RuleStrategyFactory{}, nil, NewMemoryBasketStore())
+	pricer.SetConfig(config)
+	return pricer
+}
+
+func getItems(p *Pricer, basketId string) map[string]int {
+	b, _ := p.Baskets.Get(basketId)
+	return b.Items
+}
+
 func TestCreateBasket(t *testing.T) {
 
 	//ARRANGE
-	pricer := &Pricer{}
+	pricer := NewPricer(rules.RuleStrategyFactory{}, nil, NewMemoryBasketStore())
 
 	//ACT
-	id := pricer.CreateBasket()
+	id, _ := pricer.CreateBasket()
 
 	//ASSERT
-	if l := len(basketSession.baskets); l != 1 {
-		t.Errorf("No basket has been inserted in the sessionBasket, expected: %d, got: %d", 1, l)
+	if baskets, _ := pricer.Baskets.List(); len(baskets) != 1 {
+		t.Errorf("No basket has been inserted in the basket store, expected: %d, got: %d", 1, len(baskets))
 	}
 
-	if _, exs := basketSession.baskets[id]; !exs {
+	if _, err := pricer.Baskets.Get(id); err != nil {
 		t.Errorf("The returned basket id doesn't match the inserted one")
 	}
 }
 
 func TestCreateAccessRemoveBasketConcurrent(t *testing.T) {
-	pricer := &Pricer{}
+	pricer := NewPricer(rules.RuleStrategyFactory{}, nil, NewMemoryBasketStore())
 	for i := 0; i < 10; i++ {
-		id := pricer.CreateBasket()
+		id, _ := pricer.CreateBasket()
 		go pricer.ScanItem("VOUCHER", id)
 		go pricer.GetTotalAmount(id)
 		go pricer.RemoveBasket(id)
@@ -59,9 +80,9 @@ func TestCreateAccessRemoveBasketConcurrent(t *testing.T) {
 func TestScanItem(t *testing.T) {
 	//ARRANGE
 	itemsParserMock := new(MockedItemsParser)
-	pricer := &Pricer{ItemsParser: itemsParserMock}
+	pricer := NewPricer(rules.RuleStrategyFactory{}, itemsParserMock, NewMemoryBasketStore())
 	pricer.LoadItems("DUMMYPATH")
-	bId := pricer.CreateBasket()
+	bId, _ := pricer.CreateBasket()
 
 	//ACT
 	_, err := pricer.ScanItem("VOUCHER", bId)
@@ -71,7 +92,7 @@ func TestScanItem(t *testing.T) {
 		t.Errorf("Item scanning failed when it should've worked, err: %+v", err)
 	}
 
-	if v, _ := basketSession.baskets[bId].items["VOUCHER"]; v != 1 {
+	if v, _ := getItems(pricer, bId)["VOUCHER"]; v != 1 {
 		t.Errorf("The item inserted in the basket doesn't match the provided one, expected: %s %d, got: %d", "VOUCHER", 1, v)
 	}
 
@@ -81,9 +102,9 @@ func TestScanItemNonExistentItem(t *testing.T) {
 
 	//ARRANGE
 	itemsParserMock := new(MockedItemsParser)
-	pricer := &Pricer{ItemsParser: itemsParserMock}
+	pricer := NewPricer(rules.RuleStrategyFactory{}, itemsParserMock, NewMemoryBasketStore())
 	pricer.LoadItems("DUMMYPATH")
-	bId := pricer.CreateBasket()
+	bId, _ := pricer.CreateBasket()
 
 	//ACT
 	_, err := pricer.ScanItem("NONEXISTENT", bId)
@@ -99,7 +120,7 @@ func TestScanItemNonExistentBasket(t *testing.T) {
 
 	//ARRANGE
 	itemsParserMock := new(MockedItemsParser)
-	pricer := &Pricer{ItemsParser: itemsParserMock}
+	pricer := NewPricer(rules.RuleStrategyFactory{}, itemsParserMock, NewMemoryBasketStore())
 	pricer.LoadItems("DUMMYPATH")
 
 	//ACT
@@ -115,15 +136,15 @@ func TestScanItemNonExistentBasket(t *testing.T) {
 func TestRemoveBasket(t *testing.T) {
 
 	//ARRANGE
-	pricer := &Pricer{}
-	bId := pricer.CreateBasket()
+	pricer := NewPricer(rules.RuleStrategyFactory{}, nil, NewMemoryBasketStore())
+	bId, _ := pricer.CreateBasket()
 
 	//ACT
 	pricer.RemoveBasket(bId)
 
 	//ASSERT
-	if _, exs := basketSession.baskets[bId]; exs {
-		t.Errorf("The current basket shouldn't be present in tbe baskets session map")
+	if _, err := pricer.Baskets.Get(bId); err != ErrBasketNotFound {
+		t.Errorf("The current basket shouldn't be present in tbe basket store")
 	}
 
 }
@@ -131,15 +152,15 @@ func TestRemoveBasket(t *testing.T) {
 func TestGetTotalAmountNoItems(t *testing.T) {
 
 	//ASSERT
-	pricer := &Pricer{}
-	bId := pricer.CreateBasket()
+	pricer := NewPricer(rules.RuleStrategyFactory{}, nil, NewMemoryBasketStore())
+	bId, _ := pricer.CreateBasket()
 
 	//ACT
 	amount, err := pricer.GetTotalAmount(bId)
 
 	//ASSERT
-	if amount != 0 {
-		t.Errorf("The amount calculated should be 0 if there are no items scanned, got: %f", float64(amount))
+	if amount.Amount != 0 {
+		t.Errorf("The amount calculated should be 0 if there are no items scanned, got: %s", amount)
 	}
 
 	if err != nil {
@@ -153,19 +174,18 @@ func TestGetTotalAmountWithItems(t *testing.T) {
 	//ARRANGE
 	itemsParserMock := new(MockedItemsParser)
 	rulesStrategyFactory := rules.RuleStrategyFactory{RuleExecutors: []rules.RuleStrategyExecutor{rules.DefaultRuleStrategy{}}}
-	pricer := &Pricer{ItemsParser: itemsParserMock, StrategyFactory: rulesStrategyFactory}
+	pricer := NewPricer(rulesStrategyFactory, itemsParserMock, NewMemoryBasketStore())
 	pricer.LoadItems("DUMMYPATH")
 
-
-	bId := pricer.CreateBasket()
+	bId, _ := pricer.CreateBasket()
 	pricer.ScanItem("VOUCHER", bId)
 
 	//ACT
 	amount, err := pricer.GetTotalAmount(bId)
-	amount = amount / 100
+
 	//ASSERT
-	if amount != 5.0 {
-		t.Errorf("The amount calculated should be VOUCHER's value, expected: %f, got: %f", 5.0, float64(amount))
+	if amount != money.New(500, "EUR") {
+		t.Errorf("The amount calculated should be VOUCHER's value, expected: %s, got: %s", money.New(500, "EUR"), amount)
 	}
 
 	if err != nil {
@@ -177,7 +197,7 @@ func TestGetTotalAmountWithItems(t *testing.T) {
 func TestGetTotalAmountNonExistentBasket(t *testing.T) {
 
 	//ARRANGE
-	pricer := &Pricer{}
+	pricer := NewPricer(rules.RuleStrategyFactory{}, nil, NewMemoryBasketStore())
 
 	//ACT
 	_, err := pricer.GetTotalAmount("FAKEBASKETID")
@@ -195,12 +215,12 @@ func TestGetTotalAmountApplyingBulkRule(t *testing.T) {
 	itemsParserMock := new(MockedItemsParser)
 	rulesStrategyFactory := rules.RuleStrategyFactory{RuleExecutors: []rules.RuleStrategyExecutor{
 		rules.BulkRuleStrategy{
-			Rule: parser.BulkRule {RuleName: "Bulk Rule", AffectedItem: "TSHIRT", TriggerAmount: 3, DiscountPercentage: 5}}}}
-	pricer := &Pricer{ItemsParser: itemsParserMock, StrategyFactory: rulesStrategyFactory}
+			Rule: parser.BulkRule{RuleName: "Bulk Rule", AffectedItem: "TSHIRT", TriggerAmount: 3, DiscountPercentage: 5}}}}
+	pricer := NewPricer(rulesStrategyFactory, itemsParserMock, NewMemoryBasketStore())
 	pricer.LoadItems("DUMMYPATH")
 
 	//ASSERT
-	bId := pricer.CreateBasket()
+	bId, _ := pricer.CreateBasket()
 	pricer.ScanItem("TSHIRT", bId)
 	pricer.ScanItem("TSHIRT", bId)
 	pricer.ScanItem("TSHIRT", bId)
@@ -211,8 +231,8 @@ func TestGetTotalAmountApplyingBulkRule(t *testing.T) {
 	calc, err := pricer.GetTotalAmount(bId)
 
 	//ASSERT
-	if calc != expectedCalc {
-		t.Errorf("Applying the BulkRule to TSHIRT should've produced %0.2f, got: %0.2f", float64(expectedCalc), float64(calc))
+	if calc.Amount != expectedCalc {
+		t.Errorf("Applying the BulkRule to TSHIRT should've produced %0.2f, got: %0.2f", float64(expectedCalc), float64(calc.Amount))
 	}
 
 	if err != nil {
@@ -227,11 +247,11 @@ func TestGetTotalAmountApplyingBundleNxMRuleExactBundle(t *testing.T) {
 	itemsParserMock := new(MockedItemsParser)
 	rulesStrategyFactory := rules.RuleStrategyFactory{RuleExecutors: []rules.RuleStrategyExecutor{
 		rules.NxMRuleStrategy{Rule: parser.NxMRule{RuleName: "NxM Rule", AffectedItem: "VOUCHER", BuyN: 2, PayM: 1}}}}
-	pricer := &Pricer{ItemsParser: itemsParserMock, StrategyFactory: rulesStrategyFactory}
+	pricer := NewPricer(rulesStrategyFactory, itemsParserMock, NewMemoryBasketStore())
 	pricer.LoadItems("DUMMYPATH")
 
 	//ASSERT
-	bId := pricer.CreateBasket()
+	bId, _ := pricer.CreateBasket()
 	pricer.ScanItem("VOUCHER", bId)
 	pricer.ScanItem("VOUCHER", bId)
 
@@ -241,8 +261,8 @@ func TestGetTotalAmountApplyingBundleNxMRuleExactBundle(t *testing.T) {
 	calc, err := pricer.GetTotalAmount(bId)
 
 	//ASSERT
-	if calc != expectedCalc {
-		t.Errorf("Applying the NxM to VOUCH should've produced %0.2f, got: %0.2f", float64(expectedCalc), float64(calc))
+	if calc.Amount != expectedCalc {
+		t.Errorf("Applying the NxM to VOUCH should've produced %0.2f, got: %0.2f", float64(expectedCalc), float64(calc.Amount))
 	}
 
 	if err != nil {
@@ -257,19 +277,18 @@ func TestGetTotalAmountApplyingBundleNxMRuleBundleAndRemainder(t *testing.T) {
 	itemsParserMock := new(MockedItemsParser)
 	rulesStrategyFactory := rules.RuleStrategyFactory{
 		RuleExecutors: []rules.RuleStrategyExecutor{
-		rules.NxMRuleStrategy{
-			Rule: parser.NxMRule{
-				RuleName: "NxM Rule",
-				AffectedItem: "VOUCHER",
-				BuyN: 2,
-				PayM: 1}}},
-	}
-	pricer := &Pricer{ItemsParser: itemsParserMock, StrategyFactory: rulesStrategyFactory}
+			rules.NxMRuleStrategy{
+				Rule: parser.NxMRule{
+					RuleName:     "NxM Rule",
+					AffectedItem: "VOUCHER",
+					BuyN:         2,
+					PayM:         1}}},
+	}
+	pricer := NewPricer(rulesStrategyFactory, itemsParserMock, NewMemoryBasketStore())
 	pricer.LoadItems("DUMMYPATH")
 
-
 	//ASSERT
-	bId := pricer.CreateBasket()
+	bId, _ := pricer.CreateBasket()
 	pricer.ScanItem("VOUCHER", bId)
 	pricer.ScanItem("VOUCHER", bId)
 	pricer.ScanItem("VOUCHER", bId)
@@ -280,8 +299,8 @@ func TestGetTotalAmountApplyingBundleNxMRuleBundleAndRemainder(t *testing.T) {
 	calc, err := pricer.GetTotalAmount(bId)
 
 	//ASSERT
-	if calc != expectedCalc {
-		t.Errorf("Applying the NxM to VOUCH should've produced %0.2f, got: %0.2f", float64(expectedCalc), float64(calc))
+	if calc.Amount != expectedCalc {
+		t.Errorf("Applying the NxM to VOUCH should've produced %0.2f, got: %0.2f", float64(expectedCalc), float64(calc.Amount))
 	}
 
 	if err != nil {
@@ -295,11 +314,11 @@ func TestGetTotalAmountApplyingDefaultRule(t *testing.T) {
 	//ARRANGE
 	itemsParserMock := new(MockedItemsParser)
 	rulesStrategyFactory := rules.RuleStrategyFactory{RuleExecutors: []rules.RuleStrategyExecutor{rules.DefaultRuleStrategy{}}}
-	pricer := &Pricer{ItemsParser: itemsParserMock, StrategyFactory: rulesStrategyFactory}
+	pricer := NewPricer(rulesStrategyFactory, itemsParserMock, NewMemoryBasketStore())
 	pricer.LoadItems("DUMMYPATH")
 
 	//ASSERT
-	bId := pricer.CreateBasket()
+	bId, _ := pricer.CreateBasket()
 	pricer.ScanItem("MUG", bId)
 	pricer.ScanItem("MUG", bId)
 	pricer.ScanItem("MUG", bId)
@@ -310,8 +329,8 @@ func TestGetTotalAmountApplyingDefaultRule(t *testing.T) {
 	calc, err := pricer.GetTotalAmount(bId)
 
 	//ASSERT
-	if calc != expectedCalc {
-		t.Errorf("Applying the NxM to VOUCH should've produced %0.2f, got: %0.2f", float64(expectedCalc), float64(calc))
+	if calc.Amount != expectedCalc {
+		t.Errorf("Applying the NxM to VOUCH should've produced %0.2f, got: %0.2f", float64(expectedCalc), float64(calc.Amount))
 	}
 
 	if err != nil {
@@ -327,11 +346,11 @@ func TestGetTotalAmountCompleteChallengeExample1(t *testing.T) {
 	rulesStrategyFactory := rules.RuleStrategyFactory{RuleExecutors: []rules.RuleStrategyExecutor{
 		rules.DefaultRuleStrategy{}},
 	}
-	pricer := &Pricer{ItemsParser: itemsParserMock, StrategyFactory: rulesStrategyFactory}
+	pricer := NewPricer(rulesStrategyFactory, itemsParserMock, NewMemoryBasketStore())
 	pricer.LoadItems("DUMMYPATH")
 
 	//ASSERT
-	bId := pricer.CreateBasket()
+	bId, _ := pricer.CreateBasket()
 	pricer.ScanItem("VOUCHER", bId)
 	pricer.ScanItem("TSHIRT", bId)
 	pricer.ScanItem("MUG", bId)
@@ -342,8 +361,8 @@ func TestGetTotalAmountCompleteChallengeExample1(t *testing.T) {
 	calc, err := pricer.GetTotalAmount(bId)
 
 	//ASSERT
-	if calc != expectedCalc {
-		t.Errorf("Mixed items calculation should've produced %0.2f, got: %0.2f", float64(expectedCalc), float64(calc))
+	if calc.Amount != expectedCalc {
+		t.Errorf("Mixed items calculation should've produced %0.2f, got: %0.2f", float64(expectedCalc), float64(calc.Amount))
 	}
 
 	if err != nil {
@@ -357,22 +376,19 @@ func TestGetTotalAmountCompleteChallengeExample2(t *testing.T) {
 	//ARRANGE
 	itemsParserMock := new(MockedItemsParser)
 	rulesStrategyFactory := rules.RuleStrategyFactory{RuleExecutors: []rules.RuleStrategyExecutor{
-		rules.DefaultRuleStrategy{},
 		rules.NxMRuleStrategy{
 			Rule: parser.NxMRule{
-				RuleName: "NxM Rule",
+				RuleName:     "NxM Rule",
 				AffectedItem: "VOUCHER",
-				BuyN: 2,
-				PayM: 1}}},
+				BuyN:         2,
+				PayM:         1}},
+		rules.DefaultRuleStrategy{}},
 	}
-	pricer := &Pricer{ItemsParser: itemsParserMock, StrategyFactory: rulesStrategyFactory}
+	pricer := NewPricer(rulesStrategyFactory, itemsParserMock, NewMemoryBasketStore())
 	pricer.LoadItems("DUMMYPATH")
-	rules.IncludedItems = map[string]bool{
-		"VOUCHER": true,
-	}
 
 	//ASSERT
-	bId := pricer.CreateBasket()
+	bId, _ := pricer.CreateBasket()
 	pricer.ScanItem("VOUCHER", bId)
 	pricer.ScanItem("TSHIRT", bId)
 	pricer.ScanItem("VOUCHER", bId)
@@ -383,8 +399,8 @@ func TestGetTotalAmountCompleteChallengeExample2(t *testing.T) {
 	calc, err := pricer.GetTotalAmount(bId)
 
 	//ASSERT
-	if calc != expectedCalc {
-		t.Errorf("Mixed items calculation should've produced %0.2f, got: %0.2f", float64(expectedCalc), float64(calc))
+	if calc.Amount != expectedCalc {
+		t.Errorf("Mixed items calculation should've produced %0.2f, got: %0.2f", float64(expectedCalc), float64(calc.Amount))
 	}
 
 	if err != nil {
@@ -398,22 +414,19 @@ func TestGetTotalAmountCompleteChallengeExample3(t *testing.T) {
 	//ARRANGE
 	itemsParserMock := new(MockedItemsParser)
 	rulesStrategyFactory := rules.RuleStrategyFactory{RuleExecutors: []rules.RuleStrategyExecutor{
-		rules.DefaultRuleStrategy{},
-		rules.BulkRuleStrategy {
+		rules.BulkRuleStrategy{
 			Rule: parser.BulkRule{
-				RuleName: "Bulk Rule",
-				AffectedItem: "TSHIRT",
-				TriggerAmount: 3,
-				DiscountPercentage: 5}}},
+				RuleName:           "Bulk Rule",
+				AffectedItem:       "TSHIRT",
+				TriggerAmount:      3,
+				DiscountPercentage: 5}},
+		rules.DefaultRuleStrategy{}},
 	}
-	pricer := &Pricer{ItemsParser: itemsParserMock, StrategyFactory: rulesStrategyFactory}
+	pricer := NewPricer(rulesStrategyFactory, itemsParserMock, NewMemoryBasketStore())
 	pricer.LoadItems("DUMMYPATH")
-	rules.IncludedItems = map[string]bool{
-		"TSHIRT": true,
-	}
 
 	//ASSERT
-	bId := pricer.CreateBasket()
+	bId, _ := pricer.CreateBasket()
 	pricer.ScanItem("TSHIRT", bId)
 	pricer.ScanItem("TSHIRT", bId)
 	pricer.ScanItem("TSHIRT", bId)
@@ -426,8 +439,8 @@ func TestGetTotalAmountCompleteChallengeExample3(t *testing.T) {
 	calc, err := pricer.GetTotalAmount(bId)
 
 	//ASSERT
-	if calc != expectedCalc {
-		t.Errorf("Mixed items calculation should've produced %0.2f, got: %0.2f", float64(expectedCalc), float64(calc))
+	if calc.Amount != expectedCalc {
+		t.Errorf("Mixed items calculation should've produced %0.2f, got: %0.2f", float64(expectedCalc), float64(calc.Amount))
 	}
 
 	if err != nil {
@@ -441,29 +454,25 @@ func TestGetTotalAmountCompleteChallengeExample4(t *testing.T) {
 	//ARRANGE
 	itemsParserMock := new(MockedItemsParser)
 	rulesStrategyFactory := rules.RuleStrategyFactory{RuleExecutors: []rules.RuleStrategyExecutor{
-		rules.DefaultRuleStrategy{},
 		rules.NxMRuleStrategy{
 			Rule: parser.NxMRule{
-				RuleName: "NxM Rule",
+				RuleName:     "NxM Rule",
 				AffectedItem: "VOUCHER",
-				BuyN: 2,
-				PayM: 1}},
-		rules.BulkRuleStrategy {
+				BuyN:         2,
+				PayM:         1}},
+		rules.BulkRuleStrategy{
 			Rule: parser.BulkRule{
-				RuleName: "Bulk Rule",
-				AffectedItem: "TSHIRT",
-				TriggerAmount: 3,
-				DiscountPercentage: 5}}},
+				RuleName:           "Bulk Rule",
+				AffectedItem:       "TSHIRT",
+				TriggerAmount:      3,
+				DiscountPercentage: 5}},
+		rules.DefaultRuleStrategy{}},
 	}
-	pricer := &Pricer{ItemsParser: itemsParserMock, StrategyFactory: rulesStrategyFactory}
+	pricer := NewPricer(rulesStrategyFactory, itemsParserMock, NewMemoryBasketStore())
 	pricer.LoadItems("DUMMYPATH")
-	rules.IncludedItems = map[string]bool{
-		"TSHIRT": true,
-		"VOUCHER": true,
-	}
 
 	//ASSERT
-	bId := pricer.CreateBasket()
+	bId, _ := pricer.CreateBasket()
 	pricer.ScanItem("VOUCHER", bId)
 	pricer.ScanItem("TSHIRT", bId)
 	pricer.ScanItem("VOUCHER", bId)
@@ -478,8 +487,8 @@ func TestGetTotalAmountCompleteChallengeExample4(t *testing.T) {
 	calc, err := pricer.GetTotalAmount(bId)
 
 	//ASSERT
-	if calc != expectedCalc {
-		t.Errorf("Mixed items calculation should've produced %0.2f, got: %0.2f", float64(expectedCalc), float64(calc))
+	if calc.Amount != expectedCalc {
+		t.Errorf("Mixed items calculation should've produced %0.2f, got: %0.2f", float64(expectedCalc), float64(calc.Amount))
 	}
 
 	if err != nil {
@@ -487,3 +496,498 @@ func TestGetTotalAmountCompleteChallengeExample4(t *testing.T) {
 	}
 
 }
+
+func TestGetTotalAmountRoundingModes(t *testing.T) {
+
+	//ARRANGE
+	//3 mugs with a 5% discount cost exactly 21.375, which has to be rounded only once, when obtaining the total
+	expected := map[money.RoundingMode]int64{
+		money.HalfUp:   2138,
+		money.HalfEven: 2138,
+		money.Floor:    2137,
+	}
+	rulesStrategyFactory := rules.RuleStrategyFactory{RuleExecutors: []rules.RuleStrategyExecutor{
+		rules.BulkRuleStrategy{
+			Rule: parser.BulkRule{RuleName: "Mug Bulk Rule", AffectedItem: "MUG", TriggerAmount: 3, DiscountPercentage: 5}}}}
+
+	for mode, expectedCalc := range expected {
+		pricer := NewPricer(rulesStrategyFactory, new(MockedItemsParser), NewMemoryBasketStore())
+		pricer.Rounding = mode
+		pricer.LoadItems("DUMMYPATH")
+		bId, _ := pricer.CreateBasket()
+		pricer.ScanItem("MUG", bId)
+		pricer.ScanItem("MUG", bId)
+		pricer.ScanItem("MUG", bId)
+
+		//ACT
+		calc, _ := pricer.GetTotalAmount(bId)
+
+		//ASSERT
+		if calc != money.New(expectedCalc, "EUR") {
+			t.Errorf("Rounding with %s should've produced %d, got: %+v", mode, expectedCalc, calc)
+		}
+	}
+
+}
+
+func TestRemoveItem(t *testing.T) {
+
+	//ARRANGE
+	pricer := NewPricer(rules.RuleStrategyFactory{}, new(MockedItemsParser), NewMemoryBasketStore())
+	pricer.LoadItems("DUMMYPATH")
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("VOUCHER", bId)
+	pricer.ScanItem("VOUCHER", bId)
+
+	//ACT
+	_, err := pricer.RemoveItem("VOUCHER", bId)
+
+	//ASSERT
+	if err != nil {
+		t.Errorf("Item removal failed when it should've worked, err: %+v", err)
+	}
+
+	if v := getItems(pricer, bId)["VOUCHER"]; v != 1 {
+		t.Errorf("A single unit should have been removed from the basket, expected: %d, got: %d", 1, v)
+	}
+
+}
+
+func TestRemoveItemLastUnitRemovesLine(t *testing.T) {
+
+	//ARRANGE
+	pricer := NewPricer(rules.RuleStrategyFactory{}, new(MockedItemsParser), NewMemoryBasketStore())
+	pricer.LoadItems("DUMMYPATH")
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("VOUCHER", bId)
+
+	//ACT
+	pricer.RemoveItem("VOUCHER", bId)
+
+	//ASSERT
+	if _, exs := getItems(pricer, bId)["VOUCHER"]; exs {
+		t.Errorf("The line of an item without units left shouldn't be present in the basket")
+	}
+
+}
+
+func TestRemoveItemNotInBasket(t *testing.T) {
+
+	//ARRANGE
+	pricer := NewPricer(rules.RuleStrategyFactory{}, new(MockedItemsParser), NewMemoryBasketStore())
+	pricer.LoadItems("DUMMYPATH")
+	bId, _ := pricer.CreateBasket()
+
+	//ACT
+	_, err := pricer.RemoveItem("MUG", bId)
+
+	//ASSERT
+	if err == nil {
+		t.Errorf("There should've been an error produced by removing an item that isn't in the basket")
+	}
+
+}
+
+func TestRemoveItemNonExistentBasket(t *testing.T) {
+
+	//ARRANGE
+	pricer := NewPricer(rules.RuleStrategyFactory{}, new(MockedItemsParser), NewMemoryBasketStore())
+	pricer.LoadItems("DUMMYPATH")
+
+	//ACT
+	_, err := pricer.RemoveItem("MUG", "FAKEBASKETID")
+
+	//ASSERT
+	if err == nil {
+		t.Errorf("There should've been an error produced by accessing a non existent basket")
+	}
+
+}
+
+func TestSetItemQuantity(t *testing.T) {
+
+	//ARRANGE
+	pricer := NewPricer(rules.RuleStrategyFactory{}, new(MockedItemsParser), NewMemoryBasketStore())
+	pricer.LoadItems("DUMMYPATH")
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("MUG", bId)
+
+	//ACT
+	_, err := pricer.SetItemQuantity("MUG", bId, 5)
+
+	//ASSERT
+	if err != nil {
+		t.Errorf("Setting the quantity failed when it should've worked, err: %+v", err)
+	}
+
+	if v := getItems(pricer, bId)["MUG"]; v != 5 {
+		t.Errorf("The quantity of the item doesn't match the provided one, expected: %d, got: %d", 5, v)
+	}
+
+}
+
+func TestSetItemQuantityZeroRemovesLine(t *testing.T) {
+
+	//ARRANGE
+	pricer := NewPricer(rules.RuleStrategyFactory{}, new(MockedItemsParser), NewMemoryBasketStore())
+	pricer.LoadItems("DUMMYPATH")
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("MUG", bId)
+
+	//ACT
+	pricer.SetItemQuantity("MUG", bId, 0)
+
+	//ASSERT
+	if _, exs := getItems(pricer, bId)["MUG"]; exs {
+		t.Errorf("Setting the quantity to 0 should remove the line from the basket")
+	}
+
+}
+
+func TestSetItemQuantityNegative(t *testing.T) {
+
+	//ARRANGE
+	pricer := NewPricer(rules.RuleStrategyFactory{}, new(MockedItemsParser), NewMemoryBasketStore())
+	pricer.LoadItems("DUMMYPATH")
+	bId, _ := pricer.CreateBasket()
+
+	//ACT
+	_, err := pricer.SetItemQuantity("MUG", bId, -1)
+
+	//ASSERT
+	if err == nil {
+		t.Errorf("There should've been an error produced by setting a negative quantity")
+	}
+
+}
+
+func TestSetItemQuantityNonExistentItem(t *testing.T) {
+
+	//ARRANGE
+	pricer := NewPricer(rules.RuleStrategyFactory{}, new(MockedItemsParser), NewMemoryBasketStore())
+	pricer.LoadItems("DUMMYPATH")
+	bId, _ := pricer.CreateBasket()
+
+	//ACT
+	_, err := pricer.SetItemQuantity("NONEXISTENT", bId, 2)
+
+	//ASSERT
+	if err == nil {
+		t.Errorf("There should've been an error produced by setting the quantity of a non configured item")
+	}
+
+}
+
+func TestClearBasket(t *testing.T) {
+
+	//ARRANGE
+	pricer := NewPricer(rules.RuleStrategyFactory{}, new(MockedItemsParser), NewMemoryBasketStore())
+	pricer.LoadItems("DUMMYPATH")
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("MUG", bId)
+	pricer.ScanItem("VOUCHER", bId)
+
+	//ACT
+	_, err := pricer.ClearBasket(bId)
+
+	//ASSERT
+	if err != nil {
+		t.Errorf("Clearing the basket failed when it should've worked, err: %+v", err)
+	}
+
+	if l := len(getItems(pricer, bId)); l != 0 {
+		t.Errorf("The basket should be empty after clearing it, got %d lines", l)
+	}
+
+}
+
+func TestClearBasketNonExistentBasket(t *testing.T) {
+
+	//ARRANGE
+	pricer := NewPricer(rules.RuleStrategyFactory{}, nil, NewMemoryBasketStore())
+
+	//ACT
+	_, err := pricer.ClearBasket("FAKEBASKETID")
+
+	//ASSERT
+	if err == nil {
+		t.Errorf("There should've been an error produced by clearing a non existent basket")
+	}
+
+}
+
+func TestGetBasket(t *testing.T) {
+
+	//ARRANGE
+	pricer := NewPricer(rules.RuleStrategyFactory{}, new(MockedItemsParser), NewMemoryBasketStore())
+	pricer.LoadItems("DUMMYPATH")
+	before := time.Now()
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("VOUCHER", bId)
+	pricer.ScanItem("MUG", bId)
+	pricer.ScanItem("MUG", bId)
+
+	expectedLines := []BasketLine{
+		{ItemId: "MUG", Name: "Company Coffee Mug", Quantity: 2, Unit: parser.UnitEach},
+		{ItemId: "VOUCHER", Name: "Company Voucher", Quantity: 1, Unit: parser.UnitEach},
+	}
+
+	//ACT
+	contents, err := pricer.GetBasket(bId)
+
+	//ASSERT
+	if err != nil {
+		t.Errorf("Getting the basket failed when it should've worked, err: %+v", err)
+	}
+
+	if contents.BasketId != bId || contents.CreatedAt.Before(before) {
+		t.Errorf("The basket id and creation time don't match, got: %s created at %s", contents.BasketId, contents.CreatedAt)
+	}
+
+	if len(contents.Lines) != len(expectedLines) {
+		t.Fatalf("The basket should have %d lines, got: %+v", len(expectedLines), contents.Lines)
+	}
+	for i := range expectedLines {
+		if contents.Lines[i] != expectedLines[i] {
+			t.Errorf("Basket line %d doesn't match, expected: %+v, got: %+v", i, expectedLines[i], contents.Lines[i])
+		}
+	}
+
+}
+
+func TestGetBasketNonExistentBasket(t *testing.T) {
+
+	//ARRANGE
+	pricer := NewPricer(rules.RuleStrategyFactory{}, nil, NewMemoryBasketStore())
+
+	//ACT
+	_, err := pricer.GetBasket("FAKEBASKETID")
+
+	//ASSERT
+	if err == nil {
+		t.Errorf("An error should've been produced by getting the contents of a non existent basket")
+	}
+
+}
+
+func TestScanWeightedItem(t *testing.T) {
+
+	//ARRANGE
+	items := parser.ConfiguredItems{
+		"CHEESE": {Name: "Manchego Cheese", Price: money.New(1890, "EUR"), Unit: parser.UnitKilogram,
+			VariableBarcode: parser.VariableBarcode{Code: "2012345", Embeds: parser.EmbedsMeasure}},
+		"MUG": {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), Unit: parser.UnitEach},
+	}
+	r := parser.Rules{BulkRules: []parser.BulkRule{
+		{RuleName: "Cheese by the wheel", AffectedItem: "CHEESE", TriggerAmount: 2, DiscountPercentage: 10, Measured: true},
+	}}
+	pricer := newTestPricer(t, r, items)
+	pricer.Rounding = money.HalfUp
+	bId, _ := pricer.CreateBasket()
+	first, _ := money.ParseDecimal("1.5")
+	second, _ := money.ParseDecimal("0.75")
+
+	//ACT
+	pricer.ScanItem("MUG", bId)
+	_, firstErr := pricer.ScanWeightedItem("CHEESE", bId, first)
+	_, secondErr := pricer.ScanWeightedItem("CHEESE", bId, second)
+	total, _ := pricer.GetTotalAmount(bId)
+
+	//ASSERT
+	if firstErr != nil || secondErr != nil {
+		t.Fatalf("The cheese should have been weighed, got: %v, %v", firstErr, secondErr)
+	}
+
+	//7.50 of the mug and 2.25 kg of cheese at 18.90 - 10% = 38.27
+	if total != money.New(4577, "EUR") {
+		t.Errorf("The weighed quantities should have been added up and discounted, expected: 45.77 EUR, got: %s", total)
+	}
+
+	b, _ := pricer.Baskets.Get(bId)
+	if b.Items["MUG"] != 1 || b.Measures["CHEESE"] != first+second {
+		t.Errorf("The mug should have been counted and the cheese weighed, got: %v %v", b.Items, b.Measures)
+	}
+}
+
+func TestScanWeightedItemNotValid(t *testing.T) {
+	//ARRANGE
+	items := parser.ConfiguredItems{
+		"CHEESE": {Name: "Manchego Cheese", Price: money.New(1890, "EUR"), Unit: parser.UnitKilogram,
+			VariableBarcode: parser.VariableBarcode{Code: "2012345", Embeds: parser.EmbedsMeasure}},
+		"MUG": {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), Unit: parser.UnitEach},
+	}
+	r := parser.Rules{BulkRules: []parser.BulkRule{
+		{RuleName: "Cheese by the wheel", AffectedItem: "CHEESE", TriggerAmount: 2, DiscountPercentage: 10, Measured: true},
+	}}
+	pricer := newTestPricer(t, r, items)
+	pricer.Rounding = money.HalfUp
+	bId, _ := pricer.CreateBasket()
+	quantity, _ := money.ParseDecimal("0.5")
+
+	//ACT
+	_, byUnitsErr := pricer.ScanItem("CHEESE", bId)
+	_, setErr := pricer.SetItemQuantity("CHEESE", bId, 2)
+	_, weighedErr := pricer.ScanWeightedItem("MUG", bId, quantity)
+	_, zeroErr := pricer.ScanWeightedItem("CHEESE", bId, 0)
+
+	//ASSERT
+	if !errors.Is(byUnitsErr, ErrItemMeasured) || !errors.Is(setErr, ErrItemMeasured) {
+		t.Errorf("The cheese should not be counted, got: %v, %v", byUnitsErr, setErr)
+	}
+
+	if !errors.Is(weighedErr, ErrItemNotMeasured) {
+		t.Errorf("The mug should not be weighed, got: %v", weighedErr)
+	}
+
+	if !errors.Is(zeroErr, ErrInvalidQuantity) {
+		t.Errorf("A weighed quantity of 0 should not be valid, got: %v", zeroErr)
+	}
+}
+
+func TestScanVariableBarcode(t *testing.T) {
+	//ARRANGE
+	items := parser.ConfiguredItems{
+		"CHEESE": {Name: "Manchego Cheese", Price: money.New(1890, "EUR"), Unit: parser.UnitKilogram,
+			VariableBarcode: parser.VariableBarcode{Code: "2012345", Embeds: parser.EmbedsMeasure}},
+		"MUG": {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), Unit: parser.UnitEach},
+	}
+	r := parser.Rules{BulkRules: []parser.BulkRule{
+		{RuleName: "Cheese by the wheel", AffectedItem: "CHEESE", TriggerAmount: 2, DiscountPercentage: 10, Measured: true},
+	}}
+	pricer := newTestPricer(t, r, items)
+	pricer.Rounding = money.HalfUp
+	bId, _ := pricer.CreateBasket()
+	expected, _ := money.ParseDecimal("0.35")
+
+	//ACT
+	_, err := pricer.ScanVariableBarcode("2012345003507", bId)
+	_, checkDigitErr := pricer.ScanVariableBarcode("2012345003506", bId)
+	_, unknownErr := pricer.ScanVariableBarcode("2099999003503", bId)
+
+	//ASSERT
+	if err != nil {
+		t.Fatalf("The barcode should have been scanned, got: %v", err)
+	}
+
+	contents, _ := pricer.GetBasket(bId)
+	if len(contents.Lines) != 1 || contents.Lines[0].Measure != expected || contents.Lines[0].Unit != parser.UnitKilogram {
+		t.Errorf("The 350 g of cheese embedded in the barcode should be in the basket, got: %+v", contents.Lines)
+	}
+
+	if !errors.Is(checkDigitErr, ErrInvalidBarcode) {
+		t.Errorf("A barcode with a wrong check digit should not be valid, got: %v", checkDigitErr)
+	}
+
+	if !errors.Is(unknownErr, ErrItemNotConfigured) {
+		t.Errorf("A barcode of an item not configured should not be scanned, got: %v", unknownErr)
+	}
+}
+
+func TestRemoveItemMeasured(t *testing.T) {
+	//ARRANGE
+	items := parser.ConfiguredItems{
+		"CHEESE": {Name: "Manchego Cheese", Price: money.New(1890, "EUR"), Unit: parser.UnitKilogram,
+			VariableBarcode: parser.VariableBarcode{Code: "2012345", Embeds: parser.EmbedsMeasure}},
+		"MUG": {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), Unit: parser.UnitEach},
+	}
+	r := parser.Rules{BulkRules: []parser.BulkRule{
+		{RuleName: "Cheese by the wheel", AffectedItem: "CHEESE", TriggerAmount: 2, DiscountPercentage: 10, Measured: true},
+	}}
+	pricer := newTestPricer(t, r, items)
+	pricer.Rounding = money.HalfUp
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanVariableBarcode("2012345003507", bId)
+
+	//ACT
+	_, err := pricer.RemoveItem("CHEESE", bId)
+
+	//ASSERT
+	if b, _ := pricer.Baskets.Get(bId); err != nil || len(b.Measures) != 0 {
+		t.Errorf("The whole quantity of cheese should have been removed, got: %v %v", b.Measures, err)
+	}
+}
+
+func TestScanItemByBarcode(t *testing.T) {
+	//ARRANGE
+	items := parser.ConfiguredItems{
+		"MUG":     {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), Barcodes: []string{"4006381333931", "96385074"}},
+		"VOUCHER": {Name: "Company Voucher", Price: money.New(500, "EUR")},
+	}
+	pricer := newTestPricer(t, parser.Rules{}, items)
+	bId, _ := pricer.CreateBasket()
+
+	//ACT
+	_, eanErr := pricer.ScanItem("4006381333931", bId)
+	_, ean8Err := pricer.ScanItem("96385074", bId)
+	_, removeErr := pricer.RemoveItem("96385074", bId)
+	_, unknownErr := pricer.ScanItem("8437000000013", bId)
+
+	//ASSERT
+	if eanErr != nil || ean8Err != nil || removeErr != nil {
+		t.Fatalf("The mug should have been scanned and removed by its barcodes, got: %v, %v, %v", eanErr, ean8Err, removeErr)
+	}
+
+	if items := getItems(pricer, bId); len(items) != 1 || items["MUG"] != 1 {
+		t.Errorf("The barcodes should have been resolved to the mug, got: %v", items)
+	}
+
+	var pricerErr *PricerError
+	if !errors.As(unknownErr, &pricerErr) || !errors.Is(unknownErr, ErrItemNotConfigured) || pricerErr.ItemId != "8437000000013" {
+		t.Errorf("An unknown barcode should be reported as an item not configured, got: %v", unknownErr)
+	}
+}
+
+func TestSetItemQuantityByBarcodeTwin(t *testing.T) {
+	//ARRANGE
+	items := parser.ConfiguredItems{
+		"MUG": {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), Barcodes: []string{"0040000000006"}},
+	}
+	pricer := newTestPricer(t, parser.Rules{}, items)
+	bId, _ := pricer.CreateBasket()
+
+	//ACT
+	_, setErr := pricer.SetItemQuantity("0040000000006", bId, 3)
+	_, upcErr := pricer.ScanItem("040000000006", bId)
+
+	//ASSERT
+	if setErr != nil || upcErr != nil {
+		t.Fatalf("The mug should have been found by its EAN-13 and its UPC-A twin, got: %v, %v", setErr, upcErr)
+	}
+
+	if items := getItems(pricer, bId); len(items) != 1 || items["MUG"] != 4 {
+		t.Errorf("The barcodes should have been resolved to the mug, got: %v", items)
+	}
+}
+
+func TestScanItemVariants(t *testing.T) {
+	//ARRANGE
+	items := parser.ConfiguredItems{
+		"TSHIRT":    {Name: "Company T-Shirt", Price: money.New(2000, "EUR"), VariantAxes: []string{"size"}, Variants: []string{"TSHIRT-M", "TSHIRT-XL"}},
+		"TSHIRT-M":  {Name: "Company T-Shirt (M)", Price: money.New(2000, "EUR"), Barcodes: []string{"4006381333931"}, Parent: "TSHIRT"},
+		"TSHIRT-XL": {Name: "Company T-Shirt (XL)", Price: money.New(2200, "EUR"), Parent: "TSHIRT"},
+	}
+	r := parser.Rules{NxmRules: []parser.NxMRule{{RuleName: "T-Shirts 2x1", AffectedItem: "TSHIRT", BuyN: 2, PayM: 1}}}
+	pricer := newTestPricer(t, r, items)
+	bId, _ := pricer.CreateBasket()
+
+	//ACT
+	_, parentErr := pricer.ScanItem("TSHIRT", bId)
+	_, setErr := pricer.SetItemQuantity("TSHIRT", bId, 2)
+	_, barcodeErr := pricer.ScanItem("4006381333931", bId)
+	_, idErr := pricer.ScanItem("TSHIRT-XL", bId)
+	total, _ := pricer.GetTotalAmount(bId)
+
+	//ASSERT
+	if !errors.Is(parentErr, ErrItemHasVariants) || !errors.Is(setErr, ErrItemHasVariants) {
+		t.Errorf("The T-shirt should be sold through its variants, got: %v, %v", parentErr, setErr)
+	}
+
+	if barcodeErr != nil || idErr != nil {
+		t.Fatalf("The variants should have been scanned, got: %v, %v", barcodeErr, idErr)
+	}
+
+	//The M and XL T-shirts count together for the 2x1, the M one is free
+	if total != money.New(2200, "EUR") {
+		t.Errorf("The variants should have been counted together, expected: 22.00 EUR, got: %s", total)
+	}
+}
@@ -0,0 +1,127 @@
+package pricer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+//Reloads the rules and item definitions files of a running Pricer
+//The new configuration replaces the one in use only if both files are valid and consistent with each other, otherwise
+//the Pricer keeps working with the previous one. With the parsers in Strict mode a file with an invalid rule or item is
+//rejected, otherwise it's loaded without it, but the rules and items left must still be consistent with each other
+type ConfigReloader struct {
+	Pricer        *Pricer
+	RuleParser    parser.RuleParser
+	ItemsParser   parser.ItemsParser
+	RulesFilePath string
+	ItemsFilePath string
+
+	reloadLock sync.Mutex
+}
+
+//The state of a watched file, a change in any of them means the file has been modified
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+//Parses both files and replaces the configuration of the Pricer with them
+//Each file is read once, so the version of the configuration is always the one of the contents parsed, even if the
+//files are modified meanwhile. Reloads are serialized, so the configuration in use is always the one parsed last
+func (r *ConfigReloader) Reload() error {
+	r.reloadLock.Lock()
+	defer r.reloadLock.Unlock()
+	log.Infof("Reloading configuration from %s and %s", r.RulesFilePath, r.ItemsFilePath)
+
+	itemsData, err := ioutil.ReadFile(r.ItemsFilePath)
+	if err != nil {
+		return r.rejected(err)
+	}
+	rulesData, err := ioutil.ReadFile(r.RulesFilePath)
+	if err != nil {
+		return r.rejected(err)
+	}
+	items, err := r.ItemsParser.ParseItemsData(r.ItemsFilePath, itemsData)
+	if err != nil {
+		return r.rejected(err)
+	}
+	rules, err := r.RuleParser.ParseRulesData(r.RulesFilePath, rulesData)
+	if err != nil {
+		return r.rejected(err)
+	}
+	config, err := NewPricingConfig(rules, items)
+	if err != nil {
+		return r.rejected(err)
+	}
+	config.Version = ConfigVersion(rulesData, itemsData)
+
+	r.Pricer.SetConfig(config)
+	log.Infof("Configuration %s loaded: %d items and %d pricing rules", config.Version, len(items), rules.Count())
+	return nil
+}
+
+func (r *ConfigReloader) rejected(err error) error {
+	log.Errorf("The configuration has been rejected, any previous one is still in use: %v", err)
+	return fmt.Errorf("the configuration couldn't be loaded: %v", err)
+}
+
+//Checks the files every interval and reloads the configuration when any of them has been modified, until stop is closed
+//The files are polled instead of watched so it works the same way on every platform and with editors that replace the
+//file instead of writing to it. A file that can't be read is ignored until it is back, ie: while it is being replaced
+func (r *ConfigReloader) Watch(interval time.Duration, stop <-chan struct{}) {
+	paths := []string{r.RulesFilePath, r.ItemsFilePath}
+	versions := make(map[string]fileVersion)
+	for _, path := range paths {
+		versions[path], _ = statFile(path)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			changed := false
+			for _, path := range paths {
+				v, err := statFile(path)
+				if err != nil {
+					continue
+				}
+				if v != versions[path] {
+					log.Infof("The configuration file %s has been modified", path)
+					versions[path] = v
+					changed = true
+				}
+			}
+			if changed {
+				r.Reload()
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+//Returns the version of the configuration made of the files with the given contents: the first 12 hex digits of the
+//SHA-256 of their contents, so the same files always give the same version, even after a restart
+func ConfigVersion(contents ...[]byte) string {
+	h := sha256.New()
+	for _, d := range contents {
+		h.Write(d)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+func statFile(path string) (fileVersion, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileVersion{}, err
+	}
+	return fileVersion{modTime: info.ModTime(), size: info.Size()}, nil
+}
@@ -0,0 +1,328 @@
+package pricer
+
+import (
+	"errors"
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"github.com/dagozba/golangsmallshop/internal/rules"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+const reloadTestItems = `
+currency: EUR
+items:
+  MUG:
+    name: Company Coffee Mug
+    price: 7.50
+`
+
+const reloadTestTwoForOne = `
+rules:
+  nxmRules:
+  - affectedItem: MUG
+    ruleName: "2x1"
+    buyN: 2
+    payM: 1
+`
+
+const reloadTestThreeForTwo = `
+rules:
+  nxmRules:
+  - affectedItem: MUG
+    ruleName: "3x2"
+    buyN: 3
+    payM: 2
+`
+
+//Creates a Pricer loaded from config files written in a temporary directory, along with a reloader for those files
+func newReloadablePricer(t *testing.T) (*Pricer, *ConfigReloader) {
+	dir := t.TempDir()
+	itemsPath := filepath.Join(dir, "item_definitions.yaml")
+	rulesPath := filepath.Join(dir, "rules.yaml")
+	ioutil.WriteFile(itemsPath, []byte(reloadTestItems), 0644)
+	ioutil.WriteFile(rulesPath, []byte(reloadTestTwoForOne), 0644)
+
+	factory := &rules.RuleStrategyFactory{RuleParser: parser.RuleParser{}}
+	factory.LoadRules(rulesPath)
+	p := NewPricer(*factory, parser.ItemsParser{}, NewMemoryBasketStore())
+	p.LoadItems(itemsPath)
+	reloader := &ConfigReloader{
+		Pricer:        p,
+		RuleParser:    parser.RuleParser{Strict: true},
+		ItemsParser:   parser.ItemsParser{Strict: true},
+		RulesFilePath: rulesPath,
+		ItemsFilePath: itemsPath,
+	}
+	return p, reloader
+}
+
+func newBasketWithMugs(p *Pricer, mugs int) string {
+	bId, _ := p.CreateBasket()
+	p.SetItemQuantity("MUG", bId, mugs)
+	return bId
+}
+
+func TestReloadReplacesConfiguration(t *testing.T) {
+
+	//ARRANGE
+	p, reloader := newReloadablePricer(t)
+	bId := newBasketWithMugs(p, 3)
+	before, _ := p.GetTotalAmount(bId)
+	ioutil.WriteFile(reloader.RulesFilePath, []byte(reloadTestThreeForTwo), 0644)
+
+	//ACT
+	err := reloader.Reload()
+	after, _ := p.GetTotalAmount(bId)
+
+	//ASSERT
+	if err != nil {
+		t.Errorf("Reloading a valid configuration shouldn't have produced an error, got: %+v", err)
+	}
+	if before != money.New(1500, "EUR") || after != money.New(1500, "EUR") {
+		t.Errorf("3 mugs should cost 15.00 EUR with both the 2x1 and the 3x2, got: %s and %s", before, after)
+	}
+	if total, _ := p.GetTotalAmount(newBasketWithMugs(p, 2)); total != money.New(1500, "EUR") {
+		t.Errorf("The 2x1 shouldn't be applied once the 3x2 has been loaded, expected 15.00 EUR for 2 mugs, got: %s", total)
+	}
+
+}
+
+func TestReloadSetsConfigVersion(t *testing.T) {
+
+	//ARRANGE
+	p, reloader := newReloadablePricer(t)
+	reloader.Reload()
+	first := p.Config().Version
+
+	//ACT
+	reloader.Reload()
+	same := p.Config().Version
+	ioutil.WriteFile(reloader.RulesFilePath, []byte(reloadTestThreeForTwo), 0644)
+	reloader.Reload()
+	changed := p.Config().Version
+
+	//ASSERT
+	if len(first) != 12 || same != first {
+		t.Errorf("The same files should always give the same version, got: %s and %s", first, same)
+	}
+	if expected := ConfigVersion([]byte(reloadTestTwoForOne), []byte(reloadTestItems)); first != expected {
+		t.Errorf("The version should have been taken from the contents parsed, expected: %s, got: %s", expected, first)
+	}
+	if changed == first {
+		t.Errorf("The version should have changed along with the rules, got: %s", changed)
+	}
+
+}
+
+func TestReloadRemovingItemInBasket(t *testing.T) {
+
+	//ARRANGE
+	p, reloader := newReloadablePricer(t)
+	p.Orders = NewMemoryOrderStore()
+	ioutil.WriteFile(reloader.ItemsFilePath, []byte(reloadTestItems+`
+  TSHIRT:
+    name: Company T-Shirt
+    price: 20.00
+`), 0644)
+	reloader.Reload()
+	bId := newBasketWithMugs(p, 1)
+	p.SetItemQuantity("TSHIRT", bId, 2)
+	ioutil.WriteFile(reloader.ItemsFilePath, []byte(reloadTestItems), 0644)
+	reloader.Reload()
+
+	//ACT
+	_, totalErr := p.GetTotalAmount(bId)
+	_, breakdownErr := p.GetBasketBreakdown(bId)
+	_, checkoutErr := p.Checkout(bId)
+	_, setErr := p.SetItemQuantity("TSHIRT", bId, 1)
+	_, removeErr := p.RemoveItem("TSHIRT", bId)
+	_, scanErr := p.ScanItem("TSHIRT", bId)
+	total, err := p.GetTotalAmount(bId)
+
+	//ASSERT
+	if !errors.Is(totalErr, ErrItemNotConfigured) || !errors.Is(breakdownErr, ErrItemNotConfigured) || !errors.Is(checkoutErr, ErrItemNotConfigured) {
+		t.Errorf("A basket with an item removed by the new configuration shouldn't be priced, got: %v, %v, %v", totalErr, breakdownErr, checkoutErr)
+	}
+	if setErr != nil || removeErr != nil {
+		t.Errorf("The removed item should be taken out of the basket, got: %v, %v", setErr, removeErr)
+	}
+	if !errors.Is(scanErr, ErrItemNotConfigured) {
+		t.Errorf("The removed item shouldn't be scanned again, got: %v", scanErr)
+	}
+	if err != nil || total != money.New(750, "EUR") {
+		t.Errorf("The basket should be priced again once the removed item is out of it, got: %s %v", total, err)
+	}
+
+}
+
+func TestLoadItemsKeepsConfigurationOnError(t *testing.T) {
+
+	//ARRANGE
+	p, _ := newReloadablePricer(t)
+	bId := newBasketWithMugs(p, 2)
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"Missing file", ""},
+		{"Broken yaml", "items: [MUG"},
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), "item_definitions.yaml")
+		if test.value != "" {
+			ioutil.WriteFile(path, []byte(test.value), 0644)
+		}
+		p.ItemsParser = parser.ItemsParser{Strict: true}
+
+		//ACT
+		err := p.LoadItems(path)
+
+		//ASSERT
+		if err == nil {
+			t.Errorf("%s: the item definitions should have been rejected", test.name)
+		}
+		if total, _ := p.GetTotalAmount(bId); total != money.New(750, "EUR") {
+			t.Errorf("%s: the previous configuration should still be in use, expected 7.50 EUR, got: %s", test.name, total)
+		}
+	}
+
+}
+
+func TestReloadRejectsInvalidConfiguration(t *testing.T) {
+
+	//ARRANGE
+	p, reloader := newReloadablePricer(t)
+	bId := newBasketWithMugs(p, 2)
+	tests := []struct {
+		name  string
+		path  string
+		value string
+	}{
+		{"Broken yaml", reloader.RulesFilePath, "rules: [nxmRules"},
+		{"Invalid rule", reloader.RulesFilePath, "rules:\n  nxmRules:\n  - affectedItem: MUG\n    buyN: 1\n    payM: 2\n"},
+		{"Rule on a non configured item", reloader.RulesFilePath, "rules:\n  nxmRules:\n  - affectedItem: BOOK\n    buyN: 2\n    payM: 1\n"},
+		{"Invalid item", reloader.ItemsFilePath, "items:\n  MUG:\n    name: Company Coffee Mug\n    price: -1\n"},
+	}
+
+	for _, test := range tests {
+		ioutil.WriteFile(reloader.RulesFilePath, []byte(reloadTestTwoForOne), 0644)
+		ioutil.WriteFile(reloader.ItemsFilePath, []byte(reloadTestItems), 0644)
+		ioutil.WriteFile(test.path, []byte(test.value), 0644)
+
+		//ACT
+		err := reloader.Reload()
+
+		//ASSERT
+		if err == nil {
+			t.Errorf("%s: the configuration should have been rejected", test.name)
+		}
+		if total, _ := p.GetTotalAmount(bId); total != money.New(750, "EUR") {
+			t.Errorf("%s: the previous configuration should still be in use, expected 7.50 EUR, got: %s", test.name, total)
+		}
+	}
+
+}
+
+func TestLenientReloadChecksConsistency(t *testing.T) {
+
+	//ARRANGE
+	p, reloader := newReloadablePricer(t)
+	reloader.RuleParser = parser.RuleParser{}
+	reloader.ItemsParser = parser.ItemsParser{}
+	bId := newBasketWithMugs(p, 2)
+	tests := []struct {
+		name     string
+		value    string
+		accepted bool
+	}{
+		{"Invalid rule discarded", "rules:\n  nxmRules:\n  - affectedItem: MUG\n    ruleName: broken\n    buyN: 1\n    payM: 2\n", true},
+		{"Rule on a non configured item", "rules:\n  nxmRules:\n  - affectedItem: BOOK\n    ruleName: Book 2x1\n    buyN: 2\n    payM: 1\n", false},
+	}
+
+	for _, test := range tests {
+		ioutil.WriteFile(reloader.RulesFilePath, []byte(test.value), 0644)
+
+		//ACT
+		err := reloader.Reload()
+
+		//ASSERT
+		if (err == nil) != test.accepted {
+			t.Errorf("%s: expected the configuration to be accepted: %t, got: %v", test.name, test.accepted, err)
+		}
+		if total, _ := p.GetTotalAmount(bId); total != money.New(1500, "EUR") {
+			t.Errorf("%s: the invalid 2x1 shouldn't be in use, expected 15.00 EUR, got: %s", test.name, total)
+		}
+	}
+
+}
+
+func TestWatchReloadsModifiedFiles(t *testing.T) {
+
+	//ARRANGE
+	p, reloader := newReloadablePricer(t)
+	stop := make(chan struct{})
+	defer close(stop)
+	go reloader.Watch(10*time.Millisecond, stop)
+	time.Sleep(50 * time.Millisecond)
+
+	//ACT
+	ioutil.WriteFile(reloader.RulesFilePath, []byte(reloadTestThreeForTwo), 0644)
+
+	//ASSERT
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if total, _ := p.GetTotalAmount(newBasketWithMugs(p, 2)); total == money.New(1500, "EUR") {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("The modified rules file should have been reloaded")
+
+}
+
+func TestReloadWhileCalculating(t *testing.T) {
+
+	//ARRANGE
+	p, reloader := newReloadablePricer(t)
+	bId := newBasketWithMugs(p, 6)
+	twoForOne, threeForTwo := money.New(2250, "EUR"), money.New(3000, "EUR")
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	//ACT
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if i%2 == 0 {
+				ioutil.WriteFile(reloader.RulesFilePath, []byte(reloadTestThreeForTwo), 0644)
+			} else {
+				ioutil.WriteFile(reloader.RulesFilePath, []byte(reloadTestTwoForOne), 0644)
+			}
+			reloader.Reload()
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				//ASSERT
+				if total, err := p.GetTotalAmount(bId); err != nil || (total != twoForOne && total != threeForTwo) {
+					t.Errorf("The total should be the one of either the old or the new configuration, got: %s (%v)", total, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(stop)
+
+}
@@ -0,0 +1,247 @@
+package pricer
+
+import (
+	"fmt"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	log "github.com/sirupsen/logrus"
+	"sync"
+)
+
+//Tracks the units in stock of the items and the units of them reserved by the baskets
+//The units scanned into a basket are reserved until they are removed from it, the basket is removed or it expires, or
+//they are sold when the basket is checked out. Only the items with a stock level are limited by it, the units of the
+//rest of them are reserved too, so they count as soon as a stock level is set for them
+//The stock levels are kept in memory, they are taken from the item definitions when the server starts and can be set
+//at runtime, see Pricer.SetStockLevel
+//A nil Inventory is valid and doesn't track any stock
+type Inventory struct {
+	levels   map[string]int
+	reserved map[string]map[string]int
+	lock     *sync.Mutex
+}
+
+//The stock of an item: the units OnHand, the ones Reserved by the baskets and the ones Available to be scanned
+//Tracked is false if the item has no stock level, so its units are not limited
+type StockLevel struct {
+	ItemId    string
+	Tracked   bool
+	OnHand    int
+	Reserved  int
+	Available int
+}
+
+//Error returned when there aren't enough units of an item in stock for a basket, with the units still Available
+type StockError struct {
+	Available int
+}
+
+func (e *StockError) Error() string {
+	return fmt.Sprintf("%v, %d units are available", ErrInsufficientStock, e.Available)
+}
+
+func (e *StockError) Unwrap() error {
+	return ErrInsufficientStock
+}
+
+//Creates an inventory reserving the units of the baskets already in the given store, ie: the ones restored from the
+//baskets log of a FileBasketStore. The units of the baskets already checked out have been sold, so they are not reserved
+func NewInventory(baskets BasketStore) (*Inventory, error) {
+	s := &Inventory{levels: make(map[string]int), reserved: make(map[string]map[string]int), lock: new(sync.Mutex)}
+	stored, err := baskets.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range stored {
+		if b.OrderNumber == "" {
+			s.restore(b.Id, b.Items)
+		}
+	}
+	return s, nil
+}
+
+//Sets the stock level of the configured items with stock that are not tracked yet
+//The levels already tracked are kept, as they have changed with the sales since they were configured
+func (s *Inventory) load(items parser.ConfiguredItems) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for id, item := range items {
+		if _, tracked := s.levels[id]; !tracked && item.HasStock() {
+			s.levels[id] = *item.Stock
+			log.Infof("Tracking the stock of item %s, %d units in stock", id, *item.Stock)
+		}
+	}
+}
+
+//Sets the units in stock of the item, the units reserved by the baskets are kept even if there aren't enough of them
+func (s *Inventory) setLevel(item string, units int) StockLevel {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.levels[item] = units
+	return s.levelOf(item)
+}
+
+//Returns the stock of the item
+func (s *Inventory) level(item string) StockLevel {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.levelOf(item)
+}
+
+func (s *Inventory) levelOf(item string) StockLevel {
+	onHand, tracked := s.levels[item]
+	l := StockLevel{ItemId: item, Tracked: tracked, OnHand: onHand}
+	for _, units := range s.reserved[item] {
+		l.Reserved += units
+	}
+	if tracked && onHand > l.Reserved {
+		l.Available = onHand - l.Reserved
+	}
+	return l
+}
+
+//Sets the units of the item reserved by the basket, returns a StockError if the basket needs more units than the
+//available ones. Reserving fewer units than the reserved ones never fails
+func (s *Inventory) reserve(basketId string, item string, units int) error {
+	if s == nil {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if l := s.levelOf(item); l.Tracked && units-s.reserved[item][basketId] > l.Available {
+		return &StockError{Available: l.Available}
+	}
+	s.setReserved(basketId, item, units)
+	return nil
+}
+
+//Sets the units of the given items reserved by the basket without checking the stock, ie: to undo a reservation of a
+//basket change that couldn't be stored
+func (s *Inventory) restore(basketId string, items map[string]int) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for item, units := range items {
+		s.setReserved(basketId, item, units)
+	}
+}
+
+func (s *Inventory) setReserved(basketId string, item string, units int) {
+	if units <= 0 {
+		delete(s.reserved[item], basketId)
+		if len(s.reserved[item]) == 0 {
+			delete(s.reserved, item)
+		}
+		return
+	}
+	if s.reserved[item] == nil {
+		s.reserved[item] = make(map[string]int)
+	}
+	s.reserved[item][basketId] = units
+}
+
+//Releases all the units reserved by the basket
+func (s *Inventory) release(basketId string) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.releaseBasket(basketId)
+}
+
+//Releases all the units reserved by the basket, returning them by item
+func (s *Inventory) releaseBasket(basketId string) map[string]int {
+	released := make(map[string]int)
+	for item, baskets := range s.reserved {
+		if units, exs := baskets[basketId]; exs {
+			released[item] = units
+			s.setReserved(basketId, item, 0)
+		}
+	}
+	return released
+}
+
+//Takes the units reserved by the basket out of the stock once they have been sold, when the basket is checked out, and
+//returns them by item, so the sale can be undone with uncommit
+//A stock level set below the units reserved by the baskets is left at zero
+func (s *Inventory) commit(basketId string) map[string]int {
+	if s == nil {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	sold := s.releaseBasket(basketId)
+	for item, units := range sold {
+		if onHand, tracked := s.levels[item]; tracked {
+			if s.levels[item] = onHand - units; s.levels[item] < 0 {
+				s.levels[item] = 0
+			}
+			log.Infof("%d units of item %s sold, %d units left in stock", units, item, s.levels[item])
+		}
+	}
+	return sold
+}
+
+//Puts back in stock the units sold by a basket whose order couldn't be placed, they are reserved by the basket again
+//if it's still open
+func (s *Inventory) uncommit(basketId string, sold map[string]int, reserve bool) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for item, units := range sold {
+		if onHand, tracked := s.levels[item]; tracked {
+			s.levels[item] = onHand + units
+		}
+		if reserve {
+			s.setReserved(basketId, item, units)
+		}
+	}
+}
+
+//Sets the units in stock of an item, ie: when new units are received or after a stock count, the units reserved by the
+//baskets are kept. The stock of an item without a stock level starts being tracked
+//returns an error if the stock is not tracked, the item has not been defined by configuration, it can't be counted by
+//units or the units are negative. The item can be given by its id or by any of its barcodes
+func (p *Pricer) SetStockLevel(i string, units int) (StockLevel, error) {
+	i = p.resolveItem(i)
+	log.Infof("Setting the stock of item %s to %d units", i, units)
+	if err := p.checkStocked(i); err != nil {
+		return StockLevel{}, err
+	}
+	if units < 0 {
+		return StockLevel{}, newPricerError(ErrInvalidQuantity, "", i)
+	}
+	l := p.Stock.setLevel(i, units)
+	log.Infof("Item %s has %d units in stock, %d of them reserved", i, l.OnHand, l.Reserved)
+	return l, nil
+}
+
+//Returns the stock of an item
+//returns an error if the stock is not tracked, the item has not been defined by configuration or it can't be counted
+//by units. The item can be given by its id or by any of its barcodes
+func (p Pricer) GetStockLevel(i string) (StockLevel, error) {
+	i = p.resolveItem(i)
+	log.Infof("Getting the stock of item %s", i)
+	if err := p.checkStocked(i); err != nil {
+		return StockLevel{}, err
+	}
+	return p.Stock.level(i), nil
+}
+
+//Checks the stock of the given item can be tracked: the stock is tracked and the item is configured and counted by units
+func (p Pricer) checkStocked(i string) error {
+	if p.Stock == nil {
+		return newPricerError(ErrStockNotTracked, "", i)
+	}
+	if _, exs := p.Config().Items[i]; !exs {
+		return newPricerError(ErrItemNotConfigured, "", i)
+	}
+	return newPricerError(p.checkCounted(i), "", i)
+}
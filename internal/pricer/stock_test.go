@@ -0,0 +1,227 @@
+package pricer
+
+import (
+	"errors"
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"github.com/dagozba/golangsmallshop/internal/rules"
+	"testing"
+	"time"
+)
+
+func TestScanItemReservesStock(t *testing.T) {
+	//ARRANGE
+	stock := 3
+	items := parser.ConfiguredItems{
+		"MUG":     {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), Barcodes: []string{"96385074"}, Stock: &stock},
+		"VOUCHER": {Name: "Company Voucher", Price: money.New(500, "EUR")},
+	}
+	pricer := newTestPricer(t, parser.Rules{}, items)
+	pricer.Stock, _ = NewInventory(pricer.Baskets)
+	pricer.SetConfig(pricer.Config())
+	first, _ := pricer.CreateBasket()
+	second, _ := pricer.CreateBasket()
+
+	//ACT
+	pricer.ScanItem("MUG", first)
+	pricer.ScanItem("96385074", first)
+	_, lastErr := pricer.ScanItem("MUG", second)
+	_, soldOutErr := pricer.ScanItem("MUG", second)
+	_, voucherErr := pricer.SetItemQuantity("VOUCHER", second, 100)
+
+	//ASSERT
+	if lastErr != nil || voucherErr != nil {
+		t.Fatalf("The last mug and the vouchers should have been scanned, got: %v, %v", lastErr, voucherErr)
+	}
+
+	var stockErr *StockError
+	if !errors.Is(soldOutErr, ErrInsufficientStock) || !errors.As(soldOutErr, &stockErr) || stockErr.Available != 0 {
+		t.Errorf("No mugs should be left for the second basket, got: %v", soldOutErr)
+	}
+
+	if items := getItems(pricer, second); items["MUG"] != 1 {
+		t.Errorf("The mug out of stock should not have been added to the basket, got: %v", items)
+	}
+
+	if l, _ := pricer.GetStockLevel("MUG"); l != (StockLevel{ItemId: "MUG", Tracked: true, OnHand: 3, Reserved: 3}) {
+		t.Errorf("Every mug should have been reserved, got: %+v", l)
+	}
+}
+
+func TestSetItemQuantityInsufficientStock(t *testing.T) {
+	//ARRANGE
+	stock := 3
+	items := parser.ConfiguredItems{
+		"MUG":     {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), Barcodes: []string{"96385074"}, Stock: &stock},
+		"VOUCHER": {Name: "Company Voucher", Price: money.New(500, "EUR")},
+	}
+	pricer := newTestPricer(t, parser.Rules{}, items)
+	pricer.Stock, _ = NewInventory(pricer.Baskets)
+	pricer.SetConfig(pricer.Config())
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("MUG", bId)
+
+	//ACT
+	_, err := pricer.SetItemQuantity("MUG", bId, 4)
+
+	//ASSERT
+	var stockErr *StockError
+	if !errors.As(err, &stockErr) || stockErr.Available != 2 || err.Error() != "there isn't enough stock of the specified item, 2 units are available" {
+		t.Errorf("Only 2 more mugs should be available, got: %v", err)
+	}
+
+	if l, _ := pricer.GetStockLevel("MUG"); l.Reserved != 1 {
+		t.Errorf("The reservation of the basket should have been kept, got: %+v", l)
+	}
+}
+
+func TestStockReleased(t *testing.T) {
+	//ARRANGE
+	stock := 3
+	items := parser.ConfiguredItems{
+		"MUG":     {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), Barcodes: []string{"96385074"}, Stock: &stock},
+		"VOUCHER": {Name: "Company Voucher", Price: money.New(500, "EUR")},
+	}
+	pricer := newTestPricer(t, parser.Rules{}, items)
+	pricer.Stock, _ = NewInventory(pricer.Baskets)
+	pricer.SetConfig(pricer.Config())
+	removed, _ := pricer.CreateBasket()
+	cleared, _ := pricer.CreateBasket()
+	deleted, _ := pricer.CreateBasket()
+	for _, bId := range []string{removed, cleared, deleted} {
+		pricer.ScanItem("MUG", bId)
+	}
+
+	//ACT & ASSERT
+	pricer.RemoveItem("MUG", removed)
+	if l, _ := pricer.GetStockLevel("MUG"); l.Reserved != 2 || l.Available != 1 {
+		t.Errorf("Removing the mug should have released it, got: %+v", l)
+	}
+
+	pricer.ClearBasket(cleared)
+	if l, _ := pricer.GetStockLevel("MUG"); l.Reserved != 1 || l.Available != 2 {
+		t.Errorf("Clearing the basket should have released its mug, got: %+v", l)
+	}
+
+	pricer.RemoveBasket(deleted)
+	if l, _ := pricer.GetStockLevel("MUG"); l.Reserved != 0 || l.Available != 3 {
+		t.Errorf("Removing the basket should have released its mug, got: %+v", l)
+	}
+}
+
+func TestStockReleasedOnExpiry(t *testing.T) {
+	//ARRANGE
+	stock := 3
+	items := parser.ConfiguredItems{
+		"MUG":     {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), Barcodes: []string{"96385074"}, Stock: &stock},
+		"VOUCHER": {Name: "Company Voucher", Price: money.New(500, "EUR")},
+	}
+	pricer := newTestPricer(t, parser.Rules{}, items)
+	pricer.Stock, _ = NewInventory(pricer.Baskets)
+	pricer.SetConfig(pricer.Config())
+	pricer.Janitor = NewBasketJanitor(pricer.Baskets, ExpiryPolicy{IdleTTL: 30 * time.Minute})
+	pricer.Janitor.Stock = pricer.Stock
+	bId, _ := pricer.CreateBasket()
+	pricer.SetItemQuantity("MUG", bId, 3)
+
+	//ACT
+	evicted := pricer.Janitor.Sweep(time.Now().Add(time.Hour))
+
+	//ASSERT
+	if l, _ := pricer.GetStockLevel("MUG"); evicted != 1 || l.Reserved != 0 || l.Available != 3 {
+		t.Errorf("The mugs of the expired basket should have been released, got: %+v", l)
+	}
+}
+
+func TestInventoryCommit(t *testing.T) {
+	//ARRANGE
+	stock := 3
+	items := parser.ConfiguredItems{
+		"MUG":     {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), Barcodes: []string{"96385074"}, Stock: &stock},
+		"VOUCHER": {Name: "Company Voucher", Price: money.New(500, "EUR")},
+	}
+	pricer := newTestPricer(t, parser.Rules{}, items)
+	pricer.Stock, _ = NewInventory(pricer.Baskets)
+	pricer.SetConfig(pricer.Config())
+	sold, _ := pricer.CreateBasket()
+	open, _ := pricer.CreateBasket()
+	pricer.SetItemQuantity("MUG", sold, 2)
+	pricer.ScanItem("VOUCHER", sold)
+	pricer.ScanItem("MUG", open)
+
+	//ACT
+	pricer.Stock.commit(sold)
+
+	//ASSERT
+	if l, _ := pricer.GetStockLevel("MUG"); l != (StockLevel{ItemId: "MUG", Tracked: true, OnHand: 1, Reserved: 1}) {
+		t.Errorf("The mugs sold should have been taken out of the stock, got: %+v", l)
+	}
+
+	if l, _ := pricer.GetStockLevel("VOUCHER"); l.Tracked || l.Reserved != 0 {
+		t.Errorf("The voucher sold should have been released, got: %+v", l)
+	}
+}
+
+func TestSetStockLevel(t *testing.T) {
+	//ARRANGE
+	stock := 3
+	items := parser.ConfiguredItems{
+		"MUG":     {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), Barcodes: []string{"96385074"}, Stock: &stock},
+		"VOUCHER": {Name: "Company Voucher", Price: money.New(500, "EUR")},
+	}
+	pricer := newTestPricer(t, parser.Rules{}, items)
+	pricer.Stock, _ = NewInventory(pricer.Baskets)
+	pricer.SetConfig(pricer.Config())
+	bId, _ := pricer.CreateBasket()
+	pricer.SetItemQuantity("VOUCHER", bId, 2)
+
+	//ACT
+	level, err := pricer.SetStockLevel("VOUCHER", 5)
+	_, negativeErr := pricer.SetStockLevel("VOUCHER", -1)
+	_, unknownErr := pricer.SetStockLevel("CAP", 5)
+	_, notTrackedErr := NewPricer(rules.RuleStrategyFactory{}, nil, NewMemoryBasketStore()).GetStockLevel("MUG")
+
+	//ASSERT
+	if err != nil || level != (StockLevel{ItemId: "VOUCHER", Tracked: true, OnHand: 5, Reserved: 2, Available: 3}) {
+		t.Errorf("The vouchers already scanned should count as reserved, got: %+v, %v", level, err)
+	}
+
+	if !errors.Is(negativeErr, ErrInvalidQuantity) || !errors.Is(unknownErr, ErrItemNotConfigured) || !errors.Is(notTrackedErr, ErrStockNotTracked) {
+		t.Errorf("The stock level should not have been set, got: %v, %v, %v", negativeErr, unknownErr, notTrackedErr)
+	}
+}
+
+func TestStockLevelKeptOnReload(t *testing.T) {
+	//ARRANGE
+	stock := 3
+	items := parser.ConfiguredItems{
+		"MUG":     {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), Barcodes: []string{"96385074"}, Stock: &stock},
+		"VOUCHER": {Name: "Company Voucher", Price: money.New(500, "EUR")},
+	}
+	pricer := newTestPricer(t, parser.Rules{}, items)
+	pricer.Stock, _ = NewInventory(pricer.Baskets)
+	pricer.SetConfig(pricer.Config())
+	pricer.SetStockLevel("MUG", 10)
+
+	//ACT
+	pricer.SetConfig(pricer.Config())
+
+	//ASSERT
+	if l, _ := pricer.GetStockLevel("MUG"); l.OnHand != 10 {
+		t.Errorf("The stock level should not have been reset by the configuration, got: %+v", l)
+	}
+}
+
+func TestNewInventoryReservesStoredBaskets(t *testing.T) {
+	//ARRANGE
+	store := NewMemoryBasketStore()
+	store.Create(Basket{Id: "B", CreatedAt: time.Now(), Items: map[string]int{"MUG": 2}})
+
+	//ACT
+	inventory, err := NewInventory(store)
+
+	//ASSERT
+	if l := inventory.level("MUG"); err != nil || l.Reserved != 2 {
+		t.Errorf("The mugs of the stored basket should have been reserved, got: %+v, %v", l, err)
+	}
+}
@@ -0,0 +1,90 @@
+package pricer
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"github.com/dagozba/golangsmallshop/internal/rules"
+	"sort"
+)
+
+//The tax of a basket for a tax class
+//Net is the amount without tax, Tax the tax charged on it at the Rate of the class and Gross the amount paid, Net and
+//Tax always add up to Gross
+type TaxLine struct {
+	TaxClass string
+	Rate     money.Decimal
+	Net      money.Money
+	Tax      money.Money
+	Gross    money.Money
+}
+
+//The tax of a whole basket, by tax class sorted by class, and the sum of every class
+type TaxSummary struct {
+	Lines []TaxLine
+	Net   money.Money
+	Tax   money.Money
+	Gross money.Money
+}
+
+//Calculates the tax of a basket from the result of its rules
+//The tax is calculated on the amount paid for every item after every promotion, the basket promotions included, as
+//their discount is allocated to the items they discounted, see rules.RuleResult.ItemSubtotals
+//The amounts of every class are rounded once: the total of the basket is rounded first and split between the classes,
+//the part lost to the rounding of every class going to the class with the biggest amount, then the tax of every class
+//is rounded. When the prices include the tax the total is the gross amount the tax is taken out of, otherwise it's
+//the net amount the tax is added to
+func taxSummary(result rules.RuleResult, items parser.ConfiguredItems, rounding money.RoundingMode) TaxSummary {
+	currency := items.Currency()
+	amounts := make(map[string]money.Subtotal)
+	rates := make(map[string]money.Decimal)
+	for item, subtotal := range result.ItemSubtotals {
+		class := items[item].TaxClass
+		amounts[class] = amounts[class].Add(subtotal)
+		rates[class] = items[item].TaxRate
+	}
+	classes := make([]string, 0, len(amounts))
+	for class := range amounts {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	total := money.Zero(currency).Add(result.Subtotal).Round(rounding)
+	allocated := make(map[string]int64, len(classes))
+	remainder := total.Amount
+	biggest := ""
+	for _, class := range classes {
+		allocated[class] = amounts[class].Round(rounding).Amount
+		remainder -= allocated[class]
+		if biggest == "" || amounts[class].Micros > amounts[biggest].Micros {
+			biggest = class
+		}
+	}
+	if biggest != "" {
+		allocated[biggest] += remainder
+	}
+
+	included := items.TaxIncluded()
+	summary := TaxSummary{Net: money.New(0, currency), Tax: money.New(0, currency), Gross: money.New(0, currency)}
+	if len(classes) == 0 {
+		summary.Net, summary.Gross = total, total
+	}
+	for _, class := range classes {
+		rate := rates[class]
+		amount := money.New(allocated[class], currency)
+		line := TaxLine{TaxClass: class, Rate: rate}
+		if included {
+			line.Gross = amount
+			line.Tax = amount.Subtotal().Ratio(rate, rate+money.DecimalFromInt(100)).Round(rounding)
+			line.Net = money.New(amount.Amount-line.Tax.Amount, currency)
+		} else {
+			line.Net = amount
+			line.Tax = amount.Subtotal().Ratio(rate, money.DecimalFromInt(100)).Round(rounding)
+			line.Gross = money.New(amount.Amount+line.Tax.Amount, currency)
+		}
+		summary.Lines = append(summary.Lines, line)
+		summary.Net.Amount += line.Net.Amount
+		summary.Tax.Amount += line.Tax.Amount
+		summary.Gross.Amount += line.Gross.Amount
+	}
+	return summary
+}
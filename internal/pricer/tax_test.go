@@ -0,0 +1,99 @@
+package pricer
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"github.com/dagozba/golangsmallshop/internal/rules"
+	"reflect"
+	"testing"
+)
+
+func TestGetBasketBreakdownTaxIncluded(t *testing.T) {
+
+	//ARRANGE
+	items := parser.ConfiguredItems{
+		"TSHIRT":  {Name: "Company T-Shirt", Price: money.New(2000, "EUR"), TaxClass: parser.StandardTax, TaxRate: money.DecimalFromInt(21), TaxIncluded: true},
+		"MUG":     {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), TaxClass: parser.ReducedTax, TaxRate: money.DecimalFromInt(10), TaxIncluded: true},
+		"VOUCHER": {Name: "Company Voucher", Price: money.New(500, "EUR"), TaxClass: parser.ZeroTax, TaxIncluded: true},
+	}
+	r := parser.Rules{BasketRules: []parser.BasketRule{{RuleName: "10% off", DiscountPercentage: 10}}}
+	pricer := newTestPricer(t, r, items)
+	pricer.Rounding = money.HalfUp
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("TSHIRT", bId)
+	pricer.ScanItem("MUG", bId)
+	pricer.ScanItem("VOUCHER", bId)
+
+	//The 3.25 off the basket is taken 2.00 off the T-shirt, 0.75 off the mug and 0.50 off the voucher
+	expected := []TaxLine{
+		{TaxClass: "reduced", Rate: money.DecimalFromInt(10), Net: money.New(614, "EUR"), Tax: money.New(61, "EUR"), Gross: money.New(675, "EUR")},
+		{TaxClass: "standard", Rate: money.DecimalFromInt(21), Net: money.New(1488, "EUR"), Tax: money.New(312, "EUR"), Gross: money.New(1800, "EUR")},
+		{TaxClass: "zero", Net: money.New(450, "EUR"), Tax: money.New(0, "EUR"), Gross: money.New(450, "EUR")},
+	}
+
+	//ACT
+	breakdown, err := pricer.GetBasketBreakdown(bId)
+	total, _ := pricer.GetTotalAmount(bId)
+
+	//ASSERT
+	if err != nil || !reflect.DeepEqual(breakdown.Taxes, expected) {
+		t.Errorf("The tax should have been calculated on the discounted lines, expected: %+v, got: %+v (%v)", expected, breakdown.Taxes, err)
+	}
+
+	if breakdown.Net != money.New(2552, "EUR") || breakdown.Tax != money.New(373, "EUR") || breakdown.Total != money.New(2925, "EUR") || total != breakdown.Total {
+		t.Errorf("The tax should have been taken out of the total, expected: 25.52 + 3.73 = 29.25, got: %s + %s = %s (%s)", breakdown.Net, breakdown.Tax, breakdown.Total, total)
+	}
+
+}
+
+func TestGetBasketBreakdownTaxExcluded(t *testing.T) {
+
+	//ARRANGE
+	items := parser.ConfiguredItems{
+		"TSHIRT":  {Name: "Company T-Shirt", Price: money.New(2000, "EUR"), TaxClass: parser.StandardTax, TaxRate: money.DecimalFromInt(21), TaxIncluded: false},
+		"MUG":     {Name: "Company Coffee Mug", Price: money.New(750, "EUR"), TaxClass: parser.ReducedTax, TaxRate: money.DecimalFromInt(10), TaxIncluded: false},
+		"VOUCHER": {Name: "Company Voucher", Price: money.New(500, "EUR"), TaxClass: parser.ZeroTax, TaxIncluded: false},
+	}
+	r := parser.Rules{BasketRules: []parser.BasketRule{{RuleName: "10% off", DiscountPercentage: 10}}}
+	pricer := newTestPricer(t, r, items)
+	pricer.Rounding = money.HalfUp
+	bId, _ := pricer.CreateBasket()
+	pricer.ScanItem("TSHIRT", bId)
+	pricer.ScanItem("MUG", bId)
+	pricer.ScanItem("VOUCHER", bId)
+
+	//ACT
+	breakdown, _ := pricer.GetBasketBreakdown(bId)
+	total, _ := pricer.GetTotalAmount(bId)
+
+	//ASSERT
+	if breakdown.Net != money.New(2925, "EUR") || breakdown.Tax != money.New(446, "EUR") || total != money.New(3371, "EUR") || breakdown.Total != total {
+		t.Errorf("The tax should have been added to the total, expected: 29.25 + 4.46 = 33.71, got: %s + %s = %s (%s)", breakdown.Net, breakdown.Tax, breakdown.Total, total)
+	}
+
+	if len(breakdown.AppliedRules) != 1 || breakdown.AppliedRules[0].Discount != money.New(325, "EUR") {
+		t.Errorf("The discount should have been taken off the prices without tax, got: %+v", breakdown.AppliedRules)
+	}
+
+}
+
+func TestTaxSummaryRoundsOnce(t *testing.T) {
+
+	//ARRANGE
+	items := parser.ConfiguredItems{
+		"TSHIRT": {Price: money.New(2000, "EUR"), TaxClass: parser.StandardTax, TaxRate: money.DecimalFromInt(21), TaxIncluded: true},
+		"MUG":    {Price: money.New(750, "EUR"), TaxClass: parser.ReducedTax, TaxRate: money.DecimalFromInt(10), TaxIncluded: true},
+	}
+	//Half a cent charged for each item
+	half := money.Subtotal{Micros: 500000, Currency: "EUR"}
+	result := rules.RuleResult{Subtotal: half.Add(half), ItemSubtotals: map[string]money.Subtotal{"TSHIRT": half, "MUG": half}}
+
+	//ACT
+	summary := taxSummary(result, items, money.HalfUp)
+
+	//ASSERT
+	if summary.Gross != money.New(1, "EUR") || summary.Lines[0].Gross.Amount+summary.Lines[1].Gross.Amount != 1 {
+		t.Errorf("The classes should add up to the total rounded once, expected: 0.01, got: %+v", summary)
+	}
+
+}
@@ -0,0 +1,50 @@
+package rules
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/parser"
+)
+
+//Returns the executors of the item rules active for a basket with the given coupons applied: the rules that are not
+//linked to any coupon and the ones linked to any of the applied coupons, in the same order
+func ActiveExecutors(executors []RuleStrategyExecutor, coupons map[string]parser.Coupon, applied []string) []RuleStrategyExecutor {
+	inactive := inactiveRules(coupons, applied)
+	if len(inactive) == 0 {
+		return executors
+	}
+	var active []RuleStrategyExecutor
+	for _, e := range executors {
+		if !inactive[nameOf(e)] {
+			active = append(active, e)
+		}
+	}
+	return active
+}
+
+//Returns the executors of the basket rules active for a basket with the given coupons applied, see ActiveExecutors
+func ActiveBasketExecutors(executors []BasketRuleStrategyExecutor, coupons map[string]parser.Coupon, applied []string) []BasketRuleStrategyExecutor {
+	inactive := inactiveRules(coupons, applied)
+	if len(inactive) == 0 {
+		return executors
+	}
+	var active []BasketRuleStrategyExecutor
+	for _, e := range executors {
+		if !inactive[nameOf(e)] {
+			active = append(active, e)
+		}
+	}
+	return active
+}
+
+//Returns the names of the rules linked to coupons, none of which has been applied
+func inactiveRules(coupons map[string]parser.Coupon, applied []string) map[string]bool {
+	inactive := make(map[string]bool)
+	for _, c := range coupons {
+		inactive[c.RuleName] = true
+	}
+	for _, code := range applied {
+		if c, exs := coupons[code]; exs {
+			delete(inactive, c.RuleName)
+		}
+	}
+	return inactive
+}
@@ -0,0 +1,44 @@
+package rules
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"testing"
+)
+
+func TestActiveExecutors(t *testing.T) {
+	//ARRANGE
+	executors := BuildRuleExecutors(parser.Rules{
+		BulkRules: []parser.BulkRule{{RuleName: "Bulk Rule", AffectedItem: "TSHIRT", TriggerAmount: 3, DiscountPercentage: 5}},
+		NxmRules:  []parser.NxMRule{{RuleName: "2x1", AffectedItem: "VOUCHER", BuyN: 2, PayM: 1}},
+	})
+	coupons := map[string]parser.Coupon{"VOUCHERS": {Code: "VOUCHERS", RuleName: "2x1"}}
+
+	//ACT
+	withoutCoupon := ActiveExecutors(executors, coupons, nil)
+	withCoupon := ActiveExecutors(executors, coupons, []string{"VOUCHERS"})
+
+	//ASSERT
+	if len(withoutCoupon) != 2 || nameOf(withoutCoupon[0]) != "Bulk Rule" {
+		t.Errorf("The rule linked to the coupon should not be active without it, got: %v", withoutCoupon)
+	}
+
+	if len(withCoupon) != len(executors) {
+		t.Errorf("Every rule should be active with the coupon, got: %v", withCoupon)
+	}
+
+}
+
+func TestActiveBasketExecutors(t *testing.T) {
+	//ARRANGE
+	executors := BuildBasketExecutors(parser.Rules{BasketRules: []parser.BasketRule{{RuleName: "Spring", DiscountPercentage: 10}}})
+	coupons := map[string]parser.Coupon{"SPRING10": {Code: "SPRING10", RuleName: "Spring"}}
+
+	//ACT
+	active := ActiveBasketExecutors(executors, coupons, []string{"OTHER"})
+
+	//ASSERT
+	if len(active) != 0 {
+		t.Errorf("The basket rule linked to the coupon should not be active without it, got: %v", active)
+	}
+
+}
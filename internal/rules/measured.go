@@ -0,0 +1,93 @@
+package rules
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+)
+
+//Interface of the rules that price the items sold by weight or measure, which are never seen by the rest of the rules
+//The measured items are the quantity of every item scanned, in units of the item, see ExecuteMeasuredRules
+type MeasuredRuleStrategyExecutor interface {
+	ExecuteMeasuredRule(conf parser.ConfiguredItems, measuredItems map[string]money.Decimal) RuleResult
+}
+
+//Prices the items sold by weight or measure, every item is priced by the first measured rule in the given order giving
+//it a discount, or at its configured price otherwise. The executors that are not measured rules are ignored
+//The result has no priced units, the measured items are not counted
+func ExecuteMeasuredRules(executors []RuleStrategyExecutor, conf parser.ConfiguredItems, measuredItems map[string]money.Decimal) RuleResult {
+	total := RuleResult{Subtotal: money.Zero(conf.Currency())}
+	priced := make(map[string]bool, len(measuredItems))
+	for _, executor := range executors {
+		e, ok := executor.(MeasuredRuleStrategyExecutor)
+		if !ok {
+			continue
+		}
+		result := e.ExecuteMeasuredRule(conf, measuredItems)
+		if len(result.Adjustments) == 0 || priced[result.Adjustments[0].AffectedItem] {
+			continue
+		}
+		priced[result.Adjustments[0].AffectedItem] = true
+		total = total.Merge(result)
+	}
+	for item, quantity := range measuredItems {
+		if !priced[item] {
+			subtotal := conf[item].Price.Subtotal().TimesDecimal(quantity)
+			total = total.Merge(RuleResult{Subtotal: subtotal, ItemSubtotals: map[string]money.Subtotal{item: subtotal}})
+		}
+	}
+	return total
+}
+
+//Executes a measured BulkRule calculation, the counterpart of ExecuteRule for the items sold by weight or measure
+//The thresholds of the tiers are quantities of the unit of the item, ie: 2 is 2 kg for an item sold by kg
+//whole quantity: once the quantity reaches the threshold of a tier all of it is priced at the highest tier reached
+//graduated: the quantity up to the first threshold is priced at the configured price and every band between two
+//thresholds at its own tier, ie: with tiers at 1 and 5 kg, 6 kg are 1 kg at the configured price, 4 kg at the first
+//tier and 1 kg at the second one
+//Returns an empty result if the rule is not measured or gives no discount
+func (s BulkRuleStrategy) ExecuteMeasuredRule(conf parser.ConfiguredItems, measuredItems map[string]money.Decimal) RuleResult {
+	q, exs := measuredItems[s.Rule.AffectedItem]
+	if !s.Rule.Measured || !exs {
+		return RuleResult{}
+	}
+	price := conf[s.Rule.AffectedItem].Price
+	gross := price.Subtotal().TimesDecimal(q)
+	subtotal := gross
+	if s.Rule.Mode == parser.BulkGraduated {
+		subtotal = s.graduatedMeasurePrice(price, q)
+	} else {
+		for _, t := range s.Rule.PriceTiers() {
+			if q >= money.DecimalFromInt(int64(t.Threshold)) {
+				subtotal = t.PriceOfMeasure(price, q)
+			}
+		}
+	}
+	discount := gross.Sub(subtotal)
+	if discount.Micros <= 0 {
+		return RuleResult{}
+	}
+	return RuleResult{Subtotal: subtotal, ItemSubtotals: map[string]money.Subtotal{s.Rule.AffectedItem: subtotal},
+		Adjustments: []Adjustment{{RuleName: s.Rule.RuleName, AffectedItem: s.Rule.AffectedItem, Measure: q, Discount: discount}}}
+}
+
+//Prices every band of the quantity at its own tier, the quantity up to the first threshold at the configured price
+func (s BulkRuleStrategy) graduatedMeasurePrice(price money.Money, quantity money.Decimal) money.Subtotal {
+	tiers := s.Rule.PriceTiers()
+	if len(tiers) == 0 || quantity <= money.DecimalFromInt(int64(tiers[0].Threshold)) {
+		return price.Subtotal().TimesDecimal(quantity)
+	}
+	start := money.DecimalFromInt(int64(tiers[0].Threshold))
+	subtotal := price.Subtotal().TimesDecimal(start)
+	for i, t := range tiers {
+		end := quantity
+		if i+1 < len(tiers) && money.DecimalFromInt(int64(tiers[i+1].Threshold)) < end {
+			end = money.DecimalFromInt(int64(tiers[i+1].Threshold))
+		}
+		if end <= start {
+			break
+		}
+		subtotal = subtotal.Add(t.PriceOfMeasure(price, end-start))
+		start = end
+	}
+	return subtotal
+}
@@ -0,0 +1,85 @@
+package rules
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"testing"
+)
+
+func getMeasuredItems() parser.ConfiguredItems {
+	items := getConfiguredItems()
+	items["CHEESE"] = parser.ItemDefinition{Name: "Manchego Cheese", Price: money.New(1890, "EUR"), Unit: parser.UnitKilogram}
+	return items
+}
+
+func kilograms(s string) money.Decimal {
+	d, _ := money.ParseDecimal(s)
+	return d
+}
+
+func TestExecuteMeasuredRulesWholeQuantity(t *testing.T) {
+	//ARRANGE
+	executors := BuildRuleExecutors(parser.Rules{BulkRules: []parser.BulkRule{
+		{RuleName: "Cheese by the wheel", AffectedItem: "CHEESE", TriggerAmount: 2, DiscountPercentage: 10, Measured: true},
+	}})
+
+	//ACT
+	reached := ExecuteMeasuredRules(executors, getMeasuredItems(), map[string]money.Decimal{"CHEESE": kilograms("2.5")})
+	notReached := ExecuteMeasuredRules(executors, getMeasuredItems(), map[string]money.Decimal{"CHEESE": kilograms("1.5")})
+
+	//ASSERT
+	if reached.Subtotal.Micros != 4252500000 || len(reached.Adjustments) != 1 || reached.Adjustments[0].Measure != kilograms("2.5") ||
+		reached.Adjustments[0].Discount.Micros != 472500000 {
+		t.Errorf("The 2.5 kg should be 10%% off, expected 42.525 EUR, got: %+v", reached)
+	}
+
+	if notReached.Subtotal.Micros != 2835000000 || len(notReached.Adjustments) != 0 {
+		t.Errorf("The 1.5 kg should be priced at the configured price, expected 28.35 EUR, got: %+v", notReached)
+	}
+
+	if len(reached.PricedUnits) != 0 || reached.ItemSubtotals["CHEESE"].Micros != 4252500000 {
+		t.Errorf("The measured item should be charged without units, got: %+v", reached)
+	}
+}
+
+func TestExecuteMeasuredRulesGraduated(t *testing.T) {
+	//ARRANGE
+	fifteen, _ := money.ParseDecimal("15.00")
+	executors := BuildRuleExecutors(parser.Rules{BulkRules: []parser.BulkRule{
+		{RuleName: "Cheese tiers", AffectedItem: "CHEESE", Mode: parser.BulkGraduated, Measured: true, Tiers: []parser.BulkTier{
+			{Threshold: 1, DiscountPercentage: 10},
+			{Threshold: 5, UnitPrice: fifteen},
+		}},
+	}})
+
+	//ACT
+	result := ExecuteMeasuredRules(executors, getMeasuredItems(), map[string]money.Decimal{"CHEESE": kilograms("6")})
+
+	//ASSERT
+	//1 kg at 18.90, 4 kg at 17.01 and 1 kg at 15.00
+	if result.Subtotal.Micros != 10194000000 {
+		t.Errorf("Every band of the quantity should be priced at its own tier, expected 101.94 EUR, got: %s", result.Subtotal)
+	}
+}
+
+func TestOptimiserExecuteMeasuredItems(t *testing.T) {
+	//ARRANGE
+	executors := BuildRuleExecutors(parser.Rules{BulkRules: []parser.BulkRule{
+		{RuleName: "Cheese by the wheel", AffectedItem: "CHEESE", TriggerAmount: 2, DiscountPercentage: 10, Measured: true},
+		{RuleName: "Mugs", AffectedItem: "MUG", TriggerAmount: 2, DiscountPercentage: 20},
+	}})
+	var optimiser *Optimiser
+
+	//ACT
+	result, _ := optimiser.Execute(executors, nil, getMeasuredItems(), map[string]int{"MUG": 2}, map[string]money.Decimal{"CHEESE": kilograms("2.5")})
+
+	//ASSERT
+	//12.00 of the mugs and 42.525 of the cheese
+	if result.Subtotal.Micros != 5452500000 || len(result.Adjustments) != 2 {
+		t.Errorf("The counted and measured items should be priced by their own rules, expected 54.525 EUR, got: %+v", result)
+	}
+
+	if result.PricedUnits["CHEESE"] != 0 || result.PricedUnits["MUG"] != 2 {
+		t.Errorf("Only the counted items should have priced units, got: %v", result.PricedUnits)
+	}
+}
@@ -0,0 +1,89 @@
+package rules
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"sort"
+)
+
+//Items are the items of the group of the rule, resolved when the rules are loaded, the variants of an item with
+//variants are counted as items of the group
+type MixAndMatchRuleStrategy struct {
+	Rule  parser.MixAndMatchRule
+	Items []string
+}
+
+//A unit of an item and its price
+type pricedUnit struct {
+	item  string
+	price money.Money
+}
+
+//Executes a Mix and Match Rule calculation
+//It counts the units of every item of the group together, sorted from the most expensive, and for every complete set
+//of BuyN units, BuyN - PayM units are free, selected according to the rule selection, see parser.MixAndMatchRule
+//Only the units forming complete sets are priced by the rule, the paid ones being the most expensive units left once
+//the free ones are selected, so the cheapest units are left to the rules executed after it
+//The customer selection is priced as the most expensive one on its own, ExecuteRules picks the alternative giving the
+//lowest total along with the rules executed after it, see alternatives
+//The adjustment consumes every unit of the sets and reports the units discounted of every item
+func (s MixAndMatchRuleStrategy) ExecuteRule(conf parser.ConfiguredItems, scannedItems map[string]int) RuleResult {
+	selection := s.Rule.Selection
+	if selection == parser.SelectCustomer {
+		selection = parser.SelectMostExpensive
+	}
+	return s.executeSelection(conf, scannedItems, selection)
+}
+
+//Returns the results of the customer selection to choose from: the most expensive units free, leaving the cheapest
+//ones to the rules executed after it, the same free units paying with the cheapest ones, leaving the most expensive
+//ones, and the cheapest units free. There are none for the rest of the selections
+func (s MixAndMatchRuleStrategy) alternatives(conf parser.ConfiguredItems, scannedItems map[string]int) []RuleResult {
+	if s.Rule.Selection != parser.SelectCustomer {
+		return nil
+	}
+	return []RuleResult{
+		s.executeSelection(conf, scannedItems, parser.SelectMostExpensive),
+		s.executeSelection(conf, scannedItems, selectPayingCheapest),
+		s.executeSelection(conf, scannedItems, parser.SelectCheapest),
+	}
+}
+
+//The most expensive units are free and the cheapest ones are paid, only tried by the customer selection
+const selectPayingCheapest = "payingCheapest"
+
+func (s MixAndMatchRuleStrategy) executeSelection(conf parser.ConfiguredItems, scannedItems map[string]int, selection string) RuleResult {
+	units := scannedUnits(conf, s.Items, scannedItems)
+	sets := len(units) / s.Rule.BuyN
+	if sets == 0 {
+		return RuleResult{}
+	}
+
+	paid, free := selectUnits(units, sets*s.Rule.PayM, sets*(s.Rule.BuyN-s.Rule.PayM), selection)
+	result, adjustment := freeUnitsResult(conf.Currency(), paid, free)
+	adjustment.RuleName = s.Rule.RuleName
+	result.Adjustments = []Adjustment{adjustment}
+	return result
+}
+
+//Returns the given number of paid and free units among the given units, sorted from the most expensive
+func selectUnits(units []pricedUnit, paid int, free int, selection string) ([]pricedUnit, []pricedUnit) {
+	switch selection {
+	case parser.SelectMostExpensive:
+		return units[free : free+paid], units[:free]
+	case selectPayingCheapest:
+		return units[len(units)-paid:], units[:free]
+	default:
+		return units[:paid], units[len(units)-free:]
+	}
+}
+
+//Returns the given units by item sorted by item id
+func sortedUnits(units map[string]int) []ItemUnits {
+	var sorted []ItemUnits
+	for item, u := range units {
+		sorted = append(sorted, ItemUnits{ItemId: item, Units: u})
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ItemId < sorted[j].ItemId })
+	return sorted
+}
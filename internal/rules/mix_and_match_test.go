@@ -0,0 +1,117 @@
+package rules
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"reflect"
+	"testing"
+)
+
+func getMixAndMatchRules(selection string) parser.Rules {
+	return parser.Rules{
+		ItemGroups: map[string][]string{"merchandise": {"TSHIRT", "MUG", "VOUCHER"}},
+		MixAndMatchRules: []parser.MixAndMatchRule{
+			{RuleName: "Any 3 merchandise", Group: "merchandise", BuyN: 3, PayM: 2, Selection: selection},
+		},
+	}
+}
+
+func TestMixAndMatchRuleSelections(t *testing.T) {
+	//ARRANGE
+	//2 TSHIRT at 20.00, a MUG at 7.50 and 2 VOUCHER at 5.00 form a set of 3 units and leave 2 to the default rule
+	scanned := map[string]int{"TSHIRT": 2, "MUG": 1, "VOUCHER": 2}
+	tests := []struct {
+		selection  string
+		discount   money.Money
+		discounted []ItemUnits
+		total      money.Money
+	}{
+		{parser.SelectCheapest, money.New(500, "EUR"), []ItemUnits{{ItemId: "VOUCHER", Units: 1}}, money.New(5250, "EUR")},
+		{parser.SelectMostExpensive, money.New(2000, "EUR"), []ItemUnits{{ItemId: "TSHIRT", Units: 1}}, money.New(3750, "EUR")},
+		{parser.SelectCustomer, money.New(2000, "EUR"), []ItemUnits{{ItemId: "TSHIRT", Units: 1}}, money.New(3750, "EUR")},
+	}
+
+	for _, test := range tests {
+		//ACT
+		result := ExecuteRules(BuildRuleExecutors(getMixAndMatchRules(test.selection)), getConfiguredItems(), scanned)
+
+		//ASSERT
+		if total := result.Subtotal.Round(money.HalfUp); total != test.total {
+			t.Errorf("%s: expected a total of %s, got: %s", test.selection, test.total, total)
+		}
+		if len(result.Adjustments) != 1 || result.Adjustments[0].Discount.Round(money.HalfUp) != test.discount ||
+			!reflect.DeepEqual(result.Adjustments[0].Discounted, test.discounted) {
+			t.Errorf("%s: expected %s off %v, got: %+v", test.selection, test.discount, test.discounted, result.Adjustments)
+		}
+	}
+}
+
+func TestMixAndMatchRuleCustomerSelectionEverySet(t *testing.T) {
+	//ARRANGE
+	rule := MixAndMatchRuleStrategy{Rule: getMixAndMatchRules(parser.SelectCustomer).MixAndMatchRules[0], Items: []string{"MUG", "TSHIRT", "VOUCHER"}}
+	scanned := map[string]int{"TSHIRT": 2, "MUG": 2, "VOUCHER": 2}
+	expectedConsumed := []ItemUnits{{ItemId: "MUG", Units: 2}, {ItemId: "TSHIRT", Units: 2}, {ItemId: "VOUCHER", Units: 2}}
+
+	//ACT
+	result := rule.ExecuteRule(getConfiguredItems(), scanned)
+
+	//ASSERT
+	//The sets are TSHIRT, MUG, MUG and TSHIRT, VOUCHER, VOUCHER, the T-shirts are free
+	a := result.Adjustments[0]
+	if a.Units != 2 || a.Discount.Round(money.HalfUp) != money.New(4000, "EUR") ||
+		!reflect.DeepEqual(a.Discounted, []ItemUnits{{ItemId: "TSHIRT", Units: 2}}) {
+		t.Errorf("Both T-shirts should be free, got: %+v", a)
+	}
+	if result.Subtotal.Round(money.HalfUp) != money.New(2500, "EUR") {
+		t.Errorf("The mugs and the vouchers should have been paid, got: %s", result.Subtotal)
+	}
+	if !reflect.DeepEqual(a.Consumed, expectedConsumed) || result.PricedUnits["VOUCHER"] != 2 {
+		t.Errorf("Every unit of the sets should have been consumed, got: %+v", a.Consumed)
+	}
+}
+
+func TestMixAndMatchRuleCustomerSelectionRulesAfter(t *testing.T) {
+	//ARRANGE
+	//5 units form a set and leave 2 units to the NxM rule executed after the mix and match rule
+	tests := []struct {
+		name      string
+		selection string
+		scanned   map[string]int
+		nxmItem   string
+		total     money.Money
+	}{
+		//The most expensive selection pays with 2 T-shirts, the customer one pays with the mug and the voucher
+		{"Most expensive, T-shirts 2x1", parser.SelectMostExpensive, map[string]int{"TSHIRT": 3, "MUG": 1, "VOUCHER": 1}, "TSHIRT", money.New(5250, "EUR")},
+		{"Customer, T-shirts 2x1", parser.SelectCustomer, map[string]int{"TSHIRT": 3, "MUG": 1, "VOUCHER": 1}, "TSHIRT", money.New(3250, "EUR")},
+		//Both selections pay with the mug and a voucher, so the 2 vouchers left get the NxM discount
+		{"Most expensive, vouchers 2x1", parser.SelectMostExpensive, map[string]int{"TSHIRT": 1, "MUG": 1, "VOUCHER": 3}, "VOUCHER", money.New(1750, "EUR")},
+		{"Customer, vouchers 2x1", parser.SelectCustomer, map[string]int{"TSHIRT": 1, "MUG": 1, "VOUCHER": 3}, "VOUCHER", money.New(1750, "EUR")},
+	}
+
+	for _, test := range tests {
+		r := getMixAndMatchRules(test.selection)
+		r.MixAndMatchRules[0].Priority = 1
+		r.NxmRules = []parser.NxMRule{{RuleName: "2x1", AffectedItem: test.nxmItem, BuyN: 2, PayM: 1}}
+
+		//ACT
+		result := ExecuteRules(BuildRuleExecutors(r), getConfiguredItems(), test.scanned)
+
+		//ASSERT
+		if total := result.Subtotal.Round(money.HalfUp); total != test.total {
+			t.Errorf("%s: expected a total of %s, got: %s", test.name, test.total, total)
+		}
+	}
+}
+
+func TestMixAndMatchRuleIncompleteSet(t *testing.T) {
+	//ARRANGE
+	rule := MixAndMatchRuleStrategy{Rule: getMixAndMatchRules("").MixAndMatchRules[0], Items: []string{"MUG", "TSHIRT", "VOUCHER"}}
+
+	//ACT
+	result := rule.ExecuteRule(getConfiguredItems(), map[string]int{"TSHIRT": 1, "MUG": 1})
+
+	//ASSERT
+	if len(result.Adjustments) != 0 || len(result.PricedUnits) != 0 {
+		t.Errorf("No unit should have been priced without a complete set, got: %+v", result)
+	}
+}
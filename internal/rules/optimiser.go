@@ -0,0 +1,289 @@
+package rules
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"sort"
+	"time"
+)
+
+//Default limits of the Optimiser search
+const (
+	DefaultExactLimit     = 5040
+	DefaultMaxEvaluations = 500
+	DefaultBudget         = 20 * time.Millisecond
+)
+
+//Searches the order in which the competing rules are executed that gives the cheapest total for a basket
+//Two rules compete when they are not stackable and either affect the same item or share an exclusive group, as the
+//first one executed takes the units, or the group, the other one could have discounted. Only the order of the rules
+//of every set of competing rules changes, the rest keep the configured order
+//When the number of possible orders is up to ExactLimit all of them are evaluated, otherwise a local search swapping
+//two rules at a time is done until no swap gives a cheaper total or MaxEvaluations is reached. Either way the search
+//stops once the Budget is exhausted, keeping the cheapest order found so far
+//The orders are always evaluated in the same sequence and only a strictly cheaper one replaces the best one, so the
+//configured order wins any tie and the same basket always gets the same assignment unless the budget runs out
+//A nil Optimiser is valid and executes the rules in the configured order
+//Zero limits use the default ones
+type Optimiser struct {
+	ExactLimit     int
+	MaxEvaluations int
+	Budget         time.Duration
+}
+
+//The order of execution picked by the Optimiser for a basket
+//Rules are the names of the non stackable item rules in the order they were executed
+//Exhaustive is true when every possible order was evaluated and TimedOut when the budget ran out before the end
+//Saving is how much cheaper the basket is than with the configured order
+type Assignment struct {
+	Rules      []string
+	Evaluated  int
+	Exhaustive bool
+	TimedOut   bool
+	Saving     money.Subtotal
+}
+
+//Executes the item and basket rules on the scanned and measured items in the cheapest order found, see Optimiser
+//Returns the result of the rules, the same ExecuteRules, ExecuteMeasuredRules and ExecuteBasketRules would return for
+//that order, and the assignment picked, which is nil for a nil Optimiser
+func (o *Optimiser) Execute(executors []RuleStrategyExecutor, basketExecutors []BasketRuleStrategyExecutor,
+	conf parser.ConfiguredItems, scannedItems map[string]int, measuredItems map[string]money.Decimal) (RuleResult, *Assignment) {
+
+	evaluate := func(order []RuleStrategyExecutor) RuleResult {
+		items := ExecuteRules(order, conf, scannedItems).Merge(ExecuteMeasuredRules(order, conf, measuredItems))
+		return ExecuteBasketRules(basketExecutors, conf, items)
+	}
+	if o == nil {
+		return evaluate(executors), nil
+	}
+
+	s := search{executors: executors, components: competingRules(executors), evaluate: evaluate,
+		deadline: time.Now().Add(o.budget()), maxEvaluations: o.maxEvaluations()}
+	s.perms = make([][]int, len(s.components))
+	for c := range s.components {
+		s.perms[c] = identity(len(s.components[c]))
+	}
+	s.try()
+	baseline := s.best
+
+	if size, ok := s.size(o.exactLimit()); ok {
+		s.exhaustive(size)
+	} else {
+		s.localSearch()
+	}
+
+	assignment := &Assignment{Evaluated: s.evaluated, Exhaustive: s.complete, TimedOut: s.timedOut,
+		Saving: baseline.Subtotal.Sub(s.best.Subtotal)}
+	for _, e := range s.bestOrder {
+		if r, ok := e.(ResolvableRule); ok && !r.Options().Stackable {
+			assignment.Rules = append(assignment.Rules, r.Name())
+		}
+	}
+	return s.best, assignment
+}
+
+func (o *Optimiser) exactLimit() int {
+	if o.ExactLimit > 0 {
+		return o.ExactLimit
+	}
+	return DefaultExactLimit
+}
+
+func (o *Optimiser) maxEvaluations() int {
+	if o.MaxEvaluations > 0 {
+		return o.MaxEvaluations
+	}
+	return DefaultMaxEvaluations
+}
+
+func (o *Optimiser) budget() time.Duration {
+	if o.Budget > 0 {
+		return o.Budget
+	}
+	return DefaultBudget
+}
+
+//The state of the search of a basket
+//components are the positions of every set of competing rules in the executors and perms the order in which every
+//set is currently placed in those positions
+type search struct {
+	executors      []RuleStrategyExecutor
+	components     [][]int
+	perms          [][]int
+	evaluate       func(order []RuleStrategyExecutor) RuleResult
+	deadline       time.Time
+	maxEvaluations int
+
+	best      RuleResult
+	bestOrder []RuleStrategyExecutor
+	evaluated int
+	complete  bool
+	timedOut  bool
+}
+
+//Returns the number of possible orders, and false if it's higher than the given limit
+func (s *search) size(limit int) (int, bool) {
+	size := 1
+	for _, c := range s.components {
+		for n := 2; n <= len(c); n++ {
+			size *= n
+			if size > limit {
+				return size, false
+			}
+		}
+	}
+	return size, true
+}
+
+//Evaluates every possible order, the permutations of the last set change first
+func (s *search) exhaustive(size int) {
+	for i := 1; i < size; i++ {
+		c := len(s.perms) - 1
+		for !nextPermutation(s.perms[c]) {
+			c--
+		}
+		if !s.try() {
+			return
+		}
+	}
+	s.complete = true
+}
+
+//Swaps two rules of the same set at a time, keeping the swap if it gives a cheaper total, until no swap does
+func (s *search) localSearch() {
+	for improved := true; improved; {
+		improved = false
+		for c := range s.perms {
+			p := s.perms[c]
+			for i := 0; i < len(p); i++ {
+				for j := i + 1; j < len(p); j++ {
+					p[i], p[j] = p[j], p[i]
+					before := s.best.Subtotal
+					if s.evaluated >= s.maxEvaluations || !s.try() {
+						return
+					}
+					if s.best.Subtotal.Micros < before.Micros {
+						improved = true
+					} else {
+						p[i], p[j] = p[j], p[i]
+					}
+				}
+			}
+		}
+	}
+}
+
+//Evaluates the current order, keeping it if it's the first one or strictly cheaper than the best one
+//Returns false, without evaluating it, if the budget has run out
+func (s *search) try() bool {
+	if s.evaluated > 0 && time.Now().After(s.deadline) {
+		s.timedOut = true
+		return false
+	}
+	order := s.order()
+	result := s.evaluate(order)
+	s.evaluated++
+	if s.evaluated == 1 || result.Subtotal.Micros < s.best.Subtotal.Micros {
+		s.best, s.bestOrder = result, order
+	}
+	return true
+}
+
+//Returns a copy of the executors with every set of competing rules placed in its current order
+func (s *search) order() []RuleStrategyExecutor {
+	order := make([]RuleStrategyExecutor, len(s.executors))
+	copy(order, s.executors)
+	for c, positions := range s.components {
+		for k, pos := range positions {
+			order[pos] = s.executors[positions[s.perms[c][k]]]
+		}
+	}
+	return order
+}
+
+//Groups the positions of the non stackable rules that affect the same items or share an exclusive group
+//Rules that don't compete with any other rule are left out, as their order doesn't change the total
+func competingRules(executors []RuleStrategyExecutor) [][]int {
+	parent := make([]int, len(executors))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(i int) int
+	root = func(i int) int {
+		if parent[i] != i {
+			parent[i] = root(parent[i])
+		}
+		return parent[i]
+	}
+
+	owners := make(map[string]int)
+	link := func(key string, i int) {
+		if j, exs := owners[key]; exs {
+			parent[root(i)] = root(j)
+		} else {
+			owners[key] = i
+		}
+	}
+	for i, e := range executors {
+		r, ok := e.(ResolvableRule)
+		if !ok || r.Options().Stackable {
+			continue
+		}
+		for _, item := range r.AffectedItems() {
+			link("item:"+item, i)
+		}
+		if group := r.Options().ExclusiveGroup; group != "" {
+			link("group:"+group, i)
+		}
+	}
+
+	sets := make(map[int][]int)
+	for _, i := range owners {
+		sets[root(i)] = nil
+	}
+	for i := range executors {
+		if _, exs := sets[root(i)]; exs {
+			sets[root(i)] = append(sets[root(i)], i)
+		}
+	}
+	var components [][]int
+	for _, positions := range sets {
+		if len(positions) > 1 {
+			components = append(components, positions)
+		}
+	}
+	sort.Slice(components, func(i, j int) bool { return components[i][0] < components[j][0] })
+	return components
+}
+
+func identity(n int) []int {
+	p := make([]int, n)
+	for i := range p {
+		p[i] = i
+	}
+	return p
+}
+
+//Rearranges the permutation into the next one in lexicographic order
+//Returns false, leaving the first permutation, when the given one is the last one
+func nextPermutation(p []int) bool {
+	i := len(p) - 2
+	for i >= 0 && p[i] >= p[i+1] {
+		i--
+	}
+	if i < 0 {
+		for l, r := 0, len(p)-1; l < r; l, r = l+1, r-1 {
+			p[l], p[r] = p[r], p[l]
+		}
+		return false
+	}
+	j := len(p) - 1
+	for p[j] <= p[i] {
+		j--
+	}
+	p[i], p[j] = p[j], p[i]
+	for l, r := i+1, len(p)-1; l < r; l, r = l+1, r-1 {
+		p[l], p[r] = p[r], p[l]
+	}
+	return true
+}
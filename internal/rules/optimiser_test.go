@@ -0,0 +1,129 @@
+package rules
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"reflect"
+	"testing"
+	"time"
+)
+
+//A 3x2 executed before a 40% bulk discount on the vouchers, which is cheaper for 3 or more vouchers
+func getCompetingRules() parser.Rules {
+	return parser.Rules{
+		NxmRules: []parser.NxMRule{{RuleName: "3x2", AffectedItem: "VOUCHER", BuyN: 3, PayM: 2,
+			RuleOptions: parser.RuleOptions{Priority: 1}}},
+		BulkRules: []parser.BulkRule{
+			{RuleName: "40% off", AffectedItem: "VOUCHER", TriggerAmount: 3, DiscountPercentage: 40},
+			{RuleName: "Bulk Rule", AffectedItem: "TSHIRT", TriggerAmount: 3, DiscountPercentage: 5},
+		},
+	}
+}
+
+func TestOptimiserExecuteCheapestOrder(t *testing.T) {
+	//ARRANGE
+	executors := BuildRuleExecutors(getCompetingRules())
+	optimiser := &Optimiser{}
+
+	//ACT
+	//The 3x2 charges 10.00 for 3 vouchers and the 4th one is charged at 5.00, 40% off all of them is 12.00
+	result, assignment := optimiser.Execute(executors, nil, getConfiguredItems(), map[string]int{"VOUCHER": 4, "TSHIRT": 3}, nil)
+
+	//ASSERT
+	if result.Subtotal.Round(money.HalfUp) != money.New(6900, "EUR") {
+		t.Errorf("The cheapest order should have been picked, expected: 69.00, got %s", result.Subtotal)
+	}
+
+	expected := &Assignment{Rules: []string{"40% off", "3x2", "Bulk Rule"}, Evaluated: 2, Exhaustive: true,
+		Saving: money.New(300, "EUR").Subtotal()}
+	if !reflect.DeepEqual(assignment, expected) {
+		t.Errorf("The assignment doesn't match, expected: %+v, got: %+v", expected, assignment)
+	}
+
+}
+
+func TestOptimiserExecuteKeepsConfiguredOrderOnTie(t *testing.T) {
+	//ARRANGE
+	executors := BuildRuleExecutors(getCompetingRules())
+	optimiser := &Optimiser{}
+
+	//ACT
+	//With 3 vouchers the 3x2 charges 10.00 and 40% off is 9.00
+	_, cheaper := optimiser.Execute(executors, nil, getConfiguredItems(), map[string]int{"VOUCHER": 3}, nil)
+	//No rule is triggered by 2 vouchers, so both orders charge the same
+	_, tie := optimiser.Execute(executors, nil, getConfiguredItems(), map[string]int{"VOUCHER": 2}, nil)
+
+	//ASSERT
+	if cheaper.Rules[0] != "40% off" {
+		t.Errorf("The bulk rule should have been executed first, got: %v", cheaper.Rules)
+	}
+
+	if tie.Rules[0] != "3x2" || !tie.Saving.IsZero() {
+		t.Errorf("The configured order should be kept when no order is cheaper, got: %+v", tie)
+	}
+
+}
+
+func TestOptimiserExecuteLocalSearch(t *testing.T) {
+	//ARRANGE
+	executors := BuildRuleExecutors(getCompetingRules())
+	optimiser := &Optimiser{ExactLimit: 1}
+
+	//ACT
+	result, assignment := optimiser.Execute(executors, nil, getConfiguredItems(), map[string]int{"VOUCHER": 4}, nil)
+
+	//ASSERT
+	if result.Subtotal.Round(money.HalfUp) != money.New(1200, "EUR") || assignment.Exhaustive {
+		t.Errorf("The local search should have found the cheapest order, expected: 12.00, got %s (%+v)", result.Subtotal, assignment)
+	}
+
+}
+
+func TestOptimiserExecuteBudget(t *testing.T) {
+	//ARRANGE
+	executors := BuildRuleExecutors(getCompetingRules())
+	optimiser := &Optimiser{Budget: time.Nanosecond}
+
+	//ACT
+	time.Sleep(time.Millisecond)
+	result, assignment := optimiser.Execute(executors, nil, getConfiguredItems(), map[string]int{"VOUCHER": 4}, nil)
+
+	//ASSERT
+	if !assignment.TimedOut || assignment.Evaluated != 1 || result.Subtotal.Round(money.HalfUp) != money.New(1500, "EUR") {
+		t.Errorf("Only the configured order should have been evaluated, got %s (%+v)", result.Subtotal, assignment)
+	}
+
+}
+
+func TestOptimiserExecuteNil(t *testing.T) {
+	//ARRANGE
+	var optimiser *Optimiser
+
+	//ACT
+	result, assignment := optimiser.Execute(BuildRuleExecutors(getCompetingRules()), nil, getConfiguredItems(), map[string]int{"VOUCHER": 4}, nil)
+
+	//ASSERT
+	if assignment != nil || result.Subtotal.Round(money.HalfUp) != money.New(1500, "EUR") {
+		t.Errorf("The rules should have been executed in the configured order, got %s (%+v)", result.Subtotal, assignment)
+	}
+
+}
+
+func TestCompetingRules(t *testing.T) {
+	//ARRANGE
+	r := getCompetingRules()
+	r.BuyXGetYRules = []parser.BuyXGetYRule{{RuleName: "Free Mug", TriggerItem: "TSHIRT", TriggerQuantity: 1, RewardItem: "MUG",
+		RewardQuantity: 1, RewardDiscountPercentage: 100, RuleOptions: parser.RuleOptions{ExclusiveGroup: "merch"}}}
+	r.BulkRules[1].ExclusiveGroup = "merch"
+
+	//ACT
+	components := competingRules(BuildRuleExecutors(r))
+
+	//ASSERT
+	//3x2, Free Mug, 40% off, Bulk Rule and the default rule
+	expected := [][]int{{0, 2}, {1, 3}}
+	if !reflect.DeepEqual(components, expected) {
+		t.Errorf("The rules sharing an item or an exclusive group should compete, expected: %v, got: %v", expected, components)
+	}
+
+}
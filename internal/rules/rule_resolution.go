@@ -0,0 +1,166 @@
+package rules
+
+import (
+	"fmt"
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	log "github.com/sirupsen/logrus"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+//Implemented by the strategies of the configured rules, so they can be ordered and resolved against each other
+//AffectedItems are the items whose price is changed by the rule, see parser.RuleOptions
+type ResolvableRule interface {
+	Name() string
+	Options() parser.RuleOptions
+	AffectedItems() []string
+}
+
+func (s BulkRuleStrategy) Name() string                { return s.Rule.RuleName }
+func (s BulkRuleStrategy) Options() parser.RuleOptions { return s.Rule.RuleOptions }
+func (s BulkRuleStrategy) AffectedItems() []string     { return []string{s.Rule.AffectedItem} }
+
+func (s NxMRuleStrategy) Name() string                { return s.Rule.RuleName }
+func (s NxMRuleStrategy) Options() parser.RuleOptions { return s.Rule.RuleOptions }
+func (s NxMRuleStrategy) AffectedItems() []string     { return []string{s.Rule.AffectedItem} }
+
+func (s BuyXGetYRuleStrategy) Name() string                { return s.Rule.RuleName }
+func (s BuyXGetYRuleStrategy) Options() parser.RuleOptions { return s.Rule.RuleOptions }
+func (s BuyXGetYRuleStrategy) AffectedItems() []string     { return []string{s.Rule.RewardItem} }
+
+func (s BundleRuleStrategy) Name() string                { return s.Rule.RuleName }
+func (s BundleRuleStrategy) Options() parser.RuleOptions { return s.Rule.RuleOptions }
+func (s BundleRuleStrategy) AffectedItems() []string     { return s.Rule.Items() }
+
+func (s MixAndMatchRuleStrategy) Name() string                { return s.Rule.RuleName }
+func (s MixAndMatchRuleStrategy) Options() parser.RuleOptions { return s.Rule.RuleOptions }
+func (s MixAndMatchRuleStrategy) AffectedItems() []string     { return s.Items }
+
+func (s BasketRuleStrategy) Name() string                { return s.Rule.RuleName }
+func (s BasketRuleStrategy) Options() parser.RuleOptions { return s.Rule.RuleOptions }
+func (s BasketRuleStrategy) AffectedItems() []string     { return nil }
+
+//Returns the options of the given executor, the executors that are not configured rules have the default ones
+func optionsOf(executor interface{}) parser.RuleOptions {
+	if r, ok := executor.(ResolvableRule); ok {
+		return r.Options()
+	}
+	return parser.RuleOptions{}
+}
+
+func nameOf(executor interface{}) string {
+	if r, ok := executor.(ResolvableRule); ok {
+		return r.Name()
+	}
+	return fmt.Sprintf("%T", executor)
+}
+
+//Logs, for every item, the rules that can change its price in the order they are executed
+//The stackable rules are executed once the rest of the item rules have priced the basket, so they go last
+func logItemResolution(executors []RuleStrategyExecutor) {
+	order := make(map[string][]string)
+	var items []string
+	for _, stackable := range []bool{false, true} {
+		for _, e := range executors {
+			r, ok := e.(ResolvableRule)
+			if !ok || r.Options().Stackable != stackable {
+				continue
+			}
+			for _, item := range r.AffectedItems() {
+				if _, exs := order[item]; !exs {
+					items = append(items, item)
+				}
+				order[item] = append(order[item], describe(r))
+			}
+		}
+	}
+	sort.Strings(items)
+	for _, item := range items {
+		log.Infof("Rules affecting item %s, in order of execution: %s", item, strings.Join(order[item], ", "))
+	}
+}
+
+//Logs the basket rules in the order they are executed
+func logBasketResolution(executors []BasketRuleStrategyExecutor) {
+	var order []string
+	for _, e := range executors {
+		if r, ok := e.(ResolvableRule); ok {
+			order = append(order, describe(r))
+		}
+	}
+	if len(order) > 0 {
+		log.Infof("Basket rules, in order of execution: %s", strings.Join(order, ", "))
+	}
+}
+
+func describe(r ResolvableRule) string {
+	o := r.Options()
+	d := fmt.Sprintf("%s (priority %d", r.Name(), o.Priority)
+	if o.Stackable {
+		d += ", stackable"
+	}
+	if o.ExclusiveGroup != "" {
+		d += ", exclusive group " + o.ExclusiveGroup
+	}
+	if o.IsScheduled() {
+		d += ", scheduled"
+	}
+	return d + ")"
+}
+
+//Returns the rule to report as skipped if the given executor belongs to an exclusive group that a previous rule has
+//already been applied for. Rules that wouldn't give any discount are never skipped, as there's nothing to choose from
+func (r RuleResult) exclusiveSkip(executor interface{}, result RuleResult) (SkippedRule, bool) {
+	group := optionsOf(executor).ExclusiveGroup
+	if group == "" || len(result.Adjustments) == 0 {
+		return SkippedRule{}, false
+	}
+	if applied, exs := r.groups[group]; exs {
+		return SkippedRule{RuleName: nameOf(executor),
+			Reason: fmt.Sprintf("the rule %s of the exclusive group %s has already been applied", applied, group)}, true
+	}
+	return SkippedRule{}, false
+}
+
+//Records the exclusive group of the executor as applied if its result gives any discount
+func claimGroup(executor interface{}, result RuleResult) RuleResult {
+	if group := optionsOf(executor).ExclusiveGroup; group != "" && len(result.Adjustments) > 0 {
+		result.groups = map[string]string{group: nameOf(executor)}
+	}
+	return result
+}
+
+//Applies the discount of a stackable rule on top of the amounts already charged for its items
+//The rule is executed on all the scanned units as if it was the only rule, and the same share of the price of the
+//items it discounts is taken off the amount already charged for them, ie: a stackable 10% off takes 10% off the
+//amount left by an NxM rule
+func stack(executor RuleStrategyExecutor, conf parser.ConfiguredItems, scannedItems map[string]int, priced RuleResult) RuleResult {
+	currency := conf.Currency()
+	result := executor.ExecuteRule(conf, scannedItems)
+	stacked := RuleResult{Subtotal: money.Zero(currency), ItemSubtotals: make(map[string]money.Subtotal)}
+	ruleDiscount := money.Zero(currency)
+	for item, subtotal := range result.ItemSubtotals {
+		discount := conf[item].Price.Times(result.PricedUnits[item]).Sub(subtotal)
+		listPrice := conf[item].Price.Times(scannedItems[item])
+		if discount.Micros <= 0 || listPrice.Micros <= 0 {
+			continue
+		}
+		share := proportion(priced.ItemSubtotals[item], big.NewInt(discount.Micros), big.NewInt(listPrice.Micros))
+		stacked.ItemSubtotals[item] = money.Zero(currency).Sub(share)
+		stacked.Subtotal = stacked.Subtotal.Sub(share)
+		ruleDiscount = ruleDiscount.Add(discount)
+	}
+	if stacked.Subtotal.IsZero() {
+		return RuleResult{}
+	}
+
+	total := money.Zero(currency).Sub(stacked.Subtotal)
+	for _, a := range result.Adjustments {
+		a.Discount = proportion(total, big.NewInt(a.Discount.Micros), big.NewInt(ruleDiscount.Micros))
+		a.Stacked = true
+		stacked.Adjustments = append(stacked.Adjustments, a)
+	}
+	return stacked
+}
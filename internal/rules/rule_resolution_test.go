@@ -0,0 +1,120 @@
+package rules
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"testing"
+)
+
+func TestBuildRuleExecutorsSortedByPriority(t *testing.T) {
+	//ARRANGE
+	r := parser.Rules{
+		BulkRules: []parser.BulkRule{
+			{RuleName: "Low", AffectedItem: "TSHIRT", TriggerAmount: 3, DiscountPercentage: 5},
+			{RuleName: "High", AffectedItem: "MUG", TriggerAmount: 3, DiscountPercentage: 5, RuleOptions: parser.RuleOptions{Priority: 10}},
+		},
+		NxmRules: []parser.NxMRule{
+			{RuleName: "Medium", AffectedItem: "VOUCHER", BuyN: 2, PayM: 1, RuleOptions: parser.RuleOptions{Priority: 5}},
+		},
+	}
+
+	//ACT
+	executors := BuildRuleExecutors(r)
+
+	//ASSERT
+	var names []string
+	for _, e := range executors[:len(executors)-1] {
+		names = append(names, nameOf(e))
+	}
+	if len(names) != 3 || names[0] != "High" || names[1] != "Medium" || names[2] != "Low" {
+		t.Errorf("The rules should be sorted by priority, got: %v", names)
+	}
+
+	if _, ok := executors[len(executors)-1].(DefaultRuleStrategy); !ok {
+		t.Errorf("The default rule should always be executed last, got: %T", executors[len(executors)-1])
+	}
+
+}
+
+func TestExecuteRulesExclusiveGroup(t *testing.T) {
+	//ARRANGE
+	executors := BuildRuleExecutors(parser.Rules{
+		BulkRules: []parser.BulkRule{{RuleName: "Bulk", AffectedItem: "TSHIRT", TriggerAmount: 3, DiscountPercentage: 5,
+			RuleOptions: parser.RuleOptions{ExclusiveGroup: "summer"}}},
+		NxmRules: []parser.NxMRule{{RuleName: "2x1", AffectedItem: "VOUCHER", BuyN: 2, PayM: 1,
+			RuleOptions: parser.RuleOptions{ExclusiveGroup: "summer"}}},
+	})
+
+	//ACT
+	result := ExecuteRules(executors, getConfiguredItems(), map[string]int{"TSHIRT": 3, "VOUCHER": 2})
+
+	//ASSERT
+	//57.00 for the discounted T-shirts, the vouchers are charged at their full price
+	if result.Subtotal.Round(money.HalfUp) != money.New(6700, "EUR") {
+		t.Errorf("Only the first rule of the group should have been applied, expected: 67.00, got %s", result.Subtotal)
+	}
+
+	if len(result.Skipped) != 1 || result.Skipped[0].RuleName != "2x1" {
+		t.Errorf("The 2x1 rule should have been reported as skipped, got: %+v", result.Skipped)
+	}
+
+	if result.PricedUnits["VOUCHER"] != 2 {
+		t.Errorf("The vouchers of the skipped rule should have been priced by the default rule, got: %d", result.PricedUnits["VOUCHER"])
+	}
+
+}
+
+func TestExecuteRulesStackable(t *testing.T) {
+	//ARRANGE
+	executors := BuildRuleExecutors(parser.Rules{
+		BulkRules: []parser.BulkRule{{RuleName: "Loyalty", AffectedItem: "VOUCHER", TriggerAmount: 1, DiscountPercentage: 10,
+			RuleOptions: parser.RuleOptions{Stackable: true}}},
+		NxmRules: []parser.NxMRule{{RuleName: "2x1", AffectedItem: "VOUCHER", BuyN: 2, PayM: 1}},
+	})
+
+	//ACT
+	result := ExecuteRules(executors, getConfiguredItems(), map[string]int{"VOUCHER": 4})
+
+	//ASSERT
+	//The 2x1 charges 10.00 for the 4 vouchers and the stacked 10% takes 1.00 off it
+	if result.Subtotal.Round(money.HalfUp) != money.New(900, "EUR") {
+		t.Errorf("The stackable rule should discount the amount left by the 2x1, expected: 9.00, got %s", result.Subtotal)
+	}
+
+	if result.PricedUnits["VOUCHER"] != 4 {
+		t.Errorf("The stacked rule should not price the units again, got: %d", result.PricedUnits["VOUCHER"])
+	}
+
+	last := result.Adjustments[len(result.Adjustments)-1]
+	if last.RuleName != "Loyalty" || !last.Stacked || last.Discount.Round(money.HalfUp) != money.New(100, "EUR") {
+		t.Errorf("The stacked rule should have been reported with a 1.00 discount, got: %+v", last)
+	}
+
+}
+
+func TestExecuteBasketRulesNotStackable(t *testing.T) {
+	//ARRANGE
+	threshold, _ := money.ParseDecimal("10.00")
+	amount, _ := money.ParseDecimal("2.00")
+	executors := BuildBasketExecutors(parser.Rules{BasketRules: []parser.BasketRule{
+		{RuleName: "10%", Threshold: threshold, DiscountPercentage: 10},
+		{RuleName: "2.00 off", Threshold: threshold, DiscountAmount: amount},
+		{RuleName: "Stacked 2.00 off", Threshold: threshold, DiscountAmount: amount, RuleOptions: parser.RuleOptions{Stackable: true}},
+	}})
+	c := getConfiguredItems()
+	itemsResult := ExecuteRules(BuildRuleExecutors(parser.Rules{}), c, map[string]int{"TSHIRT": 1})
+
+	//ACT
+	result := ExecuteBasketRules(executors, c, itemsResult)
+
+	//ASSERT
+	//20.00 - 10% - 2.00
+	if result.Subtotal.Round(money.HalfUp) != money.New(1600, "EUR") {
+		t.Errorf("Only the first and the stackable basket rules should have been applied, expected: 16.00, got %s", result.Subtotal)
+	}
+
+	if len(result.Skipped) != 1 || result.Skipped[0].RuleName != "2.00 off" {
+		t.Errorf("The non stackable basket rule should have been reported as skipped, got: %+v", result.Skipped)
+	}
+
+}
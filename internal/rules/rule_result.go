@@ -0,0 +1,125 @@
+package rules
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"math/big"
+	"sort"
+)
+
+//The outcome of executing a rule on a basket
+//Subtotal is the exact, not rounded, amount charged by the rule for the items it prices
+//Adjustments explain every discount the rule granted so it can be reported back to the customer
+//PricedUnits are the units of each item charged by the rule, the rules executed after it don't see them
+//ItemSubtotals split the subtotal between the items it was charged for, so the amount paid for each item is known
+//Skipped are the rules that would have given a discount but weren't applied because of the rules applied before them
+type RuleResult struct {
+	Subtotal      money.Subtotal
+	Adjustments   []Adjustment
+	PricedUnits   map[string]int
+	ItemSubtotals map[string]money.Subtotal
+	Skipped       []SkippedRule
+	//The rule applied for every exclusive group
+	groups map[string]string
+}
+
+//A discount granted by a rule, ie: the 5% off of a Bulk Rule applied to 3 TSHIRT
+//Consumed are the units of the items the discount required, ie: the TSHIRT bought to get a MUG for free or the items
+//forming a bundle
+//Stacked adjustments were applied on top of the discounts of other rules
+//Measure is the quantity discounted of an item sold by weight or measure, which has no units
+//Discounted are the units of every item selected for the discount by a rule discounting several items, ie: the
+//cheapest item of a mix and match
+type Adjustment struct {
+	RuleName     string
+	AffectedItem string
+	Units        int
+	Measure      money.Decimal
+	Discount     money.Subtotal
+	Consumed     []ItemUnits
+	Discounted   []ItemUnits
+	Stacked      bool
+}
+
+//A rule that wasn't applied and the reason why
+type SkippedRule struct {
+	RuleName string
+	Reason   string
+}
+
+//A number of units of an item
+type ItemUnits struct {
+	ItemId string
+	Units  int
+}
+
+//Adds the subtotals and priced units and appends the adjustments of the given result to this one
+func (r RuleResult) Merge(o RuleResult) RuleResult {
+	priced := make(map[string]int, len(r.PricedUnits)+len(o.PricedUnits))
+	for k, v := range r.PricedUnits {
+		priced[k] += v
+	}
+	for k, v := range o.PricedUnits {
+		priced[k] += v
+	}
+	subtotals := make(map[string]money.Subtotal, len(r.ItemSubtotals)+len(o.ItemSubtotals))
+	for k, v := range r.ItemSubtotals {
+		subtotals[k] = subtotals[k].Add(v)
+	}
+	for k, v := range o.ItemSubtotals {
+		subtotals[k] = subtotals[k].Add(v)
+	}
+	groups := make(map[string]string, len(r.groups)+len(o.groups))
+	for k, v := range r.groups {
+		groups[k] = v
+	}
+	for k, v := range o.groups {
+		if _, exs := groups[k]; !exs {
+			groups[k] = v
+		}
+	}
+	return RuleResult{Subtotal: r.Subtotal.Add(o.Subtotal), Adjustments: append(r.Adjustments, o.Adjustments...), PricedUnits: priced,
+		ItemSubtotals: subtotals, Skipped: append(r.Skipped, o.Skipped...), groups: groups}
+}
+
+//Result of a rule that charges the given units of a single item
+func itemResult(item string, units int, subtotal money.Subtotal) RuleResult {
+	return RuleResult{Subtotal: subtotal, PricedUnits: map[string]int{item: units}, ItemSubtotals: map[string]money.Subtotal{item: subtotal}}
+}
+
+//Splits the amount between the items proportionally to their weights, ie: the price of a bundle between its components
+//The part lost to the divisions is given to the item with the biggest weight, so the parts always add up to the amount
+func allocate(amount money.Subtotal, weights map[string]money.Subtotal) map[string]money.Subtotal {
+	items := make([]string, 0, len(weights))
+	total := new(big.Int)
+	for k, w := range weights {
+		items = append(items, k)
+		total.Add(total, big.NewInt(w.Micros))
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	sort.Strings(items)
+
+	parts := make(map[string]money.Subtotal, len(items))
+	biggest := items[0]
+	allocated := money.Zero(amount.Currency)
+	for _, k := range items {
+		part := money.Zero(amount.Currency)
+		if total.Sign() != 0 {
+			part = proportion(amount, big.NewInt(weights[k].Micros), total)
+		}
+		parts[k] = part
+		allocated = allocated.Add(part)
+		if weights[k].Micros > weights[biggest].Micros {
+			biggest = k
+		}
+	}
+	parts[biggest] = parts[biggest].Add(amount.Sub(allocated))
+	return parts
+}
+
+//Returns amount * numerator / denominator, the intermediate product can be bigger than an int64
+func proportion(amount money.Subtotal, numerator *big.Int, denominator *big.Int) money.Subtotal {
+	share := new(big.Int).Mul(big.NewInt(amount.Micros), numerator)
+	return money.Subtotal{Micros: share.Quo(share, denominator).Int64(), Currency: amount.Currency}
+}
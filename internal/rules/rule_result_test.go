@@ -0,0 +1,46 @@
+package rules
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"testing"
+)
+
+func TestAllocate(t *testing.T) {
+
+	//ARRANGE
+	//10.00 split between 7.50 and 20.00 is 2.727272... and 7.272727..., the part lost goes to the biggest one
+	amount := money.New(1000, "EUR").Subtotal()
+	weights := map[string]money.Subtotal{
+		"MUG":    money.New(750, "EUR").Subtotal(),
+		"TSHIRT": money.New(2000, "EUR").Subtotal(),
+	}
+
+	//ACT
+	parts := allocate(amount, weights)
+
+	//ASSERT
+	if parts["MUG"].Micros != 272727272 || parts["TSHIRT"].Micros != 727272728 {
+		t.Errorf("The amount should have been split proportionally, got: %v", parts)
+	}
+
+	if parts["MUG"].Add(parts["TSHIRT"]) != amount {
+		t.Errorf("The parts should add up to the amount, got: %v", parts)
+	}
+
+}
+
+func TestMergeItemSubtotals(t *testing.T) {
+
+	//ARRANGE
+	first := itemResult("MUG", 2, money.New(1500, "EUR").Subtotal())
+	second := itemResult("MUG", 1, money.New(750, "EUR").Subtotal())
+
+	//ACT
+	merged := first.Merge(second)
+
+	//ASSERT
+	if merged.PricedUnits["MUG"] != 3 || merged.ItemSubtotals["MUG"] != money.New(2250, "EUR").Subtotal() {
+		t.Errorf("The units and subtotals of the item should have been added, got: %+v", merged)
+	}
+
+}
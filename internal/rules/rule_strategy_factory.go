@@ -1,18 +1,48 @@
 package rules
 
 import (
+	"fmt"
+	"github.com/dagozba/golangsmallshop/internal/money"
 	"github.com/dagozba/golangsmallshop/internal/parser"
 	log "github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+	"time"
 )
 
+//The baskets are priced in two phases: the RuleExecutors price the scanned items in the item phase, then the
+//BasketExecutors are executed on the already discounted result of the item phase in the basket phase
+//Coupons and Location are the coupons and the time zone of the schedules of the loaded rules
 type RuleStrategyFactory struct {
-	RuleExecutors []RuleStrategyExecutor
-	RuleParser    parser.IRuleParser
+	RuleExecutors   []RuleStrategyExecutor
+	BasketExecutors []BasketRuleStrategyExecutor
+	Coupons         map[string]parser.Coupon
+	Location        *time.Location
+	RuleParser      parser.IRuleParser
+	rules           parser.Rules
 }
 
 //Interface that serves as an abstraction layer for the Pricer, executing this method for any struct that implements this interface
+//The returned subtotal is not rounded, the Pricer rounds the sum of all the subtotals once to obtain the final amount
+//Every discount applied by the rule is reported as an Adjustment of the result
+//The scanned items are the units not priced yet by the rules executed before, see ExecuteRules
 type RuleStrategyExecutor interface {
-	ExecuteRule(conf parser.ConfiguredItems, scannedItems map[string]int) int64
+	ExecuteRule(conf parser.ConfiguredItems, scannedItems map[string]int) RuleResult
+}
+
+//Implemented by the rules that can select the units they price in several ways, ExecuteRules executes the alternative
+//giving the lowest total along with the rules executed after it instead of the result of ExecuteRule
+//No alternatives means the result of ExecuteRule is used
+type alternativeRuleExecutor interface {
+	RuleStrategyExecutor
+	alternatives(conf parser.ConfiguredItems, scannedItems map[string]int) []RuleResult
+}
+
+//Interface of the rules executed in the basket phase, once all the items have been priced
+//The priced result is the result of the item phase merged with the result of the basket rules executed before, the
+//returned result is merged into it, see ExecuteBasketRules
+type BasketRuleStrategyExecutor interface {
+	ExecuteBasketRule(conf parser.ConfiguredItems, priced RuleResult) RuleResult
 }
 
 type BulkRuleStrategy struct {
@@ -23,53 +53,337 @@ type NxMRuleStrategy struct {
 	Rule parser.NxMRule
 }
 
-type DefaultRuleStrategy struct{}
+type BuyXGetYRuleStrategy struct {
+	Rule parser.BuyXGetYRule
+}
 
+type BundleRuleStrategy struct {
+	Rule parser.BundleRule
+}
 
-var IncludedItems map[string]bool
+type BasketRuleStrategy struct {
+	Rule parser.BasketRule
+}
+
+type DefaultRuleStrategy struct{}
 
 //It begins parsing the rules defined in the /configs/rules.yaml file
-//then it creates a matching rule strategy and adds it to the executors slice
-//all the items affected by any promotion are added to a map so the default rule can apply to the items not included in it
+//then it creates a matching rule strategy for each of them, replacing the executors loaded before
 func (f *RuleStrategyFactory) LoadRules(filePath string) error {
 	log.Info("Parsing initial Rules for Rule Strategy Factory")
 	rules, err := f.RuleParser.ParseRulesFile(filePath)
 	if err != nil {
 		return err
 	}
-	IncludedItems = make(map[string]bool)
+	f.rules = rules
+	f.RuleExecutors = BuildRuleExecutors(rules)
+	f.BasketExecutors = BuildBasketExecutors(rules)
+	f.Coupons = rules.CouponsByCode()
+	f.Location = rules.Location()
+	return nil
+}
+
+//Resolves the items selected by category or tags by the loaded rules against the given item definitions and rebuilds
+//the executors with them, the rules selecting their items can't be executed until then, see parser.ItemSelector
+//The executors are kept as they are if none of the loaded rules selects its items
+func (f *RuleStrategyFactory) ResolveSelectors(items parser.ConfiguredItems) {
+	if !f.rules.HasSelectors() {
+		return
+	}
+	f.RuleExecutors = BuildRuleExecutors(f.rules.ResolveSelectors(items))
+}
+
+//Creates a new slice with a matching rule strategy for each of the given rules, followed by the default rule
+//The rules are sorted by priority, the ones with the same priority keep the order of their type: bundles go first so
+//the units they take are not seen by the rest of the rules, then the Buy X get Y rules take the reward units they
+//discount. The bulk and NxM rules price the units left of their items, then the mix and match rules count the units
+//left of their groups together and the default rule prices the rest
+//A bulk or NxM rule selecting its items by category or tags is executed for every item it selects, as if it was
+//defined once for each of them. The selectors must have been resolved, see parser.Rules.ResolveSelectors
+//The executors don't share any state, so a new slice can replace the one in use without affecting running calculations
+func BuildRuleExecutors(rules parser.Rules) []RuleStrategyExecutor {
+	var executors []RuleStrategyExecutor
+	for _, v := range rules.BundleRules {
+		executors = append(executors, BundleRuleStrategy{Rule: v})
+		log.Infof("Applying Bundle rule for items: %s - Bundled units will not be seen by other rules", strings.Join(v.Items(), ", "))
+	}
+
+	for _, v := range rules.BuyXGetYRules {
+		executors = append(executors, BuyXGetYRuleStrategy{Rule: v})
+		log.Infof("Applying Buy X get Y rule for items: %s -> %s", v.TriggerItem, v.RewardItem)
+	}
+
 	for _, v := range rules.BulkRules {
-		f.RuleExecutors = append(f.RuleExecutors, BulkRuleStrategy{Rule: v})
-		log.Infof("Applying BulkRule for item: %s - Default rule will not be applied to this item", v.AffectedItem)
-		IncludedItems[v.AffectedItem] = true
+		warnEmptySelection(v.RuleName, v.ItemSelector)
+		for _, item := range v.AffectedItems(v.AffectedItem) {
+			v.AffectedItem = item
+			executors = append(executors, BulkRuleStrategy{Rule: v})
+			log.Infof("Applying BulkRule for item: %s - Default rule will not be applied to this item", v.AffectedItem)
+		}
 	}
 
 	for _, v := range rules.NxmRules {
-		f.RuleExecutors = append(f.RuleExecutors, NxMRuleStrategy{Rule: v})
-		log.Infof("Applying Bundle (NxMRule) for item: %s - Default rule will not be applied to this item", v.AffectedItem)
-		IncludedItems[v.AffectedItem] = true
+		warnEmptySelection(v.RuleName, v.ItemSelector)
+		for _, item := range v.AffectedItems(v.AffectedItem) {
+			v.AffectedItem = item
+			executors = append(executors, NxMRuleStrategy{Rule: v})
+			log.Infof("Applying Bundle (NxMRule) for item: %s - Default rule will not be applied to this item", v.AffectedItem)
+		}
 	}
 
-	f.RuleExecutors = append(f.RuleExecutors, DefaultRuleStrategy{})
+	for _, v := range rules.MixAndMatchRules {
+		warnEmptySelection(v.RuleName, v.ItemSelector)
+		items := rules.MixAndMatchItems(v)
+		executors = append(executors, MixAndMatchRuleStrategy{Rule: v, Items: items})
+		if v.ItemSelector.IsSet() {
+			log.Infof("Applying Mix and Match rule %s for the items %s: %s", v.RuleName, v.ItemSelector, strings.Join(items, ", "))
+		} else {
+			log.Infof("Applying Mix and Match rule %s for the items of the group %s: %s", v.RuleName, v.Group, strings.Join(items, ", "))
+		}
+	}
+
+	sort.SliceStable(executors, func(i, j int) bool { return optionsOf(executors[i]).Priority > optionsOf(executors[j]).Priority })
+	logItemResolution(executors)
+	return append(executors, DefaultRuleStrategy{})
+}
+
+//Logs a warning for a rule whose resolved selector doesn't select any item, as it's never applied
+//A selector not resolved yet has no selected items, nil instead of an empty slice, and is not reported
+func warnEmptySelection(ruleName string, s parser.ItemSelector) {
+	if s.IsSet() && s.SelectedItems != nil && len(s.SelectedItems) == 0 {
+		log.Warnf("The rule %s is not applied, there's no item %s", ruleName, s)
+	}
+}
 
-	return err
+//Creates a new slice with a matching basket rule strategy for each of the given basket rules, sorted by priority
+//the ones with the same priority keep the order they are defined in
+func BuildBasketExecutors(rules parser.Rules) []BasketRuleStrategyExecutor {
+	var executors []BasketRuleStrategyExecutor
+	for _, v := range rules.BasketRules {
+		executors = append(executors, BasketRuleStrategy{Rule: v})
+		log.Infof("Applying Basket rule %s - It will be applied once all the items have been priced", v.RuleName)
+	}
+	sort.SliceStable(executors, func(i, j int) bool { return optionsOf(executors[i]).Priority > optionsOf(executors[j]).Priority })
+	logBasketResolution(executors)
+	return executors
+}
+
+//Executes the basket rules in the given order on the result of the item phase, every rule sees the amounts already
+//discounted by the item rules and by the basket rules executed before it
+//Only the first basket rule giving a discount is applied, unless the rules after it are stackable, and only the first
+//rule of every exclusive group, including the groups of the item rules. The rest of them are reported as skipped
+//Returns the result of the item phase merged with the result of every basket rule
+func ExecuteBasketRules(executors []BasketRuleStrategyExecutor, conf parser.ConfiguredItems, itemsResult RuleResult) RuleResult {
+	total := itemsResult
+	applied := ""
+	for _, executor := range executors {
+		result := executor.ExecuteBasketRule(conf, total)
+		if len(result.Adjustments) == 0 {
+			continue
+		}
+		if skipped, ok := total.exclusiveSkip(executor, result); ok {
+			total.Skipped = append(total.Skipped, skipped)
+			continue
+		}
+		if applied != "" && !optionsOf(executor).Stackable {
+			total.Skipped = append(total.Skipped, SkippedRule{RuleName: nameOf(executor),
+				Reason: fmt.Sprintf("the basket rule %s has already been applied and this rule is not stackable", applied)})
+			continue
+		}
+		applied = nameOf(executor)
+		total = total.Merge(claimGroup(executor, result))
+	}
+	return total
+}
+
+//Executes the rules in the given order, every rule only sees the units of the scanned items that haven't been priced
+//by the rules executed before it, so no unit is charged twice. The scanned items are not modified
+//The stackable rules are executed last, applying their discounts on top of the amounts charged by the rest of them,
+//and only the first rule of every exclusive group giving a discount is applied, the rest are reported as skipped
+//A rule with alternatives is executed choosing the cheapest of them, see alternativeRuleExecutor
+func ExecuteRules(executors []RuleStrategyExecutor, conf parser.ConfiguredItems, scannedItems map[string]int) RuleResult {
+	remaining := unpricedUnits(scannedItems, nil)
+	total := RuleResult{Subtotal: money.Zero(conf.Currency())}
+	var stackable []RuleStrategyExecutor
+	for i, executor := range executors {
+		if optionsOf(executor).Stackable {
+			stackable = append(stackable, executor)
+			continue
+		}
+		result := executor.ExecuteRule(conf, remaining)
+		if a, ok := executor.(alternativeRuleExecutor); ok {
+			result = cheapestAlternative(a, executors[i+1:], conf, remaining, result)
+		}
+		if skipped, ok := total.exclusiveSkip(executor, result); ok {
+			total.Skipped = append(total.Skipped, skipped)
+			continue
+		}
+		remaining = unpricedUnits(remaining, result.PricedUnits)
+		total = total.Merge(claimGroup(executor, result))
+	}
+
+	for _, executor := range stackable {
+		result := stack(executor, conf, scannedItems, total)
+		if skipped, ok := total.exclusiveSkip(executor, result); ok {
+			total.Skipped = append(total.Skipped, skipped)
+			continue
+		}
+		total = total.Merge(claimGroup(executor, result))
+	}
+	return total
+}
+
+//Returns the alternative of the rule giving the lowest total once the given rules, executed after it, price the units
+//it leaves, the first one is kept on a tie. The given result is returned if the rule has no alternatives
+func cheapestAlternative(a alternativeRuleExecutor, after []RuleStrategyExecutor, conf parser.ConfiguredItems,
+	scannedItems map[string]int, result RuleResult) RuleResult {
+
+	var cheapest money.Subtotal
+	for i, alternative := range a.alternatives(conf, scannedItems) {
+		total := ExecuteRules(after, conf, unpricedUnits(scannedItems, alternative.PricedUnits)).Subtotal.Add(alternative.Subtotal)
+		if i == 0 || total.Micros < cheapest.Micros {
+			result, cheapest = alternative, total
+		}
+	}
+	return result
+}
+
+//Returns a copy of the given units without the priced ones
+func unpricedUnits(units map[string]int, priced map[string]int) map[string]int {
+	remaining := make(map[string]int, len(units))
+	for k, v := range units {
+		remaining[k] = v
+	}
+	for k, v := range priced {
+		remaining[k] -= v
+		if remaining[k] <= 0 {
+			delete(remaining, k)
+		}
+	}
+	return remaining
 }
 
 //Executes the BulkRule calculation
 //It gets the number of items affected by this rule in the scanned items map
-//if the number of items affected is equal or higher than the configured trigger amount (ie: if you buy 10 and trigger amount is 5)
+//if the number of items affected reaches the threshold of a tier (ie: if you buy 10 and trigger amount is 5), they are
+//priced according to the mode of the rule:
+//whole quantity: all the units are priced at the highest tier reached, ie: number of items * configured price * (100 - discount %) / 100
+//graduated: every band of units is priced at its own tier, the units below the first threshold at the configured price
 //else, it applies the default formula
-//As the discount % is given as an integer, it has to be converted into a decimal in order to apply it to the configured price
-//final calculation formula is: number of items * configured price * % in decimal (if 20, then 0.20)
-func (s BulkRuleStrategy) ExecuteRule(conf parser.ConfiguredItems, scannedItems map[string]int) int64 {
-	if a, exs := scannedItems[s.Rule.AffectedItem]; exs && int(a) >= s.Rule.TriggerAmount {
-		result := float32(a) * conf[s.Rule.AffectedItem].Price * float32(1.0-float32(s.Rule.DiscountPercentage)/100)
-		return int64(result * 100)
-	} else if exs {
-		return int64((conf[s.Rule.AffectedItem].Price * float32(a)) * 100)
-	} else {
-		return 0
+//The result is exact, the rounding to cents is left to the Pricer
+//A measured rule doesn't count units, see ExecuteMeasuredRule, and the units of the variants of an item with variants
+//are counted together, see executeOnVariants
+func (s BulkRuleStrategy) ExecuteRule(conf parser.ConfiguredItems, scannedItems map[string]int) RuleResult {
+	if conf[s.Rule.AffectedItem].HasVariants() && !s.Rule.Measured {
+		return s.executeOnVariants(conf, scannedItems)
+	}
+	a, exs := scannedItems[s.Rule.AffectedItem]
+	if !exs || s.Rule.Measured {
+		return RuleResult{}
+	}
+	price := conf[s.Rule.AffectedItem].Price
+	gross := price.Times(a)
+	subtotal, discounted := gross, 0
+	if s.Rule.Mode == parser.BulkGraduated {
+		subtotal, discounted = s.graduatedPrice(price, a)
+	} else if tier, reached := s.reachedTier(a); reached {
+		subtotal, discounted = tier.PriceOf(price, a), a
+	}
+	result := itemResult(s.Rule.AffectedItem, a, subtotal)
+	result.Adjustments = s.adjustments(discounted, gross.Sub(subtotal))
+	return result
+}
+
+//Executes the rule on the units of every variant of the affected item counted together: the tier reached by all of them
+//prices every unit in whole quantity mode and, in graduated mode, the units are sorted from the most expensive before
+//being split in bands, so the cheapest units are the ones priced at the highest tiers
+func (s BulkRuleStrategy) executeOnVariants(conf parser.ConfiguredItems, scannedItems map[string]int) RuleResult {
+	units := scannedUnits(conf, []string{s.Rule.AffectedItem}, scannedItems)
+	if len(units) == 0 {
+		return RuleResult{}
+	}
+	tiers := s.Rule.PriceTiers()
+	//The units of every item by tier, the units at the first position are not priced at any tier
+	byTier := make(map[string][]int)
+	discounted := 0
+	for i, u := range units {
+		reached := len(units)
+		if s.Rule.Mode == parser.BulkGraduated {
+			reached = i + 1
+		}
+		tier := 0
+		for t := range tiers {
+			if reached >= tiers[t].Threshold {
+				tier = t + 1
+			}
+		}
+		if byTier[u.item] == nil {
+			byTier[u.item] = make([]int, len(tiers)+1)
+		}
+		byTier[u.item][tier]++
+		if tier > 0 {
+			discounted++
+		}
 	}
+
+	result := RuleResult{Subtotal: money.Zero(conf.Currency()), PricedUnits: make(map[string]int), ItemSubtotals: make(map[string]money.Subtotal)}
+	gross := money.Zero(conf.Currency())
+	for item, counts := range byTier {
+		price := conf[item].Price
+		subtotal := price.Times(counts[0])
+		for t, n := range counts[1:] {
+			if n > 0 {
+				subtotal = subtotal.Add(tiers[t].PriceOf(price, n))
+			}
+		}
+		for _, n := range counts {
+			result.PricedUnits[item] += n
+		}
+		result.ItemSubtotals[item] = subtotal
+		result.Subtotal = result.Subtotal.Add(subtotal)
+		gross = gross.Add(price.Times(result.PricedUnits[item]))
+	}
+	result.Adjustments = s.adjustments(discounted, gross.Sub(result.Subtotal))
+	return result
+}
+
+//Returns the highest tier reached by the given units, if any
+func (s BulkRuleStrategy) reachedTier(units int) (parser.BulkTier, bool) {
+	var reached parser.BulkTier
+	found := false
+	for _, t := range s.Rule.PriceTiers() {
+		if units >= t.Threshold {
+			reached, found = t, true
+		}
+	}
+	return reached, found
+}
+
+//Prices every band of units at its own tier, returns the price and the number of units priced at any tier
+func (s BulkRuleStrategy) graduatedPrice(price money.Money, units int) (money.Subtotal, int) {
+	tiers := s.Rule.PriceTiers()
+	if len(tiers) == 0 || units < tiers[0].Threshold {
+		return price.Times(units), 0
+	}
+	subtotal := price.Times(tiers[0].Threshold - 1)
+	for i, t := range tiers {
+		last := units
+		if i+1 < len(tiers) && tiers[i+1].Threshold-1 < last {
+			last = tiers[i+1].Threshold - 1
+		}
+		if last < t.Threshold {
+			break
+		}
+		subtotal = subtotal.Add(t.PriceOf(price, last-t.Threshold+1))
+	}
+	return subtotal, units - (tiers[0].Threshold - 1)
+}
+
+func (s BulkRuleStrategy) adjustments(units int, discount money.Subtotal) []Adjustment {
+	if discount.IsZero() {
+		return nil
+	}
+	return []Adjustment{{RuleName: s.Rule.RuleName, AffectedItem: s.Rule.AffectedItem, Units: units, Discount: discount}}
 }
 
 //Executes a Bundle Rule calculation
@@ -77,21 +391,226 @@ func (s BulkRuleStrategy) ExecuteRule(conf parser.ConfiguredItems, scannedItems
 //then it calculates the number of bundles (ie: for a Buy 2 pay 1 Rule and 3 items, the number of bundles is 2)
 //then it calculates the reminder, which is the item left over from the bundle that is not affected by the promotion
 //finally, it applies the formula: (number of bundles * items_to_pay) * item price
-func (s NxMRuleStrategy) ExecuteRule(conf parser.ConfiguredItems, scannedItems map[string]int) int64 {
+//The units affected by the promotion are the ones forming complete bundles, the discount is the price of the free ones
+//The units of the variants of an item with variants are counted together, see executeOnVariants
+func (s NxMRuleStrategy) ExecuteRule(conf parser.ConfiguredItems, scannedItems map[string]int) RuleResult {
+	if conf[s.Rule.AffectedItem].HasVariants() {
+		return s.executeOnVariants(conf, scannedItems)
+	}
 	a, _ := scannedItems[s.Rule.AffectedItem]
+	if a == 0 {
+		return RuleResult{}
+	}
 	bundles := a / s.Rule.BuyN
 	remainder := a % s.Rule.BuyN
-	return int64((float32(bundles*s.Rule.PayM+remainder) * conf[s.Rule.AffectedItem].Price) * 100)
+	price := conf[s.Rule.AffectedItem].Price
+	result := itemResult(s.Rule.AffectedItem, a, price.Times(bundles*s.Rule.PayM+remainder))
+	if free := bundles * (s.Rule.BuyN - s.Rule.PayM); free > 0 {
+		result.Adjustments = []Adjustment{{
+			RuleName:     s.Rule.RuleName,
+			AffectedItem: s.Rule.AffectedItem,
+			Units:        bundles * s.Rule.BuyN,
+			Discount:     price.Times(free),
+		}}
+	}
+	return result
 }
 
-//Executes the default rule for all items not affected by pricing rules
-//default rule is just the items' configured price
-func (DefaultRuleStrategy) ExecuteRule(conf parser.ConfiguredItems, scannedItems map[string]int) int64 {
-	var totalAmount float32
-	for k, v := range scannedItems {
-		if _, ok := IncludedItems[k]; !ok {
-			totalAmount += conf[k].Price * float32(v)
+//Executes the rule on the units of every variant of the affected item counted together, as their prices can differ
+//the free units of the bundles are the cheapest ones, the adjustment reports the units discounted of every variant
+func (s NxMRuleStrategy) executeOnVariants(conf parser.ConfiguredItems, scannedItems map[string]int) RuleResult {
+	units := scannedUnits(conf, []string{s.Rule.AffectedItem}, scannedItems)
+	if len(units) == 0 {
+		return RuleResult{}
+	}
+	bundles := len(units) / s.Rule.BuyN
+	free := bundles * (s.Rule.BuyN - s.Rule.PayM)
+	result, adjustment := freeUnitsResult(conf.Currency(), units[:len(units)-free], units[len(units)-free:])
+	if free > 0 {
+		result.Adjustments = []Adjustment{{
+			RuleName:     s.Rule.RuleName,
+			AffectedItem: s.Rule.AffectedItem,
+			Units:        bundles * s.Rule.BuyN,
+			Discount:     adjustment.Discount,
+			Discounted:   adjustment.Discounted,
+		}}
+	}
+	return result
+}
+
+//Executes a Buy X get Y Rule calculation
+//Every TriggerQuantity units of the trigger item scanned give RewardQuantity units of the reward item at a discount, but
+//only the reward units actually scanned are discounted, and no more than MaxApplications times if there's a cap
+//When the trigger and the reward are the same item, the rewarded units can't be used as triggers too, ie: for a buy 2
+//get 1 free, 3 units are needed for the first free one
+//Only the discounted reward units are priced by the rule, the trigger units are left to the rules executed after it
+//and the ones consumed by the discounted units are reported in the adjustment
+//The units of the variants of a trigger or reward item with variants are counted together and the cheapest reward
+//units are the ones discounted, the adjustment reports the units discounted of every variant
+func (s BuyXGetYRuleStrategy) ExecuteRule(conf parser.ConfiguredItems, scannedItems map[string]int) RuleResult {
+	rewarded, applications := s.rewardedUnits(conf, scannedItems)
+	if rewarded == 0 {
+		return RuleResult{}
+	}
+	units := scannedUnits(conf, []string{s.Rule.RewardItem}, scannedItems)
+	counts, itemsGross := countUnits(units[len(units)-rewarded:])
+	gross := money.Zero(conf.Currency())
+	for _, g := range itemsGross {
+		gross = gross.Add(g)
+	}
+	discount := gross.Percent(s.Rule.RewardDiscountPercentage)
+	subtotal := gross.Sub(discount)
+	result := RuleResult{Subtotal: subtotal, PricedUnits: counts, ItemSubtotals: allocate(subtotal, itemsGross)}
+	result.Adjustments = []Adjustment{{
+		RuleName:     s.Rule.RuleName,
+		AffectedItem: s.Rule.RewardItem,
+		Units:        rewarded,
+		Discount:     discount,
+		Consumed:     []ItemUnits{{ItemId: s.Rule.TriggerItem, Units: applications * s.Rule.TriggerQuantity}},
+	}}
+	if conf[s.Rule.RewardItem].HasVariants() {
+		result.Adjustments[0].Discounted = sortedUnits(counts)
+	}
+	return result
+}
+
+//Returns the number of reward units discounted and the number of times the rule has been applied to get them
+func (s BuyXGetYRuleStrategy) rewardedUnits(conf parser.ConfiguredItems, scannedItems map[string]int) (int, int) {
+	x, y := s.Rule.TriggerQuantity, s.Rule.RewardQuantity
+	var applications, rewarded int
+	if s.Rule.TriggerItem == s.Rule.RewardItem {
+		units := unitsOf(conf, s.Rule.TriggerItem, scannedItems)
+		applications = units / (x + y)
+		rewarded = applications * y
+		if remainder := units % (x + y); remainder > x {
+			applications++
+			rewarded += remainder - x
+		}
+	} else {
+		applications = unitsOf(conf, s.Rule.TriggerItem, scannedItems) / x
+		rewarded = applications * y
+		if scanned := unitsOf(conf, s.Rule.RewardItem, scannedItems); rewarded > scanned {
+			rewarded = scanned
 		}
 	}
-	return int64(totalAmount * 100)
+	if s.Rule.MaxApplications > 0 && applications > s.Rule.MaxApplications {
+		applications = s.Rule.MaxApplications
+		if rewarded > applications*y {
+			rewarded = applications * y
+		}
+	}
+	//Applications that didn't get any reward unit don't consume trigger units
+	return rewarded, (rewarded + y - 1) / y
+}
+
+//Executes a Bundle Rule calculation
+//It forms as many complete bundles as the scanned items allow, the number of bundles is the lowest number of times the
+//quantity of any component fits in its scanned units (ie: 3 T-shirts and 2 mugs make 2 T-shirt + mug bundles)
+//The bundled units are charged at the bundle price, or at the discount percentage off their price, and they are not seen
+//by the rules executed after it, so the leftovers are priced normally
+//The bundle is not applied if the bundle price is not below the price of the units it takes, ie: variants cheaper than
+//their parent, and its units are left to the rules executed after it
+func (s BundleRuleStrategy) ExecuteRule(conf parser.ConfiguredItems, scannedItems map[string]int) RuleResult {
+	bundles := -1
+	for _, c := range s.Rule.Components {
+		if n := unitsOf(conf, c.Item, scannedItems) / c.Quantity; bundles < 0 || n < bundles {
+			bundles = n
+		}
+	}
+	if bundles <= 0 {
+		return RuleResult{}
+	}
+
+	gross := money.Zero(conf.Currency())
+	priced := make(map[string]int)
+	componentsGross := make(map[string]money.Subtotal)
+	var consumed []ItemUnits
+	for _, c := range s.Rule.Components {
+		units := c.Quantity * bundles
+		available := scannedUnits(conf, []string{c.Item}, scannedItems)
+		counts, itemsGross := countUnits(available[len(available)-units:])
+		for item, n := range counts {
+			componentsGross[item] = componentsGross[item].Add(itemsGross[item])
+			gross = gross.Add(itemsGross[item])
+			priced[item] += n
+		}
+		consumed = append(consumed, ItemUnits{ItemId: c.Item, Units: units})
+	}
+
+	subtotal := gross.Percent(100 - s.Rule.DiscountPercentage)
+	if s.Rule.BundlePrice != 0 {
+		subtotal = money.SubtotalFromDecimal(s.Rule.BundlePrice, conf.Currency()).Times(bundles)
+		if subtotal.Micros >= gross.Micros {
+			return RuleResult{}
+		}
+	}
+	//The bundle price is split between the components according to their price
+	result := RuleResult{Subtotal: subtotal, PricedUnits: priced, ItemSubtotals: allocate(subtotal, componentsGross)}
+	if discount := gross.Sub(subtotal); !discount.IsZero() {
+		result.Adjustments = []Adjustment{{
+			RuleName:     s.Rule.RuleName,
+			AffectedItem: strings.Join(s.Rule.Items(), "+"),
+			Units:        bundles,
+			Discount:     discount,
+			Consumed:     consumed,
+		}}
+	}
+	return result
+}
+
+//Executes a Basket Rule calculation
+//The amount spent is the sum of what is charged for every item but the excluded ones, if it reaches the threshold it
+//is discounted by the percentage or the fixed amount of the rule, never more than the amount spent nor the maximum discount
+//The discount is split between the discounted items according to their amounts, so the amount paid for each item is still known
+func (s BasketRuleStrategy) ExecuteBasketRule(conf parser.ConfiguredItems, priced RuleResult) RuleResult {
+	currency := conf.Currency()
+	excluded := make(map[string]bool)
+	for _, i := range s.Rule.ExcludedItems {
+		for _, sold := range conf.ItemsOf(i) {
+			excluded[sold] = true
+		}
+	}
+	eligible := make(map[string]money.Subtotal)
+	spent := money.Zero(currency)
+	units := 0
+	for item, subtotal := range priced.ItemSubtotals {
+		if !excluded[item] {
+			eligible[item] = subtotal
+			spent = spent.Add(subtotal)
+			units += priced.PricedUnits[item]
+		}
+	}
+	if spent.Micros <= 0 || spent.Micros < money.SubtotalFromDecimal(s.Rule.Threshold, currency).Micros {
+		return RuleResult{}
+	}
+
+	discount := spent.Percent(s.Rule.DiscountPercentage)
+	if s.Rule.DiscountAmount != 0 {
+		discount = money.SubtotalFromDecimal(s.Rule.DiscountAmount, currency)
+	}
+	if maxDiscount := money.SubtotalFromDecimal(s.Rule.MaxDiscount, currency); s.Rule.MaxDiscount != 0 && discount.Micros > maxDiscount.Micros {
+		discount = maxDiscount
+	}
+	if discount.Micros > spent.Micros {
+		discount = spent
+	}
+	if discount.IsZero() {
+		return RuleResult{}
+	}
+
+	return RuleResult{
+		Subtotal:      money.Zero(currency).Sub(discount),
+		ItemSubtotals: allocate(money.Zero(currency).Sub(discount), eligible),
+		Adjustments:   []Adjustment{{RuleName: s.Rule.RuleName, Units: units, Discount: discount}},
+	}
+}
+
+//Executes the default rule for all items not priced by the pricing rules
+//default rule is just the items' configured price, so it never produces adjustments
+func (DefaultRuleStrategy) ExecuteRule(conf parser.ConfiguredItems, scannedItems map[string]int) RuleResult {
+	result := RuleResult{Subtotal: money.Zero(conf.Currency())}
+	for k, v := range scannedItems {
+		result = result.Merge(itemResult(k, v, conf[k].Price.Times(v)))
+	}
+	return result
 }
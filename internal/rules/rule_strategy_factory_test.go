@@ -1,8 +1,10 @@
 package rules
 
 import (
+	"github.com/dagozba/golangsmallshop/internal/money"
 	"github.com/dagozba/golangsmallshop/internal/parser"
 	"github.com/stretchr/testify/mock"
+	"reflect"
 	"testing"
 )
 
@@ -10,37 +12,36 @@ type MockedRulesParser struct {
 	mock.Mock
 }
 
-func (m MockedRulesParser) ParseRulesFile(p string) (parser.Rules, error) {
+func (m *MockedRulesParser) ParseRulesFile(p string) (parser.Rules, error) {
 	return parser.Rules{
-		NxmRules:  []parser.NxMRule{{
+		NxmRules: []parser.NxMRule{{
 			RuleName:     "NxM Rule",
 			AffectedItem: "VOUCHER",
 			BuyN:         2,
 			PayM:         1,
 		}},
 		BulkRules: []parser.BulkRule{{
-			RuleName: "BulkRule",
-			AffectedItem: "TSHIRT",
-			TriggerAmount: 3,
+			RuleName:           "BulkRule",
+			AffectedItem:       "TSHIRT",
+			TriggerAmount:      3,
 			DiscountPercentage: 5,
 		}},
 	}, nil
 }
 
-
 func getConfiguredItems() parser.ConfiguredItems {
 	return parser.ConfiguredItems{
 		"VOUCHER": {
 			Name:  "Company Voucher",
-			Price: 5.00,
+			Price: money.New(500, "EUR"),
 		},
 		"TSHIRT": {
 			Name:  "Company T-Shirt",
-			Price: 20.00,
+			Price: money.New(2000, "EUR"),
 		},
 		"MUG": {
 			Name:  "Company Coffee Mug",
-			Price: 7.5,
+			Price: money.New(750, "EUR"),
 		},
 	}
 }
@@ -49,7 +50,7 @@ func TestLoadConfiguredRules(t *testing.T) {
 
 	//ARRANGE
 	expectedRules := 3
-	rulesFactory := RuleStrategyFactory{RuleParser: MockedRulesParser{}}
+	rulesFactory := RuleStrategyFactory{RuleParser: &MockedRulesParser{}}
 
 	//ACT
 	rulesFactory.LoadRules("PATH")
@@ -61,10 +62,26 @@ func TestLoadConfiguredRules(t *testing.T) {
 
 }
 
+func TestLoadRulesTwice(t *testing.T) {
+
+	//ARRANGE
+	rulesFactory := RuleStrategyFactory{RuleParser: &MockedRulesParser{}}
+	rulesFactory.LoadRules("PATH")
+
+	//ACT
+	rulesFactory.LoadRules("PATH")
+
+	//ASSERT
+	if actualLength := len(rulesFactory.RuleExecutors); actualLength != 3 {
+		t.Errorf("Loading the rules again should replace the executors, expected: %d, got: %d", 3, actualLength)
+	}
+
+}
+
 func TestNxMRuleStrategy_ExecuteRuleExactBundle(t *testing.T) {
 
 	//ARRANGE
-	rulesFactory := RuleStrategyFactory{RuleParser: MockedRulesParser{}}
+	rulesFactory := RuleStrategyFactory{RuleParser: &MockedRulesParser{}}
 	rulesFactory.LoadRules("PATH")
 	var nxmRuleStrategy RuleStrategyExecutor
 	for _, v := range rulesFactory.RuleExecutors {
@@ -84,8 +101,8 @@ func TestNxMRuleStrategy_ExecuteRuleExactBundle(t *testing.T) {
 	})
 
 	//ASSERT
-	if result != expectedCalc {
-		t.Errorf("NxMRule should have been applied once, expected: %.2f, got %.2f", float64(expectedCalc), float64(result))
+	if result.Subtotal.Round(money.HalfUp).Amount != expectedCalc {
+		t.Errorf("NxMRule should have been applied once, expected: %.2f, got %.2f", float64(expectedCalc), float64(result.Subtotal.Round(money.HalfUp).Amount))
 	}
 
 }
@@ -93,7 +110,7 @@ func TestNxMRuleStrategy_ExecuteRuleExactBundle(t *testing.T) {
 func TestNxMRuleStrategy_ExecuteRuleBundleAndRemainder(t *testing.T) {
 
 	//ARRANGE
-	rulesFactory := RuleStrategyFactory{RuleParser: MockedRulesParser{}}
+	rulesFactory := RuleStrategyFactory{RuleParser: &MockedRulesParser{}}
 	rulesFactory.LoadRules("DUMMY_PATH")
 	var nxmRuleStrategy RuleStrategyExecutor
 	for _, v := range rulesFactory.RuleExecutors {
@@ -113,17 +130,16 @@ func TestNxMRuleStrategy_ExecuteRuleBundleAndRemainder(t *testing.T) {
 	})
 
 	//ASSERT
-	if result != expectedCalc {
-		t.Errorf("NxMRule should have been applied once, expected: %.2f, got %.2f", float64(expectedCalc), float64(result))
+	if result.Subtotal.Round(money.HalfUp).Amount != expectedCalc {
+		t.Errorf("NxMRule should have been applied once, expected: %.2f, got %.2f", float64(expectedCalc), float64(result.Subtotal.Round(money.HalfUp).Amount))
 	}
 
 }
 
-
 func TestNxMRuleStrategy_ExecuteRuleNoAffectedItems(t *testing.T) {
 
 	//ARRANGE
-	rulesFactory := RuleStrategyFactory{RuleParser: MockedRulesParser{}}
+	rulesFactory := RuleStrategyFactory{RuleParser: &MockedRulesParser{}}
 	rulesFactory.LoadRules("DUMMY_PATH")
 	var nxmRuleStrategy RuleStrategyExecutor
 	for _, v := range rulesFactory.RuleExecutors {
@@ -145,15 +161,15 @@ func TestNxMRuleStrategy_ExecuteRuleNoAffectedItems(t *testing.T) {
 
 	//ASSERT
 	//ASSERT
-	if result != expectedCalc {
-		t.Errorf("NxMRule should have not been applied, expected: %.2f, got %.2f", float64(expectedCalc), float64(result))
+	if result.Subtotal.Round(money.HalfUp).Amount != expectedCalc {
+		t.Errorf("NxMRule should have not been applied, expected: %.2f, got %.2f", float64(expectedCalc), float64(result.Subtotal.Round(money.HalfUp).Amount))
 	}
 
 }
 
 func TestBulkRuleStrategy_ExecuteRule(t *testing.T) {
 	//ARRANGE
-	rulesFactory := RuleStrategyFactory{RuleParser: MockedRulesParser{}}
+	rulesFactory := RuleStrategyFactory{RuleParser: &MockedRulesParser{}}
 	rulesFactory.LoadRules("DUMMY_PATH")
 	var bulkRuleStrategy RuleStrategyExecutor
 	for _, v := range rulesFactory.RuleExecutors {
@@ -173,15 +189,15 @@ func TestBulkRuleStrategy_ExecuteRule(t *testing.T) {
 	})
 
 	//ASSERT
-	if result != expectedCalc {
-		t.Errorf("BUlkRule should have been applied once, expected: %.2f, got %.2f", float64(expectedCalc), float64(result))
+	if result.Subtotal.Round(money.HalfUp).Amount != expectedCalc {
+		t.Errorf("BUlkRule should have been applied once, expected: %.2f, got %.2f", float64(expectedCalc), float64(result.Subtotal.Round(money.HalfUp).Amount))
 	}
 
 }
 
 func TestBulkRuleStrategy_ExecuteRuleCorrectItemDiscountNotTriggered(t *testing.T) {
 	//ARRANGE
-	rulesFactory := RuleStrategyFactory{RuleParser: MockedRulesParser{}}
+	rulesFactory := RuleStrategyFactory{RuleParser: &MockedRulesParser{}}
 	rulesFactory.LoadRules("DUMMY_PATH")
 	var bulkRuleStrategy RuleStrategyExecutor
 	for _, v := range rulesFactory.RuleExecutors {
@@ -201,15 +217,15 @@ func TestBulkRuleStrategy_ExecuteRuleCorrectItemDiscountNotTriggered(t *testing.
 	})
 
 	//ASSERT
-	if result != expectedCalc {
-		t.Errorf("BUlkRule should have not been applied, expected: %.2f, got %.2f", float64(expectedCalc), float64(result))
+	if result.Subtotal.Round(money.HalfUp).Amount != expectedCalc {
+		t.Errorf("BUlkRule should have not been applied, expected: %.2f, got %.2f", float64(expectedCalc), float64(result.Subtotal.Round(money.HalfUp).Amount))
 	}
 
 }
 
 func TestBulkRuleStrategy_ExecuteRuleNotAffectedItems(t *testing.T) {
 	//ARRANGE
-	rulesFactory := RuleStrategyFactory{RuleParser: MockedRulesParser{}}
+	rulesFactory := RuleStrategyFactory{RuleParser: &MockedRulesParser{}}
 	rulesFactory.LoadRules("DUMMY_PATH")
 	var bulkRuleStrategy RuleStrategyExecutor
 	for _, v := range rulesFactory.RuleExecutors {
@@ -230,15 +246,15 @@ func TestBulkRuleStrategy_ExecuteRuleNotAffectedItems(t *testing.T) {
 	})
 
 	//ASSERT
-	if result != expectedCalc {
-		t.Errorf("BUlkRule should have not been applied, expected: %.2f, got %.2f", float64(expectedCalc), float64(result))
+	if result.Subtotal.Round(money.HalfUp).Amount != expectedCalc {
+		t.Errorf("BUlkRule should have not been applied, expected: %.2f, got %.2f", float64(expectedCalc), float64(result.Subtotal.Round(money.HalfUp).Amount))
 	}
 
 }
 
 func TestDefaultRuleStrategy_ExecuteRule(t *testing.T) {
 	//ARRANGE
-	rulesFactory := RuleStrategyFactory{RuleParser: MockedRulesParser{}}
+	rulesFactory := RuleStrategyFactory{RuleParser: &MockedRulesParser{}}
 	rulesFactory.LoadRules("DUMMY_PATH")
 	var defaultRuleStrategy RuleStrategyExecutor
 	for _, v := range rulesFactory.RuleExecutors {
@@ -258,69 +274,527 @@ func TestDefaultRuleStrategy_ExecuteRule(t *testing.T) {
 	})
 
 	//ASSERT
-	if result != expectedCalc {
-		t.Errorf("Default rule should have been applied once, expected: %.2f, got %.2f", float64(expectedCalc), float64(result))
+	if result.Subtotal.Round(money.HalfUp).Amount != expectedCalc {
+		t.Errorf("Default rule should have been applied once, expected: %.2f, got %.2f", float64(expectedCalc), float64(result.Subtotal.Round(money.HalfUp).Amount))
 	}
 
 }
 
-
 func TestDefaultRuleStrategy_ExecuteRuleOnlyNonAffectedItems(t *testing.T) {
 	//ARRANGE
-	rulesFactory := RuleStrategyFactory{RuleParser: MockedRulesParser{}}
+	rulesFactory := RuleStrategyFactory{RuleParser: &MockedRulesParser{}}
 	rulesFactory.LoadRules("DUMMY_PATH")
-	var defaultRuleStrategy RuleStrategyExecutor
-	for _, v := range rulesFactory.RuleExecutors {
-		switch v.(type) {
-		case DefaultRuleStrategy:
-			defaultRuleStrategy = v
-		}
-	}
 
-	var expectedCalc int64 = 0
+	//The voucher and the T-shirt are priced by their rules, so the default rule must not charge them again
+	var expectedCalc int64 = 2500
+	expectedPriced := map[string]int{"VOUCHER": 1, "TSHIRT": 1}
 
 	c := getConfiguredItems()
 
 	//ACT
-	result := defaultRuleStrategy.ExecuteRule(c, map[string]int{
+	result := ExecuteRules(rulesFactory.RuleExecutors, c, map[string]int{
 		"VOUCHER": 1,
 		"TSHIRT":  1,
 	})
 
 	//ASSERT
-	if result != expectedCalc {
-		t.Errorf("BUlkRule should have not been applied, expected: %.2f, got %.2f", float64(expectedCalc), float64(result))
+	if result.Subtotal.Round(money.HalfUp).Amount != expectedCalc {
+		t.Errorf("Every item should have been charged once, expected: %.2f, got %.2f", float64(expectedCalc), float64(result.Subtotal.Round(money.HalfUp).Amount))
+	}
+
+	if !reflect.DeepEqual(result.PricedUnits, expectedPriced) {
+		t.Errorf("Every unit should have been priced once, expected: %v, got %v", expectedPriced, result.PricedUnits)
 	}
 
 }
 
 func TestDefaultRuleStrategy_ExecuteRuleMixedItemsNotAffectedIgnored(t *testing.T) {
 	//ARRANGE
-	rulesFactory := RuleStrategyFactory{RuleParser: MockedRulesParser{}}
+	rulesFactory := RuleStrategyFactory{RuleParser: &MockedRulesParser{}}
 	rulesFactory.LoadRules("DUMMY_PATH")
-	var defaultRuleStrategy RuleStrategyExecutor
-	for _, v := range rulesFactory.RuleExecutors {
-		switch v.(type) {
-		case DefaultRuleStrategy:
-			defaultRuleStrategy = v
-		}
-	}
+	defaultRuleStrategy := rulesFactory.RuleExecutors[len(rulesFactory.RuleExecutors)-1]
 
 	var expectedCalc int64 = 750
 
 	c := getConfiguredItems()
 
 	//ACT
+	//The default rule only sees the units left by the rules executed before it
 	result := defaultRuleStrategy.ExecuteRule(c, map[string]int{
-		"VOUCHER": 1,
-		"MUG":     1,
-		"TSHIRT":  1,
+		"MUG": 1,
+	})
+
+	//ASSERT
+	if _, ok := defaultRuleStrategy.(DefaultRuleStrategy); !ok {
+		t.Errorf("The default rule should be the last one to be executed, got: %T", defaultRuleStrategy)
+	}
+
+	if result.Subtotal.Round(money.HalfUp).Amount != expectedCalc {
+		t.Errorf("Default rule should have priced the mug, expected: %.2f, got %.2f", float64(expectedCalc), float64(result.Subtotal.Round(money.HalfUp).Amount))
+	}
+
+}
+
+func TestBulkRuleStrategy_ExecuteRuleIsNotRounded(t *testing.T) {
+	//ARRANGE
+	bulkRuleStrategy := BulkRuleStrategy{Rule: parser.BulkRule{RuleName: "Mug Bulk Rule", AffectedItem: "MUG", TriggerAmount: 3, DiscountPercentage: 5}}
+
+	//3 mugs * 7.50 * 0.95 = 21.375
+	var expectedMicros int64 = 2137500000
+
+	c := getConfiguredItems()
+
+	//ACT
+	result := bulkRuleStrategy.ExecuteRule(c, map[string]int{
+		"MUG": 3,
+	})
+
+	//ASSERT
+	if result.Subtotal.Micros != expectedMicros {
+		t.Errorf("BulkRule subtotal should be exact and not rounded, expected: %d, got %d", expectedMicros, result.Subtotal.Micros)
+	}
+
+}
+
+func TestBulkRuleStrategy_ExecuteRuleReportsAdjustment(t *testing.T) {
+	//ARRANGE
+	bulkRuleStrategy := BulkRuleStrategy{Rule: parser.BulkRule{RuleName: "Bulk Rule", AffectedItem: "TSHIRT", TriggerAmount: 3, DiscountPercentage: 5}}
+
+	expectedAdjustment := Adjustment{RuleName: "Bulk Rule", AffectedItem: "TSHIRT", Units: 3, Discount: money.New(300, "EUR").Subtotal()}
+
+	c := getConfiguredItems()
+
+	//ACT
+	result := bulkRuleStrategy.ExecuteRule(c, map[string]int{
+		"TSHIRT": 3,
 	})
 
 	//ASSERT
-	if result != expectedCalc {
-		t.Errorf("BUlkRule should been applied once, ignoring non affected items, expected: %.2f, got %.2f", float64(expectedCalc), float64(result))
+	if len(result.Adjustments) != 1 || !reflect.DeepEqual(result.Adjustments[0], expectedAdjustment) {
+		t.Errorf("BulkRule should have reported its discount, expected: %+v, got %+v", expectedAdjustment, result.Adjustments)
 	}
 
 }
 
+func TestBulkRuleStrategy_ExecuteRuleNotTriggeredNoAdjustments(t *testing.T) {
+	//ARRANGE
+	bulkRuleStrategy := BulkRuleStrategy{Rule: parser.BulkRule{RuleName: "Bulk Rule", AffectedItem: "TSHIRT", TriggerAmount: 3, DiscountPercentage: 5}}
+
+	c := getConfiguredItems()
+
+	//ACT
+	result := bulkRuleStrategy.ExecuteRule(c, map[string]int{
+		"TSHIRT": 2,
+	})
+
+	//ASSERT
+	if len(result.Adjustments) != 0 {
+		t.Errorf("BulkRule should not have reported any discount, got %+v", result.Adjustments)
+	}
+
+}
+
+func TestNxMRuleStrategy_ExecuteRuleReportsAdjustment(t *testing.T) {
+	//ARRANGE
+	nxmRuleStrategy := NxMRuleStrategy{Rule: parser.NxMRule{RuleName: "NxM Rule", AffectedItem: "VOUCHER", BuyN: 2, PayM: 1}}
+
+	//Only the 4 vouchers forming two complete bundles are affected, 2 of them are free
+	expectedAdjustment := Adjustment{RuleName: "NxM Rule", AffectedItem: "VOUCHER", Units: 4, Discount: money.New(1000, "EUR").Subtotal()}
+
+	c := getConfiguredItems()
+
+	//ACT
+	result := nxmRuleStrategy.ExecuteRule(c, map[string]int{
+		"VOUCHER": 5,
+	})
+
+	//ASSERT
+	if len(result.Adjustments) != 1 || !reflect.DeepEqual(result.Adjustments[0], expectedAdjustment) {
+		t.Errorf("NxMRule should have reported its discount, expected: %+v, got %+v", expectedAdjustment, result.Adjustments)
+	}
+
+}
+
+func TestBuyXGetYRuleStrategy_ExecuteRule(t *testing.T) {
+	//ARRANGE
+	buyXGetYRuleStrategy := BuyXGetYRuleStrategy{Rule: parser.BuyXGetYRule{RuleName: "Free Mug", TriggerItem: "TSHIRT", TriggerQuantity: 1,
+		RewardItem: "MUG", RewardQuantity: 1, RewardDiscountPercentage: 100}}
+
+	//Only 2 of the 3 t-shirts are needed for the 2 mugs scanned
+	expectedAdjustment := Adjustment{RuleName: "Free Mug", AffectedItem: "MUG", Units: 2, Discount: money.New(1500, "EUR").Subtotal(),
+		Consumed: []ItemUnits{{ItemId: "TSHIRT", Units: 2}}}
+
+	c := getConfiguredItems()
+
+	//ACT
+	result := buyXGetYRuleStrategy.ExecuteRule(c, map[string]int{
+		"TSHIRT": 3,
+		"MUG":    2,
+	})
+
+	//ASSERT
+	if !result.Subtotal.IsZero() || result.PricedUnits["MUG"] != 2 || result.PricedUnits["TSHIRT"] != 0 {
+		t.Errorf("BuyXGetYRule should only price the rewarded mugs, got %v for %v", result.Subtotal, result.PricedUnits)
+	}
+
+	if len(result.Adjustments) != 1 || !reflect.DeepEqual(result.Adjustments[0], expectedAdjustment) {
+		t.Errorf("BuyXGetYRule should have reported its discount, expected: %+v, got %+v", expectedAdjustment, result.Adjustments)
+	}
+
+}
+
+func TestBuyXGetYRuleStrategy_ExecuteRuleRewardNotScanned(t *testing.T) {
+	//ARRANGE
+	buyXGetYRuleStrategy := BuyXGetYRuleStrategy{Rule: parser.BuyXGetYRule{RuleName: "Free Mug", TriggerItem: "TSHIRT", TriggerQuantity: 1,
+		RewardItem: "MUG", RewardQuantity: 1, RewardDiscountPercentage: 100}}
+
+	c := getConfiguredItems()
+
+	//ACT
+	result := buyXGetYRuleStrategy.ExecuteRule(c, map[string]int{
+		"TSHIRT": 3,
+	})
+
+	//ASSERT
+	if !result.Subtotal.IsZero() || len(result.Adjustments) != 0 {
+		t.Errorf("BuyXGetYRule should not discount reward items that haven't been scanned, got %+v", result)
+	}
+
+}
+
+func TestBuyXGetYRuleStrategy_ExecuteRuleMaxApplications(t *testing.T) {
+	//ARRANGE
+	buyXGetYRuleStrategy := BuyXGetYRuleStrategy{Rule: parser.BuyXGetYRule{RuleName: "Half Price Vouchers", TriggerItem: "TSHIRT", TriggerQuantity: 2,
+		RewardItem: "VOUCHER", RewardQuantity: 2, RewardDiscountPercentage: 50, MaxApplications: 1}}
+
+	//Three applications are possible, but only one is allowed: 2 vouchers at half price
+	expectedAdjustment := Adjustment{RuleName: "Half Price Vouchers", AffectedItem: "VOUCHER", Units: 2, Discount: money.New(500, "EUR").Subtotal(),
+		Consumed: []ItemUnits{{ItemId: "TSHIRT", Units: 2}}}
+
+	c := getConfiguredItems()
+
+	//ACT
+	result := buyXGetYRuleStrategy.ExecuteRule(c, map[string]int{
+		"TSHIRT":  6,
+		"VOUCHER": 10,
+	})
+
+	//ASSERT
+	if len(result.Adjustments) != 1 || !reflect.DeepEqual(result.Adjustments[0], expectedAdjustment) {
+		t.Errorf("BuyXGetYRule should have been applied once, expected: %+v, got %+v", expectedAdjustment, result.Adjustments)
+	}
+
+}
+
+func TestBuyXGetYRuleStrategy_ExecuteRuleSameItem(t *testing.T) {
+	//ARRANGE
+	buyXGetYRuleStrategy := BuyXGetYRuleStrategy{Rule: parser.BuyXGetYRule{RuleName: "Buy 2 Mugs Get 1 Free", TriggerItem: "MUG", TriggerQuantity: 2,
+		RewardItem: "MUG", RewardQuantity: 1, RewardDiscountPercentage: 100}}
+
+	c := getConfiguredItems()
+
+	for scanned, expectedRewarded := range map[int]int{2: 0, 3: 1, 5: 1, 6: 2} {
+		//ACT
+		result := buyXGetYRuleStrategy.ExecuteRule(c, map[string]int{
+			"MUG": scanned,
+		})
+
+		//ASSERT
+		rewarded := 0
+		if len(result.Adjustments) == 1 {
+			rewarded = result.Adjustments[0].Units
+			if consumed := result.Adjustments[0].Consumed[0].Units; consumed != rewarded*2 {
+				t.Errorf("BuyXGetYRule should have consumed %d mugs out of %d, got %d", rewarded*2, scanned, consumed)
+			}
+		}
+		if rewarded != expectedRewarded {
+			t.Errorf("BuyXGetYRule should have rewarded %d mugs out of %d, got %d", expectedRewarded, scanned, rewarded)
+		}
+	}
+
+}
+
+func TestBundleRuleStrategy_ExecuteRule(t *testing.T) {
+	//ARRANGE
+	price, _ := money.ParseDecimal("25.00")
+	bundleRuleStrategy := BundleRuleStrategy{Rule: parser.BundleRule{RuleName: "T-shirt + Mug", BundlePrice: price,
+		Components: []parser.BundleComponent{{Item: "TSHIRT", Quantity: 1}, {Item: "MUG", Quantity: 1}}}}
+
+	//3 T-shirts and 2 mugs make 2 bundles, 27.50 each, sold at 25.00
+	expectedAdjustment := Adjustment{RuleName: "T-shirt + Mug", AffectedItem: "TSHIRT+MUG", Units: 2, Discount: money.New(500, "EUR").Subtotal(),
+		Consumed: []ItemUnits{{ItemId: "TSHIRT", Units: 2}, {ItemId: "MUG", Units: 2}}}
+
+	c := getConfiguredItems()
+
+	//ACT
+	result := bundleRuleStrategy.ExecuteRule(c, map[string]int{
+		"TSHIRT": 3,
+		"MUG":    2,
+	})
+
+	//ASSERT
+	if result.Subtotal.Round(money.HalfUp) != money.New(5000, "EUR") {
+		t.Errorf("BundleRule should have charged 2 bundles, expected: 50.00, got %s", result.Subtotal)
+	}
+
+	if result.PricedUnits["TSHIRT"] != 2 || result.PricedUnits["MUG"] != 2 {
+		t.Errorf("BundleRule should only price the bundled units, got %v", result.PricedUnits)
+	}
+
+	if len(result.Adjustments) != 1 || !reflect.DeepEqual(result.Adjustments[0], expectedAdjustment) {
+		t.Errorf("BundleRule should have reported its discount, expected: %+v, got %+v", expectedAdjustment, result.Adjustments)
+	}
+
+}
+
+func TestBundleRuleStrategy_ExecuteRuleIncompleteBundle(t *testing.T) {
+	//ARRANGE
+	bundleRuleStrategy := BundleRuleStrategy{Rule: parser.BundleRule{RuleName: "Mugs pack", DiscountPercentage: 10,
+		Components: []parser.BundleComponent{{Item: "MUG", Quantity: 3}}}}
+
+	c := getConfiguredItems()
+
+	//ACT
+	result := bundleRuleStrategy.ExecuteRule(c, map[string]int{
+		"MUG": 2,
+	})
+
+	//ASSERT
+	if !result.Subtotal.IsZero() || len(result.PricedUnits) != 0 || len(result.Adjustments) != 0 {
+		t.Errorf("BundleRule should not price anything without a complete bundle, got %+v", result)
+	}
+
+}
+
+func TestExecuteRulesBundleNotCheaper(t *testing.T) {
+	//ARRANGE
+	price, _ := money.ParseDecimal("25.00")
+	executors := BuildRuleExecutors(parser.Rules{BundleRules: []parser.BundleRule{{RuleName: "T-shirt + Mug", BundlePrice: price,
+		Components: []parser.BundleComponent{{Item: "TSHIRT", Quantity: 1}, {Item: "MUG", Quantity: 1}}}}})
+	c := getVariantItems()
+	small := c["TSHIRT-S"]
+	small.Price = money.New(1000, "EUR")
+	c["TSHIRT-S"] = small
+
+	//ACT
+	result := ExecuteRules(executors, c, map[string]int{
+		"TSHIRT-S": 1,
+		"MUG":      1,
+	})
+
+	//ASSERT
+	if result.Subtotal.Round(money.HalfUp) != money.New(1750, "EUR") || len(result.Adjustments) != 0 {
+		t.Errorf("The bundle should not charge more than its units, expected: 17.50 without adjustments, got %s %+v", result.Subtotal, result.Adjustments)
+	}
+
+}
+
+func TestExecuteRulesBundleLeftovers(t *testing.T) {
+	//ARRANGE
+	executors := BuildRuleExecutors(parser.Rules{
+		BundleRules: []parser.BundleRule{{RuleName: "T-shirt + Mug", DiscountPercentage: 20,
+			Components: []parser.BundleComponent{{Item: "TSHIRT", Quantity: 1}, {Item: "MUG", Quantity: 1}}}},
+		BulkRules: []parser.BulkRule{{RuleName: "Bulk Rule", AffectedItem: "TSHIRT", TriggerAmount: 3, DiscountPercentage: 5}},
+	})
+
+	//1 bundle at 27.50 * 0.8 = 22.00, the 3 T-shirts left trigger the bulk rule: 3 * 20.00 * 0.95 = 57.00
+	var expectedCalc int64 = 7900
+
+	c := getConfiguredItems()
+
+	//ACT
+	result := ExecuteRules(executors, c, map[string]int{
+		"TSHIRT": 4,
+		"MUG":    1,
+	})
+
+	//ASSERT
+	if result.Subtotal.Round(money.HalfUp).Amount != expectedCalc {
+		t.Errorf("The bundled units should not be seen by the bulk rule, expected: %.2f, got %s", float64(expectedCalc)/100, result.Subtotal)
+	}
+
+	if len(result.Adjustments) != 2 || result.Adjustments[1].Units != 3 {
+		t.Errorf("The bulk rule should only affect the T-shirts left, got %+v", result.Adjustments)
+	}
+
+}
+
+func getTieredBulkRule(mode string) parser.BulkRule {
+	unitPrice, _ := money.ParseDecimal("15.00")
+	return parser.BulkRule{RuleName: "Tiered Rule", AffectedItem: "TSHIRT", Mode: mode, Tiers: []parser.BulkTier{
+		{Threshold: 3, DiscountPercentage: 5},
+		{Threshold: 10, DiscountPercentage: 12},
+		{Threshold: 50, UnitPrice: unitPrice},
+	}}
+}
+
+func TestBulkRuleStrategy_ExecuteRuleTiersWholeQuantity(t *testing.T) {
+	//ARRANGE
+	bulkRuleStrategy := BulkRuleStrategy{Rule: getTieredBulkRule(parser.BulkWholeQuantity)}
+
+	c := getConfiguredItems()
+
+	//ACT & ASSERT
+	//2 below the first tier, 9 at 5% off, 10 at 12% off, 50 at 15.00
+	for units, expected := range map[int]int64{2: 4000, 9: 17100, 10: 17600, 50: 75000} {
+		result := bulkRuleStrategy.ExecuteRule(c, map[string]int{"TSHIRT": units})
+		if result.Subtotal.Round(money.HalfUp).Amount != expected {
+			t.Errorf("%d T-shirts should be priced at the highest tier reached, expected: %d, got %s", units, expected, result.Subtotal)
+		}
+	}
+
+}
+
+func TestBulkRuleStrategy_ExecuteRuleTiersGraduated(t *testing.T) {
+	//ARRANGE
+	bulkRuleStrategy := BulkRuleStrategy{Rule: getTieredBulkRule(parser.BulkGraduated)}
+
+	//2 T-shirts at 20.00, 7 at 19.00, 40 at 17.60 and 1 at 15.00
+	var expectedCalc int64 = 89200
+	expectedAdjustment := Adjustment{RuleName: "Tiered Rule", AffectedItem: "TSHIRT", Units: 48, Discount: money.New(10800, "EUR").Subtotal()}
+
+	c := getConfiguredItems()
+
+	//ACT
+	result := bulkRuleStrategy.ExecuteRule(c, map[string]int{
+		"TSHIRT": 50,
+	})
+
+	//ASSERT
+	if result.Subtotal.Round(money.HalfUp).Amount != expectedCalc {
+		t.Errorf("Every band should be priced at its own tier, expected: %d, got %s", expectedCalc, result.Subtotal)
+	}
+
+	if len(result.Adjustments) != 1 || !reflect.DeepEqual(result.Adjustments[0], expectedAdjustment) {
+		t.Errorf("BulkRule should have reported the units priced at any tier, expected: %+v, got %+v", expectedAdjustment, result.Adjustments)
+	}
+
+}
+
+func TestBulkRuleStrategy_ExecuteRuleTiersGraduatedBelowFirstTier(t *testing.T) {
+	//ARRANGE
+	bulkRuleStrategy := BulkRuleStrategy{Rule: getTieredBulkRule(parser.BulkGraduated)}
+
+	c := getConfiguredItems()
+
+	//ACT
+	result := bulkRuleStrategy.ExecuteRule(c, map[string]int{
+		"TSHIRT": 2,
+	})
+
+	//ASSERT
+	if result.Subtotal.Round(money.HalfUp) != money.New(4000, "EUR") || len(result.Adjustments) != 0 {
+		t.Errorf("No tier should have been applied, got %+v", result)
+	}
+
+}
+
+func TestBasketRuleStrategy_ExecuteBasketRule(t *testing.T) {
+	//ARRANGE
+	threshold, _ := money.ParseDecimal("50.00")
+	maxDiscount, _ := money.ParseDecimal("8.00")
+	basketRuleStrategy := BasketRuleStrategy{Rule: parser.BasketRule{RuleName: "10% over 50", Threshold: threshold,
+		DiscountPercentage: 10, MaxDiscount: maxDiscount, ExcludedItems: []string{"VOUCHER"}}}
+
+	c := getConfiguredItems()
+
+	//ACT & ASSERT
+	//The vouchers don't count, so 47.50 doesn't reach the threshold, 55.00 is 5.50 off and 87.50 reaches the cap
+	for items, expected := range map[[3]int]int64{{2, 1, 10}: 0, {2, 2, 10}: 550, {4, 1, 10}: 800} {
+		priced := ExecuteRules(BuildRuleExecutors(parser.Rules{}), c, map[string]int{"TSHIRT": items[0], "MUG": items[1], "VOUCHER": items[2]})
+
+		result := basketRuleStrategy.ExecuteBasketRule(c, priced)
+
+		discount := money.Zero("EUR").Sub(result.Subtotal).Round(money.HalfUp).Amount
+		if discount != expected {
+			t.Errorf("The discount for %v should be %d, got %d", items, expected, discount)
+		}
+		if _, exs := result.ItemSubtotals["VOUCHER"]; exs {
+			t.Errorf("The excluded vouchers should not be discounted, got %v", result.ItemSubtotals)
+		}
+	}
+
+}
+
+func TestExecuteBasketRulesSeeDiscountedAmounts(t *testing.T) {
+	//ARRANGE
+	threshold, _ := money.ParseDecimal("60.00")
+	amount, _ := money.ParseDecimal("5.00")
+	rulesFactory := RuleStrategyFactory{RuleParser: &MockedRulesParser{}}
+	rulesFactory.LoadRules("DUMMY_PATH")
+	basketExecutors := BuildBasketExecutors(parser.Rules{BasketRules: []parser.BasketRule{
+		{RuleName: "5.00 off over 60", Threshold: threshold, DiscountAmount: amount},
+	}})
+
+	c := getConfiguredItems()
+
+	//ACT
+	//3 T-shirts are 60.00, but only 57.00 once the bulk rule has been applied
+	itemsResult := ExecuteRules(rulesFactory.RuleExecutors, c, map[string]int{"TSHIRT": 3})
+	notReached := ExecuteBasketRules(basketExecutors, c, itemsResult)
+	itemsResult = ExecuteRules(rulesFactory.RuleExecutors, c, map[string]int{"TSHIRT": 3, "MUG": 1})
+	reached := ExecuteBasketRules(basketExecutors, c, itemsResult)
+
+	//ASSERT
+	if notReached.Subtotal.Round(money.HalfUp) != money.New(5700, "EUR") {
+		t.Errorf("The threshold should be checked against the discounted amount, expected: 57.00, got %s", notReached.Subtotal)
+	}
+
+	if reached.Subtotal.Round(money.HalfUp) != money.New(5950, "EUR") || len(reached.Adjustments) != 2 {
+		t.Errorf("The basket rule should have been applied after the bulk rule, expected: 59.50, got %s (%+v)", reached.Subtotal, reached.Adjustments)
+	}
+
+}
+
+func TestLoadRulesBasketPhase(t *testing.T) {
+	//ARRANGE
+	rulesFactory := RuleStrategyFactory{RuleParser: &MockedRulesParser{}}
+
+	//ACT
+	rulesFactory.LoadRules("DUMMY_PATH")
+
+	//ASSERT
+	if len(rulesFactory.BasketExecutors) != 0 {
+		t.Errorf("There should be no basket rules, got: %d", len(rulesFactory.BasketExecutors))
+	}
+
+}
+
+type selectorRulesParser struct{}
+
+func (selectorRulesParser) ParseRulesFile(p string) (parser.Rules, error) {
+	return parser.Rules{
+		NxmRules: []parser.NxMRule{{
+			RuleName:     "Merch 2x1",
+			ItemSelector: parser.ItemSelector{AffectedCategory: "merch"},
+			BuyN:         2,
+			PayM:         1,
+		}},
+	}, nil
+}
+
+func TestResolveSelectorsExecutesRuleForEverySelectedItem(t *testing.T) {
+	//ARRANGE
+	rulesFactory := RuleStrategyFactory{RuleParser: selectorRulesParser{}}
+	rulesFactory.LoadRules("DUMMY_PATH")
+	items := getConfiguredItems()
+	for id, category := range map[string]string{"TSHIRT": "merch/apparel", "MUG": "merch/kitchen", "VOUCHER": "gifts"} {
+		item := items[id]
+		item.Category = category
+		items[id] = item
+	}
+
+	//ACT
+	unresolved := len(rulesFactory.RuleExecutors)
+	rulesFactory.ResolveSelectors(items)
+	result := ExecuteRules(rulesFactory.RuleExecutors, items, map[string]int{"TSHIRT": 2, "MUG": 2, "VOUCHER": 2})
+
+	//ASSERT
+	if unresolved != 1 || len(rulesFactory.RuleExecutors) != 3 {
+		t.Errorf("The rule should only be executed once resolved, for the mug and the T-shirt, got: %d and %d executors", unresolved, len(rulesFactory.RuleExecutors))
+	}
+	//A T-shirt and a mug free, the vouchers at their price
+	if total := result.Subtotal.Round(money.HalfUp); total != money.New(3750, "EUR") || len(result.Adjustments) != 2 {
+		t.Errorf("The rule should have been applied to every item of the category, expected: 37.50, got: %s (%+v)", total, result.Adjustments)
+	}
+}
@@ -0,0 +1,28 @@
+package rules
+
+import (
+	"time"
+)
+
+//Returns the executors of the item rules whose schedule is active at the given time, in the same order
+//The days and times of the day of the schedules are checked in the location of the given time
+func ScheduledExecutors(executors []RuleStrategyExecutor, at time.Time) []RuleStrategyExecutor {
+	var active []RuleStrategyExecutor
+	for _, e := range executors {
+		if optionsOf(e).ActiveAt(at) {
+			active = append(active, e)
+		}
+	}
+	return active
+}
+
+//Returns the executors of the basket rules whose schedule is active at the given time, see ScheduledExecutors
+func ScheduledBasketExecutors(executors []BasketRuleStrategyExecutor, at time.Time) []BasketRuleStrategyExecutor {
+	var active []BasketRuleStrategyExecutor
+	for _, e := range executors {
+		if optionsOf(e).ActiveAt(at) {
+			active = append(active, e)
+		}
+	}
+	return active
+}
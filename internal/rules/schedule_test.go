@@ -0,0 +1,48 @@
+package rules
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"testing"
+	"time"
+)
+
+func TestScheduledExecutors(t *testing.T) {
+	//ARRANGE
+	weekend := parser.RuleOptions{Schedule: parser.Schedule{DaysOfWeek: []string{"saturday", "sunday"}}}
+	executors := BuildRuleExecutors(parser.Rules{
+		BulkRules: []parser.BulkRule{{RuleName: "Bulk Rule", AffectedItem: "TSHIRT", TriggerAmount: 3, DiscountPercentage: 5}},
+		NxmRules:  []parser.NxMRule{{RuleName: "Weekend 2x1", AffectedItem: "VOUCHER", BuyN: 2, PayM: 1, RuleOptions: weekend}},
+	})
+	saturday := time.Date(2026, 6, 13, 12, 0, 0, 0, time.UTC)
+
+	//ACT
+	onWeekend := ScheduledExecutors(executors, saturday)
+	onMonday := ScheduledExecutors(executors, saturday.AddDate(0, 0, 2))
+
+	//ASSERT
+	if len(onWeekend) != len(executors) {
+		t.Errorf("Every rule should be active on the weekend, got: %v", onWeekend)
+	}
+
+	if len(onMonday) != 2 || nameOf(onMonday[0]) != "Bulk Rule" {
+		t.Errorf("The weekend rule should not be active on monday, got: %v", onMonday)
+	}
+
+}
+
+func TestScheduledBasketExecutors(t *testing.T) {
+	//ARRANGE
+	until := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	executors := BuildBasketExecutors(parser.Rules{BasketRules: []parser.BasketRule{{RuleName: "Spring", DiscountPercentage: 10,
+		RuleOptions: parser.RuleOptions{Schedule: parser.Schedule{ValidUntil: until}}}}})
+
+	//ACT
+	before := ScheduledBasketExecutors(executors, until.Add(-time.Second))
+	after := ScheduledBasketExecutors(executors, until)
+
+	//ASSERT
+	if len(before) != 1 || len(after) != 0 {
+		t.Errorf("The basket rule should only be active until the end of its validity window, got: %v and %v", before, after)
+	}
+
+}
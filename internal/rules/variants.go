@@ -0,0 +1,63 @@
+package rules
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"sort"
+)
+
+//Returns every unit scanned of the given items along with its price, sorted from the most expensive, the units with
+//the same price keep the order of their items. The units of an item with variants are the units of its variants, so
+//mixed variants are counted together, see parser.ConfiguredItems.ItemsOf
+func scannedUnits(conf parser.ConfiguredItems, items []string, scannedItems map[string]int) []pricedUnit {
+	var units []pricedUnit
+	for _, item := range items {
+		for _, sold := range conf.ItemsOf(item) {
+			for n := 0; n < scannedItems[sold]; n++ {
+				units = append(units, pricedUnit{item: sold, price: conf[sold].Price})
+			}
+		}
+	}
+	sort.SliceStable(units, func(i, j int) bool { return units[i].price.Amount > units[j].price.Amount })
+	return units
+}
+
+//Returns the number of units scanned of the given item, the units of all its variants if it has them
+func unitsOf(conf parser.ConfiguredItems, item string, scannedItems map[string]int) int {
+	units := 0
+	for _, sold := range conf.ItemsOf(item) {
+		units += scannedItems[sold]
+	}
+	return units
+}
+
+//Returns the given units by item and the price of all of them by item
+func countUnits(units []pricedUnit) (map[string]int, map[string]money.Subtotal) {
+	counts := make(map[string]int)
+	gross := make(map[string]money.Subtotal)
+	for _, u := range units {
+		counts[u.item]++
+		gross[u.item] = gross[u.item].Add(u.price.Subtotal())
+	}
+	return counts, gross
+}
+
+//Returns the result of pricing the paid units at their price and the free ones at zero, along with an adjustment
+//discounting the free units, which consumes all of them and reports the free ones by item
+func freeUnitsResult(currency string, paid []pricedUnit, free []pricedUnit) (RuleResult, Adjustment) {
+	result := RuleResult{Subtotal: money.Zero(currency), PricedUnits: make(map[string]int), ItemSubtotals: make(map[string]money.Subtotal)}
+	discount := money.Zero(currency)
+	discounted := make(map[string]int)
+	for _, u := range paid {
+		result.PricedUnits[u.item]++
+		result.ItemSubtotals[u.item] = result.ItemSubtotals[u.item].Add(u.price.Subtotal())
+		result.Subtotal = result.Subtotal.Add(u.price.Subtotal())
+	}
+	for _, u := range free {
+		result.PricedUnits[u.item]++
+		result.ItemSubtotals[u.item] = result.ItemSubtotals[u.item].Add(money.Zero(currency))
+		discounted[u.item]++
+		discount = discount.Add(u.price.Subtotal())
+	}
+	return result, Adjustment{Units: len(free), Discount: discount, Consumed: sortedUnits(result.PricedUnits), Discounted: sortedUnits(discounted)}
+}
@@ -0,0 +1,80 @@
+package rules
+
+import (
+	"github.com/dagozba/golangsmallshop/internal/money"
+	"github.com/dagozba/golangsmallshop/internal/parser"
+	"reflect"
+	"testing"
+)
+
+func getVariantItems() parser.ConfiguredItems {
+	c := getConfiguredItems()
+	c["TSHIRT"] = parser.ItemDefinition{
+		Name:        "Company T-Shirt",
+		Price:       money.New(2000, "EUR"),
+		VariantAxes: []string{"size"},
+		Variants:    []string{"TSHIRT-L", "TSHIRT-S", "TSHIRT-XXL"},
+	}
+	c["TSHIRT-S"] = parser.ItemDefinition{Name: "Company T-Shirt (S)", Price: money.New(2000, "EUR"), Parent: "TSHIRT"}
+	c["TSHIRT-L"] = parser.ItemDefinition{Name: "Company T-Shirt (L)", Price: money.New(2000, "EUR"), Parent: "TSHIRT"}
+	c["TSHIRT-XXL"] = parser.ItemDefinition{Name: "Company T-Shirt (XXL)", Price: money.New(2200, "EUR"), Parent: "TSHIRT"}
+	return c
+}
+
+func TestNxMRuleStrategy_ExecuteRuleMixedVariants(t *testing.T) {
+	//ARRANGE
+	nxmRuleStrategy := NxMRuleStrategy{Rule: parser.NxMRule{RuleName: "T-Shirts 3x2", AffectedItem: "TSHIRT", BuyN: 3, PayM: 2}}
+	expectedAdjustment := Adjustment{
+		RuleName:     "T-Shirts 3x2",
+		AffectedItem: "TSHIRT",
+		Units:        3,
+		Discount:     money.New(2000, "EUR").Subtotal(),
+		Discounted:   []ItemUnits{{ItemId: "TSHIRT-S", Units: 1}},
+	}
+
+	//ACT
+	result := nxmRuleStrategy.ExecuteRule(getVariantItems(), map[string]int{"TSHIRT-S": 1, "TSHIRT-L": 1, "TSHIRT-XXL": 1, "MUG": 1})
+
+	//ASSERT
+	//The XXL and a 20.00 T-shirt are paid, the other one is free
+	if result.Subtotal.Round(money.HalfUp) != money.New(4200, "EUR") {
+		t.Errorf("The cheapest T-shirt should have been free, got %s", result.Subtotal)
+	}
+	if !reflect.DeepEqual(result.PricedUnits, map[string]int{"TSHIRT-S": 1, "TSHIRT-L": 1, "TSHIRT-XXL": 1}) {
+		t.Errorf("Every variant should have been priced, got %v", result.PricedUnits)
+	}
+	if len(result.Adjustments) != 1 || !reflect.DeepEqual(result.Adjustments[0], expectedAdjustment) {
+		t.Errorf("Expected the adjustment %+v, got %+v", expectedAdjustment, result.Adjustments)
+	}
+}
+
+func TestBulkRuleStrategy_ExecuteRuleMixedVariants(t *testing.T) {
+	//ARRANGE
+	scanned := map[string]int{"TSHIRT-L": 2, "TSHIRT-XXL": 1}
+
+	//ACT & ASSERT
+	//3 T-shirts reach the first tier: every one at 5% off in whole quantity mode, only the third and cheapest one in
+	//graduated mode
+	for mode, expected := range map[string]money.Money{parser.BulkWholeQuantity: money.New(5890, "EUR"), parser.BulkGraduated: money.New(6100, "EUR")} {
+		result := BulkRuleStrategy{Rule: getTieredBulkRule(mode)}.ExecuteRule(getVariantItems(), scanned)
+		if result.Subtotal.Round(money.HalfUp) != expected {
+			t.Errorf("%s: the variants should have been counted together, expected: %s, got %s", mode, expected, result.Subtotal)
+		}
+		if result.PricedUnits["TSHIRT-L"] != 2 || result.PricedUnits["TSHIRT-XXL"] != 1 {
+			t.Errorf("%s: every variant should have been priced, got %v", mode, result.PricedUnits)
+		}
+	}
+}
+
+func TestExecuteRulesVariantsWithoutRules(t *testing.T) {
+	//ARRANGE
+	executors := BuildRuleExecutors(parser.Rules{})
+
+	//ACT
+	result := ExecuteRules(executors, getVariantItems(), map[string]int{"TSHIRT-S": 1, "TSHIRT-XXL": 2})
+
+	//ASSERT
+	if result.Subtotal.Round(money.HalfUp) != money.New(6400, "EUR") {
+		t.Errorf("Every variant should have been priced at its own price, got %s", result.Subtotal)
+	}
+}